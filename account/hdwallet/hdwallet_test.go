@@ -0,0 +1,176 @@
+package hdwallet
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/annchain/OG/common/crypto"
+)
+
+func TestMnemonic(t *testing.T) {
+	if len(englishWordList) != 2048 {
+		t.Fatalf("expected 2048 words, got %d", len(englishWordList))
+	}
+	// test vectors of BIP-39 with passphrase TREZOR.
+	cases := []struct {
+		entropy, mnemonic, seed string
+	}{
+		{
+			"00000000000000000000000000000000",
+			"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+			"c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+		},
+		{
+			"7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+			"legal winner thank year wave sausage worth useful legal winner thank yellow",
+			"2e8905819b8723fe2c1d161860e5ee1830318dbf49a83bd451cfb8440c28bd6fa457fe1296106559a3c80937a1c1069be3a3a5bd381ee6260e8d9739fce1f607",
+		},
+		{
+			"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+			"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo vote",
+			"dd48c104698c30cfe2b6142103248622fb7bb0ff692eebb00089b32d22484e1613912f0a5b694407be899ffd31ed3992c456cdf60f5d4564b8ba3f05a69890ad",
+		},
+	}
+	for _, c := range cases {
+		entropy, _ := hex.DecodeString(c.entropy)
+		mnemonic, err := EntropyToMnemonic(entropy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mnemonic != c.mnemonic {
+			t.Fatalf("expected mnemonic %q, got %q", c.mnemonic, mnemonic)
+		}
+		decoded, err := MnemonicToEntropy(mnemonic)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(decoded) != c.entropy {
+			t.Fatalf("expected entropy %s, got %x", c.entropy, decoded)
+		}
+		seed, err := NewSeed(mnemonic, "TREZOR")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(seed) != c.seed {
+			t.Fatalf("expected seed %s, got %x", c.seed, seed)
+		}
+	}
+
+	for _, bad := range []string{
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon",
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon",
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon og",
+	} {
+		if IsMnemonicValid(bad) {
+			t.Fatalf("expected invalid mnemonic %q", bad)
+		}
+	}
+
+	mnemonic, err := NewMnemonic(256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(strings.Fields(mnemonic)) != 24 || !IsMnemonicValid(mnemonic) {
+		t.Fatalf("invalid random mnemonic %q", mnemonic)
+	}
+}
+
+func TestDerivationPath(t *testing.T) {
+	path, err := ParseDerivationPath("m/44'/60'/0'/0/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path.String() != "m/44'/60'/0'/0/1" {
+		t.Fatalf("unexpected path %s", path)
+	}
+	if child, _ := DefaultEd25519BasePath.Child(3); child.String() != "m/44'/60'/0'/0'/3'" {
+		t.Fatalf("unexpected ed25519 child path %s", child)
+	}
+	if child, _ := DefaultSecp256k1BasePath.Child(3); child.String() != "m/44'/60'/0'/0/3" {
+		t.Fatalf("unexpected secp256k1 child path %s", child)
+	}
+	for _, bad := range []string{"", "44'/0", "m/x", "m/2147483648"} {
+		if _, err := ParseDerivationPath(bad); err == nil {
+			t.Fatalf("expected error parsing %q", bad)
+		}
+	}
+}
+
+func TestDeriveKey(t *testing.T) {
+	// test vector 1 of BIP-32 and SLIP-10.
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	cases := []struct {
+		cryptoType crypto.CryptoType
+		path       string
+		key        string
+	}{
+		{crypto.CryptoTypeSecp256k1, "m", "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35"},
+		{crypto.CryptoTypeSecp256k1, "m/0'/1", "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368"},
+		{crypto.CryptoTypeSecp256k1, "m/0'/1/2'/2/1000000000", "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8"},
+		{crypto.CryptoTypeEd25519, "m", "2b4be7f19ee27bbf30c667b642d5f4aa69fd169872f8fc3059c08ebae2eb19e7"},
+		{crypto.CryptoTypeEd25519, "m/0'", "68e0fe46dfb67e368c75379acec591dad19df3cde26e63b93a8e704f1dade7a3"},
+		{crypto.CryptoTypeEd25519, "m/0'/1'/2'/2'/1000000000'", "8f94d394a8e8fd6b1bc2f3f49f5c47e385281d5c17e65324b0f62483e37e8793"},
+	}
+	for _, c := range cases {
+		path, err := ParseDerivationPath(c.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		master, err := NewMasterKey(seed, c.cryptoType)
+		if err != nil {
+			t.Fatal(err)
+		}
+		key, err := master.Derive(path)
+		if err != nil {
+			t.Fatalf("derive %s %s error: %v", c.cryptoType, c.path, err)
+		}
+		if hex.EncodeToString(key.key) != c.key {
+			t.Fatalf("%s %s: expected key %s, got %x", c.cryptoType, c.path, c.key, key.key)
+		}
+	}
+
+	master, _ := NewMasterKey(seed, crypto.CryptoTypeEd25519)
+	if _, err := master.Child(0); err == nil {
+		t.Fatal("expected error deriving a normal ed25519 child")
+	}
+}
+
+func TestDeriveAccounts(t *testing.T) {
+	seed, err := NewSeed("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, cryptoType := range []crypto.CryptoType{crypto.CryptoTypeSecp256k1, crypto.CryptoTypeEd25519} {
+		accounts, err := DeriveAccounts(seed, cryptoType, DefaultBasePath(cryptoType), 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(accounts) != 3 {
+			t.Fatalf("expected 3 accounts, got %d", len(accounts))
+		}
+		signer := crypto.NewSigner(cryptoType)
+		for i, account := range accounts {
+			priv, err := DeriveKey(seed, cryptoType, account.Path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(priv.Bytes) != string(account.PrivateKey.Bytes) {
+				t.Fatalf("account %d mismatches the key at %s", i, account.Path)
+			}
+			if signer.Address(signer.PubKey(priv)) != account.Address {
+				t.Fatalf("account %d mismatches its address", i)
+			}
+			// the signer works with the derived key.
+			msg := []byte("og")
+			if !signer.Verify(signer.PubKey(priv), signer.Sign(priv, msg), msg) {
+				t.Fatalf("account %d fails to verify its signature", i)
+			}
+		}
+	}
+	// the first ethereum account of the mnemonic.
+	accounts, _ := DeriveAccounts(seed, crypto.CryptoTypeSecp256k1, DefaultSecp256k1BasePath, 1)
+	if accounts[0].Address.Hex() != "0x9858effd232b4033e47d90003d41ec34ecaeda94" {
+		t.Fatalf("unexpected first account %s", accounts[0].Address.Hex())
+	}
+}
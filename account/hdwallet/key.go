@@ -0,0 +1,188 @@
+package hdwallet
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/annchain/OG/common/crypto"
+	"github.com/annchain/OG/types"
+	"golang.org/x/crypto/ed25519"
+)
+
+// ErrInvalidKey is returned for the keys out of the range of secp256k1,
+// which happens with a probability lower than 1 in 2^127. BIP-32 skips to
+// the next index in that case.
+var ErrInvalidKey = errors.New("derived key is invalid, try the next index")
+
+var secp256k1N = crypto.S256().Params().N
+
+// ExtendedKey is a private key along with the chain code deriving its
+// children.
+type ExtendedKey struct {
+	cryptoType crypto.CryptoType
+	key        []byte
+	chainCode  []byte
+}
+
+// NewMasterKey derives the master key of cryptoType from seed. The
+// secp256k1 keys are derived as BIP-32 and the ed25519 keys as SLIP-10.
+func NewMasterKey(seed []byte, cryptoType crypto.CryptoType) (*ExtendedKey, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, fmt.Errorf("seed length %d is out of [16, 64]", len(seed))
+	}
+	var curve string
+	switch cryptoType {
+	case crypto.CryptoTypeSecp256k1:
+		curve = "Bitcoin seed"
+	case crypto.CryptoTypeEd25519:
+		curve = "ed25519 seed"
+	default:
+		return nil, fmt.Errorf("unknown crypto type %d", cryptoType)
+	}
+	il, ir := hmacSHA512([]byte(curve), seed)
+	if cryptoType == crypto.CryptoTypeSecp256k1 && !isValidSecp256k1Key(il) {
+		return nil, ErrInvalidKey
+	}
+	return &ExtendedKey{cryptoType: cryptoType, key: il, chainCode: ir}, nil
+}
+
+// Child derives the child key at index, which is hardened if it is not
+// below HardenedOffset. Ed25519 keys have hardened children only.
+func (k *ExtendedKey) Child(index uint32) (*ExtendedKey, error) {
+	hardened := index >= HardenedOffset
+	if k.cryptoType == crypto.CryptoTypeEd25519 && !hardened {
+		return nil, fmt.Errorf("ed25519 key has hardened children only, got index %d", index)
+	}
+
+	var data []byte
+	if hardened {
+		data = append([]byte{0}, k.key...)
+	} else {
+		data = k.compressedPubKey()
+	}
+	var indexBytes [4]byte
+	binary.BigEndian.PutUint32(indexBytes[:], index)
+	data = append(data, indexBytes[:]...)
+	il, ir := hmacSHA512(k.chainCode, data)
+
+	if k.cryptoType == crypto.CryptoTypeEd25519 {
+		return &ExtendedKey{cryptoType: k.cryptoType, key: il, chainCode: ir}, nil
+	}
+	// the child key is (il + key) mod n.
+	if new(big.Int).SetBytes(il).Cmp(secp256k1N) >= 0 {
+		return nil, ErrInvalidKey
+	}
+	n := new(big.Int).SetBytes(il)
+	n.Add(n, new(big.Int).SetBytes(k.key))
+	n.Mod(n, secp256k1N)
+	if n.Sign() == 0 {
+		return nil, ErrInvalidKey
+	}
+	child := make([]byte, 32)
+	n.FillBytes(child)
+	return &ExtendedKey{cryptoType: k.cryptoType, key: child, chainCode: ir}, nil
+}
+
+// Derive derives the key at path from k, which is usually the master key.
+func (k *ExtendedKey) Derive(path DerivationPath) (*ExtendedKey, error) {
+	key := k
+	for _, index := range path {
+		var err error
+		if key, err = key.Child(index); err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+// PrivateKey returns the private key of k in the format of its signer.
+func (k *ExtendedKey) PrivateKey() crypto.PrivateKey {
+	if k.cryptoType == crypto.CryptoTypeEd25519 {
+		return crypto.PrivateKeyFromBytes(k.cryptoType, ed25519.NewKeyFromSeed(k.key))
+	}
+	return crypto.PrivateKeyFromBytes(k.cryptoType, append([]byte{}, k.key...))
+}
+
+// Address returns the address of k.
+func (k *ExtendedKey) Address() types.Address {
+	signer := crypto.NewSigner(k.cryptoType)
+	return signer.Address(signer.PubKey(k.PrivateKey()))
+}
+
+func (k *ExtendedKey) compressedPubKey() []byte {
+	priv, _ := crypto.ToECDSA(k.key)
+	return crypto.CompressPubkey(&priv.PublicKey)
+}
+
+// DeriveKey derives the key of cryptoType at path from the seed of a
+// mnemonic.
+func DeriveKey(seed []byte, cryptoType crypto.CryptoType, path DerivationPath) (crypto.PrivateKey, error) {
+	master, err := NewMasterKey(seed, cryptoType)
+	if err != nil {
+		return crypto.PrivateKey{}, err
+	}
+	key, err := master.Derive(path)
+	if err != nil {
+		return crypto.PrivateKey{}, err
+	}
+	return key.PrivateKey(), nil
+}
+
+// Account is a key derived from a mnemonic.
+type Account struct {
+	Path       DerivationPath
+	Address    types.Address
+	PrivateKey crypto.PrivateKey
+}
+
+// DeriveAccounts derives the first n accounts of cryptoType under base,
+// the children of base at index 0 to n-1. The indexes of invalid keys are
+// skipped as BIP-32 requires.
+func DeriveAccounts(seed []byte, cryptoType crypto.CryptoType, base DerivationPath, n int) ([]Account, error) {
+	master, err := NewMasterKey(seed, cryptoType)
+	if err != nil {
+		return nil, err
+	}
+	parent, err := master.Derive(base)
+	if err != nil {
+		return nil, err
+	}
+	signer := crypto.NewSigner(cryptoType)
+	accounts := make([]Account, 0, n)
+	for index := uint32(0); len(accounts) < n; index++ {
+		path, err := base.Child(index)
+		if err != nil {
+			return nil, err
+		}
+		key, err := parent.Child(path[len(path)-1])
+		if err == ErrInvalidKey {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		priv := key.PrivateKey()
+		accounts = append(accounts, Account{
+			Path:       path,
+			Address:    signer.Address(signer.PubKey(priv)),
+			PrivateKey: priv,
+		})
+	}
+	return accounts, nil
+}
+
+func hmacSHA512(key, data []byte) ([]byte, []byte) {
+	mac := hmac.New(sha512.New, key)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+	return sum[:32], sum[32:]
+}
+
+func isValidSecp256k1Key(key []byte) bool {
+	n := new(big.Int).SetBytes(key)
+	return n.Sign() > 0 && n.Cmp(secp256k1N) < 0
+}
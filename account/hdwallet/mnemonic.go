@@ -0,0 +1,131 @@
+// Package hdwallet derives deterministic keys from a BIP-39 mnemonic, the
+// secp256k1 keys through BIP-32 and the ed25519 keys through SLIP-10.
+package hdwallet
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+)
+
+// ErrInvalidMnemonic is returned for a mnemonic of unknown words, of a
+// wrong number of words or with a wrong checksum.
+var ErrInvalidMnemonic = errors.New("invalid mnemonic")
+
+var wordIndex = func() map[string]int {
+	m := make(map[string]int, len(englishWordList))
+	for i, word := range englishWordList {
+		m[word] = i
+	}
+	return m
+}()
+
+// NewEntropy reads bits of random entropy for a mnemonic. bits must be a
+// multiple of 32 in [128, 256].
+func NewEntropy(bits int) ([]byte, error) {
+	if err := checkEntropyBits(bits); err != nil {
+		return nil, err
+	}
+	entropy := make([]byte, bits/8)
+	if _, err := io.ReadFull(rand.Reader, entropy); err != nil {
+		return nil, fmt.Errorf("read random entropy error: %v", err)
+	}
+	return entropy, nil
+}
+
+// NewMnemonic generates a mnemonic of bits random entropy, 12 words for
+// 128 bits and 24 words for 256 bits.
+func NewMnemonic(bits int) (string, error) {
+	entropy, err := NewEntropy(bits)
+	if err != nil {
+		return "", err
+	}
+	return EntropyToMnemonic(entropy)
+}
+
+// EntropyToMnemonic encodes entropy into words, with the checksum of the
+// first len(entropy)/4 bits of its sha256 appended.
+func EntropyToMnemonic(entropy []byte) (string, error) {
+	bits := len(entropy) * 8
+	if err := checkEntropyBits(bits); err != nil {
+		return "", err
+	}
+	checksumBits := uint(bits / 32)
+	checksum := sha256.Sum256(entropy)
+
+	// entropy || checksum as a big number, 11 bits per word.
+	n := new(big.Int).SetBytes(entropy)
+	n.Lsh(n, checksumBits)
+	n.Or(n, big.NewInt(int64(checksum[0]>>(8-checksumBits))))
+
+	words := make([]string, (bits+int(checksumBits))/11)
+	mask := big.NewInt(2047)
+	for i := len(words) - 1; i >= 0; i-- {
+		words[i] = englishWordList[new(big.Int).And(n, mask).Int64()]
+		n.Rsh(n, 11)
+	}
+	return strings.Join(words, " "), nil
+}
+
+// MnemonicToEntropy decodes mnemonic back into its entropy, checking the
+// words and the checksum.
+func MnemonicToEntropy(mnemonic string) ([]byte, error) {
+	words := strings.Fields(mnemonic)
+	if len(words)%3 != 0 || len(words) < 12 || len(words) > 24 {
+		return nil, ErrInvalidMnemonic
+	}
+	n := new(big.Int)
+	for _, word := range words {
+		i, ok := wordIndex[word]
+		if !ok {
+			return nil, ErrInvalidMnemonic
+		}
+		n.Lsh(n, 11)
+		n.Or(n, big.NewInt(int64(i)))
+	}
+	checksumBits := uint(len(words) * 11 / 33)
+	checksum := new(big.Int).And(n, big.NewInt(1<<checksumBits-1)).Int64()
+	n.Rsh(n, checksumBits)
+
+	entropy := make([]byte, len(words)*11*32/33/8)
+	n.FillBytes(entropy)
+	expected := sha256.Sum256(entropy)
+	if int64(expected[0]>>(8-checksumBits)) != checksum {
+		return nil, ErrInvalidMnemonic
+	}
+	return entropy, nil
+}
+
+// IsMnemonicValid tells whether mnemonic is of known words with a right
+// checksum.
+func IsMnemonicValid(mnemonic string) bool {
+	_, err := MnemonicToEntropy(mnemonic)
+	return err == nil
+}
+
+// NewSeed checks mnemonic and stretches it with passphrase into the 64
+// bytes seed of the master keys. An empty passphrase is allowed, and a
+// different passphrase leads to a different wallet.
+func NewSeed(mnemonic, passphrase string) ([]byte, error) {
+	if !IsMnemonicValid(mnemonic) {
+		return nil, ErrInvalidMnemonic
+	}
+	mnemonic = norm.NFKD.String(strings.Join(strings.Fields(mnemonic), " "))
+	salt := norm.NFKD.String("mnemonic" + passphrase)
+	return pbkdf2.Key([]byte(mnemonic), []byte(salt), 2048, 64, sha512.New), nil
+}
+
+func checkEntropyBits(bits int) error {
+	if bits%32 != 0 || bits < 128 || bits > 256 {
+		return fmt.Errorf("entropy bits must be a multiple of 32 in [128, 256], got %d", bits)
+	}
+	return nil
+}
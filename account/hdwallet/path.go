@@ -0,0 +1,95 @@
+package hdwallet
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/annchain/OG/common/crypto"
+)
+
+// HardenedOffset is added to the index of a hardened child.
+const HardenedOffset uint32 = 0x80000000
+
+// DerivationPath is the indexes of the children from the master key down
+// to a key, written as m/44'/60'/0'/0/0 where ' marks a hardened index.
+type DerivationPath []uint32
+
+// The secp256k1 addresses of OG are the ethereum ones, so the ethereum
+// path of BIP-44 is followed and the wallets of the secp256k1 keys are
+// compatible with the ethereum ones. SLIP-10 derives hardened ed25519 keys
+// only, so every index of the ed25519 path is hardened.
+var (
+	DefaultSecp256k1BasePath = DerivationPath{HardenedOffset + 44, HardenedOffset + 60, HardenedOffset + 0, 0}
+	DefaultEd25519BasePath   = DerivationPath{HardenedOffset + 44, HardenedOffset + 60, HardenedOffset + 0, HardenedOffset + 0}
+)
+
+// DefaultBasePath is the base path of the accounts of cryptoType, the
+// index of an account is appended to it by Child.
+func DefaultBasePath(cryptoType crypto.CryptoType) DerivationPath {
+	if cryptoType == crypto.CryptoTypeEd25519 {
+		return DefaultEd25519BasePath
+	}
+	return DefaultSecp256k1BasePath
+}
+
+// ParseDerivationPath parses a path like m/44'/60'/0'/0/0. Both ' and h
+// mark a hardened index.
+func ParseDerivationPath(path string) (DerivationPath, error) {
+	parts := strings.Split(strings.TrimSpace(path), "/")
+	if len(parts) == 0 || parts[0] != "m" {
+		return nil, fmt.Errorf("derivation path must start with m: %q", path)
+	}
+	var result DerivationPath
+	for _, part := range parts[1:] {
+		hardened := strings.HasSuffix(part, "'") || strings.HasSuffix(part, "h")
+		if hardened {
+			part = part[:len(part)-1]
+		}
+		index, err := strconv.ParseUint(part, 10, 32)
+		if err != nil || index >= uint64(HardenedOffset) {
+			return nil, fmt.Errorf("invalid index %q in derivation path %q", part, path)
+		}
+		if hardened {
+			index += uint64(HardenedOffset)
+		}
+		result = append(result, uint32(index))
+	}
+	return result, nil
+}
+
+// Child appends index to a copy of p. The index is hardened if every index
+// of p is, so the child of an ed25519 path is still valid.
+func (p DerivationPath) Child(index uint32) (DerivationPath, error) {
+	if index >= HardenedOffset {
+		return nil, fmt.Errorf("index %d is out of range", index)
+	}
+	child := make(DerivationPath, len(p), len(p)+1)
+	copy(child, p)
+	if len(p) > 0 && p.allHardened() {
+		index += HardenedOffset
+	}
+	return append(child, index), nil
+}
+
+func (p DerivationPath) allHardened() bool {
+	for _, index := range p {
+		if index < HardenedOffset {
+			return false
+		}
+	}
+	return true
+}
+
+func (p DerivationPath) String() string {
+	var b strings.Builder
+	b.WriteString("m")
+	for _, index := range p {
+		if index >= HardenedOffset {
+			fmt.Fprintf(&b, "/%d'", index-HardenedOffset)
+		} else {
+			fmt.Fprintf(&b, "/%d", index)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,236 @@
+package keystore
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/annchain/OG/common/crypto"
+	"github.com/annchain/OG/types"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// StandardScryptN and StandardScryptP take about a second and 256MB
+	// of memory to derive a key on a modern machine.
+	StandardScryptN = 1 << 18
+	StandardScryptP = 1
+
+	// LightScryptN and LightScryptP take about 100ms and 4MB of memory,
+	// for the machines where the standard parameters are too slow.
+	LightScryptN = 1 << 12
+	LightScryptP = 6
+
+	scryptR     = 8
+	scryptDKLen = 32
+
+	keyVersion = 3
+)
+
+// ErrDecrypt is returned when the passphrase of a key file is wrong.
+var ErrDecrypt = errors.New("could not decrypt key with given passphrase")
+
+// Key is a decrypted private key along with the address it signs for.
+type Key struct {
+	Id         uuid.UUID
+	Address    types.Address
+	PrivateKey crypto.PrivateKey
+}
+
+// NewKey wraps priv into a Key with a random id.
+func NewKey(priv crypto.PrivateKey) (*Key, error) {
+	signer := crypto.NewSigner(priv.Type)
+	if signer == nil {
+		return nil, fmt.Errorf("unknown crypto type %d", priv.Type)
+	}
+	return &Key{
+		Id:         uuid.New(),
+		Address:    signer.Address(signer.PubKey(priv)),
+		PrivateKey: priv,
+	}, nil
+}
+
+// encryptedKeyJSON is the format of a key file. It follows the version 3
+// key files of ethereum, with the crypto type of the key added since the
+// address of an ed25519 key can't be derived the ethereum way.
+type encryptedKeyJSON struct {
+	Address    string     `json:"address"`
+	CryptoType string     `json:"crypto_type"`
+	Crypto     cryptoJSON `json:"crypto"`
+	Id         string     `json:"id"`
+	Version    int        `json:"version"`
+}
+
+type cryptoJSON struct {
+	Cipher       string           `json:"cipher"`
+	CipherText   string           `json:"ciphertext"`
+	CipherParams cipherparamsJSON `json:"cipherparams"`
+	KDF          string           `json:"kdf"`
+	KDFParams    scryptParamsJSON `json:"kdfparams"`
+	MAC          string           `json:"mac"`
+}
+
+type cipherparamsJSON struct {
+	IV string `json:"iv"`
+}
+
+type scryptParamsJSON struct {
+	N     int    `json:"n"`
+	R     int    `json:"r"`
+	P     int    `json:"p"`
+	DKLen int    `json:"dklen"`
+	Salt  string `json:"salt"`
+}
+
+// EncryptKey encrypts key with passphrase into the json of a key file.
+// The encryption key is derived by scrypt with parameters scryptN and
+// scryptP, and the private key is encrypted by aes-128-ctr.
+func EncryptKey(key *Key, passphrase string, scryptN, scryptP int) ([]byte, error) {
+	salt := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, fmt.Errorf("read random salt error: %v", err)
+	}
+	derivedKey, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, scryptDKLen)
+	if err != nil {
+		return nil, err
+	}
+	iv := make([]byte, aes.BlockSize)
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return nil, fmt.Errorf("read random iv error: %v", err)
+	}
+	cipherText, err := aesCTRXOR(derivedKey[:16], key.PrivateKey.Bytes, iv)
+	if err != nil {
+		return nil, err
+	}
+	mac := crypto.Keccak256(derivedKey[16:32], cipherText)
+
+	return json.Marshal(encryptedKeyJSON{
+		Address:    hex.EncodeToString(key.Address.ToBytes()),
+		CryptoType: key.PrivateKey.Type.String(),
+		Crypto: cryptoJSON{
+			Cipher:       "aes-128-ctr",
+			CipherText:   hex.EncodeToString(cipherText),
+			CipherParams: cipherparamsJSON{IV: hex.EncodeToString(iv)},
+			KDF:          "scrypt",
+			KDFParams: scryptParamsJSON{
+				N:     scryptN,
+				R:     scryptR,
+				P:     scryptP,
+				DKLen: scryptDKLen,
+				Salt:  hex.EncodeToString(salt),
+			},
+			MAC: hex.EncodeToString(mac),
+		},
+		Id:      key.Id.String(),
+		Version: keyVersion,
+	})
+}
+
+// DecryptKey decrypts the json of a key file with passphrase. ErrDecrypt is
+// returned if the passphrase is wrong.
+func DecryptKey(keyJSON []byte, passphrase string) (*Key, error) {
+	var k encryptedKeyJSON
+	if err := json.Unmarshal(keyJSON, &k); err != nil {
+		return nil, fmt.Errorf("key file format error: %v", err)
+	}
+	if k.Version != keyVersion {
+		return nil, fmt.Errorf("unsupported key file version %d", k.Version)
+	}
+	if k.Crypto.Cipher != "aes-128-ctr" {
+		return nil, fmt.Errorf("unsupported cipher %s", k.Crypto.Cipher)
+	}
+	if k.Crypto.KDF != "scrypt" {
+		return nil, fmt.Errorf("unsupported kdf %s", k.Crypto.KDF)
+	}
+	cryptoType, err := parseCryptoType(k.CryptoType)
+	if err != nil {
+		return nil, err
+	}
+	id, err := uuid.Parse(k.Id)
+	if err != nil {
+		return nil, fmt.Errorf("key id format error: %v", err)
+	}
+	mac, err := hex.DecodeString(k.Crypto.MAC)
+	if err != nil {
+		return nil, fmt.Errorf("mac format error: %v", err)
+	}
+	iv, err := hex.DecodeString(k.Crypto.CipherParams.IV)
+	if err != nil || len(iv) != aes.BlockSize {
+		return nil, fmt.Errorf("iv format error")
+	}
+	cipherText, err := hex.DecodeString(k.Crypto.CipherText)
+	if err != nil {
+		return nil, fmt.Errorf("ciphertext format error: %v", err)
+	}
+	salt, err := hex.DecodeString(k.Crypto.KDFParams.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("salt format error: %v", err)
+	}
+	params := k.Crypto.KDFParams
+	if params.DKLen != scryptDKLen {
+		return nil, fmt.Errorf("unsupported derived key length %d", params.DKLen)
+	}
+	derivedKey, err := scrypt.Key([]byte(passphrase), salt, params.N, params.R, params.P, params.DKLen)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(crypto.Keccak256(derivedKey[16:32], cipherText), mac) {
+		return nil, ErrDecrypt
+	}
+	plainText, err := aesCTRXOR(derivedKey[:16], cipherText, iv)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := NewKey(crypto.PrivateKeyFromBytes(cryptoType, plainText))
+	if err != nil {
+		return nil, err
+	}
+	key.Id = id
+	if hex.EncodeToString(key.Address.ToBytes()) != k.Address {
+		return nil, fmt.Errorf("key file of %s holds the key of %s", k.Address, key.Address.Hex())
+	}
+	return key, nil
+}
+
+// keyFileAddress reads the address of a key file without decrypting it.
+func keyFileAddress(keyJSON []byte) (types.Address, error) {
+	var k struct {
+		Address string `json:"address"`
+	}
+	if err := json.Unmarshal(keyJSON, &k); err != nil {
+		return types.Address{}, err
+	}
+	b, err := hex.DecodeString(k.Address)
+	if err != nil || len(b) != types.AddressLength {
+		return types.Address{}, fmt.Errorf("address format error")
+	}
+	return types.BytesToAddress(b), nil
+}
+
+func parseCryptoType(s string) (crypto.CryptoType, error) {
+	switch s {
+	case crypto.CryptoTypeEd25519.String():
+		return crypto.CryptoTypeEd25519, nil
+	case crypto.CryptoTypeSecp256k1.String():
+		return crypto.CryptoTypeSecp256k1, nil
+	}
+	return 0, fmt.Errorf("unknown crypto type %q", s)
+}
+
+func aesCTRXOR(key, inText, iv []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	outText := make([]byte, len(inText))
+	cipher.NewCTR(block, iv).XORKeyStream(outText, inText)
+	return outText, nil
+}
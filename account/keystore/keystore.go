@@ -0,0 +1,317 @@
+// Package keystore keeps private keys in files encrypted with passphrases,
+// and holds the keys unlocked for the node to sign with.
+package keystore
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/annchain/OG/account/hdwallet"
+	"github.com/annchain/OG/common/crypto"
+	"github.com/annchain/OG/types"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrNoMatch = errors.New("no key for given address")
+	ErrLocked  = errors.New("account is locked")
+	ErrExists  = errors.New("account already exists")
+)
+
+// KeyStore manages the key files in a directory. Each file holds one key,
+// named after the time it is written and its address.
+type KeyStore struct {
+	dir     string
+	scryptN int
+	scryptP int
+
+	// storeMu keeps two keys of the same address from being stored.
+	storeMu sync.Mutex
+
+	mu       sync.RWMutex
+	unlocked map[types.Address]*unlocked
+}
+
+type unlocked struct {
+	key *Key
+	// abort stops the timer locking the key again.
+	abort chan struct{}
+}
+
+// NewKeyStore creates a KeyStore on dir, which is created on the first
+// key written. Keys are encrypted with the scrypt parameters scryptN and
+// scryptP, see StandardScryptN and LightScryptN.
+func NewKeyStore(dir string, scryptN, scryptP int) *KeyStore {
+	return &KeyStore{
+		dir:      dir,
+		scryptN:  scryptN,
+		scryptP:  scryptP,
+		unlocked: make(map[types.Address]*unlocked),
+	}
+}
+
+// Dir returns the directory of the key files.
+func (ks *KeyStore) Dir() string {
+	return ks.dir
+}
+
+// NewAccount generates a key of cryptoType and stores it encrypted with
+// passphrase.
+func (ks *KeyStore) NewAccount(cryptoType crypto.CryptoType, passphrase string) (types.Address, error) {
+	signer := crypto.NewSigner(cryptoType)
+	if signer == nil {
+		return types.Address{}, fmt.Errorf("unknown crypto type %d", cryptoType)
+	}
+	_, priv, err := signer.RandomKeyPair()
+	if err != nil {
+		return types.Address{}, fmt.Errorf("generate key error: %v", err)
+	}
+	return ks.Import(priv, passphrase)
+}
+
+// Import stores priv encrypted with passphrase.
+func (ks *KeyStore) Import(priv crypto.PrivateKey, passphrase string) (types.Address, error) {
+	key, err := NewKey(priv)
+	if err != nil {
+		return types.Address{}, err
+	}
+	return key.Address, ks.storeKey(key, passphrase)
+}
+
+// ImportMnemonic stores the key of cryptoType derived at path from
+// mnemonic and its BIP-39 passphrase, encrypted with passphrase.
+func (ks *KeyStore) ImportMnemonic(mnemonic, mnemonicPassphrase string, cryptoType crypto.CryptoType,
+	path hdwallet.DerivationPath, passphrase string) (types.Address, error) {
+	seed, err := hdwallet.NewSeed(mnemonic, mnemonicPassphrase)
+	if err != nil {
+		return types.Address{}, err
+	}
+	priv, err := hdwallet.DeriveKey(seed, cryptoType, path)
+	if err != nil {
+		return types.Address{}, fmt.Errorf("derive key at %s error: %v", path, err)
+	}
+	return ks.Import(priv, passphrase)
+}
+
+// ImportKeyJSON stores the key in a key file exported by Export, which is
+// decrypted with passphrase and encrypted again with newPassphrase.
+func (ks *KeyStore) ImportKeyJSON(keyJSON []byte, passphrase, newPassphrase string) (types.Address, error) {
+	key, err := DecryptKey(keyJSON, passphrase)
+	if err != nil {
+		return types.Address{}, err
+	}
+	return key.Address, ks.storeKey(key, newPassphrase)
+}
+
+// Export returns the key file of addr encrypted with newPassphrase instead
+// of passphrase.
+func (ks *KeyStore) Export(addr types.Address, passphrase, newPassphrase string) ([]byte, error) {
+	key, err := ks.getDecryptedKey(addr, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return EncryptKey(key, newPassphrase, ks.scryptN, ks.scryptP)
+}
+
+// Accounts lists the addresses of the key files, sorted by file name,
+// which is by the time they are written.
+func (ks *KeyStore) Accounts() ([]types.Address, error) {
+	files, err := ks.keyFiles()
+	if err != nil {
+		return nil, err
+	}
+	addrs := make([]types.Address, 0, len(files))
+	for _, file := range files {
+		addrs = append(addrs, file.addr)
+	}
+	return addrs, nil
+}
+
+// HasAddress tells whether there is a key file of addr.
+func (ks *KeyStore) HasAddress(addr types.Address) bool {
+	_, err := ks.find(addr)
+	return err == nil
+}
+
+// Unlock decrypts the key of addr with passphrase and holds it until
+// timeout passes or Lock is called. A timeout of 0 holds it until Lock.
+// Unlocking an unlocked key again resets its timeout.
+func (ks *KeyStore) Unlock(addr types.Address, passphrase string, timeout time.Duration) error {
+	key, err := ks.getDecryptedKey(addr, passphrase)
+	if err != nil {
+		return err
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if u, ok := ks.unlocked[addr]; ok {
+		close(u.abort)
+	}
+	u := &unlocked{key: key, abort: make(chan struct{})}
+	ks.unlocked[addr] = u
+	if timeout > 0 {
+		go ks.expire(addr, u, timeout)
+	}
+	logrus.WithField("addr", addr.Hex()).WithField("timeout", timeout).Info("account unlocked")
+	return nil
+}
+
+// Lock drops the unlocked key of addr.
+func (ks *KeyStore) Lock(addr types.Address) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	u, ok := ks.unlocked[addr]
+	if !ok {
+		if !ks.HasAddress(addr) {
+			return ErrNoMatch
+		}
+		return nil
+	}
+	close(u.abort)
+	delete(ks.unlocked, addr)
+	logrus.WithField("addr", addr.Hex()).Info("account locked")
+	return nil
+}
+
+// LockAll drops all the unlocked keys, used when the node stops.
+func (ks *KeyStore) LockAll() {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	for addr, u := range ks.unlocked {
+		close(u.abort)
+		delete(ks.unlocked, addr)
+	}
+}
+
+// Key returns the unlocked private key of addr, or ErrLocked if it is not
+// unlocked.
+func (ks *KeyStore) Key(addr types.Address) (crypto.PrivateKey, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	u, ok := ks.unlocked[addr]
+	if !ok {
+		return crypto.PrivateKey{}, ErrLocked
+	}
+	return u.key.PrivateKey, nil
+}
+
+func (ks *KeyStore) expire(addr types.Address, u *unlocked, timeout time.Duration) {
+	t := time.NewTimer(timeout)
+	defer t.Stop()
+	select {
+	case <-u.abort:
+	case <-t.C:
+		ks.mu.Lock()
+		// the key may be unlocked again after the timer fired.
+		if ks.unlocked[addr] == u {
+			delete(ks.unlocked, addr)
+			logrus.WithField("addr", addr.Hex()).Info("account locked on timeout")
+		}
+		ks.mu.Unlock()
+	}
+}
+
+func (ks *KeyStore) getDecryptedKey(addr types.Address, passphrase string) (*Key, error) {
+	path, err := ks.find(addr)
+	if err != nil {
+		return nil, err
+	}
+	keyJSON, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return DecryptKey(keyJSON, passphrase)
+}
+
+func (ks *KeyStore) storeKey(key *Key, passphrase string) error {
+	ks.storeMu.Lock()
+	defer ks.storeMu.Unlock()
+	if ks.HasAddress(key.Address) {
+		return ErrExists
+	}
+	keyJSON, err := EncryptKey(key, passphrase, ks.scryptN, ks.scryptP)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(ks.dir, 0700); err != nil {
+		return err
+	}
+	// write to a temp file first so that a broken key file is never left.
+	name := keyFileName(key.Address)
+	tmp, err := ioutil.TempFile(ks.dir, "."+name+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(keyJSON); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(ks.dir, name))
+}
+
+type keyFile struct {
+	path string
+	addr types.Address
+}
+
+// keyFiles reads the addresses of the key files in dir, skipping the
+// hidden files and the ones not of the key file format.
+func (ks *KeyStore) keyFiles() ([]keyFile, error) {
+	infos, err := ioutil.ReadDir(ks.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var files []keyFile
+	for _, info := range infos {
+		if info.IsDir() || strings.HasPrefix(info.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(ks.dir, info.Name())
+		keyJSON, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		addr, err := keyFileAddress(keyJSON)
+		if err != nil {
+			logrus.WithError(err).WithField("file", path).Debug("skip file in keystore")
+			continue
+		}
+		files = append(files, keyFile{path: path, addr: addr})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].path < files[j].path })
+	return files, nil
+}
+
+func (ks *KeyStore) find(addr types.Address) (string, error) {
+	files, err := ks.keyFiles()
+	if err != nil {
+		return "", err
+	}
+	for _, file := range files {
+		if file.addr == addr {
+			return file.path, nil
+		}
+	}
+	return "", ErrNoMatch
+}
+
+// keyFileName is UTC--<created time>--<hex address>.
+func keyFileName(addr types.Address) string {
+	ts := time.Now().UTC().Format("2006-01-02T15-04-05.000000000Z")
+	return fmt.Sprintf("UTC--%s--%x", ts, addr.ToBytes())
+}
@@ -0,0 +1,165 @@
+package keystore
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/annchain/OG/account/hdwallet"
+	"github.com/annchain/OG/common/crypto"
+)
+
+func newTestKeyStore(t *testing.T) (*KeyStore, func()) {
+	dir, err := ioutil.TempDir("", "og-keystore-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewKeyStore(dir, LightScryptN, LightScryptP), func() { os.RemoveAll(dir) }
+}
+
+func TestKeyStore(t *testing.T) {
+	ks, clean := newTestKeyStore(t)
+	defer clean()
+
+	for _, cryptoType := range []crypto.CryptoType{crypto.CryptoTypeEd25519, crypto.CryptoTypeSecp256k1} {
+		addr, err := ks.NewAccount(cryptoType, "foo")
+		if err != nil {
+			t.Fatalf("new %s account error: %v", cryptoType, err)
+		}
+		if !ks.HasAddress(addr) {
+			t.Fatalf("%s account not found", cryptoType)
+		}
+		if _, err := ks.Key(addr); err != ErrLocked {
+			t.Fatalf("expected locked key, got %v", err)
+		}
+		if err := ks.Unlock(addr, "bar", 0); err != ErrDecrypt {
+			t.Fatalf("expected decrypt error, got %v", err)
+		}
+		if err := ks.Unlock(addr, "foo", 0); err != nil {
+			t.Fatalf("unlock error: %v", err)
+		}
+		priv, err := ks.Key(addr)
+		if err != nil {
+			t.Fatalf("unlocked key error: %v", err)
+		}
+		signer := crypto.NewSigner(cryptoType)
+		if priv.Type != cryptoType || signer.Address(signer.PubKey(priv)) != addr {
+			t.Fatalf("unlocked key mismatches address %s", addr.Hex())
+		}
+		if err := ks.Lock(addr); err != nil {
+			t.Fatalf("lock error: %v", err)
+		}
+		if _, err := ks.Key(addr); err != ErrLocked {
+			t.Fatalf("expected locked key after lock, got %v", err)
+		}
+	}
+
+	addrs, err := ks.Accounts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(addrs) != 2 {
+		t.Fatalf("expected 2 accounts, got %d", len(addrs))
+	}
+}
+
+func TestKeyStoreImportExport(t *testing.T) {
+	ks, clean := newTestKeyStore(t)
+	defer clean()
+
+	_, priv, err := crypto.NewSigner(crypto.CryptoTypeSecp256k1).RandomKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := ks.Import(priv, "foo")
+	if err != nil {
+		t.Fatalf("import error: %v", err)
+	}
+	if _, err := ks.Import(priv, "foo"); err != ErrExists {
+		t.Fatalf("expected exists error, got %v", err)
+	}
+	if _, err := ks.Export(addr, "bar", "baz"); err != ErrDecrypt {
+		t.Fatalf("expected decrypt error, got %v", err)
+	}
+	keyJSON, err := ks.Export(addr, "foo", "baz")
+	if err != nil {
+		t.Fatalf("export error: %v", err)
+	}
+
+	other, clean2 := newTestKeyStore(t)
+	defer clean2()
+	if _, err := other.ImportKeyJSON(keyJSON, "foo", "qux"); err != ErrDecrypt {
+		t.Fatalf("expected decrypt error, got %v", err)
+	}
+	imported, err := other.ImportKeyJSON(keyJSON, "baz", "qux")
+	if err != nil {
+		t.Fatalf("import key json error: %v", err)
+	}
+	if imported != addr {
+		t.Fatalf("imported %s, expected %s", imported.Hex(), addr.Hex())
+	}
+	if err := other.Unlock(addr, "qux", 0); err != nil {
+		t.Fatalf("unlock imported key error: %v", err)
+	}
+	key, _ := other.Key(addr)
+	if key.Type != priv.Type || string(key.Bytes) != string(priv.Bytes) {
+		t.Fatal("imported key mismatches")
+	}
+}
+
+func TestKeyStoreImportMnemonic(t *testing.T) {
+	ks, clean := newTestKeyStore(t)
+	defer clean()
+
+	mnemonic, err := hdwallet.NewMnemonic(128)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seed, _ := hdwallet.NewSeed(mnemonic, "")
+	for _, cryptoType := range []crypto.CryptoType{crypto.CryptoTypeEd25519, crypto.CryptoTypeSecp256k1} {
+		accounts, err := hdwallet.DeriveAccounts(seed, cryptoType, hdwallet.DefaultBasePath(cryptoType), 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		addr, err := ks.ImportMnemonic(mnemonic, "", cryptoType, accounts[1].Path, "foo")
+		if err != nil {
+			t.Fatalf("import %s mnemonic error: %v", cryptoType, err)
+		}
+		if addr != accounts[1].Address {
+			t.Fatalf("imported %s, expected %s", addr.Hex(), accounts[1].Address.Hex())
+		}
+	}
+	if _, err := ks.ImportMnemonic("abandon", "", crypto.CryptoTypeEd25519, hdwallet.DefaultEd25519BasePath, "foo"); err != hdwallet.ErrInvalidMnemonic {
+		t.Fatalf("expected invalid mnemonic, got %v", err)
+	}
+}
+
+func TestKeyStoreUnlockTimeout(t *testing.T) {
+	ks, clean := newTestKeyStore(t)
+	defer clean()
+
+	addr, err := ks.NewAccount(crypto.CryptoTypeEd25519, "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.Unlock(addr, "foo", 50*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	// unlocking again with no timeout cancels the earlier one.
+	if err := ks.Unlock(addr, "foo", 0); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if _, err := ks.Key(addr); err != nil {
+		t.Fatalf("expected unlocked key, got %v", err)
+	}
+
+	if err := ks.Unlock(addr, "foo", 50*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if _, err := ks.Key(addr); err != ErrLocked {
+		t.Fatalf("expected key locked on timeout, got %v", err)
+	}
+}
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/annchain/OG/common/hexutil"
+	"github.com/annchain/OG/core"
+	"github.com/annchain/OG/og"
+	"github.com/annchain/OG/ogdb"
+	"github.com/annchain/OG/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// dbCmd groups the commands inspecting the db of a stopped node offline.
+// The db can't be opened twice, so the node must be stopped first.
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Inspect and check the db of a stopped node",
+	Long:  `Inspect and check the db configured in [db] of a stopped node. The node must be stopped since the db can't be opened twice.`,
+}
+
+var dbInfoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Print the genesis and the latest sequencer",
+	Run: func(cmd *cobra.Command, args []string) {
+		db, accessor := openAccessor()
+		defer db.Close()
+
+		printSequencer("genesis", accessor.ReadGenesis())
+		printSequencer("latest", accessor.ReadLatestSequencer())
+	},
+}
+
+var dbTxCmd = &cobra.Command{
+	Use:   "tx <hash>",
+	Short: "Dump a tx or sequencer by hash",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		hash, err := types.HexStringToHash(args[0])
+		panicIfError(err, "invalid hash")
+
+		db, accessor := openAccessor()
+		defer db.Close()
+
+		tx := accessor.ReadTransaction(hash)
+		if tx == nil {
+			panicIfError(fmt.Errorf("%s not found", hash.Hex()), "failed to read tx")
+		}
+		data, err := json.MarshalIndent(tx, "", "  ")
+		panicIfError(err, "failed to marshal tx")
+		fmt.Println(string(data))
+	},
+}
+
+var dbSeqsCmd = &cobra.Command{
+	Use:   "seqs",
+	Short: "List the sequencers in a height range",
+	Run: func(cmd *cobra.Command, args []string) {
+		db, accessor := openAccessor()
+		defer db.Close()
+
+		from, to := heightRange(cmd, accessor)
+		for height := from; height <= to; height++ {
+			seq, err := accessor.ReadSequencerByHeight(height)
+			if err != nil {
+				fmt.Printf("%d\tmissing\n", height)
+			} else {
+				txs := 0
+				if hashes, err := accessor.ReadIndexedTxHashs(height); err == nil {
+					txs = len(*hashes)
+				}
+				fmt.Printf("%d\t%s\ttxs %d\troot %s\n", height, seq.GetTxHash().Hex(), txs, seq.StateRoot.Hex())
+			}
+			if height == to {
+				break
+			}
+		}
+	},
+}
+
+var dbCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check every height has its sequencer and the txs it confirms",
+	Long:  `Check every height in the range has its sequencer, the hashes of the txs it confirms and the txs themselves, the state root and the confirm time. The range is from genesis to the latest sequencer by default.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		db, accessor := openAccessor()
+		defer db.Close()
+
+		from, to := heightRange(cmd, accessor)
+		issues := accessor.CheckLedger(from, to)
+		for _, issue := range issues {
+			fmt.Println(issue)
+		}
+		fmt.Printf("checked heights %d to %d, %d issues found\n", from, to, len(issues))
+	},
+}
+
+var dbOrphansCmd = &cobra.Command{
+	Use:   "orphans",
+	Short: "Report the keys not reachable from the ledger",
+	Long:  `Scan all the keys in db and report the ones not reachable from the ledger, such as the data of the heights above the latest sequencer left by an interrupted rollback, and the keys of unknown kinds.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		db, accessor := openAccessor()
+		defer db.Close()
+
+		report, err := accessor.ScanKeys()
+		panicIfError(err, "failed to scan keys")
+
+		var kinds []string
+		for kind := range report.Counts {
+			kinds = append(kinds, kind)
+		}
+		sort.Strings(kinds)
+		for _, kind := range kinds {
+			fmt.Printf("%s\t%d keys\n", kind, report.Counts[kind])
+		}
+		for _, orphan := range report.Orphans {
+			fmt.Printf("%s\t%s\n", hexutil.Encode(orphan.Key), orphan.Reason)
+		}
+		fmt.Printf("%d orphaned keys found\n", len(report.Orphans))
+	},
+}
+
+// openAccessor opens the db configured in [db] for the db commands.
+func openAccessor() (ogdb.Database, *core.Accessor) {
+	readConfig()
+	initLogger()
+
+	switch viper.GetString("db.name") {
+	case "leveldb", "boltdb":
+	default:
+		panicIfError(fmt.Errorf("db %q is not persisted", viper.GetString("db.name")), "nothing to inspect")
+	}
+	db, err := og.CreateDB()
+	panicIfError(err, "failed to open db")
+	return db, core.NewAccessor(db)
+}
+
+// heightRange reads the height range from the flags, which is from genesis
+// to the latest sequencer by default.
+func heightRange(cmd *cobra.Command, accessor *core.Accessor) (uint64, uint64) {
+	from, _ := cmd.Flags().GetUint64("from")
+	to, _ := cmd.Flags().GetUint64("to")
+	if !cmd.Flags().Changed("to") {
+		latest := accessor.ReadLatestSequencer()
+		if latest == nil {
+			panicIfError(fmt.Errorf("latest sequencer not found"), "please set the height range")
+		}
+		to = latest.Height
+	}
+	if from > to {
+		panicIfError(fmt.Errorf("from %d is above to %d", from, to), "invalid arguments")
+	}
+	return from, to
+}
+
+func printSequencer(name string, seq *types.Sequencer) {
+	if seq == nil {
+		fmt.Printf("%s: not found\n", name)
+		return
+	}
+	fmt.Printf("%s: height %d hash %s\n  %s\n", name, seq.Height, seq.GetTxHash().Hex(), seq.Dump())
+}
+
+func init() {
+	rootCmd.AddCommand(dbCmd)
+	dbCmd.AddCommand(dbInfoCmd, dbTxCmd, dbSeqsCmd, dbCheckCmd, dbOrphansCmd)
+
+	for _, cmd := range []*cobra.Command{dbSeqsCmd, dbCheckCmd} {
+		cmd.Flags().Uint64("from", 0, "The lowest sequencer height")
+		cmd.Flags().Uint64("to", 0, "The highest sequencer height, the latest one by default")
+	}
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/annchain/OG/common/crypto"
+	"github.com/annchain/OG/consensus/dpos"
+	"github.com/annchain/OG/core"
+	"github.com/annchain/OG/core/state"
+	"github.com/annchain/OG/og"
+	"github.com/annchain/OG/types"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// exportCmd writes the ledger of a stopped node into a file, which can be
+// imported by another node with importCmd.
+var exportCmd = &cobra.Command{
+	Use:   "export <file>",
+	Short: "Export the ledger into a file",
+	Long:  `Export genesis and every sequencer with the txs it confirms into a file, up to the latest sequencer or the height given. The node must be stopped before exporting.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		readConfig()
+		initLogger()
+
+		db, err := og.CreateDB()
+		panicIfError(err, "failed to open db")
+		defer db.Close()
+		olddb, err := og.GetOldDb()
+		panicIfError(err, "failed to open old db")
+		defer olddb.Close()
+
+		statedbConfig := state.StateDBConfig{
+			PurgeTimer:     time.Duration(viper.GetInt("statedb.purge_timer_s")),
+			BeatExpireTime: time.Second * time.Duration(viper.GetInt("statedb.beat_expire_time_s")),
+		}
+		dag, err := core.NewDag(core.DefaultDagConfig(), statedbConfig, db, olddb)
+		panicIfError(err, "failed to create dag")
+		defer dag.Stop()
+
+		if !dag.LoadLastState() {
+			panicIfError(fmt.Errorf("genesis is not found"), "nothing to export")
+		}
+		to := dag.LatestSequencer().Height
+		if cmd.Flags().Changed("to") {
+			to = viper.GetUint64("export.to")
+		}
+
+		file, err := os.Create(args[0])
+		panicIfError(err, "failed to create file")
+		defer file.Close()
+		err = core.ExportChain(dag, file, to)
+		panicIfError(err, "failed to export")
+		log.WithField("to", to).WithField("file", args[0]).Info("ledger exported")
+	},
+}
+
+// importCmd replays the ledger exported by exportCmd on a stopped node.
+var importCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import the ledger from an exported file",
+	Long:  `Import the ledger from a file written by export. Every sequencer is verified and executed as if it were received from the network, and the heights already in the ledger are skipped, so an interrupted import can be run again. The genesis of the file must be the one of the node. The node must be stopped before importing.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		readConfig()
+		initLogger()
+
+		var cryptoType crypto.CryptoType
+		switch viper.GetString("crypto.algorithm") {
+		case "ed25519":
+			cryptoType = crypto.CryptoTypeEd25519
+		case "secp256k1":
+			cryptoType = crypto.CryptoTypeSecp256k1
+		default:
+			panicIfError(fmt.Errorf("unknown crypto algorithm: %s", viper.GetString("crypto.algorithm")), "invalid config")
+		}
+		signer := crypto.NewSigner(cryptoType)
+		types.Signer = signer
+
+		// the sequencers can't be verified without the consensus engine.
+		if viper.GetString("consensus") != "dpos" {
+			panicIfError(fmt.Errorf("consensus %q can't verify sequencers", viper.GetString("consensus")), "failed to import")
+		}
+		genesis, err := og.LoadGenesis(cryptoType)
+		panicIfError(err, "failed to load genesis")
+		org, err := og.NewOg(og.OGConfig{CryptoType: cryptoType, Genesis: genesis})
+		panicIfError(err, "failed to open ledger")
+		dposConfig := dpos.DefaultDposConfig()
+		if viper.IsSet("dpos.slot_duration_ms") {
+			dposConfig.SlotDurationMs = viper.GetInt64("dpos.slot_duration_ms")
+		}
+		if viper.IsSet("dpos.max_clock_drift_ms") {
+			dposConfig.MaxClockDriftMs = viper.GetInt64("dpos.max_clock_drift_ms")
+		}
+		org.TxPool.Consensus = dpos.NewDpos(dposConfig, org.Dag)
+
+		verifier := &og.TxFormatVerifier{
+			Signer:       signer,
+			CryptoType:   cryptoType,
+			MaxTxHash:    types.HexToHash(viper.GetString("max_tx_hash")),
+			MaxMinedHash: types.HexToHash(viper.GetString("max_mined_hash")),
+		}
+		verify := func(tx types.Txi) error {
+			if !verifier.Verify(tx) {
+				return fmt.Errorf("bad hash or signature")
+			}
+			return nil
+		}
+
+		file, err := os.Open(args[0])
+		panicIfError(err, "failed to open file")
+		defer file.Close()
+		from := org.Dag.LatestSequencer().Height
+		n, err := core.ImportChain(org.TxPool, file, verify)
+		// stop dag before exiting on error to keep the heights imported.
+		org.Dag.Stop()
+		log.WithField("from", from).WithField("imported", n).Info("ledger imported")
+		panicIfError(err, "failed to import")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd, importCmd)
+
+	exportCmd.Flags().Uint64("to", 0, "The highest sequencer height to export, the latest one by default")
+	viper.BindPFlag("export.to", exportCmd.Flags().Lookup("to"))
+}
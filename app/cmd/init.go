@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/annchain/OG/core"
+	"github.com/annchain/OG/core/state"
+	"github.com/annchain/OG/og"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// initCmd writes the genesis described by a genesis file into the db, so
+// that the node starts the network of the file instead of the sample one.
+var initCmd = &cobra.Command{
+	Use:   "init <genesis file>",
+	Short: "Write the genesis of a genesis file into the db",
+	Long:  `Write the genesis sequencer and the genesis state described by a genesis file, in json or toml, into the db configured in [db]. Nothing is changed if the db already holds the same genesis, and it fails if the db holds another one. The chain id and the difficulty targets not set in the file are taken from config. Set genesis.path to the file as well, as the node checks its db against the configured genesis at startup.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		readConfig()
+		initLogger()
+
+		genesis, err := core.LoadGenesis(args[0])
+		panicIfError(err, "failed to load genesis")
+		err = og.ApplyChainParams(genesis)
+		panicIfError(err, "failed to load genesis")
+
+		db, err := og.CreateDB()
+		panicIfError(err, "failed to open db")
+		defer db.Close()
+		olddb, err := og.GetOldDb()
+		panicIfError(err, "failed to open old db")
+		defer olddb.Close()
+
+		statedbConfig := state.StateDBConfig{
+			PurgeTimer:     time.Duration(viper.GetInt("statedb.purge_timer_s")),
+			BeatExpireTime: time.Second * time.Duration(viper.GetInt("statedb.beat_expire_time_s")),
+		}
+		dag, err := core.NewDag(core.DefaultDagConfig(), statedbConfig, db, olddb)
+		panicIfError(err, "failed to create dag")
+		defer dag.Stop()
+
+		if dag.LoadLastState() {
+			err = genesis.Check(dag.Genesis())
+			panicIfError(err, "db is initialized with another genesis")
+			log.WithField("hash", dag.Genesis().GetTxHash().Hex()).Info("genesis already written")
+			return
+		}
+		err = dag.InitGenesis(genesis)
+		panicIfError(err, "failed to write genesis")
+		fmt.Printf("genesis %s written, state root %s\n", dag.Genesis().GetTxHash().Hex(), dag.Genesis().StateRoot.Hex())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(initCmd)
+}
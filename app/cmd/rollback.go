@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/annchain/OG/core"
+	"github.com/annchain/OG/core/state"
+	"github.com/annchain/OG/og"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// rollbackCmd reverts the local ledger to an earlier sequencer height.
+// The node must be stopped since the db can't be opened twice.
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback",
+	Short: "Roll back the ledger to a sequencer height",
+	Long:  `Roll back the ledger to a sequencer height. Everything confirmed above the height is removed and the state is restored to the one committed by the sequencer at the height. The node must be stopped before rolling back.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		readConfig()
+		initLogger()
+
+		if !cmd.Flags().Changed("height") {
+			panicIfError(fmt.Errorf("height is required"), "invalid arguments")
+		}
+		height := viper.GetUint64("rollback.height")
+
+		db, err := og.CreateDB()
+		panicIfError(err, "failed to open db")
+		defer db.Close()
+		olddb, err := og.GetOldDb()
+		panicIfError(err, "failed to open old db")
+		defer olddb.Close()
+
+		statedbConfig := state.StateDBConfig{
+			PurgeTimer:     time.Duration(viper.GetInt("statedb.purge_timer_s")),
+			BeatExpireTime: time.Second * time.Duration(viper.GetInt("statedb.beat_expire_time_s")),
+		}
+		dag, err := core.NewDag(core.DefaultDagConfig(), statedbConfig, db, olddb)
+		panicIfError(err, "failed to create dag")
+		defer dag.Stop()
+
+		if !dag.LoadLastState() {
+			panicIfError(fmt.Errorf("genesis is not found"), "nothing to roll back")
+		}
+		from := dag.LatestSequencer().Height
+		err = dag.RollBack(height)
+		panicIfError(err, "failed to roll back")
+		log.WithField("from", from).WithField("to", height).Info("ledger rolled back")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(rollbackCmd)
+
+	rollbackCmd.Flags().Uint64("height", 0, "The sequencer height to roll back to")
+	viper.BindPFlag("rollback.height", rollbackCmd.Flags().Lookup("height"))
+}
@@ -1,7 +1,13 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
+	"os"
+	"strings"
+
+	"github.com/annchain/OG/account/hdwallet"
+	"github.com/annchain/OG/account/keystore"
 	"github.com/annchain/OG/common/crypto"
 	"github.com/spf13/cobra"
 )
@@ -23,26 +29,76 @@ var (
 		Short: "account  operations for account",
 		Run:   accountCal,
 	}
-	priv_key  string
-	algorithm string
+	accountMnemonicCmd = &cobra.Command{
+		Use:   "mnemonic",
+		Short: "generate a BIP-39 mnemonic of a deterministic wallet",
+		Run:   accountMnemonic,
+	}
+
+	accountDeriveCmd = &cobra.Command{
+		Use:   "derive",
+		Short: "list the first accounts derived from a mnemonic",
+		Long:  `List the first accounts derived from a BIP-39 mnemonic, read from stdin if not given. The secp256k1 keys are derived through BIP-32 and the ed25519 keys through SLIP-10, under the base path given or the default one of the algorithm. With --keystore the derived keys are stored encrypted in the keystore.`,
+		Run:   accountDerive,
+	}
+
+	priv_key           string
+	algorithm          string
+	keystoreDir        string
+	lightScrypt        bool
+	mnemonicBits       int
+	mnemonic           string
+	mnemonicPassphrase string
+	derivationPath     string
+	deriveCount        int
 )
 
 func accountInit() {
-	accountCmd.AddCommand(accountGenCmd, accountCalCmd)
+	accountCmd.AddCommand(accountGenCmd, accountCalCmd, accountMnemonicCmd, accountDeriveCmd)
 	accountCmd.PersistentFlags().StringVarP(&algorithm, "algorithm", "a", "secp256k1", "algorithm e (ed25519) ; algorithm s (secp256k1")
 	accountCalCmd.PersistentFlags().StringVarP(&priv_key, "priv_key", "k", "", "priv_key ***")
+	for _, cmd := range []*cobra.Command{accountGenCmd, accountDeriveCmd} {
+		cmd.PersistentFlags().StringVar(&keystoreDir, "keystore", "", "keystore dir to store the keys encrypted instead of printing them")
+		cmd.PersistentFlags().BoolVar(&lightScrypt, "light_scrypt", false, "encrypt the keys with light scrypt parameters")
+	}
+	accountMnemonicCmd.PersistentFlags().IntVar(&mnemonicBits, "bits", 128, "entropy bits, 128 for 12 words and 256 for 24 words")
+	accountDeriveCmd.PersistentFlags().StringVarP(&mnemonic, "mnemonic", "m", "", "mnemonic words, read from stdin if not set")
+	accountDeriveCmd.PersistentFlags().StringVar(&mnemonicPassphrase, "mnemonic_passphrase", "", "BIP-39 passphrase of the mnemonic")
+	accountDeriveCmd.PersistentFlags().StringVarP(&derivationPath, "path", "p", "", "base derivation path, m/44'/60'/0'/0 for secp256k1 and m/44'/60'/0'/0' for ed25519 by default")
+	accountDeriveCmd.PersistentFlags().IntVarP(&deriveCount, "count", "n", 5, "number of accounts")
 }
 
-func accountGen(cmd *cobra.Command, args []string) {
-	var signer crypto.Signer
+// algorithmSigner is the signer of the algorithm flag.
+func algorithmSigner() crypto.Signer {
 	if algorithm == "secp256k1" || algorithm == "s" {
-		signer = &crypto.SignerSecp256k1{}
+		return &crypto.SignerSecp256k1{}
 	} else if algorithm == "ed25519" || algorithm == "e" {
-		signer = &crypto.SignerEd25519{}
-	} else {
+		return &crypto.SignerEd25519{}
+	}
+	return nil
+}
+
+func openKeyStore() *keystore.KeyStore {
+	scryptN, scryptP := keystore.StandardScryptN, keystore.StandardScryptP
+	if lightScrypt {
+		scryptN, scryptP = keystore.LightScryptN, keystore.LightScryptP
+	}
+	return keystore.NewKeyStore(keystoreDir, scryptN, scryptP)
+}
+
+func accountGen(cmd *cobra.Command, args []string) {
+	signer := algorithmSigner()
+	if signer == nil {
 		fmt.Println("unknown crypto algorithm", algorithm)
 		return
 	}
+	if keystoreDir != "" {
+		passphrase := readPassphrase()
+		addr, err := openKeyStore().NewAccount(signer.GetCryptoType(), passphrase)
+		panicIfError(err, "failed to store key")
+		fmt.Println(addr.Hex())
+		return
+	}
 	pub, priv, err := signer.RandomKeyPair()
 	if err != nil {
 		panic(err)
@@ -69,3 +125,75 @@ func accountCal(cmd *cobra.Command, args []string) {
 	fmt.Println(pub.String())
 	fmt.Println(addr.String())
 }
+
+func accountMnemonic(cmd *cobra.Command, args []string) {
+	words, err := hdwallet.NewMnemonic(mnemonicBits)
+	panicIfError(err, "failed to generate mnemonic")
+	fmt.Println(words)
+}
+
+func accountDerive(cmd *cobra.Command, args []string) {
+	signer := algorithmSigner()
+	if signer == nil {
+		fmt.Println("unknown crypto algorithm", algorithm)
+		return
+	}
+	cryptoType := signer.GetCryptoType()
+	base := hdwallet.DefaultBasePath(cryptoType)
+	if derivationPath != "" {
+		var err error
+		base, err = hdwallet.ParseDerivationPath(derivationPath)
+		panicIfError(err, "invalid derivation path")
+	}
+	words := mnemonic
+	if words == "" {
+		words = readLine("Mnemonic: ")
+	}
+	seed, err := hdwallet.NewSeed(words, mnemonicPassphrase)
+	panicIfError(err, "invalid mnemonic")
+	accounts, err := hdwallet.DeriveAccounts(seed, cryptoType, base, deriveCount)
+	panicIfError(err, "failed to derive accounts")
+
+	var ks *keystore.KeyStore
+	var passphrase string
+	if keystoreDir != "" {
+		ks = openKeyStore()
+		passphrase = readPassphrase()
+	}
+	for _, account := range accounts {
+		pub := signer.PubKey(account.PrivateKey)
+		fmt.Printf("%s\t%s\t%s\n", account.Path, account.Address.Hex(), pub.String())
+		if ks == nil {
+			continue
+		}
+		if _, err := ks.Import(account.PrivateKey, passphrase); err != nil && err != keystore.ErrExists {
+			panicIfError(err, "failed to store key")
+		}
+	}
+}
+
+// stdin is shared by the prompts, a buffered reader would take the input
+// of the next prompt.
+var stdin = bufio.NewReader(os.Stdin)
+
+// readLine prints prompt and reads a line from stdin.
+func readLine(prompt string) string {
+	fmt.Print(prompt)
+	line, err := stdin.ReadString('\n')
+	if err != nil && line == "" {
+		panicIfError(err, "failed to read stdin")
+	}
+	return strings.TrimRight(line, "\r\n")
+}
+
+// readPassphrase reads a passphrase from stdin twice.
+func readPassphrase() string {
+	passphrase := readLine("Passphrase: ")
+	if passphrase == "" {
+		panicIfError(fmt.Errorf("empty passphrase"), "invalid passphrase")
+	}
+	if readLine("Repeat passphrase: ") != passphrase {
+		panicIfError(fmt.Errorf("passphrases mismatch"), "invalid passphrase")
+	}
+	return passphrase
+}
@@ -0,0 +1,300 @@
+package cmd
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/annchain/OG/common/crypto"
+	"github.com/annchain/OG/common/math"
+	"github.com/annchain/OG/types"
+	"github.com/annchain/OG/vm/abi"
+	"github.com/spf13/cobra"
+)
+
+var (
+	contractCmd = &cobra.Command{
+		Use:   "contract",
+		Short: "deploy, call and query smart contracts",
+	}
+
+	contractDeployCmd = &cobra.Command{
+		Use:   "deploy <bytecode | file.bin>",
+		Short: "deploy a contract and print its address",
+		Long:  `Deploy a contract from its bytecode in hex, or from a .bin file of solc holding the bytecode in hex. The abi encoded constructor args are appended to the bytecode. The receipt is waited for and the address of the contract is printed.`,
+		Args:  cobra.ExactArgs(1),
+		Run:   contractDeploy,
+	}
+
+	contractCallCmd = &cobra.Command{
+		Use:   "call <contract address>",
+		Short: "send a tx calling a contract and print its receipt",
+		Args:  cobra.ExactArgs(1),
+		Run:   contractCall,
+	}
+
+	contractQueryCmd = &cobra.Command{
+		Use:   "query <contract address>",
+		Short: "call a contract on the latest state without sending a tx",
+		Args:  cobra.ExactArgs(1),
+		Run:   contractQuery,
+	}
+
+	contractArgs     string
+	contractData     string
+	contractAbiFile  string
+	contractMethod   string
+	contractParams   string
+	contractValue    int64
+	contractGasLimit uint64
+	contractGasPrice int64
+	contractNonce    uint64
+	receiptWait      time.Duration
+	queryHeight      int64
+)
+
+func contractInit() {
+	contractCmd.AddCommand(contractDeployCmd, contractCallCmd, contractQueryCmd)
+	for _, cmd := range []*cobra.Command{contractDeployCmd, contractCallCmd} {
+		cmd.PersistentFlags().StringVarP(&priv_key, "priv_key", "k", "", "priv_key ***")
+		cmd.PersistentFlags().Int64VarP(&contractValue, "value", "v", 0, "value sent to the contract")
+		cmd.PersistentFlags().Uint64Var(&contractGasLimit, "gas_limit", 3000000, "gas limit")
+		cmd.PersistentFlags().Int64Var(&contractGasPrice, "gas_price", 0, "gas price")
+		cmd.PersistentFlags().Uint64VarP(&contractNonce, "nonce", "n", 0, "nonce, the next one of the sender by default")
+		cmd.PersistentFlags().DurationVarP(&receiptWait, "wait", "w", 30*time.Second, "how long to wait for the receipt, 0 to print the tx hash only")
+	}
+	contractDeployCmd.PersistentFlags().StringVar(&contractArgs, "args", "", "abi encoded constructor args in hex")
+	for _, cmd := range []*cobra.Command{contractCallCmd, contractQueryCmd} {
+		cmd.PersistentFlags().StringVarP(&contractData, "data", "d", "", "abi encoded call data in hex")
+	}
+	contractQueryCmd.PersistentFlags().Int64Var(&queryHeight, "height", -1, "query on the state of a sequencer height, the latest state by default")
+	for _, cmd := range []*cobra.Command{contractDeployCmd, contractCallCmd, contractQueryCmd} {
+		cmd.PersistentFlags().StringVar(&contractAbiFile, "abi", "", "json abi file of the contract, to encode --params and decode the results and logs")
+		cmd.PersistentFlags().StringVar(&contractParams, "params", "", "args in a json array encoded by --abi, like '[\"0x...\", 100]'")
+	}
+	for _, cmd := range []*cobra.Command{contractCallCmd, contractQueryCmd} {
+		cmd.PersistentFlags().StringVarP(&contractMethod, "method", "m", "", "name or signature of the method in --abi")
+	}
+}
+
+// receiptStatusTxSuccess is core.ReceiptStatusTxSuccess.
+const receiptStatusTxSuccess = 1
+
+// ReceiptResponse is the receipt returned by query_receipt.
+type ReceiptResponse struct {
+	TxHash          string            `json:"tx_hash"`
+	Status          int               `json:"status"`
+	Result          string            `json:"result"`
+	ContractAddress string            `json:"contract_address"`
+	GasUsed         uint64            `json:"gas_used"`
+	Logs            []json.RawMessage `json:"logs"`
+}
+
+func contractDeploy(cmd *cobra.Command, args []string) {
+	code, err := readBytecode(args[0])
+	panicIfError(err, "invalid bytecode")
+	contractAbi := readAbi()
+	var ctorArgs []byte
+	if contractAbi != nil {
+		ctorArgs, err = contractAbi.PackJSON("", []byte(contractParams))
+		panicIfError(err, "failed to encode constructor args")
+	} else {
+		ctorArgs, err = decodeHex(contractArgs)
+		panicIfError(err, "constructor args are not hex")
+	}
+	if len(code) == 0 {
+		panicIfError(fmt.Errorf("empty bytecode"), "invalid bytecode")
+	}
+
+	// a tx to the empty address creates a contract.
+	receipt := sendContractTx(cmd, types.Address{}, append(code, ctorArgs...))
+	if receipt == nil {
+		return
+	}
+	printReceipt(receipt, contractAbi)
+	if receipt.Status != receiptStatusTxSuccess {
+		os.Exit(1)
+	}
+	fmt.Println(receipt.ContractAddress)
+}
+
+func contractCall(cmd *cobra.Command, args []string) {
+	addr, err := types.StringToAddress(args[0])
+	panicIfError(err, "invalid contract address")
+	contractAbi := readAbi()
+	data := callData(contractAbi)
+	if len(data) == 0 {
+		panicIfError(fmt.Errorf("empty call data"), "invalid call data")
+	}
+
+	receipt := sendContractTx(cmd, addr, data)
+	if receipt == nil {
+		return
+	}
+	printReceipt(receipt, contractAbi)
+	if receipt.Status != receiptStatusTxSuccess {
+		os.Exit(1)
+	}
+}
+
+func contractQuery(cmd *cobra.Command, args []string) {
+	addr, err := types.StringToAddress(args[0])
+	panicIfError(err, "invalid contract address")
+	contractAbi := readAbi()
+	data := callData(contractAbi)
+
+	params := []string{"contract_address", addr.Hex(), "query_data", hex.EncodeToString(data)}
+	if queryHeight >= 0 {
+		params = append(params, "height", fmt.Sprintf("%d", queryHeight))
+	}
+	var ret string
+	err = rpcGet("query_contract", &ret, params...)
+	panicIfError(err, "failed to query contract")
+	if contractAbi == nil {
+		fmt.Println("0x" + ret)
+		return
+	}
+	method, err := contractAbi.Method(contractMethod)
+	panicIfError(err, "invalid method")
+	result, err := hex.DecodeString(ret)
+	panicIfError(err, "result is not hex")
+	values, err := method.Outputs.Unpack(result)
+	if err != nil {
+		if reason, rerr := abi.UnpackRevert(result); rerr == nil {
+			err = fmt.Errorf("reverted: %s", reason)
+		}
+	}
+	panicIfError(err, "failed to decode result")
+	printJSON(values)
+}
+
+// readAbi reads the abi of --abi, nil if it's not given.
+func readAbi() *abi.ABI {
+	if contractAbiFile == "" {
+		return nil
+	}
+	f, err := os.Open(contractAbiFile)
+	panicIfError(err, "failed to open abi")
+	defer f.Close()
+	contractAbi, err := abi.JSON(f)
+	panicIfError(err, "invalid abi")
+	return contractAbi
+}
+
+// callData encodes --method and --params by contractAbi, or decodes --data
+// if contractAbi is nil.
+func callData(contractAbi *abi.ABI) []byte {
+	if contractAbi == nil {
+		data, err := decodeHex(contractData)
+		panicIfError(err, "call data is not hex")
+		return data
+	}
+	data, err := contractAbi.PackJSON(contractMethod, []byte(contractParams))
+	panicIfError(err, "failed to encode call data")
+	return data
+}
+
+// sendContractTx sends a contract tx of data to addr and waits for its
+// receipt. It returns nil if the receipt is not waited for.
+func sendContractTx(cmd *cobra.Command, addr types.Address, data []byte) *ReceiptResponse {
+	key, err := crypto.PrivateKeyFromString(priv_key)
+	panicIfError(err, "invalid private key")
+	tx := &types.Tx{
+		To:       addr,
+		Value:    math.NewBigInt(contractValue),
+		Data:     data,
+		GasLimit: contractGasLimit,
+		GasPrice: math.NewBigInt(contractGasPrice),
+	}
+	var txNonce *uint64
+	if cmd.Flags().Changed("nonce") {
+		txNonce = &contractNonce
+	}
+	hash, err := signAndSend(key, "", tx, txNonce)
+	panicIfError(err, "failed to send tx")
+	fmt.Println("tx:", hash)
+	if receiptWait <= 0 {
+		return nil
+	}
+	receipt, err := waitReceipt(hash, receiptWait)
+	panicIfError(err, "failed to get receipt")
+	return receipt
+}
+
+// waitReceipt polls the receipt of hash until it is confirmed or timeout
+// passes.
+func waitReceipt(hash string, timeout time.Duration) (*ReceiptResponse, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		var receipt ReceiptResponse
+		err := rpcGet("query_receipt", &receipt, "hash", hash)
+		if err == nil {
+			return &receipt, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("receipt of %s not found in %s: %v", hash, timeout, err)
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+// printReceipt prints receipt, followed by its logs decoded by
+// contractAbi if it's given.
+func printReceipt(receipt *ReceiptResponse, contractAbi *abi.ABI) {
+	printJSON(receipt)
+	if contractAbi == nil {
+		return
+	}
+	for _, raw := range receipt.Logs {
+		var l struct {
+			Topics []string `json:"topics"`
+			Data   string   `json:"data"`
+		}
+		if err := json.Unmarshal(raw, &l); err != nil {
+			continue
+		}
+		topics := make([]types.Hash, len(l.Topics))
+		for i, topic := range l.Topics {
+			topics[i] = types.HexToHash(topic)
+		}
+		data, _ := decodeHex(l.Data)
+		event, values, err := contractAbi.UnpackLog(topics, data)
+		if err != nil {
+			fmt.Println("undecoded log:", err)
+			continue
+		}
+		args := make(map[string]interface{}, len(values))
+		for i, arg := range event.Inputs {
+			name := arg.Name
+			if name == "" {
+				name = fmt.Sprintf("arg%d", i)
+			}
+			args[name] = values[i]
+		}
+		fmt.Print(event.Sig(), " ")
+		printJSON(args)
+	}
+}
+
+func printJSON(v interface{}) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	panicIfError(err, "failed to encode json")
+	fmt.Println(string(data))
+}
+
+// readBytecode reads the bytecode in hex from a file if s is one, or from
+// s itself.
+func readBytecode(s string) ([]byte, error) {
+	if _, err := os.Stat(s); err == nil {
+		data, err := ioutil.ReadFile(s)
+		if err != nil {
+			return nil, err
+		}
+		s = strings.TrimSpace(string(data))
+	}
+	return decodeHex(s)
+}
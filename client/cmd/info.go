@@ -1,10 +1,11 @@
 package cmd
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
-	"github.com/annchain/OG/client/httplib"
+	"sort"
+	"strings"
+
 	"github.com/spf13/cobra"
 )
 
@@ -19,21 +20,135 @@ var (
 		Short: "net_info",
 		Run:   netInfo,
 	}
+	peersCmd = &cobra.Command{
+		Use:   "peers",
+		Short: "list the peers connected to the node",
+		Run:   peers,
+	}
+	syncCmd = &cobra.Command{
+		Use:   "sync",
+		Short: "get the sync status of the node",
+		Run:   syncStatus,
+	}
 )
 
+// nodeInfo is the p2p.NodeInfo returned by the rpc server.
+type nodeInfo struct {
+	ID      string `json:"id"`
+	ShortId string `json:"short_id"`
+	Name    string `json:"name"`
+	Enode   string `json:"enode"`
+	IP      string `json:"ip"`
+	Ports   struct {
+		Discovery int `json:"discovery"`
+		Listener  int `json:"listener"`
+	} `json:"ports"`
+	ListenAddr string                     `json:"listenAddr"`
+	Protocols  map[string]json.RawMessage `json:"protocols"`
+}
+
+func (n *nodeInfo) writeTo(w *tableWriter) {
+	w.row("id", n.ID)
+	w.row("name", n.Name)
+	w.row("enode", n.Enode)
+	w.row("ip", n.IP)
+	w.row("listen addr", n.ListenAddr)
+	w.row("discovery port", n.Ports.Discovery)
+	w.row("listener port", n.Ports.Listener)
+	names := make([]string, 0, len(n.Protocols))
+	for name := range n.Protocols {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		w.row("protocol "+name, string(n.Protocols[name]))
+	}
+}
+
+// peerInfo is the p2p.PeerInfo returned by the rpc server.
+type peerInfo struct {
+	ID      string   `json:"id"`
+	ShortId string   `json:"short_id"`
+	Name    string   `json:"name"`
+	Caps    []string `json:"caps"`
+	Network struct {
+		LocalAddress  string `json:"localAddress"`
+		RemoteAddress string `json:"remoteAddress"`
+		Inbound       bool   `json:"inbound"`
+		Trusted       bool   `json:"trusted"`
+		Static        bool   `json:"static"`
+	} `json:"network"`
+}
+
+func writePeers(w *tableWriter, peers []peerInfo) {
+	w.row("ID", "NAME", "REMOTE ADDRESS", "INBOUND", "TRUSTED", "CAPS")
+	for _, p := range peers {
+		w.row(p.ShortId, p.Name, p.Network.RemoteAddress, p.Network.Inbound, p.Network.Trusted, strings.Join(p.Caps, ","))
+	}
+}
+
 func status(cmd *cobra.Command, args []string) {
-	requesrUrl := Host + "/status"
-	req := httplib.Get(requesrUrl)
-	data, err := req.Bytes()
-	if err != nil {
-		fmt.Println(err)
-		return
+	var data json.RawMessage
+	err := rpcGet("status", &data)
+	panicIfError(err, "failed to get status")
+	var resp struct {
+		NodeInfo  nodeInfo   `json:"node_info"`
+		PeersInfo []peerInfo `json:"peers_info"`
 	}
-	var out bytes.Buffer
-	json.Indent(&out, data, "", "\t")
-	fmt.Println(out.String())
+	output(data, &resp, func(w *tableWriter) {
+		resp.NodeInfo.writeTo(w)
+		w.row("peers", len(resp.PeersInfo))
+		if len(resp.PeersInfo) == 0 {
+			return
+		}
+		w.section("PEERS")
+		writePeers(w, resp.PeersInfo)
+	})
 }
 
 func netInfo(cmd *cobra.Command, args []string) {
+	var data json.RawMessage
+	err := rpcGet("net_info", &data)
+	panicIfError(err, "failed to get net info")
+	var info nodeInfo
+	output(data, &info, info.writeTo)
+}
+
+func peers(cmd *cobra.Command, args []string) {
+	var data json.RawMessage
+	err := rpcGet("peers_info", &data)
+	panicIfError(err, "failed to get peers")
+	var resp []peerInfo
+	output(data, &resp, func(w *tableWriter) {
+		writePeers(w, resp)
+	})
+}
 
+func syncStatus(cmd *cobra.Command, args []string) {
+	// sync_status is not wrapped as the other responses.
+	data, err := rpcGetBody("sync_status")
+	panicIfError(err, "failed to get sync status")
+	var resp struct {
+		Id                       string `json:"id"`
+		SyncMode                 string `json:"syncMode"`
+		CatchupSyncerStatus      string `json:"catchupSyncerStatus"`
+		CatchupSyncerEnabled     bool   `json:"catchupSyncerEnabled"`
+		IncrementalSyncerEnabled bool   `json:"incrementalSyncerEnabled"`
+		Height                   uint64 `json:"height"`
+		LatestHeight             uint64 `json:"latestHeight"`
+		BestPeer                 string `json:"bestPeer"`
+		Error                    string `json:"error"`
+		Txid                     uint32 `json:"txid"`
+	}
+	output(data, &resp, func(w *tableWriter) {
+		w.row("id", resp.Id)
+		w.row("sync mode", resp.SyncMode)
+		w.row("catchup syncer", fmt.Sprintf("%s (enabled: %t)", resp.CatchupSyncerStatus, resp.CatchupSyncerEnabled))
+		w.row("incremental syncer enabled", resp.IncrementalSyncerEnabled)
+		w.row("height", resp.Height)
+		w.row("latest height", resp.LatestHeight)
+		w.row("best peer", resp.BestPeer)
+		w.row("txid", resp.Txid)
+		w.row("error", resp.Error)
+	})
 }
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// output formats of the query commands.
+const (
+	outputJSON  = "json"
+	outputTable = "table"
+)
+
+var outputFormat string
+
+// checkOutput validates the --output flag before any command runs.
+func checkOutput(cmd *cobra.Command, args []string) error {
+	if outputFormat != outputJSON && outputFormat != outputTable {
+		return fmt.Errorf("unknown output format %q, expected %s or %s", outputFormat, outputJSON, outputTable)
+	}
+	return nil
+}
+
+// output prints data, the json returned by the rpc server, indented with
+// --output json. With --output table data is decoded into v, and then
+// printed by table.
+func output(data json.RawMessage, v interface{}, table func(w *tableWriter)) {
+	if outputFormat == outputJSON {
+		var out bytes.Buffer
+		if err := json.Indent(&out, data, "", "  "); err != nil {
+			out.Reset()
+			out.Write(data)
+		}
+		fmt.Println(out.String())
+		return
+	}
+	err := json.Unmarshal(data, v)
+	panicIfError(err, "failed to decode response")
+	w := newTableWriter()
+	table(w)
+	w.Flush()
+}
+
+// tableWriter aligns the cells of the rows it writes in columns.
+type tableWriter struct {
+	*tabwriter.Writer
+}
+
+func newTableWriter() *tableWriter {
+	return &tableWriter{tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)}
+}
+
+// row writes a row of cells.
+func (w *tableWriter) row(cells ...interface{}) {
+	strs := make([]string, len(cells))
+	for i, cell := range cells {
+		strs[i] = fmt.Sprint(cell)
+	}
+	fmt.Fprintln(w, strings.Join(strs, "\t"))
+}
+
+// section ends the columns of the rows above and writes a title for the
+// rows below, which are aligned on their own.
+func (w *tableWriter) section(title string) {
+	w.Flush()
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, title)
+}
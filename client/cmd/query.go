@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/annchain/OG/common/hexutil"
+	"github.com/annchain/OG/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	balanceCmd = &cobra.Command{
+		Use:   "balance <address>",
+		Short: "get the balance of an account",
+		Args:  cobra.ExactArgs(1),
+		Run:   balance,
+	}
+
+	nonceCmd = &cobra.Command{
+		Use:   "nonce <address>",
+		Short: "get the latest nonce of an account, -1 if it has sent no tx",
+		Args:  cobra.ExactArgs(1),
+		Run:   latestNonce,
+	}
+
+	receiptCmd = &cobra.Command{
+		Use:   "receipt <tx hash>",
+		Short: "get the receipt of a confirmed tx",
+		Args:  cobra.ExactArgs(1),
+		Run:   receipt,
+	}
+
+	transactionCmd = &cobra.Command{
+		Use:   "transaction <tx hash>",
+		Short: "get a tx or a sequencer by its hash",
+		Args:  cobra.ExactArgs(1),
+		Run:   transaction,
+	}
+
+	sequencerCmd = &cobra.Command{
+		Use:   "sequencer [height | hash]",
+		Short: "get a sequencer by its height or hash, the latest one by default",
+		Args:  cobra.MaximumNArgs(1),
+		Run:   sequencer,
+	}
+
+	txsCmd = &cobra.Command{
+		Use:   "txs [address]",
+		Short: "list the txs of an address, or the txs confirmed by the sequencer of --height",
+		Args:  cobra.MaximumNArgs(1),
+		Run:   txs,
+	}
+
+	stateHeight int64
+	txsHeight   int64
+)
+
+func queryInit() {
+	for _, cmd := range []*cobra.Command{balanceCmd, nonceCmd} {
+		cmd.PersistentFlags().Int64Var(&stateHeight, "height", -1, "query on the state of a sequencer height, the latest state by default")
+	}
+	txsCmd.PersistentFlags().Int64Var(&txsHeight, "height", -1, "height of the sequencer confirming the txs")
+}
+
+// receiptStatusNames are the names of the receipt statuses of core.
+var receiptStatusNames = map[int]string{
+	0: "sequencer",
+	1: "success",
+	2: "vm failed",
+	3: "staking failed",
+}
+
+// txResponse holds the fields of the txs and sequencers returned by the
+// rpc server. The fields of sequencers are empty for txs, and the other
+// way round.
+type txResponse struct {
+	Type         int
+	Hash         string
+	ParentsHash  []string
+	AccountNonce uint64
+	Height       uint64
+	Weight       uint64
+	From         string
+	To           string
+	Value        string
+	Data         []byte
+	GasLimit     uint64
+	GasPrice     string
+	Issuer       string
+	Timestamp    int64
+	StateRoot    string
+	ReceiptsRoot string
+}
+
+func (tx *txResponse) typeName() string {
+	return types.TxBaseType(tx.Type).String()
+}
+
+// writeTo writes the fields of tx in rows, leaving out the fields of txs
+// for sequencers and the other way round.
+func (tx *txResponse) writeTo(w *tableWriter) {
+	w.row("type", tx.typeName())
+	w.row("hash", tx.Hash)
+	w.row("height", tx.Height)
+	w.row("weight", tx.Weight)
+	w.row("nonce", tx.AccountNonce)
+	if types.TxBaseType(tx.Type) == types.TxBaseTypeSequencer {
+		w.row("issuer", tx.Issuer)
+		w.row("timestamp", tx.Timestamp)
+		w.row("state root", tx.StateRoot)
+		w.row("receipts root", tx.ReceiptsRoot)
+	} else {
+		w.row("from", tx.From)
+		w.row("to", tx.To)
+		w.row("value", tx.Value)
+		w.row("data", hexutil.Encode(tx.Data))
+		w.row("gas limit", tx.GasLimit)
+		w.row("gas price", tx.GasPrice)
+	}
+	for i, parent := range tx.ParentsHash {
+		if i == 0 {
+			w.row("parents", parent)
+		} else {
+			w.row("", parent)
+		}
+	}
+}
+
+// heightParams returns the height query param if height is not negative.
+func heightParams(height int64) []string {
+	if height < 0 {
+		return nil
+	}
+	return []string{"height", strconv.FormatInt(height, 10)}
+}
+
+func balance(cmd *cobra.Command, args []string) {
+	var data json.RawMessage
+	params := append([]string{"address", args[0]}, heightParams(stateHeight)...)
+	err := rpcGet("query_balance", &data, params...)
+	panicIfError(err, "failed to get balance")
+	var resp struct {
+		Address string `json:"address"`
+		Balance string `json:"balance"`
+	}
+	output(data, &resp, func(w *tableWriter) {
+		w.row("ADDRESS", "BALANCE")
+		w.row(resp.Address, resp.Balance)
+	})
+}
+
+func latestNonce(cmd *cobra.Command, args []string) {
+	var data json.RawMessage
+	params := append([]string{"address", args[0]}, heightParams(stateHeight)...)
+	err := rpcGet("query_nonce", &data, params...)
+	panicIfError(err, "failed to get nonce")
+	var n int64
+	output(data, &n, func(w *tableWriter) {
+		w.row("ADDRESS", "NONCE", "NEXT NONCE")
+		w.row(args[0], n, n+1)
+	})
+}
+
+func receipt(cmd *cobra.Command, args []string) {
+	var data json.RawMessage
+	err := rpcGet("query_receipt", &data, "hash", args[0])
+	panicIfError(err, "failed to get receipt")
+	var resp struct {
+		ReceiptResponse
+		Logs []logResponse `json:"logs"`
+	}
+	output(data, &resp, func(w *tableWriter) {
+		status, ok := receiptStatusNames[resp.Status]
+		if !ok {
+			status = strconv.Itoa(resp.Status)
+		}
+		w.row("tx hash", resp.TxHash)
+		w.row("status", status)
+		w.row("result", resp.Result)
+		w.row("contract address", resp.ContractAddress)
+		w.row("gas used", resp.GasUsed)
+		if len(resp.Logs) == 0 {
+			return
+		}
+		w.section("LOGS")
+		writeLogs(w, resp.Logs)
+	})
+}
+
+// logResponse is a log in the receipts returned by the rpc server, and
+// in the logs pushed by the websocket server.
+type logResponse struct {
+	Address   string   `json:"address"`
+	Topics    []string `json:"topics"`
+	Data      string   `json:"data"`
+	SeqHeight uint64   `json:"seq_height"`
+	SeqHash   string   `json:"seq_hash"`
+	TxHash    string   `json:"tx_hash"`
+	TxIndex   uint     `json:"tx_index"`
+	LogIndex  uint     `json:"log_index"`
+}
+
+func writeLogs(w *tableWriter, logs []logResponse) {
+	w.row("INDEX", "ADDRESS", "TOPICS", "DATA")
+	for _, l := range logs {
+		w.row(l.LogIndex, l.Address, strings.Join(l.Topics, ","), l.Data)
+	}
+}
+
+func transaction(cmd *cobra.Command, args []string) {
+	var data json.RawMessage
+	err := rpcGet("transaction", &data, "hash", args[0])
+	panicIfError(err, "failed to get tx")
+	var tx txResponse
+	output(data, &tx, tx.writeTo)
+}
+
+func sequencer(cmd *cobra.Command, args []string) {
+	var params []string
+	if len(args) == 1 {
+		if _, err := strconv.ParseUint(args[0], 10, 64); err == nil {
+			params = []string{"seq_id", args[0]}
+		} else {
+			params = []string{"hash", args[0]}
+		}
+	}
+	var data json.RawMessage
+	err := rpcGet("sequencer", &data, params...)
+	panicIfError(err, "failed to get sequencer")
+	var seq txResponse
+	output(data, &seq, seq.writeTo)
+}
+
+func txs(cmd *cobra.Command, args []string) {
+	var params []string
+	if len(args) == 1 {
+		params = []string{"address", args[0]}
+	} else if txsHeight >= 0 {
+		params = []string{"seq_id", strconv.FormatInt(txsHeight, 10)}
+	} else {
+		panicIfError(fmt.Errorf("expected an address or --height"), "invalid args")
+	}
+	var data json.RawMessage
+	err := rpcGet("transactions", &data, params...)
+	panicIfError(err, "failed to get txs")
+	var resp struct {
+		Total int          `json:"total"`
+		Txs   []txResponse `json:"txs"`
+	}
+	output(data, &resp, func(w *tableWriter) {
+		w.row("HASH", "TYPE", "HEIGHT", "NONCE", "FROM", "TO", "VALUE")
+		for _, tx := range resp.Txs {
+			from := tx.From
+			if types.TxBaseType(tx.Type) == types.TxBaseTypeSequencer {
+				from = tx.Issuer
+			}
+			w.row(tx.Hash, tx.typeName(), tx.Height, tx.AccountNonce, from, tx.To, tx.Value)
+		}
+		w.section(fmt.Sprintf("total: %d", resp.Total))
+	})
+}
@@ -14,6 +14,8 @@ var rootCmd = &cobra.Command{
 	Use:   "OGtool",
 	Short: "OGtool: The next generation of DLT",
 	Long:  `OG to da moon`,
+
+	PersistentPreRunE: checkOutput,
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -27,12 +29,19 @@ func Execute() {
 
 func init() {
 	rootCmd.PersistentFlags().StringVarP(&Host, "server", "s", "http://127.0.0.1:8000", fmt.Sprintf("serverurl,default: http://127.0.0.1:8000"))
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", outputTable, "output format of the queries: json or table")
 	InfoCmd.AddCommand(netInfoCmd)
-	rootCmd.AddCommand(InfoCmd)
+	rootCmd.AddCommand(InfoCmd, peersCmd, syncCmd)
 	txInit()
 	rootCmd.AddCommand(txCmd)
 	accountInit()
 	rootCmd.AddCommand(accountCmd)
+	contractInit()
+	rootCmd.AddCommand(contractCmd)
+	queryInit()
+	rootCmd.AddCommand(balanceCmd, nonceCmd, receiptCmd, transactionCmd, sequencerCmd, txsCmd)
+	watchInit()
+	rootCmd.AddCommand(watchCmd)
 }
 
 func panicIfError(err error, message string) {
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/annchain/OG/client/httplib"
+)
+
+// rpcResponse is the body of every response of the rpc server.
+type rpcResponse struct {
+	Data    json.RawMessage `json:"data"`
+	Message string          `json:"message"`
+}
+
+// rpcGet gets path from the rpc server with the query params, which are
+// pairs of keys and values, and decodes the data of the response into v.
+func rpcGet(path string, v interface{}, params ...string) error {
+	req := httplib.Get(Host + "/" + path)
+	for i := 0; i+1 < len(params); i += 2 {
+		req.Param(params[i], params[i+1])
+	}
+	return decodeResponse(req, v)
+}
+
+// rpcGetBody gets path from the rpc server with the query params and
+// returns the body of the response, for the handlers which don't wrap
+// their responses in rpcResponse.
+func rpcGetBody(path string, params ...string) ([]byte, error) {
+	req := httplib.Get(Host + "/" + path)
+	for i := 0; i+1 < len(params); i += 2 {
+		req.Param(params[i], params[i+1])
+	}
+	resp, err := req.Response()
+	if err != nil {
+		return nil, err
+	}
+	data, err := req.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", resp.Status, data)
+	}
+	return data, nil
+}
+
+// rpcPost posts body as json to path of the rpc server and decodes the
+// data of the response into v.
+func rpcPost(path string, body interface{}, v interface{}) error {
+	req := httplib.Post(Host + "/" + path)
+	if _, err := req.JSONBody(body); err != nil {
+		return fmt.Errorf("encode request error: %v", err)
+	}
+	return decodeResponse(req, v)
+}
+
+func decodeResponse(req *httplib.BeegoHTTPRequest, v interface{}) error {
+	data, err := req.Bytes()
+	if err != nil {
+		return err
+	}
+	var resp rpcResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return fmt.Errorf("decode response error: %v: %s", err, data)
+	}
+	if resp.Message != "" {
+		return errors.New(resp.Message)
+	}
+	if v == nil {
+		return nil
+	}
+	return json.Unmarshal(resp.Data, v)
+}
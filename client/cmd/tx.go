@@ -1,13 +1,14 @@
 package cmd
 
 import (
+	"encoding/hex"
 	"fmt"
-	"github.com/annchain/OG/client/httplib"
 	"github.com/annchain/OG/common/crypto"
 	"github.com/annchain/OG/common/hexutil"
 	"github.com/annchain/OG/common/math"
 	"github.com/annchain/OG/types"
 	"github.com/spf13/cobra"
+	"strings"
 )
 
 var (
@@ -17,10 +18,13 @@ var (
 		Run:   newTx,
 	}
 
-	payload string
-	to      string
-	nonce   uint64
-	value   int64
+	payload  string
+	to       string
+	nonce    uint64
+	value    int64
+	txType   string
+	gasLimit uint64
+	gasPrice int64
 )
 
 func txInit() {
@@ -29,14 +33,21 @@ func txInit() {
 	txCmd.PersistentFlags().StringVarP(&priv_key, "priv_key", "k", "", "priv_key ***")
 	txCmd.PersistentFlags().Int64VarP(&value, "value", "v", 0, "value 1")
 	txCmd.PersistentFlags().Uint64VarP(&nonce, "nonce", "n", 0, "nonce 1")
+	txCmd.PersistentFlags().StringVarP(&txType, "type", "y", "", "tx type: normal, candidate, vote or unvote")
+	txCmd.PersistentFlags().Uint64Var(&gasLimit, "gas_limit", 0, "gas limit of a contract tx")
+	txCmd.PersistentFlags().Int64Var(&gasPrice, "gas_price", 0, "gas price of a contract tx")
 }
 
 //NewTxrequest for RPC request
 type NewTxRequest struct {
+	Type      string `json:"type"`
 	Nonce     string `json:"nonce"`
 	From      string `json:"from"`
 	To        string `json:"to"`
 	Value     string `json:"value"`
+	Data      string `json:"data"`
+	GasLimit  string `json:"gas_limit"`
+	GasPrice  string `json:"gas_price"`
 	Signature string `json:"signature"`
 	Pubkey    string `json:"pubkey"`
 }
@@ -51,56 +62,83 @@ func newTx(cmd *cobra.Command, args []string) {
 		fmt.Println(err)
 		return
 	}
-	//todo smart contracts
-	//data := common.Hex2Bytes(payload)
-	// do sign work
-	signer := crypto.NewSigner(key.Type)
-	pub := signer.PubKey(key)
-	from := signer.Address(pub)
-	if nonce <= 0 {
-		nonce = getNonce(from)
+	data, err := decodeHex(payload)
+	if err != nil {
+		fmt.Println("payload is not hex:", err)
+		return
 	}
-	tx := types.Tx{
-		Value: math.NewBigInt(value),
-		To:    toAddr,
-		From:  from,
-		TxBase: types.TxBase{
-			AccountNonce: nonce,
-			Type:         types.TxBaseTypeNormal,
-		},
+	tx := &types.Tx{
+		Value:    math.NewBigInt(value),
+		To:       toAddr,
+		Data:     data,
+		GasLimit: gasLimit,
+		GasPrice: math.NewBigInt(gasPrice),
 	}
-	signature := signer.Sign(key, tx.SignatureTargets())
+	var txNonce *uint64
+	if cmd.Flags().Changed("nonce") {
+		txNonce = &nonce
+	}
+	hash, err := signAndSend(key, txType, tx, txNonce)
+	panicIfError(err, "failed to send tx")
+	fmt.Println(hash)
+}
+
+// signAndSend fills in the type, the sender and the nonce of tx, signs it
+// with key and sends it to the rpc server. The next nonce of the sender is
+// queried if txNonce is nil. It returns the hash of tx.
+func signAndSend(key crypto.PrivateKey, typeName string, tx *types.Tx, txNonce *uint64) (string, error) {
+	baseType, err := types.ParseTxBaseType(typeName)
+	if err != nil {
+		return "", err
+	}
+	tx.Type = baseType
+	signer := crypto.NewSigner(key.Type)
 	pubKey := signer.PubKey(key)
+	tx.From = signer.Address(pubKey)
+	if txNonce != nil {
+		tx.AccountNonce = *txNonce
+	} else {
+		next, err := getNonce(tx.From)
+		if err != nil {
+			return "", fmt.Errorf("query nonce error: %v", err)
+		}
+		tx.AccountNonce = next
+	}
+	signature := signer.Sign(key, tx.SignatureTargets())
 	txReq := &NewTxRequest{
+		Type:      typeName,
 		Nonce:     fmt.Sprintf("%d", tx.AccountNonce),
 		From:      tx.From.Hex(),
-		To:        to,
+		To:        tx.To.Hex(),
 		Value:     tx.Value.String(),
+		Data:      hexutil.Encode(tx.Data),
+		GasLimit:  fmt.Sprintf("%d", tx.GasLimit),
+		GasPrice:  tx.GetGasPrice().String(),
 		Signature: hexutil.Encode(signature.Bytes),
 		Pubkey:    pubKey.String(),
 	}
-	req := httplib.Post(Host + "/new_transaction")
-	_, err = req.JSONBody(&txReq)
-	if err != nil {
-		panic(fmt.Errorf("encode tx errror %v", err))
-	}
-	str, err := req.String()
-	if err != nil {
-		fmt.Println(err)
-		return
+	var hash string
+	if err := rpcPost("new_transaction", txReq, &hash); err != nil {
+		return "", err
 	}
-	fmt.Println(str)
+	return hash, nil
 }
 
-func getNonce(addr types.Address) (nonce uint64) {
-	uri := fmt.Sprintf("query_nonce?address=%s", addr.Hex())
-	req := httplib.Get(Host + "/" + uri)
-	var nonceResp struct {
-		Nonce uint64 `json:"nonce"`
+// getNonce queries the next nonce of addr, which is 0 for a new account.
+func getNonce(addr types.Address) (uint64, error) {
+	var latest int64
+	if err := rpcGet("query_nonce", &latest, "address", addr.Hex()); err != nil {
+		return 0, err
 	}
-	_, err := req.JSONBody(&nonceResp)
-	if err != nil {
-		fmt.Println("encode nonce errror ", err)
+	// -1 if addr has no nonce yet.
+	return uint64(latest + 1), nil
+}
+
+// decodeHex decodes a hex string with or without 0x.
+func decodeHex(s string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		s = s[2:]
 	}
-	return nonceResp.Nonce
+	return hex.DecodeString(s)
 }
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/gorilla/websocket"
+	"github.com/spf13/cobra"
+)
+
+// events pushed by the websocket server.
+const (
+	eventNewUnit      = "new_unit"
+	eventConfirmed    = "confirmed"
+	eventNewSequencer = "new_sequencer"
+	eventTxs          = "txs"
+	eventLogs         = "logs"
+)
+
+var (
+	watchCmd = &cobra.Command{
+		Use:   "watch [event...]",
+		Short: "subscribe to events of the websocket server and print them",
+		Long: `Subscribe to events of the websocket server and print them as they are pushed, until interrupted. The events are new_sequencer (the default), txs, logs, new_unit and confirmed.
+txs are the confirmed txs sent from or to any of --address. logs are the logs emitted by any of --address, whose i-th topic is any of the comma separated topics of the i-th --topic. Empty filters match anything.`,
+		Run: watch,
+	}
+
+	wsServer       string
+	watchAddresses []string
+	watchTopics    []string
+)
+
+func watchInit() {
+	watchCmd.PersistentFlags().StringVar(&wsServer, "ws", "ws://127.0.0.1:8002/ws", "websocket server url")
+	watchCmd.PersistentFlags().StringSliceVar(&watchAddresses, "address", nil, "addresses of the txs and logs events")
+	watchCmd.PersistentFlags().StringArrayVar(&watchTopics, "topic", nil, "comma separated topics of a position of the logs event, in the order of the positions")
+}
+
+// registerMessage subscribes the conn to an event of the websocket
+// server, as wserver.RegisterMessage.
+type registerMessage struct {
+	Event     string     `json:"event"`
+	Addresses []string   `json:"addresses"`
+	Topics    [][]string `json:"topics"`
+}
+
+// wsMessage is a message pushed by the websocket server. The units of
+// new_unit and confirmed are in Nodes, and the data of the other events
+// in Data.
+type wsMessage struct {
+	Type  string          `json:"type"`
+	Data  json.RawMessage `json:"data"`
+	Nodes []struct {
+		Data struct {
+			Unit  string `json:"unit"`
+			UnitS string `json:"unit_s"`
+		} `json:"data"`
+	} `json:"nodes"`
+}
+
+type sequencerData struct {
+	Hash      string `json:"hash"`
+	Height    uint64 `json:"height"`
+	Issuer    string `json:"issuer"`
+	Timestamp int64  `json:"timestamp"`
+	StateRoot string `json:"state_root"`
+	TxCount   int    `json:"tx_count"`
+}
+
+type txData struct {
+	Hash      string `json:"hash"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Value     string `json:"value"`
+	Nonce     uint64 `json:"nonce"`
+	SeqHeight uint64 `json:"seq_height"`
+}
+
+func watch(cmd *cobra.Command, args []string) {
+	events := args
+	if len(events) == 0 {
+		events = []string{eventNewSequencer}
+	}
+	var topics [][]string
+	for _, t := range watchTopics {
+		var alternatives []string
+		if t != "" {
+			alternatives = strings.Split(t, ",")
+		}
+		topics = append(topics, alternatives)
+	}
+
+	conn, _, err := websocket.DefaultDialer.Dial(wsServer, nil)
+	panicIfError(err, "failed to connect to the websocket server")
+	defer conn.Close()
+	for _, event := range events {
+		switch event {
+		case eventNewUnit, eventConfirmed, eventNewSequencer, eventTxs, eventLogs:
+		default:
+			panicIfError(fmt.Errorf("unknown event %q", event), "invalid args")
+		}
+		err := conn.WriteJSON(registerMessage{
+			Event:     event,
+			Addresses: watchAddresses,
+			Topics:    topics,
+		})
+		panicIfError(err, "failed to subscribe to "+event)
+	}
+
+	for {
+		_, data, err := conn.ReadMessage()
+		panicIfError(err, "websocket connection closed")
+		if outputFormat == outputJSON {
+			fmt.Println(string(data))
+			continue
+		}
+		if err := printWSMessage(data); err != nil {
+			fmt.Println("undecoded message:", err, string(data))
+		}
+	}
+}
+
+// printWSMessage prints a message of the websocket server in a line per
+// sequencer, tx, log or unit.
+func printWSMessage(data []byte) error {
+	var msg wsMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return err
+	}
+	switch msg.Type {
+	case eventNewUnit, eventConfirmed:
+		for _, node := range msg.Nodes {
+			fmt.Printf("%s\t%s\t%s\n", msg.Type, node.Data.Unit, node.Data.UnitS)
+		}
+	case eventNewSequencer:
+		var seq sequencerData
+		if err := json.Unmarshal(msg.Data, &seq); err != nil {
+			return err
+		}
+		fmt.Printf("%s\theight=%d\thash=%s\tissuer=%s\ttxs=%d\tstate_root=%s\n",
+			msg.Type, seq.Height, seq.Hash, seq.Issuer, seq.TxCount, seq.StateRoot)
+	case eventTxs:
+		var txs []txData
+		if err := json.Unmarshal(msg.Data, &txs); err != nil {
+			return err
+		}
+		for _, tx := range txs {
+			fmt.Printf("%s\theight=%d\thash=%s\tfrom=%s\tto=%s\tvalue=%s\tnonce=%d\n",
+				msg.Type, tx.SeqHeight, tx.Hash, tx.From, tx.To, tx.Value, tx.Nonce)
+		}
+	case eventLogs:
+		var logs []logResponse
+		if err := json.Unmarshal(msg.Data, &logs); err != nil {
+			return err
+		}
+		for _, l := range logs {
+			fmt.Printf("%s\theight=%d\ttx=%s\tindex=%d\taddress=%s\ttopics=%s\tdata=%s\n",
+				msg.Type, l.SeqHeight, l.TxHash, l.LogIndex, l.Address, strings.Join(l.Topics, ","), l.Data)
+		}
+	default:
+		return fmt.Errorf("unknown message type %q", msg.Type)
+	}
+	return nil
+}
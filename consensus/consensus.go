@@ -5,9 +5,31 @@ import "github.com/annchain/OG/types"
 type IDag interface {
 	LatestSequencer() *types.Sequencer
 	GetSequencer(hash types.Hash, id uint64) *types.Sequencer
+	GetDelegates() []types.Address
+	// GetDelegatesAt returns the delegate set in the state at root, or an
+	// error if the state is not available.
+	GetDelegatesAt(root types.Hash) ([]types.Address, error)
 }
 
+// ConsensusEngine decides who is allowed to issue the next sequencer and
+// verifies the sequencers issued by others.
 type ConsensusEngine interface {
-	// TODO
-	// consider if this engine interface should be implemented.
+	// VerifySequencer checks if seq is issued by the right proposer in time,
+	// prev is the sequencer right before seq. The delegate set is read from
+	// the state root of prev.
+	VerifySequencer(seq *types.Sequencer, prev *types.Sequencer) error
+
+	// CheckDeadline checks if seq is received within the slot it is issued
+	// in, by the local clock. Every sequencer extending the local head is
+	// checked, except the ones a node catches up with from its peers, or
+	// imports from a file, which are received long after their slots.
+	CheckDeadline(seq *types.Sequencer, prev *types.Sequencer) error
+
+	// VerifyHeader checks a sequencer header the same way as VerifySequencer,
+	// with the delegate set at the state root of prev given by the caller.
+	VerifyHeader(seq *types.SequencerHeader, prev *types.SequencerHeader, delegates []types.Address) error
+
+	// Proposer returns the address which is allowed to issue the sequencer
+	// following prev at timestamp (unix milliseconds).
+	Proposer(prev *types.Sequencer, timestamp int64) (types.Address, error)
 }
@@ -1,25 +1,53 @@
 package dpos
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/annchain/OG/consensus"
 	"github.com/annchain/OG/types"
 	log "github.com/sirupsen/logrus"
 )
 
+type DposConfig struct {
+	// SlotDurationMs is the time window given to a delegate to issue its
+	// sequencer. Once the window passed, the next delegate takes over.
+	SlotDurationMs int64
+	// MaxClockDriftMs is the max time a sequencer's timestamp is allowed
+	// to be ahead of the local clock, and a new sequencer is allowed to be
+	// received after its slot ends.
+	MaxClockDriftMs int64
+}
+
+func DefaultDposConfig() DposConfig {
+	return DposConfig{
+		SlotDurationMs:  5000,
+		MaxClockDriftMs: 1000,
+	}
+}
+
 // Dpos Delegate Proof-of-Stake
+//
+// The delegates take turns to issue sequencers. The delegate scheduled for
+// height h is delegates[h % n]. Time after the previous sequencer is divided
+// into slots of SlotDurationMs, and each missed slot passes the turn to the
+// next delegate, so the proposer of a sequencer is
+//
+//	delegates[(h + (seq.Timestamp - prev.Timestamp) / SlotDurationMs) % n]
 type Dpos struct {
-	dag      consensus.IDag
-	quitCh   chan bool
-	proposer *types.Address
+	conf   DposConfig
+	dag    consensus.IDag
+	quitCh chan bool
 }
 
-type ConsensusState uint64
-
-func NewDpos(dag consensus.IDag, addr *types.Address) *Dpos {
+func NewDpos(conf DposConfig, dag consensus.IDag) *Dpos {
+	if conf.SlotDurationMs <= 0 {
+		conf.SlotDurationMs = DefaultDposConfig().SlotDurationMs
+	}
 	return &Dpos{
-		dag:      dag,
-		quitCh:   make(chan bool),
-		proposer: addr,
+		conf:   conf,
+		dag:    dag,
+		quitCh: make(chan bool),
 	}
 }
 
@@ -49,25 +77,110 @@ func (d *Dpos) loop() {
 	}
 }
 
+// Proposer returns the delegate which is allowed to issue the sequencer
+// following prev at timestamp.
+func (d *Dpos) Proposer(prev *types.Sequencer, timestamp int64) (types.Address, error) {
+	if prev == nil {
+		return types.Address{}, fmt.Errorf("previous sequencer is nil")
+	}
+	delegates, err := d.delegatesAt(prev)
+	if err != nil {
+		return types.Address{}, err
+	}
+	return d.proposer(prev.GetHead(), timestamp, delegates)
+}
+
+// delegatesAt reads the delegate set at the state root of prev, which the
+// sequencer following prev is checked against.
+func (d *Dpos) delegatesAt(prev *types.Sequencer) ([]types.Address, error) {
+	delegates, err := d.dag.GetDelegatesAt(prev.StateRoot)
+	if err != nil {
+		return nil, fmt.Errorf("get delegates at %s error: %v", prev, err)
+	}
+	return delegates, nil
+}
+
+func (d *Dpos) proposer(prev *types.SequencerHeader, timestamp int64, delegates []types.Address) (types.Address, error) {
+	if timestamp <= prev.Timestamp {
+		return types.Address{}, fmt.Errorf("timestamp %d is not after previous sequencer's %d", timestamp, prev.Timestamp)
+	}
+	if len(delegates) == 0 {
+		return types.Address{}, fmt.Errorf("no delegates found")
+	}
+	missed := uint64((timestamp - prev.Timestamp) / d.conf.SlotDurationMs)
+	index := (prev.Height + 1 + missed) % uint64(len(delegates))
+	return delegates[index], nil
+}
+
 //VerifySequencer verify received sequencer
-func (d *Dpos) VerifySequencer(seq *types.Sequencer) error {
-	//todo
-	return nil
+func (d *Dpos) VerifySequencer(seq *types.Sequencer, prev *types.Sequencer) error {
+	if prev == nil {
+		return fmt.Errorf("previous sequencer of %s not found", seq)
+	}
+	delegates, err := d.delegatesAt(prev)
+	if err != nil {
+		return err
+	}
+	return d.VerifyHeader(seq.GetHead(), prev.GetHead(), delegates)
 }
 
-//newSequncer create new sequencer if it is our time
-func (d *Dpos) newSequncer() (seq *types.Sequencer) {
-	//todo
+// CheckDeadline checks if seq is received before the slot it is issued in
+// ends, so that a delegate can't issue a sequencer for its passed slot.
+func (d *Dpos) CheckDeadline(seq *types.Sequencer, prev *types.Sequencer) error {
+	if prev == nil {
+		return fmt.Errorf("previous sequencer of %s not found", seq)
+	}
+	if err := d.checkTimestamp(seq.GetHead(), prev.GetHead()); err != nil {
+		return err
+	}
+	missed := (seq.Timestamp - prev.Timestamp) / d.conf.SlotDurationMs
+	slotEnd := prev.Timestamp + (missed+1)*d.conf.SlotDurationMs
+	deadline := slotEnd + d.conf.MaxClockDriftMs
+	if now := nowMs(); now > deadline {
+		return fmt.Errorf("sequencer of the slot ending at %d is received at %d after deadline %d", slotEnd, now, deadline)
+	}
 	return nil
 }
 
-//checkDeadLine  check whether the sequencer proposed  in valid time
-func (d *Dpos) checkDeadLine(seq *types.Sequencer) error {
+// VerifyHeader verifies a sequencer header with the delegate set read from
+// the state root of prev. Light nodes have no state and learn the delegates
+// from state proofs.
+func (d *Dpos) VerifyHeader(seq *types.SequencerHeader, prev *types.SequencerHeader, delegates []types.Address) error {
+	if seq.Height != prev.Height+1 {
+		return fmt.Errorf("height mismatch, previous: %d, current: %d", prev.Height, seq.Height)
+	}
+	if err := d.checkTimestamp(seq, prev); err != nil {
+		return err
+	}
+	return d.checkProposer(seq, prev, delegates)
+}
+
+// checkTimestamp checks if the sequencer is issued after prev and not
+// ahead of the local clock.
+func (d *Dpos) checkTimestamp(seq *types.SequencerHeader, prev *types.SequencerHeader) error {
+	if seq.Timestamp <= prev.Timestamp {
+		return fmt.Errorf("sequencer timestamp %d is not after previous sequencer's %d", seq.Timestamp, prev.Timestamp)
+	}
+	limit := nowMs() + d.conf.MaxClockDriftMs
+	if seq.Timestamp > limit {
+		return fmt.Errorf("sequencer timestamp %d is ahead of local clock limit %d", seq.Timestamp, limit)
+	}
 	return nil
 }
 
-//
-func (d *Dpos) checkProposer(seq *types.Sequencer) (state ConsensusState, err error) {
-	//todo
-	return 0, nil
+func nowMs() int64 {
+	return time.Now().UnixNano() / int64(time.Millisecond)
+}
+
+// checkProposer check whether the sequencer is issued by the delegate
+// scheduled for its slot.
+func (d *Dpos) checkProposer(seq *types.SequencerHeader, prev *types.SequencerHeader, delegates []types.Address) error {
+	proposer, err := d.proposer(prev, seq.Timestamp, delegates)
+	if err != nil {
+		return err
+	}
+	if seq.Issuer != proposer {
+		return fmt.Errorf("out-of-turn issuer %s, expected %s", seq.Issuer.Hex(), proposer.Hex())
+	}
+	return nil
 }
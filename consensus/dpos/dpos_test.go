@@ -0,0 +1,180 @@
+package dpos
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/annchain/OG/types"
+)
+
+type dummyDag struct {
+	delegates []types.Address
+	// roots keeps the delegate sets at the other state roots than the
+	// empty one.
+	roots map[types.Hash][]types.Address
+}
+
+func (d *dummyDag) LatestSequencer() *types.Sequencer {
+	return nil
+}
+
+func (d *dummyDag) GetSequencer(hash types.Hash, id uint64) *types.Sequencer {
+	return nil
+}
+
+func (d *dummyDag) GetDelegates() []types.Address {
+	return d.delegates
+}
+
+func (d *dummyDag) GetDelegatesAt(root types.Hash) ([]types.Address, error) {
+	if root == (types.Hash{}) {
+		return d.delegates, nil
+	}
+	delegates, ok := d.roots[root]
+	if !ok {
+		return nil, fmt.Errorf("state at %s not found", root.Hex())
+	}
+	return delegates, nil
+}
+
+func newTestDpos() (*Dpos, []types.Address) {
+	delegates := []types.Address{
+		types.HexToAddress("0x01"),
+		types.HexToAddress("0x02"),
+		types.HexToAddress("0x03"),
+	}
+	d := NewDpos(DposConfig{SlotDurationMs: 1000, MaxClockDriftMs: 1000}, &dummyDag{delegates: delegates})
+	return d, delegates
+}
+
+func TestDposProposer(t *testing.T) {
+	d, delegates := newTestDpos()
+	prev := &types.Sequencer{TxBase: types.TxBase{Height: 4}, Timestamp: 10000}
+
+	cases := []struct {
+		timestamp int64
+		expected  types.Address
+	}{
+		{10001, delegates[2]},
+		{10999, delegates[2]},
+		// one slot missed, the turn passes to the next delegate.
+		{11000, delegates[0]},
+		{12500, delegates[1]},
+		{13000, delegates[2]},
+	}
+	for _, c := range cases {
+		proposer, err := d.Proposer(prev, c.timestamp)
+		if err != nil {
+			t.Fatalf("timestamp %d: %v", c.timestamp, err)
+		}
+		if proposer != c.expected {
+			t.Errorf("timestamp %d: proposer %s, expected %s", c.timestamp, proposer.Hex(), c.expected.Hex())
+		}
+	}
+
+	if _, err := d.Proposer(prev, prev.Timestamp); err == nil {
+		t.Error("expected error on timestamp not after previous sequencer")
+	}
+	if _, err := NewDpos(DefaultDposConfig(), &dummyDag{}).Proposer(prev, prev.Timestamp+1); err == nil {
+		t.Error("expected error on empty delegate set")
+	}
+}
+
+func TestDposVerifySequencer(t *testing.T) {
+	d, delegates := newTestDpos()
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	prev := &types.Sequencer{TxBase: types.TxBase{Height: 1}, Timestamp: now - 100}
+
+	newSeq := func(issuer types.Address, height uint64, timestamp int64) *types.Sequencer {
+		return &types.Sequencer{TxBase: types.TxBase{Height: height}, Issuer: issuer, Timestamp: timestamp}
+	}
+
+	cases := []struct {
+		name string
+		seq  *types.Sequencer
+		ok   bool
+	}{
+		{"in turn", newSeq(delegates[2], 2, now), true},
+		{"out of turn", newSeq(delegates[0], 2, now), false},
+		{"wrong height", newSeq(delegates[2], 3, now), false},
+		{"timestamp not increasing", newSeq(delegates[2], 2, prev.Timestamp), false},
+		{"timestamp beyond deadline", newSeq(delegates[2], 2, now+5000), false},
+	}
+	for _, c := range cases {
+		err := d.VerifySequencer(c.seq, prev)
+		if c.ok && err != nil {
+			t.Errorf("%s: unexpected error: %v", c.name, err)
+		}
+		if !c.ok && err == nil {
+			t.Errorf("%s: expected error", c.name)
+		}
+	}
+
+	if err := d.VerifySequencer(newSeq(delegates[2], 2, now), nil); err == nil {
+		t.Error("expected error on missing previous sequencer")
+	}
+}
+
+func TestDposDelegatesAtPrev(t *testing.T) {
+	d, delegates := newTestDpos()
+	root := types.HexToHash("0x01")
+	d.dag.(*dummyDag).roots = map[types.Hash][]types.Address{
+		root: {delegates[2], delegates[1], delegates[0]},
+	}
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	prev := &types.Sequencer{TxBase: types.TxBase{Height: 1}, Timestamp: now - 100, StateRoot: root}
+
+	// delegates[2] is in turn by the head state, but not at the root of prev.
+	seq := &types.Sequencer{TxBase: types.TxBase{Height: 2}, Issuer: delegates[2], Timestamp: now}
+	if err := d.VerifySequencer(seq, prev); err == nil {
+		t.Error("expected error on the delegate out of turn at the root of prev")
+	}
+	seq.Issuer = delegates[0]
+	if err := d.VerifySequencer(seq, prev); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if proposer, err := d.Proposer(prev, now); err != nil || proposer != delegates[0] {
+		t.Errorf("proposer %s, expected %s: %v", proposer.Hex(), delegates[0].Hex(), err)
+	}
+
+	prev.StateRoot = types.HexToHash("0x02")
+	if err := d.VerifySequencer(seq, prev); err == nil {
+		t.Error("expected error on the state of prev not available")
+	}
+}
+
+func TestDposCheckDeadline(t *testing.T) {
+	d, delegates := newTestDpos()
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	// the slot of now ends at now+500, the one before at now-500.
+	prev := &types.Sequencer{TxBase: types.TxBase{Height: 1}, Timestamp: now - 9500}
+
+	newSeq := func(timestamp int64) *types.Sequencer {
+		return &types.Sequencer{TxBase: types.TxBase{Height: 2}, Issuer: delegates[2], Timestamp: timestamp}
+	}
+	cases := []struct {
+		name string
+		seq  *types.Sequencer
+		ok   bool
+	}{
+		{"in the current slot", newSeq(now), true},
+		{"in the passed slot", newSeq(prev.Timestamp + 500), false},
+		{"in the slot just passed within clock drift", newSeq(now - 1000), true},
+		{"in the slot passed beyond clock drift", newSeq(now - 2000), false},
+		{"ahead of the clock", newSeq(now + 5000), false},
+		{"not after prev", newSeq(prev.Timestamp), false},
+	}
+	for _, c := range cases {
+		err := d.CheckDeadline(c.seq, prev)
+		if c.ok && err != nil {
+			t.Errorf("%s: unexpected error: %v", c.name, err)
+		}
+		if !c.ok && err == nil {
+			t.Errorf("%s: expected error", c.name)
+		}
+	}
+	if err := d.CheckDeadline(newSeq(now), nil); err == nil {
+		t.Error("expected error on missing previous sequencer")
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/annchain/OG/common/math"
 	"github.com/annchain/OG/ogdb"
 	"github.com/annchain/OG/types"
+	ethtypes "github.com/annchain/OG/vm/eth/core/types"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -16,7 +17,10 @@ var (
 	prefixGenesisKey   = []byte("genesis")
 	prefixLatestSeqKey = []byte("latestseq")
 
-	prefixReceiptKey = []byte("rp")
+	prefixReceiptKey  = []byte("rp")
+	prefixLogBloomKey = []byte("lb")
+
+	prefixStateRootKey = []byte("sr")
 
 	prefixTransactionKey     = []byte("tx")
 	prefixTxHashFlowKey      = []byte("fl")
@@ -31,6 +35,9 @@ var (
 	prefixAddressBalanceKey = []byte("ba")
 
 	prefixConfirmtime = []byte("cf")
+
+	prefixLightHeaderKey = []byte("lh")
+	prefixLightHeadKey   = []byte("lighthead")
 )
 
 // TODO encode uint to specific length bytes
@@ -47,6 +54,14 @@ func receiptKey(seqID uint64) []byte {
 	return append(prefixReceiptKey, encodeUint64(seqID)...)
 }
 
+func logBloomKey(seqID uint64) []byte {
+	return append(prefixLogBloomKey, encodeUint64(seqID)...)
+}
+
+func stateRootKey(seqID uint64) []byte {
+	return append(prefixStateRootKey, encodeUint64(seqID)...)
+}
+
 func transactionKey(hash types.Hash) []byte {
 	return append(prefixTransactionKey, hash.ToBytes()...)
 }
@@ -76,6 +91,14 @@ func txIndexKey(seqID uint64) []byte {
 	return append(prefixTxIndexKey, encodeUint64(seqID)...)
 }
 
+func lightHeaderKey(seqID uint64) []byte {
+	return append(prefixLightHeaderKey, encodeUint64(seqID)...)
+}
+
+func lightHeadKey() []byte {
+	return prefixLightHeadKey
+}
+
 type Accessor struct {
 	db ogdb.Database
 }
@@ -100,12 +123,12 @@ func (da *Accessor) ReadGenesis() *types.Sequencer {
 }
 
 // WriteGenesis writes geneis into db.
-func (da *Accessor) WriteGenesis(genesis *types.Sequencer) error {
+func (da *Accessor) WriteGenesis(putter ogdb.Putter, genesis *types.Sequencer) error {
 	data, err := genesis.MarshalMsg(nil)
 	if err != nil {
 		return err
 	}
-	return da.db.Put(genesisKey(), data)
+	return putter.Put(genesisKey(), data)
 }
 
 // ReadLatestSequencer get latest sequencer from db.
@@ -123,13 +146,13 @@ func (da *Accessor) ReadLatestSequencer() *types.Sequencer {
 	return &seq
 }
 
-// WriteGenesis writes latest sequencer into db.
-func (da *Accessor) WriteLatestSequencer(seq *types.Sequencer) error {
+// WriteLatestSequencer writes latest sequencer into db.
+func (da *Accessor) WriteLatestSequencer(putter ogdb.Putter, seq *types.Sequencer) error {
 	data, err := seq.MarshalMsg(nil)
 	if err != nil {
 		return err
 	}
-	return da.db.Put(latestSequencerKey(), data)
+	return putter.Put(latestSequencerKey(), data)
 }
 
 // ReadTransaction get tx or sequencer from ogdb.
@@ -173,15 +196,20 @@ func (da *Accessor) ReadTxByNonce(addr types.Address, nonce uint64) types.Txi {
 }
 
 // WriteTxHashByNonce writes tx hash into db and construct key with address and nonce.
-func (da *Accessor) WriteTxHashByNonce(addr types.Address, nonce uint64, hash types.Hash) error {
+func (da *Accessor) WriteTxHashByNonce(putter ogdb.Putter, addr types.Address, nonce uint64, hash types.Hash) error {
 	data := hash.ToBytes()
-	err := da.db.Put(txHashFlowKey(addr, nonce), data)
+	err := putter.Put(txHashFlowKey(addr, nonce), data)
 	if err != nil {
 		return fmt.Errorf("write tx hash flow to db err: %v", err)
 	}
 	return nil
 }
 
+// DeleteTxHashByNonce deletes the tx hash indexed by address and nonce.
+func (da *Accessor) DeleteTxHashByNonce(addr types.Address, nonce uint64) error {
+	return da.db.Delete(txHashFlowKey(addr, nonce))
+}
+
 // ReadAddrLatestNonce get latest nonce of an address
 func (da *Accessor) ReadAddrLatestNonce(addr types.Address) (uint64, error) {
 	has, _ := da.HasAddrLatestNonce(addr)
@@ -204,12 +232,12 @@ func (da *Accessor) HasAddrLatestNonce(addr types.Address) (bool, error) {
 	return da.db.Has(addrLatestNonceKey(addr))
 }
 
-func (da *Accessor) writeConfirmTime(cf *types.ConfirmTime) error {
+func (da *Accessor) writeConfirmTime(putter ogdb.Putter, cf *types.ConfirmTime) error {
 	data, err := cf.MarshalMsg(nil)
 	if err != nil {
 		return err
 	}
-	err = da.db.Put(confirmTimeKey(cf.SeqHeight), data)
+	err = putter.Put(confirmTimeKey(cf.SeqHeight), data)
 	if err != nil {
 		return fmt.Errorf("write tx to db batch err: %v", err)
 	}
@@ -230,13 +258,17 @@ func (da *Accessor) readConfirmTime(SeqHeight uint64) *types.ConfirmTime {
 	return &cf
 }
 
+func (da *Accessor) deleteConfirmTime(SeqHeight uint64) error {
+	return da.db.Delete(confirmTimeKey(SeqHeight))
+}
+
 // WriteReceipts write a receipt map into db.
-func (da *Accessor) WriteReceipts(seqID uint64, receipts ReceiptSet) error {
+func (da *Accessor) WriteReceipts(putter ogdb.Putter, seqID uint64, receipts ReceiptSet) error {
 	data, err := receipts.MarshalMsg(nil)
 	if err != nil {
 		return fmt.Errorf("marshal seq%d's receipts err: %v", seqID, err)
 	}
-	err = da.db.Put(receiptKey(seqID), data)
+	err = putter.Put(receiptKey(seqID), data)
 	if err != nil {
 		return fmt.Errorf("write seq%d's receipts err: %v", seqID, err)
 	}
@@ -261,6 +293,71 @@ func (da *Accessor) ReadReceipt(seqID uint64, hash types.Hash) *Receipt {
 	return receipt
 }
 
+// ReadReceipts reads all the receipts of the sequencer at seqID.
+func (da *Accessor) ReadReceipts(seqID uint64) (ReceiptSet, error) {
+	data, _ := da.db.Get(receiptKey(seqID))
+	if len(data) == 0 {
+		return nil, fmt.Errorf("receipts of seq%d not found", seqID)
+	}
+	var receipts ReceiptSet
+	_, err := receipts.UnmarshalMsg(data)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal seq%d's receipts err: %v", seqID, err)
+	}
+	return receipts, nil
+}
+
+// DeleteReceipts deletes all the receipts of the sequencer at seqID.
+func (da *Accessor) DeleteReceipts(seqID uint64) error {
+	return da.db.Delete(receiptKey(seqID))
+}
+
+// WriteLogBloom writes the bloom of all the logs in the sequencer at seqID.
+func (da *Accessor) WriteLogBloom(putter ogdb.Putter, seqID uint64, bloom ethtypes.Bloom) error {
+	err := putter.Put(logBloomKey(seqID), bloom.Bytes())
+	if err != nil {
+		return fmt.Errorf("write seq%d's log bloom err: %v", seqID, err)
+	}
+	return nil
+}
+
+// ReadLogBloom reads the log bloom of the sequencer at seqID, it returns
+// false if the sequencer has no logs.
+func (da *Accessor) ReadLogBloom(seqID uint64) (ethtypes.Bloom, bool) {
+	data, _ := da.db.Get(logBloomKey(seqID))
+	if len(data) == 0 {
+		return ethtypes.Bloom{}, false
+	}
+	return ethtypes.BytesToBloom(data), true
+}
+
+// DeleteLogBloom deletes the log bloom of the sequencer at seqID.
+func (da *Accessor) DeleteLogBloom(seqID uint64) error {
+	return da.db.Delete(logBloomKey(seqID))
+}
+
+// WriteStateRoot writes the state root committed by the sequencer at seqID.
+func (da *Accessor) WriteStateRoot(putter ogdb.Putter, seqID uint64, root types.Hash) error {
+	err := putter.Put(stateRootKey(seqID), root.ToBytes())
+	if err != nil {
+		return fmt.Errorf("write seq%d's state root err: %v", seqID, err)
+	}
+	return nil
+}
+
+// ReadStateRoot reads the state root committed by the sequencer at seqID.
+func (da *Accessor) ReadStateRoot(seqID uint64) (types.Hash, error) {
+	data, _ := da.db.Get(stateRootKey(seqID))
+	if len(data) != types.HashLength {
+		return types.Hash{}, fmt.Errorf("state root of seq%d not found", seqID)
+	}
+	return types.BytesToHash(data), nil
+}
+
+func (da *Accessor) DeleteStateRoot(seqID uint64) error {
+	return da.db.Delete(stateRootKey(seqID))
+}
+
 // WriteTransaction write the tx or sequencer into ogdb.
 func (da *Accessor) WriteTransaction(putter ogdb.Putter, tx types.Txi) error {
 	var prefix, data []byte
@@ -281,7 +378,7 @@ func (da *Accessor) WriteTransaction(putter ogdb.Putter, tx types.Txi) error {
 		return fmt.Errorf("marshal tx %s err: %v", tx.GetTxHash().String(), err)
 	}
 	data = append(prefix, data...)
-	err = da.db.Put(transactionKey(tx.GetTxHash()), data)
+	err = putter.Put(transactionKey(tx.GetTxHash()), data)
 	if err != nil {
 		return fmt.Errorf("write tx to db batch err: %v", err)
 	}
@@ -375,12 +472,58 @@ func (da *Accessor) ReadSequencerByHeight(SeqHeight uint64) (*types.Sequencer, e
 }
 
 // WriteSequencerByHeight stores the sequencer into db and indexed by its id.
-func (da *Accessor) WriteSequencerByHeight(seq *types.Sequencer) error {
+func (da *Accessor) WriteSequencerByHeight(putter ogdb.Putter, seq *types.Sequencer) error {
 	data, err := seq.MarshalMsg(nil)
 	if err != nil {
 		return err
 	}
-	return da.db.Put(seqHeightKey(seq.Height), data)
+	return putter.Put(seqHeightKey(seq.Height), data)
+}
+
+// DeleteSequencerByHeight deletes the sequencer indexed by SeqHeight.
+func (da *Accessor) DeleteSequencerByHeight(SeqHeight uint64) error {
+	return da.db.Delete(seqHeightKey(SeqHeight))
+}
+
+// ReadLightHeader get the sequencer header verified by a light node from db.
+// return nil if there is no header of height.
+func (da *Accessor) ReadLightHeader(height uint64) *types.SequencerHeader {
+	data, _ := da.db.Get(lightHeaderKey(height))
+	if len(data) == 0 {
+		return nil
+	}
+	var header types.SequencerHeader
+	_, err := header.UnmarshalMsg(data)
+	if err != nil {
+		return nil
+	}
+	return &header
+}
+
+// WriteLightHeader stores the sequencer header verified by a light node
+// and indexed by its height.
+func (da *Accessor) WriteLightHeader(putter ogdb.Putter, header *types.SequencerHeader) error {
+	data, err := header.MarshalMsg(nil)
+	if err != nil {
+		return err
+	}
+	return putter.Put(lightHeaderKey(header.Height), data)
+}
+
+// ReadLightHead get the latest header verified by a light node from db.
+// return nil if there is no header verified.
+func (da *Accessor) ReadLightHead() *types.SequencerHeader {
+	data, _ := da.db.Get(lightHeadKey())
+	if len(data) != 8 {
+		return nil
+	}
+	return da.ReadLightHeader(binary.BigEndian.Uint64(data))
+}
+
+// WriteLightHead writes the height of the latest header verified by a light
+// node into db.
+func (da *Accessor) WriteLightHead(putter ogdb.Putter, height uint64) error {
+	return putter.Put(lightHeadKey(), encodeUint64(height))
 }
 
 // ReadIndexedTxHashs get a list of txs that is confirmed by the sequencer that
@@ -400,12 +543,18 @@ func (da *Accessor) ReadIndexedTxHashs(SeqHeight uint64) (*types.Hashes, error)
 
 // WriteIndexedTxHashs stores a list of tx hashs. These related hashs are all
 // confirmed by sequencer that holds the id 'SeqHeight'.
-func (da *Accessor) WriteIndexedTxHashs(SeqHeight uint64, hashs *types.Hashes) error {
+func (da *Accessor) WriteIndexedTxHashs(putter ogdb.Putter, SeqHeight uint64, hashs *types.Hashes) error {
 	data, err := hashs.MarshalMsg(nil)
 	if err != nil {
 		return err
 	}
-	return da.db.Put(txIndexKey(SeqHeight), data)
+	return putter.Put(txIndexKey(SeqHeight), data)
+}
+
+// DeleteIndexedTxHashs deletes the tx hashs confirmed by sequencer
+// SeqHeight.
+func (da *Accessor) DeleteIndexedTxHashs(SeqHeight uint64) error {
+	return da.db.Delete(txIndexKey(SeqHeight))
 }
 
 /**
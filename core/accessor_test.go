@@ -67,7 +67,7 @@ func newTestSeq(nonce uint64) *types.Sequencer {
 	pk, _ := crypto.PrivateKeyFromString(testPkSecp1)
 	addr := newTestAddress(pk)
 
-	seq := txCreator.NewSignedSequencer(addr, nonce, []types.Hash{}, nonce, pk)
+	seq := txCreator.NewSignedSequencer(addr, nonce, nonce, pk)
 	seq.SetHash(seq.CalcTxHash())
 
 	return seq.(*types.Sequencer)
@@ -88,7 +88,7 @@ func TestTransactionStorage(t *testing.T) {
 
 	// test tx read write
 	tx := newTestUnsealTx(0)
-	err = acc.WriteTransaction(db.NewBatch(), tx)
+	err = acc.WriteTransaction(db, tx)
 	if err != nil {
 		t.Fatalf("write tx %s failed: %v", tx.GetTxHash().String(), err)
 	}
@@ -111,7 +111,7 @@ func TestTransactionStorage(t *testing.T) {
 
 	// test sequencer read write
 	seq := newTestSeq(1)
-	err = acc.WriteTransaction(db.NewBatch(), seq)
+	err = acc.WriteTransaction(db, seq)
 	if err != nil {
 		t.Fatalf("write seq %s failed: %v", seq.GetTxHash().String(), err)
 	}
@@ -134,7 +134,7 @@ func TestGenesisStorage(t *testing.T) {
 	acc := core.NewAccessor(db)
 
 	genesis := newTestSeq(0)
-	err = acc.WriteGenesis(genesis)
+	err = acc.WriteGenesis(db, genesis)
 	if err != nil {
 		t.Fatalf("write genesis error: %v", err)
 	}
@@ -157,7 +157,7 @@ func TestLatestSeqStorage(t *testing.T) {
 	acc := core.NewAccessor(db)
 
 	latestSeq := newTestSeq(0)
-	err = acc.WriteLatestSequencer(latestSeq)
+	err = acc.WriteLatestSequencer(db, latestSeq)
 	if err != nil {
 		t.Fatalf("write latest sequencer error: %v", err)
 	}
@@ -137,7 +137,7 @@ func (af *AccountFlow) Add(tx types.Txi) error {
 		log.WithField("tx", tx).Errorf("add tx that has same nonce")
 		return fmt.Errorf("already exists")
 	}
-	err := af.balance.TrySubBalance(tx.GetValue())
+	err := af.balance.TrySubBalance(txCost(tx))
 	if err != nil {
 		return err
 	}
@@ -152,7 +152,7 @@ func (af *AccountFlow) Remove(nonce uint64) error {
 	if tx == nil {
 		return nil
 	}
-	err := af.balance.TryRemoveValue(tx.GetValue())
+	err := af.balance.TryRemoveValue(txCost(tx))
 	if err != nil {
 		return err
 	}
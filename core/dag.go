@@ -3,6 +3,7 @@ package core
 import (
 	"bytes"
 	"fmt"
+	"sort"
 	"time"
 
 	// "fmt"
@@ -11,6 +12,7 @@ import (
 	"github.com/annchain/OG/common/math"
 	"github.com/annchain/OG/core/state"
 	"github.com/annchain/OG/ogdb"
+	"github.com/annchain/OG/trie"
 	"github.com/annchain/OG/types"
 	evm "github.com/annchain/OG/vm/eth/core/vm"
 	"github.com/annchain/OG/vm/ovm"
@@ -29,10 +31,34 @@ var (
 	DefaultCoinbase = types.HexToAddress("0x1234567812345678AABBCCDDEEFF998877665544")
 )
 
-type DagConfig struct{}
+type DagConfig struct {
+	// GenesisDelegates is the delegate set written into the genesis state.
+	// It stays active until MinDelegates candidates have MinStake.
+	GenesisDelegates []types.Address
+	// MaxDelegates is the max size of the delegate set elected from the
+	// candidates.
+	MaxDelegates int
+	// MinDelegates is the min number of candidates with MinStake needed to
+	// elect a new delegate set.
+	MinDelegates int
+	// MinStake is the min stake a candidate needs to be elected.
+	MinStake uint64
+	// GCMode is GCModeArchive or GCModePrune, archive if not set.
+	GCMode string
+	// StateKeepRecent is the number of latest sequencers whose state is
+	// kept by a pruning dag.
+	StateKeepRecent uint64
+	// StateCheckpointInterval is the interval of the sequencer heights
+	// whose state is kept forever by a pruning dag.
+	StateCheckpointInterval uint64
+	// TrieCacheMB is the max size of the trie nodes a pruning dag holds
+	// in memory.
+	TrieCacheMB int
+}
 
 type Dag struct {
-	conf DagConfig
+	conf        DagConfig
+	statedbConf state.StateDBConfig
 
 	db ogdb.Database
 	// TODO
@@ -40,10 +66,16 @@ type Dag struct {
 	oldDb    ogdb.Database
 	accessor *Accessor
 	statedb  *state.StateDB
+	pruner   *statePruner // nil for an archive dag
 
 	genesis         *types.Sequencer
 	latestSequencer *types.Sequencer
 
+	// importedRoot and importedDelegates keep the delegate set at the
+	// state root of the latest imported sequencer, whose state is not built.
+	importedRoot      types.Hash
+	importedDelegates []types.Address
+
 	txcached *txcached
 
 	close chan struct{}
@@ -55,12 +87,42 @@ type Dag struct {
 func NewDag(conf DagConfig, stateDBConfig state.StateDBConfig, db ogdb.Database, oldDb ogdb.Database) (*Dag, error) {
 	dag := &Dag{}
 
-	statedb, err := state.NewStateDB(stateDBConfig, state.NewDatabase(db))
+	// the state committed by latest sequencer is opened in LoadLastState.
+	statedb, err := state.NewStateDB(stateDBConfig, state.NewDatabase(db), types.Hash{})
 	if err != nil {
 		return nil, fmt.Errorf("create statedb err: %v", err)
 	}
 
+	if conf.MaxDelegates <= 0 {
+		conf.MaxDelegates = DefaultMaxDelegates
+	}
+	if conf.MinDelegates <= 0 {
+		conf.MinDelegates = DefaultMinDelegates
+	}
+	if conf.MinDelegates > conf.MaxDelegates {
+		return nil, fmt.Errorf("min delegates %d exceeds max delegates %d", conf.MinDelegates, conf.MaxDelegates)
+	}
+	if conf.MinStake == 0 {
+		conf.MinStake = DefaultMinStake
+	}
+	switch conf.GCMode {
+	case "", GCModeArchive:
+	case GCModePrune:
+		if conf.StateKeepRecent == 0 {
+			conf.StateKeepRecent = DefaultStateKeepRecent
+		}
+		if conf.StateCheckpointInterval == 0 {
+			conf.StateCheckpointInterval = DefaultStateCheckpointInterval
+		}
+		if conf.TrieCacheMB <= 0 {
+			conf.TrieCacheMB = DefaultTrieCacheMB
+		}
+		dag.pruner = newStatePruner(statedb.Database().TrieDB(), conf)
+	default:
+		return nil, fmt.Errorf("unknown gc mode %q", conf.GCMode)
+	}
 	dag.conf = conf
+	dag.statedbConf = stateDBConfig
 	dag.db = db
 	dag.oldDb = oldDb
 	dag.statedb = statedb
@@ -75,15 +137,131 @@ func NewDag(conf DagConfig, stateDBConfig state.StateDBConfig, db ogdb.Database,
 }
 
 func DefaultDagConfig() DagConfig {
-	return DagConfig{}
+	return DagConfig{
+		MaxDelegates: DefaultMaxDelegates,
+		MinDelegates: DefaultMinDelegates,
+		MinStake:     DefaultMinStake,
+		GCMode:       GCModeArchive,
+	}
 }
 
+// ConfirmBatch is the set of txs confirmed by a sequencer. TxHashes is the
+// order in which the txs are executed, see sortConfirmTxs.
 type ConfirmBatch struct {
 	Seq      *types.Sequencer
 	Batch    map[types.Address]*BatchDetail
 	TxHashes *types.Hashes
 }
 
+// orderedTxs returns the txs of the batch in the order of TxHashes. Every
+// tx in Batch must appear in TxHashes exactly once, and the txs of a
+// sender must be ordered by nonce.
+func (cb *ConfirmBatch) orderedTxs() ([]types.Txi, error) {
+	txs := make(map[types.Hash]types.Txi)
+	for _, batchDetail := range cb.Batch {
+		if batchDetail.TxList == nil {
+			return nil, fmt.Errorf("batch detail does't have txlist")
+		}
+		for _, nonce := range *batchDetail.TxList.keys {
+			txi := batchDetail.TxList.get(nonce)
+			if txi == nil {
+				return nil, fmt.Errorf("can't get tx from txlist, nonce: %d", nonce)
+			}
+			txs[txi.GetTxHash()] = txi
+		}
+	}
+	var hashes types.Hashes
+	if cb.TxHashes != nil {
+		hashes = *cb.TxHashes
+	}
+	if len(hashes) != len(txs) {
+		return nil, fmt.Errorf("batch has %d txs but %d tx hashes", len(txs), len(hashes))
+	}
+	ordered := make([]types.Txi, 0, len(hashes))
+	nonces := make(map[types.Address]uint64)
+	for _, hash := range hashes {
+		txi, ok := txs[hash]
+		if !ok {
+			return nil, fmt.Errorf("tx %s is not in batch or duplicated", hash.String())
+		}
+		delete(txs, hash)
+		if last, ok := nonces[txi.Sender()]; ok && txi.GetNonce() <= last {
+			return nil, fmt.Errorf("tx %s is executed before a tx with lower nonce", hash.String())
+		}
+		nonces[txi.Sender()] = txi.GetNonce()
+		ordered = append(ordered, txi)
+	}
+	return ordered, nil
+}
+
+// sortConfirmTxs returns the canonical execution order of the txs
+// confirmed by a sequencer. It is a topological order of the elders where
+// a tx always goes after its parents and after the tx of the same sender
+// with a lower nonce. Among the txs ready to go, the one with the smallest
+// hash goes first. Sequencers are only used for the ordering and are not
+// included in the result.
+func sortConfirmTxs(elders map[types.Hash]types.Txi) (types.Hashes, error) {
+	children := make(map[types.Hash]types.Hashes)
+	inDegree := make(map[types.Hash]int)
+	addEdge := func(from, to types.Hash) {
+		children[from] = append(children[from], to)
+		inDegree[to]++
+	}
+	senders := make(map[types.Address][]types.Txi)
+	for hash, txi := range elders {
+		if _, ok := inDegree[hash]; !ok {
+			inDegree[hash] = 0
+		}
+		for _, parent := range txi.Parents() {
+			if _, ok := elders[parent]; ok {
+				addEdge(parent, hash)
+			}
+		}
+		senders[txi.Sender()] = append(senders[txi.Sender()], txi)
+	}
+	for _, txis := range senders {
+		sort.Slice(txis, func(i, j int) bool {
+			return txis[i].GetNonce() < txis[j].GetNonce()
+		})
+		for i := 1; i < len(txis); i++ {
+			addEdge(txis[i-1].GetTxHash(), txis[i].GetTxHash())
+		}
+	}
+
+	var ready types.Hashes
+	push := func(hash types.Hash) {
+		i := sort.Search(len(ready), func(i int) bool { return ready[i].Cmp(hash) > 0 })
+		ready = append(ready, types.Hash{})
+		copy(ready[i+1:], ready[i:])
+		ready[i] = hash
+	}
+	for hash, degree := range inDegree {
+		if degree == 0 {
+			push(hash)
+		}
+	}
+	var order types.Hashes
+	visited := 0
+	for len(ready) > 0 {
+		hash := ready[0]
+		ready = ready[1:]
+		visited++
+		if elders[hash].GetType() != types.TxBaseTypeSequencer {
+			order = append(order, hash)
+		}
+		for _, child := range children[hash] {
+			inDegree[child]--
+			if inDegree[child] == 0 {
+				push(child)
+			}
+		}
+	}
+	if visited != len(elders) {
+		return nil, fmt.Errorf("txs can't be ordered, the parents conflict with the nonces")
+	}
+	return order, nil
+}
+
 // BatchDetail describes all the details of a specific address within a
 // sequencer confirmation term.
 // - TxList - represents the txs sent by this addrs, ordered by nonce.
@@ -104,6 +282,13 @@ func (dag *Dag) Start() {
 func (dag *Dag) Stop() {
 	close(dag.close)
 	dag.wg.Wait()
+	if dag.pruner != nil {
+		dag.mu.Lock()
+		if err := dag.pruner.flush(); err != nil {
+			log.WithError(err).Error("failed to flush the latest state")
+		}
+		dag.mu.Unlock()
+	}
 	dag.statedb.Stop()
 	log.Infof("Dag Stopped")
 }
@@ -118,21 +303,72 @@ func (dag *Dag) Init(genesis *types.Sequencer, genesisBalance map[types.Address]
 	if genesis.Height != 0 {
 		return fmt.Errorf("invalheight genesis: height is not zero")
 	}
+	// init genesis balance
+	for addr, value := range genesisBalance {
+		dag.statedb.SetBalance(addr, value)
+	}
+	// init genesis delegates
+	if len(dag.conf.GenesisDelegates) > dag.conf.MaxDelegates {
+		return fmt.Errorf("%d genesis delegates exceed the max %d", len(dag.conf.GenesisDelegates), dag.conf.MaxDelegates)
+	}
+	if len(dag.conf.GenesisDelegates) > 0 {
+		writeDelegates(dag.statedb, dag.conf.GenesisDelegates)
+	}
+	return dag.writeGenesis(genesis)
+}
+
+// InitGenesis inits the genesis sequencer and the genesis state described
+// by the genesis file. The delegates of dag config are not used.
+func (dag *Dag) InitGenesis(g *Genesis) error {
+	if len(g.Validators) > dag.conf.MaxDelegates {
+		return fmt.Errorf("%d genesis validators exceed the max %d delegates", len(g.Validators), dag.conf.MaxDelegates)
+	}
+	genesis, err := g.Sequencer()
+	if err != nil {
+		return err
+	}
+	if err := g.apply(dag.statedb); err != nil {
+		return err
+	}
+	return dag.writeGenesis(genesis)
+}
+
+// writeGenesis commits the genesis state set in statedb and writes the
+// genesis sequencer into db. The state root of genesis is set and hashed
+// if it's empty, or it must be the committed root.
+func (dag *Dag) writeGenesis(genesis *types.Sequencer) error {
 	var err error
 	dbBatch := dag.db.NewBatch()
 
+	// commit genesis state, genesis sequencer keeps the root so that the
+	// state can be opened again after restart.
+	root, err := dag.commitState()
+	if err != nil {
+		return err
+	}
+	if genesis.StateRoot == (types.Hash{}) {
+		genesis.StateRoot = root
+		genesis.SetHash(genesis.CalcTxHash())
+	} else if genesis.StateRoot != root {
+		return fmt.Errorf("genesis state root %s mismatches the committed root %s", genesis.StateRoot.Hex(), root.Hex())
+	}
+
 	// init genesis
-	err = dag.accessor.WriteGenesis(genesis)
+	err = dag.accessor.WriteGenesis(dbBatch, genesis)
 	if err != nil {
 		return err
 	}
 	// init latest sequencer
-	err = dag.accessor.WriteLatestSequencer(genesis)
+	err = dag.accessor.WriteLatestSequencer(dbBatch, genesis)
 	if err != nil {
 		return err
 	}
 
-	err = dag.accessor.WriteSequencerByHeight(genesis)
+	err = dag.accessor.WriteSequencerByHeight(dbBatch, genesis)
+	if err != nil {
+		return err
+	}
+	err = dag.accessor.WriteStateRoot(dbBatch, genesis.Height, genesis.StateRoot)
 	if err != nil {
 		return err
 	}
@@ -141,12 +377,12 @@ func (dag *Dag) Init(genesis *types.Sequencer, genesisBalance map[types.Address]
 	if err != nil {
 		return err
 	}
-	log.Tracef("successfully store genesis: %s", genesis.String())
-
-	// init genesis balance
-	for addr, value := range genesisBalance {
-		dag.statedb.SetBalance(addr, value)
+	err = dbBatch.Write()
+	if err != nil {
+		return fmt.Errorf("write genesis into db error: %v", err)
 	}
+	dag.txcached.add(genesis)
+	log.Tracef("successfully store genesis: %s", genesis.String())
 
 	dag.genesis = genesis
 	dag.latestSequencer = genesis
@@ -157,6 +393,9 @@ func (dag *Dag) Init(genesis *types.Sequencer, genesisBalance map[types.Address]
 
 // LoadLastState load genesis and latestsequencer data from ogdb.
 // return false if there is no genesis stored in the db.
+//
+// A crash in the middle of pushing a sequencer may leave partial data in
+// db, the ledger is repaired back to the latest complete sequencer here.
 func (dag *Dag) LoadLastState() bool {
 	dag.mu.Lock()
 	defer dag.mu.Unlock()
@@ -168,14 +407,108 @@ func (dag *Dag) LoadLastState() bool {
 	dag.genesis = genesis
 	seq := dag.accessor.ReadLatestSequencer()
 	if seq == nil {
-		dag.latestSequencer = genesis
-	} else {
-		dag.latestSequencer = seq
+		seq = genesis
 	}
+	dag.latestSequencer = dag.repairLastState(seq)
 
+	// open the state committed by latest sequencer.
+	if err := dag.statedb.Reset(dag.latestSequencer.StateRoot); err != nil {
+		log.WithError(err).Error("failed to open the state of latest sequencer")
+	}
 	return true
 }
 
+// repairLastState removes the data left by an interrupted push and returns
+// the latest sequencer whose ledger data and state are both complete.
+func (dag *Dag) repairLastState(seq *types.Sequencer) *types.Sequencer {
+	latest := seq
+	// step back until the state trie of the sequencer is found.
+	for seq.Height > 0 {
+		if _, err := dag.statedb.Database().OpenTrie(seq.StateRoot); err == nil {
+			break
+		}
+		prev, err := dag.accessor.ReadSequencerByHeight(seq.Height - 1)
+		if err != nil {
+			log.WithError(err).WithField("height", seq.Height).Error("state is missing and can't be repaired")
+			break
+		}
+		log.WithField("height", seq.Height).Warn("state of sequencer is missing, step back")
+		seq = prev
+	}
+	if seq != latest {
+		if err := dag.accessor.WriteLatestSequencer(dag.db, seq); err != nil {
+			log.WithError(err).Error("failed to repair latest sequencer")
+		}
+	}
+	// remove everything above latest sequencer.
+	dag.truncate(seq.Height)
+	return seq
+}
+
+// truncate deletes the ledger data of all the sequencers above height.
+// Heights are deleted from top to bottom so that an interrupted truncate
+// never leaves a gap, and the rest can be found again on next load.
+func (dag *Dag) truncate(height uint64) {
+	top := height
+	for dag.hasHeight(top + 1) {
+		top++
+	}
+	for h := top; h > height; h-- {
+		log.WithField("height", h).Warn("remove sequencer from ledger")
+		dag.deleteHeight(h)
+	}
+}
+
+// hasHeight checks if there is any ledger data of sequencer at height.
+func (dag *Dag) hasHeight(height uint64) bool {
+	for _, key := range [][]byte{seqHeightKey(height), txIndexKey(height), receiptKey(height), confirmTimeKey(height)} {
+		if has, _ := dag.db.Has(key); has {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteHeight deletes the ledger data of sequencer at height, including
+// the sequencer itself and the txs confirmed by it.
+func (dag *Dag) deleteHeight(height uint64) {
+	var hashes types.Hashes
+	if indexed, err := dag.accessor.ReadIndexedTxHashs(height); err == nil {
+		hashes = append(hashes, *indexed...)
+	}
+	if seq, err := dag.accessor.ReadSequencerByHeight(height); err == nil {
+		hashes = append(hashes, seq.GetTxHash())
+	}
+	for _, hash := range hashes {
+		tx := dag.accessor.ReadTransaction(hash)
+		if tx == nil {
+			continue
+		}
+		if txi := dag.accessor.ReadTxByNonce(tx.Sender(), tx.GetNonce()); txi != nil && txi.GetTxHash() == hash {
+			if err := dag.accessor.DeleteTxHashByNonce(tx.Sender(), tx.GetNonce()); err != nil {
+				log.WithError(err).Error("failed to delete tx hash by nonce")
+			}
+		}
+		if err := dag.accessor.DeleteTransaction(hash); err != nil {
+			log.WithError(err).Error("failed to delete tx")
+		}
+	}
+	// the sequencer index goes last so that an interrupted deletion can
+	// be found again.
+	for _, del := range []func(uint64) error{
+		dag.accessor.DeleteIndexedTxHashs,
+		dag.accessor.DeleteReceipts,
+		dag.accessor.DeleteLogBloom,
+		dag.accessor.DeleteStateRoot,
+		dag.accessor.deleteConfirmTime,
+		dag.accessor.DeleteSequencerByHeight,
+	} {
+		if err := del(height); err != nil {
+			log.WithError(err).WithField("height", height).Error("failed to delete ledger data")
+		}
+	}
+}
+
 // Genesis returns the genesis tx of dag
 func (dag *Dag) Genesis() *types.Sequencer {
 	dag.mu.RLock()
@@ -341,6 +674,19 @@ func (dag *Dag) GetReceipt(hash types.Hash) *Receipt {
 	return dag.accessor.ReadReceipt(seqid, hash)
 }
 
+// GetReceipts returns the receipts of all the txs confirmed by the
+// sequencer at height, keyed by tx hash.
+func (dag *Dag) GetReceipts(height uint64) ReceiptSet {
+	dag.mu.RLock()
+	defer dag.mu.RUnlock()
+
+	receipts, err := dag.accessor.ReadReceipts(height)
+	if err != nil {
+		return nil
+	}
+	return receipts
+}
+
 func (dag *Dag) GetSequencerByHash(hash types.Hash) *types.Sequencer {
 	dag.mu.RLock()
 	defer dag.mu.RUnlock()
@@ -484,6 +830,150 @@ func (dag *Dag) getState(addr types.Address, key types.Hash) types.Hash {
 	return dag.statedb.GetState(addr, key)
 }
 
+// StateRootAt returns the state root committed by the sequencer at height.
+func (dag *Dag) StateRootAt(height uint64) (types.Hash, error) {
+	dag.mu.RLock()
+	defer dag.mu.RUnlock()
+
+	return dag.stateRootAt(height)
+}
+
+func (dag *Dag) stateRootAt(height uint64) (types.Hash, error) {
+	if height > dag.latestSequencer.Height {
+		return types.Hash{}, fmt.Errorf("height %d is above latest height %d", height, dag.latestSequencer.Height)
+	}
+	if root, err := dag.accessor.ReadStateRoot(height); err == nil {
+		return root, nil
+	}
+	// the sequencers pushed before the roots are indexed.
+	seq, err := dag.accessor.ReadSequencerByHeight(height)
+	if err != nil {
+		return types.Hash{}, fmt.Errorf("read sequencer at height %d error: %v", height, err)
+	}
+	return seq.StateRoot, nil
+}
+
+// stateAt opens a read only view of the state committed by the sequencer
+// at height. The caller should stop it after use.
+func (dag *Dag) stateAt(height uint64) (*state.StateDB, error) {
+	root, err := dag.stateRootAt(height)
+	if err != nil {
+		return nil, err
+	}
+	sd, err := state.NewStateDB(dag.statedbConf, dag.statedb.Database(), root)
+	if err != nil {
+		return nil, fmt.Errorf("state of height %d is not available: %v", height, err)
+	}
+	return sd, nil
+}
+
+// GetBalanceAt returns the balance of addr in the state committed by the
+// sequencer at height.
+func (dag *Dag) GetBalanceAt(addr types.Address, height uint64) (*math.BigInt, error) {
+	dag.mu.RLock()
+	defer dag.mu.RUnlock()
+
+	sd, err := dag.stateAt(height)
+	if err != nil {
+		return nil, err
+	}
+	defer sd.Stop()
+	return sd.GetBalance(addr), nil
+}
+
+// GetLatestNonceAt returns the latest nonce of addr in the state committed
+// by the sequencer at height, or types.ErrNonceNotExist if the account
+// doesn't exist in it.
+func (dag *Dag) GetLatestNonceAt(addr types.Address, height uint64) (uint64, error) {
+	dag.mu.RLock()
+	defer dag.mu.RUnlock()
+
+	sd, err := dag.stateAt(height)
+	if err != nil {
+		return 0, err
+	}
+	defer sd.Stop()
+	if !sd.Exist(addr) {
+		return 0, types.ErrNonceNotExist
+	}
+	return sd.GetNonce(addr), nil
+}
+
+// GetStateAt returns the contract storage of key in the state committed by
+// the sequencer at height.
+func (dag *Dag) GetStateAt(addr types.Address, key types.Hash, height uint64) (types.Hash, error) {
+	dag.mu.RLock()
+	defer dag.mu.RUnlock()
+
+	sd, err := dag.stateAt(height)
+	if err != nil {
+		return types.Hash{}, err
+	}
+	defer sd.Stop()
+	return sd.GetState(addr, key), nil
+}
+
+// GetDelegates returns the current delegate set of dpos consensus.
+func (dag *Dag) GetDelegates() []types.Address {
+	dag.mu.RLock()
+	defer dag.mu.RUnlock()
+
+	delegates, err := readDelegates(dag.statedb, dag.conf.MaxDelegates)
+	if err != nil {
+		log.WithError(err).Warn("read delegates error")
+	}
+	return delegates
+}
+
+// GetDelegatesAt returns the delegate set in the state at root, which is
+// the state root of a sequencer in the dag.
+func (dag *Dag) GetDelegatesAt(root types.Hash) ([]types.Address, error) {
+	dag.mu.RLock()
+	defer dag.mu.RUnlock()
+
+	return dag.getDelegatesAt(root)
+}
+
+func (dag *Dag) getDelegatesAt(root types.Hash) ([]types.Address, error) {
+	if dag.importedDelegates != nil && root == dag.importedRoot {
+		return dag.importedDelegates, nil
+	}
+	sd, err := dag.stateAtRoot(root)
+	if err != nil {
+		return nil, fmt.Errorf("state at %s is not available: %v", root.Hex(), err)
+	}
+	defer sd.Stop()
+	return readDelegates(sd, dag.conf.MaxDelegates)
+}
+
+func (dag *Dag) stateAtRoot(root types.Hash) (*state.StateDB, error) {
+	return state.NewStateDB(dag.statedbConf, dag.statedb.Database(), root)
+}
+
+// GetCandidates returns all the registered delegate candidates.
+func (dag *Dag) GetCandidates() []Candidate {
+	dag.mu.RLock()
+	defer dag.mu.RUnlock()
+
+	return readCandidates(dag.statedb)
+}
+
+// GetVote returns the stake voted by voter to candidate.
+func (dag *Dag) GetVote(voter types.Address, candidate types.Address) *math.BigInt {
+	dag.mu.RLock()
+	defer dag.mu.RUnlock()
+
+	return readVote(dag.statedb, voter, candidate)
+}
+
+// VerifyStakingTx checks if a staking tx can be applied to the latest state.
+func (dag *Dag) VerifyStakingTx(tx *types.Tx) error {
+	dag.mu.RLock()
+	defer dag.mu.RUnlock()
+
+	return verifyStakingTx(dag.statedb, tx)
+}
+
 //GetTxsByAddress get all txs from this address
 func (dag *Dag) GetTxsByAddress(addr types.Address) []types.Txi {
 	dag.mu.RLock()
@@ -511,9 +1001,40 @@ func (dag *Dag) getTxsByAddress(addr types.Address) []types.Txi {
 	return txs
 }
 
-// RollBack rolls back the dag network.
-func (dag *Dag) RollBack() {
-	// TODO
+// RollBack reverts the ledger to the sequencer at height. Txs, receipts,
+// indexes and confirm times above height are removed and the state is
+// restored to the root committed by that sequencer.
+func (dag *Dag) RollBack(height uint64) error {
+	dag.mu.Lock()
+	defer dag.mu.Unlock()
+
+	if height > dag.latestSequencer.Height {
+		return fmt.Errorf("can't roll back to height %d above latest height %d", height, dag.latestSequencer.Height)
+	}
+	target, err := dag.accessor.ReadSequencerByHeight(height)
+	if err != nil {
+		return fmt.Errorf("read sequencer at height %d error: %v", height, err)
+	}
+	if _, err := dag.statedb.Database().OpenTrie(target.StateRoot); err != nil {
+		return fmt.Errorf("state of sequencer at height %d is missing: %v", height, err)
+	}
+	// move latest pointer first, the data left above it by an interrupted
+	// roll back is removed by LoadLastState.
+	if err := dag.accessor.WriteLatestSequencer(dag.db, target); err != nil {
+		return fmt.Errorf("write latest sequencer error: %v", err)
+	}
+	dag.truncate(height)
+	if dag.pruner != nil {
+		dag.pruner.truncate(height)
+	}
+
+	if err := dag.statedb.Reset(target.StateRoot); err != nil {
+		return fmt.Errorf("reset state error: %v", err)
+	}
+	dag.latestSequencer = target
+	dag.txcached = newTxcached(10000)
+	log.WithField("height", height).Info("dag rolled back")
+	return nil
 }
 
 func (dag *Dag) push(batch *ConfirmBatch) error {
@@ -523,111 +1044,408 @@ func (dag *Dag) push(batch *ConfirmBatch) error {
 
 	var err error
 
-	// TODO batch is not used properly.
-	dbBatch := dag.db.NewBatch()
-	receipts := make(ReceiptSet)
+	// update the state
+	receipts, receiptsRoot, err := dag.execute(dag.statedb, batch)
+	if err != nil {
+		dag.resetState()
+		return err
+	}
+	if receiptsRoot != batch.Seq.ReceiptsRoot {
+		dag.resetState()
+		return fmt.Errorf("receipts root mismatch, seq: %s, local: %s", batch.Seq.ReceiptsRoot.Hex(), receiptsRoot.Hex())
+	}
+	// commit statedb's changes to trie and triedb. The new root must be
+	// exactly the one in seq, otherwise this node has diverged from the
+	// issuer and the batch is rejected.
+	root, err := dag.statedb.Commit()
+	if err != nil {
+		log.Errorf("can't Commit statedb, err: %v", err)
+		dag.resetState()
+		return fmt.Errorf("can't Commit statedb, err: %v", err)
+	}
+	if root != batch.Seq.StateRoot {
+		dag.resetState()
+		return fmt.Errorf("state root mismatch, seq: %s, local: %s", batch.Seq.StateRoot.Hex(), root.Hex())
+	}
 
-	// store the tx and update the state
-	for _, batchDetail := range batch.Batch {
-		txlist := batchDetail.TxList
-		if txlist == nil {
-			return fmt.Errorf("batch detail does't have txlist")
-		}
-		// sort.Sort(txlist.keys)
-		for _, nonce := range *txlist.keys {
-			txi := txlist.get(nonce)
-			if txi == nil {
-				return fmt.Errorf("can't get tx from txlist, nonce: %d", nonce)
-			}
-			txi.GetBase().Height = batch.Seq.Height
-			err = dag.WriteTransaction(dbBatch, txi)
-			if err != nil {
-				return fmt.Errorf("write tx into db error: %v", err)
-			}
-			// TODO
-			// the tx processing order should based on the order managed by
-			// sequencer, now seq doesn't have such order.
-			_, receipt, err := dag.ProcessTransaction(txi)
-			if err != nil {
-				return err
-			}
-			receipts[txi.GetTxHash().Hex()] = receipt
-			log.WithField("tx", txi).Tracef("successfully process tx")
-		}
+	// flush triedb into diskdb. Trie nodes are indexed by their hashes,
+	// so flushing them ahead does no harm even if the batch below fails.
+	// A pruning dag keeps the recent states in memory instead.
+	if dag.pruner != nil {
+		err = dag.pruner.commit(batch.Seq.Height, root)
+	} else {
+		err = dag.statedb.Database().TrieDB().Commit(root, false)
+	}
+	if err != nil {
+		log.Errorf("can't flush trie from triedb into diskdb, err: %v", err)
+		dag.resetState()
+		return fmt.Errorf("can't flush trie from triedb into diskdb, err: %v", err)
 	}
 
-	// save latest sequencer into db
-	batch.Seq.GetBase().Height = batch.Seq.Height
-	err = dag.WriteTransaction(dbBatch, batch.Seq)
+	txis, err := batch.orderedTxs()
 	if err != nil {
+		dag.resetState()
 		return err
 	}
-	_, receipt, err := dag.ProcessTransaction(batch.Seq)
+	// all the ledger data of this sequencer are written in one batch, a
+	// crash in the middle of push leaves nothing but the trie nodes.
+	dbBatch := dag.db.NewBatch()
+	txis, err = dag.writeLedger(dbBatch, batch.Seq, txis, batch.TxHashes, receipts)
 	if err != nil {
+		dag.resetState()
 		return err
 	}
-
-	receipts[batch.Seq.GetTxHash().Hex()] = receipt
-	// write receipts.
-	err = dag.accessor.WriteReceipts(batch.Seq.Height, receipts)
+	err = dbBatch.Write()
 	if err != nil {
-		return err
+		log.Errorf("can't write sequencer batch into db, err: %v", err)
+		dag.resetState()
+		return fmt.Errorf("can't write sequencer batch into db, err: %v", err)
+	}
+	for _, txi := range txis {
+		dag.txcached.add(txi)
 	}
+	dag.latestSequencer = batch.Seq
 
-	// TODO
-	// get new trie root after commit, then compare new root
-	// to the root in seq. If not equal then return error.
+	log.Tracef("successfully update latest seq: %s", batch.Seq.GetTxHash().String())
+	log.WithField("height", batch.Seq.Height).WithField("txs number ", len(txis)-1).Info("new height")
 
-	// commit statedb's changes to trie and triedb
-	root, errdb := dag.statedb.Commit()
-	if errdb != nil {
-		log.Errorf("can't Commit statedb, err: ", err)
-		return fmt.Errorf("can't Commit statedb, err: %v", err)
+	return nil
+}
+
+// writeLedger puts the ledger data of seq into putter, which are the txs
+// confirmed by seq in their execution order, seq itself, the receipts and
+// the indexes of them. seq is set as the latest sequencer. It returns the
+// written txs with seq at the end.
+func (dag *Dag) writeLedger(putter ogdb.Putter, seq *types.Sequencer, txis []types.Txi, txHashes *types.Hashes, receipts ReceiptSet) ([]types.Txi, error) {
+	// store the txs
+	for _, txi := range txis {
+		txi.GetBase().Height = seq.Height
+		err := dag.WriteTransaction(putter, txi)
+		if err != nil {
+			return nil, fmt.Errorf("write tx into db error: %v", err)
+		}
 	}
-	// flush triedb into diskdb.
-	triedb := dag.statedb.Database().TrieDB()
-	err = triedb.Commit(root, false)
+
+	// save latest sequencer into db
+	seq.GetBase().Height = seq.Height
+	err := dag.WriteTransaction(putter, seq)
 	if err != nil {
-		log.Errorf("can't flush trie from triedb into diskdb, err: %v", err)
-		return fmt.Errorf("can't flush trie from triedb into diskdb, err: %v", err)
+		return nil, err
+	}
+	txis = append(txis, seq)
+
+	// write receipts and the bloom of their logs.
+	if receipts != nil {
+		err = dag.accessor.WriteReceipts(putter, seq.Height, receipts)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var logs []*vmtypes.Log
+	for _, receipt := range receipts {
+		logs = append(logs, receipt.Logs...)
+	}
+	if len(logs) > 0 {
+		err = dag.accessor.WriteLogBloom(putter, seq.Height, LogsBloom(logs))
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	log.Tracef("successfully store seq: %s", batch.Seq.GetTxHash().String())
 	// store the hashs of the txs confirmed by this sequencer.
 	txHashNum := 0
-	if batch.TxHashes != nil {
-		txHashNum = len(*batch.TxHashes)
+	if txHashes != nil {
+		txHashNum = len(*txHashes)
 	}
 	if txHashNum > 0 {
-		dag.accessor.WriteIndexedTxHashs(batch.Seq.Height, batch.TxHashes)
+		err = dag.accessor.WriteIndexedTxHashs(putter, seq.Height, txHashes)
+		if err != nil {
+			return nil, err
+		}
 	}
-	err = dag.accessor.WriteSequencerByHeight(batch.Seq)
+	err = dag.accessor.WriteSequencerByHeight(putter, seq)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	// set latest sequencer
-	err = dag.accessor.WriteLatestSequencer(batch.Seq)
+	err = dag.accessor.WriteStateRoot(putter, seq.Height, seq.StateRoot)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	dag.latestSequencer = batch.Seq
 
 	// TODO: confirm time is for tps calculation, delete later.
 	cf := types.ConfirmTime{
-		SeqHeight:   batch.Seq.Height,
+		SeqHeight:   seq.Height,
 		TxNum:       uint64(txHashNum),
 		ConfirmTime: time.Now().Format(time.RFC3339Nano),
 	}
-	dag.writeConfirmTime(&cf)
+	err = dag.accessor.writeConfirmTime(putter, &cf)
+	if err != nil {
+		return nil, err
+	}
 
-	log.Tracef("successfully update latest seq: %s", batch.Seq.GetTxHash().String())
-	log.WithField("height", batch.Seq.Height).WithField("txs number ", txHashNum).Info("new height")
+	// set latest sequencer
+	err = dag.accessor.WriteLatestSequencer(putter, seq)
+	if err != nil {
+		return nil, err
+	}
+	return txis, nil
+}
+
+// Import writes seq and the txs it confirms into the ledger without
+// executing them, which is how fast sync stores the sequencers up to the
+// pivot. seq is not checked here, fast sync checks its hash, signature
+// and issuer before. The receipts downloaded from the peers are checked to
+// cover exactly seq and its txs and to hash to the receipts root signed in
+// seq, then stored as they are. The state is not built, so seq becomes the
+// latest sequencer but the state is moved to seq only if its state trie is
+// already in db, downloaded by state sync for example.
+func (dag *Dag) Import(seq *types.Sequencer, txs types.Txs, receipts ReceiptSet) error {
+	dag.mu.Lock()
+	defer dag.mu.Unlock()
+	dag.wg.Add(1)
+	defer dag.wg.Done()
+
+	if dag.latestSequencer.Height+1 != seq.Height {
+		return fmt.Errorf("last sequencer Height mismatch old %d, new %d", dag.latestSequencer.Height, seq.Height)
+	}
+	// the delegates are changed by staking txs only, so the delegate set
+	// at seq is carried over from the latest sequencer if seq confirms
+	// none of them. The sequencer following seq can be checked with it.
+	delegates, _ := dag.getDelegatesAt(dag.latestSequencer.StateRoot)
+	for _, tx := range txs {
+		if tx.Type.IsStaking() {
+			delegates = nil
+			break
+		}
+	}
+	// the txs are ordered the same way as they were pushed.
+	elders := make(map[types.Hash]types.Txi)
+	for _, tx := range txs {
+		elders[tx.GetTxHash()] = tx
+	}
+	hashes, err := sortConfirmTxs(elders)
+	if err != nil {
+		return err
+	}
+	txis := make([]types.Txi, 0, len(hashes))
+	for _, hash := range hashes {
+		txis = append(txis, elders[hash])
+	}
+	if err := verifyReceipts(seq, hashes, receipts); err != nil {
+		return err
+	}
+
+	dbBatch := dag.db.NewBatch()
+	txis, err = dag.writeLedger(dbBatch, seq, txis, &hashes, receipts)
+	if err != nil {
+		return err
+	}
+	err = dbBatch.Write()
+	if err != nil {
+		return fmt.Errorf("can't write sequencer batch into db, err: %v", err)
+	}
+	for _, txi := range txis {
+		dag.txcached.add(txi)
+	}
+	dag.latestSequencer = seq
+	dag.importedRoot, dag.importedDelegates = seq.StateRoot, delegates
+
+	if _, err := dag.statedb.Database().OpenTrie(seq.StateRoot); err == nil {
+		if err := dag.statedb.Reset(seq.StateRoot); err != nil {
+			return fmt.Errorf("reset state error: %v", err)
+		}
+		log.WithField("height", seq.Height).Info("state is opened at imported sequencer")
+	}
+	log.WithField("height", seq.Height).WithField("txs number ", len(hashes)).Debug("imported height")
+	return nil
+}
+
+// NewStateSync creates a scheduler to download the state trie at root
+// into the db of dag.
+func (dag *Dag) NewStateSync(root types.Hash) *trie.Sync {
+	return state.NewStateSync(root, dag.db)
+}
+
+// CommitStateSync writes the state nodes completed in sched into db.
+func (dag *Dag) CommitStateSync(sched *trie.Sync) (int, error) {
+	dbBatch := dag.db.NewBatch()
+	written, err := sched.Commit(dbBatch)
+	if err != nil {
+		return 0, err
+	}
+	if err := dbBatch.Write(); err != nil {
+		return 0, fmt.Errorf("write state nodes into db error: %v", err)
+	}
+	return written, nil
+}
+
+// GetStateNode returns the state trie node or the contract code whose
+// hash is hash. They are served to the peers doing fast sync.
+func (dag *Dag) GetStateNode(hash types.Hash) ([]byte, error) {
+	return dag.statedb.Database().TrieDB().Node(hash)
+}
+
+// GetProof returns the merkle proof of the account of addr and its storage
+// keys in the state at root. They are served to the light nodes.
+func (dag *Dag) GetProof(root types.Hash, addr types.Address, keys []types.Hash) ([][]byte, error) {
+	return state.GetProof(dag.statedb.Database(), root, addr, keys)
+}
+
+// GetAccountProof returns the account of addr and the merkle proofs of it
+// and of its storage keys in the state of the latest sequencer, along with
+// the sequencer the state root belongs to.
+func (dag *Dag) GetAccountProof(addr types.Address, keys []types.Hash) (*state.AccountResult, *types.Sequencer, error) {
+	dag.mu.RLock()
+	defer dag.mu.RUnlock()
+
+	seq := dag.latestSequencer
+	result, err := state.GetAccountProof(dag.statedb.Database(), seq.StateRoot, addr, keys)
+	if err != nil {
+		return nil, nil, err
+	}
+	return result, seq, nil
+}
+
+// GetAccountProofAt returns the account of addr and the merkle proofs of
+// it and of its storage keys in the state committed by the sequencer at
+// height.
+func (dag *Dag) GetAccountProofAt(addr types.Address, keys []types.Hash, height uint64) (*state.AccountResult, *types.Sequencer, error) {
+	dag.mu.RLock()
+	defer dag.mu.RUnlock()
+
+	root, err := dag.stateRootAt(height)
+	if err != nil {
+		return nil, nil, err
+	}
+	seq, err := dag.accessor.ReadSequencerByHeight(height)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read sequencer at height %d error: %v", height, err)
+	}
+	result, err := state.GetAccountProof(dag.statedb.Database(), root, addr, keys)
+	if err != nil {
+		return nil, nil, err
+	}
+	return result, seq, nil
+}
+
+// GetLightHeader returns the sequencer header of height verified by a
+// light node, or nil if it is not verified yet.
+func (dag *Dag) GetLightHeader(height uint64) *types.SequencerHeader {
+	if height == 0 {
+		return dag.Genesis().GetHead()
+	}
+	return dag.accessor.ReadLightHeader(height)
+}
 
+// LightHead returns the latest sequencer header verified by a light node.
+// Light nodes start from the genesis, which is always trusted.
+func (dag *Dag) LightHead() *types.SequencerHeader {
+	if head := dag.accessor.ReadLightHead(); head != nil {
+		return head
+	}
+	return dag.Genesis().GetHead()
+}
+
+// WriteLightHeaders stores the sequencer headers verified by a light node
+// and moves the light head to the last one.
+func (dag *Dag) WriteLightHeaders(headers []*types.SequencerHeader) error {
+	if len(headers) == 0 {
+		return nil
+	}
+	dbBatch := dag.db.NewBatch()
+	for _, header := range headers {
+		if err := dag.accessor.WriteLightHeader(dbBatch, header); err != nil {
+			return err
+		}
+	}
+	if err := dag.accessor.WriteLightHead(dbBatch, headers[len(headers)-1].Height); err != nil {
+		return err
+	}
+	if err := dbBatch.Write(); err != nil {
+		return fmt.Errorf("write light headers into db error: %v", err)
+	}
 	return nil
 }
 
-func (dag *Dag) writeConfirmTime(cf *types.ConfirmTime) error {
-	return dag.accessor.writeConfirmTime(cf)
+// execute processes all the txs confirmed by batch.Seq and the sequencer
+// itself on statedb sd, then elects the delegates for the following
+// sequencers. Changes are not committed. The receipts are returned with
+// their receipts root.
+func (dag *Dag) execute(sd *state.StateDB, batch *ConfirmBatch) (ReceiptSet, types.Hash, error) {
+	txs, err := batch.orderedTxs()
+	if err != nil {
+		return nil, types.Hash{}, err
+	}
+	receipts := make(ReceiptSet)
+	ordered := make([]*Receipt, 0, len(txs)+1)
+	for i, txi := range txs {
+		sd.Prepare(txi.GetTxHash(), i)
+		_, receipt, err := dag.processTransaction(sd, txi, batch.Seq.Issuer)
+		if err != nil {
+			return nil, types.Hash{}, err
+		}
+		receipt.Logs = sd.GetLogs(txi.GetTxHash())
+		for _, l := range receipt.Logs {
+			l.SequenceID = batch.Seq.Height
+			l.BlockHash = batch.Seq.GetTxHash()
+		}
+		receipts[txi.GetTxHash().Hex()] = receipt
+		ordered = append(ordered, receipt)
+		log.WithField("tx", txi).Tracef("successfully process tx")
+	}
+	_, receipt, err := dag.processTransaction(sd, batch.Seq, batch.Seq.Issuer)
+	if err != nil {
+		return nil, types.Hash{}, err
+	}
+	receipts[batch.Seq.GetTxHash().Hex()] = receipt
+	ordered = append(ordered, receipt)
+
+	// elect the delegates for the following sequencers.
+	electDelegates(sd, dag.conf.MinDelegates, dag.conf.MaxDelegates, dag.conf.MinStake)
+	return receipts, ReceiptsRoot(ordered), nil
+}
+
+// PreConfirm executes batch on a copy of current state and returns the
+// state root and the receipts root the sequencer should commit to.
+// Nothing in dag is changed.
+func (dag *Dag) PreConfirm(batch *ConfirmBatch) (stateRoot types.Hash, receiptsRoot types.Hash, err error) {
+	dag.mu.RLock()
+	defer dag.mu.RUnlock()
+
+	if dag.latestSequencer.Height+1 != batch.Seq.Height {
+		return types.Hash{}, types.Hash{}, fmt.Errorf("last sequencer Height mismatch old %d, new %d", dag.latestSequencer.Height, batch.Seq.Height)
+	}
+	sd, err := state.NewStateDB(dag.statedbConf, dag.statedb.Database(), dag.latestSequencer.StateRoot)
+	if err != nil {
+		return types.Hash{}, types.Hash{}, fmt.Errorf("create statedb err: %v", err)
+	}
+	defer sd.Stop()
+
+	if _, receiptsRoot, err = dag.execute(sd, batch); err != nil {
+		return types.Hash{}, types.Hash{}, err
+	}
+	return sd.IntermediateRoot(), receiptsRoot, nil
+}
+
+// commitState commits all the changes in statedb and flushes the trie
+// into diskdb. It returns the new state root.
+func (dag *Dag) commitState() (types.Hash, error) {
+	root, err := dag.statedb.Commit()
+	if err != nil {
+		return types.Hash{}, fmt.Errorf("can't Commit statedb, err: %v", err)
+	}
+	err = dag.statedb.Database().TrieDB().Commit(root, false)
+	if err != nil {
+		return types.Hash{}, fmt.Errorf("can't flush trie from triedb into diskdb, err: %v", err)
+	}
+	return root, nil
+}
+
+// resetState drops all the uncommitted changes in statedb.
+func (dag *Dag) resetState() {
+	if err := dag.statedb.Reset(dag.latestSequencer.StateRoot); err != nil {
+		log.WithError(err).Error("failed to reset statedb")
+	}
 }
 
 func (dag *Dag) ReadConfirmTime(seqHeight uint64) *types.ConfirmTime {
@@ -641,19 +1459,13 @@ func (dag *Dag) ReadConfirmTime(seqHeight uint64) *types.ConfirmTime {
 func (dag *Dag) WriteTransaction(putter ogdb.Putter, tx types.Txi) error {
 	// Write tx hash. This is aimed to allow users to query tx hash
 	// by sender address and tx nonce.
-	err := dag.accessor.WriteTxHashByNonce(tx.Sender(), tx.GetNonce(), tx.GetTxHash())
+	err := dag.accessor.WriteTxHashByNonce(putter, tx.Sender(), tx.GetNonce(), tx.GetTxHash())
 	if err != nil {
 		return fmt.Errorf("write latest nonce err: %v", err)
 	}
 
 	// Write tx itself
-	err = dag.accessor.WriteTransaction(putter, tx)
-	if err != nil {
-		return err
-	}
-
-	dag.txcached.add(tx)
-	return nil
+	return dag.accessor.WriteTransaction(putter, tx)
 }
 
 // ProcessTransaction execute the tx and update the data in statedb.
@@ -661,10 +1473,16 @@ func (dag *Dag) WriteTransaction(putter ogdb.Putter, tx types.Txi) error {
 // Besides balance and nonce, if a tx is trying to create or call a
 // contract, vm part will be initiated to handle this.
 func (dag *Dag) ProcessTransaction(tx types.Txi) ([]byte, *Receipt, error) {
+	return dag.processTransaction(dag.statedb, tx, DefaultCoinbase)
+}
+
+// processTransaction applies tx to sd, the gas fee of contract txs is paid
+// to coinbase.
+func (dag *Dag) processTransaction(sd *state.StateDB, tx types.Txi, coinbase types.Address) ([]byte, *Receipt, error) {
 	// update nonce
-	curNonce := dag.statedb.GetNonce(tx.Sender())
-	if !dag.statedb.Exist(tx.Sender()) || tx.GetNonce() > curNonce {
-		dag.statedb.SetNonce(tx.Sender(), tx.GetNonce())
+	curNonce := sd.GetNonce(tx.Sender())
+	if !sd.Exist(tx.Sender()) || tx.GetNonce() > curNonce {
+		sd.SetNonce(tx.Sender(), tx.GetNonce())
 	}
 	// transfer balance
 	if tx.GetType() == types.TxBaseTypeSequencer {
@@ -672,27 +1490,67 @@ func (dag *Dag) ProcessTransaction(tx types.Txi) ([]byte, *Receipt, error) {
 		return nil, receipt, nil
 	}
 	txnormal := tx.(*types.Tx)
-	if txnormal.Value.Value.Sign() != 0 {
-		dag.statedb.SubBalance(txnormal.From, txnormal.Value)
-		dag.statedb.AddBalance(txnormal.To, txnormal.Value)
+	// staking txs only change the candidates and votes.
+	if txnormal.Type.IsStaking() {
+		if err := processStakingTx(sd, txnormal); err != nil {
+			receipt := NewReceipt(tx.GetTxHash(), ReceiptStatusStakingFailed, err.Error(), emptyAddress)
+			return nil, receipt, nil
+		}
+		receipt := NewReceipt(tx.GetTxHash(), ReceiptStatusTxSuccess, "", emptyAddress)
+		return nil, receipt, nil
 	}
 	// return when its not contract related tx.
-	if len(txnormal.Data) == 0 {
+	if !isContractTx(txnormal) {
+		if txnormal.Value.Value.Sign() != 0 {
+			sd.SubBalance(txnormal.From, txnormal.Value)
+			sd.AddBalance(txnormal.To, txnormal.Value)
+		}
 		receipt := NewReceipt(tx.GetTxHash(), ReceiptStatusTxSuccess, "", emptyAddress)
 		return nil, receipt, nil
 	}
+	return dag.processContractTx(sd, txnormal, coinbase)
+}
+
+// processContractTx runs a contract tx in ovm. The gas limit is paid up
+// front, the gas left is refunded to the sender after execution and the
+// fee of the gas used goes to coinbase. A failed execution still pays
+// for the gas it used, but all its other changes are reverted.
+func (dag *Dag) processContractTx(sd *state.StateDB, tx *types.Tx, coinbase types.Address) ([]byte, *Receipt, error) {
+	contractCreation := tx.To.Bytes == emptyAddress.Bytes
+	gasPrice := tx.GetGasPrice()
+
+	intrinsicGas, err := IntrinsicGas(tx.Data, contractCreation)
+	if err != nil {
+		receipt := NewReceipt(tx.GetTxHash(), ReceiptStatusOVMFailed, err.Error(), emptyAddress)
+		return nil, receipt, nil
+	}
+	if tx.GasLimit < intrinsicGas {
+		err = fmt.Errorf("intrinsic gas too low, limit: %d, need: %d", tx.GasLimit, intrinsicGas)
+		receipt := NewReceipt(tx.GetTxHash(), ReceiptStatusOVMFailed, err.Error(), emptyAddress)
+		return nil, receipt, nil
+	}
+	upfront := gasFee(tx.GasLimit, gasPrice)
+	if sd.GetBalance(tx.From).Value.Cmp(txCost(tx).Value) < 0 {
+		err = fmt.Errorf("insufficient balance for gas %s and value %s", upfront, tx.Value)
+		receipt := NewReceipt(tx.GetTxHash(), ReceiptStatusOVMFailed, err.Error(), emptyAddress)
+		return nil, receipt, nil
+	}
+	sd.SubBalance(tx.From, upfront)
+
+	// the refund counter of statedb is only cleared on commit, keep the
+	// refund of this tx apart from the previous ones.
+	refundBase := sd.GetRefund()
+	snapshot := sd.Snapshot()
 
 	// create ovm object.
-	//
-	// TODO gaslimit not implemented yet.
-	vmContext := ovm.NewOVMContext(&ovm.DefaultChainContext{}, &DefaultCoinbase, dag.statedb)
+	vmContext := ovm.NewOVMContext(&ovm.DefaultChainContext{}, &coinbase, sd)
 	txContext := &ovm.TxContext{
-		From:       txnormal.From,
-		Value:      txnormal.Value,
-		Data:       txnormal.Data,
-		GasPrice:   math.NewBigInt(0),
-		GasLimit:   DefaultGasLimit,
-		Coinbase:   DefaultCoinbase,
+		From:       tx.From,
+		Value:      tx.Value,
+		Data:       tx.Data,
+		GasPrice:   gasPrice,
+		GasLimit:   tx.GasLimit - intrinsicGas,
+		Coinbase:   coinbase,
 		SequenceID: dag.latestSequencer.Height,
 	}
 	// TODO more interpreters should be initialized, here only evm.
@@ -708,34 +1566,80 @@ func (dag *Dag) ProcessTransaction(tx types.Txi) ([]byte, *Receipt, error) {
 	var ret []byte
 	var leftOverGas uint64
 	var contractAddress = emptyAddress
-	var err error
-	if txnormal.To.Bytes == emptyAddress.Bytes {
+	if contractCreation {
 		ret, contractAddress, leftOverGas, err = ogvm.Create(vmtypes.AccountRef(txContext.From), txContext.Data, txContext.GasLimit, txContext.Value.Value, true)
 	} else {
-		ret, leftOverGas, err = ogvm.Call(vmtypes.AccountRef(txContext.From), txnormal.To, txContext.Data, txContext.GasLimit, txContext.Value.Value, true)
+		ret, leftOverGas, err = ogvm.Call(vmtypes.AccountRef(txContext.From), tx.To, txContext.Data, txContext.GasLimit, txContext.Value.Value, true)
 	}
 	if err != nil {
-		receipt := NewReceipt(tx.GetTxHash(), ReceiptStatusOVMFailed, err.Error(), emptyAddress)
-		return nil, receipt, fmt.Errorf("vm processing error: %v", err)
+		sd.RevertToSnapshot(snapshot)
 	}
-	receipt := NewReceipt(tx.GetTxHash(), ReceiptStatusTxSuccess, "", contractAddress)
 
-	// TODO
-	// not finished yet
-	//
-	// 1. refund gas
-	// 2. add service fee to coinbase
-	log.Debugf("leftOverGas not used yet, this log is for compiling, ret: %x, leftOverGas: %d", ret, leftOverGas)
+	// refund the gas left and the gas returned by storage clearing, which
+	// is capped by half of the gas used.
+	gasUsed := tx.GasLimit - leftOverGas
+	var refund uint64
+	if r := sd.GetRefund(); r > refundBase {
+		refund = r - refundBase
+	}
+	if refund > gasUsed/2 {
+		refund = gasUsed / 2
+	}
+	gasUsed -= refund
+	sd.AddBalance(tx.From, gasFee(tx.GasLimit-gasUsed, gasPrice))
+	sd.AddBalance(coinbase, gasFee(gasUsed, gasPrice))
+
+	var receipt *Receipt
+	if err != nil {
+		log.WithError(err).WithField("tx", tx).Debug("vm processing error")
+		receipt = NewReceipt(tx.GetTxHash(), ReceiptStatusOVMFailed, err.Error(), emptyAddress)
+	} else {
+		receipt = NewReceipt(tx.GetTxHash(), ReceiptStatusTxSuccess, "", contractAddress)
+	}
+	receipt.GasUsed = gasUsed
 	return ret, receipt, nil
 }
 
+// SimulateTx executes tx on the latest state as if it was confirmed by
+// a sequencer issued by DefaultCoinbase, then reverts all its changes.
+// The signature and nonce of tx are not checked.
+func (dag *Dag) SimulateTx(tx *types.Tx) ([]byte, *Receipt, error) {
+	if tx.Type.IsStaking() {
+		return nil, nil, fmt.Errorf("staking tx can't be simulated")
+	}
+	dag.mu.Lock()
+	defer dag.mu.Unlock()
+
+	snapshot := dag.statedb.Snapshot()
+	defer dag.statedb.RevertToSnapshot(snapshot)
+	return dag.processTransaction(dag.statedb, tx, DefaultCoinbase)
+}
+
 // CallContract calls contract but disallow any modifications on
 // statedb. This method will call ovm.StaticCall() to satisfy this.
 func (dag *Dag) CallContract(addr types.Address, data []byte) ([]byte, error) {
+	return dag.callContract(dag.statedb, dag.latestSequencer.Height, addr, data)
+}
+
+// CallContractAt calls contract on the state committed by the sequencer at
+// height, the same way as CallContract.
+func (dag *Dag) CallContractAt(addr types.Address, data []byte, height uint64) ([]byte, error) {
+	dag.mu.RLock()
+	defer dag.mu.RUnlock()
+
+	sd, err := dag.stateAt(height)
+	if err != nil {
+		return nil, err
+	}
+	defer sd.Stop()
+	return dag.callContract(sd, height, addr, data)
+}
+
+func (dag *Dag) callContract(sd *state.StateDB, height uint64, addr types.Address, data []byte) ([]byte, error) {
 	// create ovm object.
 	//
 	// TODO gaslimit not implemented yet.
-	vmContext := ovm.NewOVMContext(&ovm.DefaultChainContext{}, &DefaultCoinbase, dag.statedb)
+	vmContext := ovm.NewOVMContext(&ovm.DefaultChainContext{}, &DefaultCoinbase, sd)
 	txContext := &ovm.TxContext{
 		From:       DefaultCoinbase,
 		Value:      math.NewBigInt(0),
@@ -743,7 +1647,7 @@ func (dag *Dag) CallContract(addr types.Address, data []byte) ([]byte, error) {
 		GasPrice:   math.NewBigInt(0),
 		GasLimit:   DefaultGasLimit,
 		Coinbase:   DefaultCoinbase,
-		SequenceID: dag.latestSequencer.Height,
+		SequenceID: height,
 	}
 	// TODO more interpreters should be initialized, here only evm.
 	evmInterpreter := evm.NewEVMInterpreter(vmContext, txContext,
@@ -0,0 +1,317 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/annchain/OG/common/math"
+	"github.com/annchain/OG/core/state"
+	"github.com/annchain/OG/ogdb"
+	"github.com/annchain/OG/types"
+)
+
+func newTestPushDag(t *testing.T, db ogdb.Database) *Dag {
+	dag, err := NewDag(DefaultDagConfig(), state.DefaultStateDBConfig(), db, nil)
+	if err != nil {
+		t.Fatalf("create dag error: %v", err)
+	}
+	return dag
+}
+
+func newTestPushBatch(from types.Address, to types.Address, value int64) *ConfirmBatch {
+	tx := newTestStakingTx(types.TxBaseTypeNormal, from, to, value)
+	tx.Hash = types.HexToHash("0x01")
+	txlist := NewTxList()
+	txlist.put(tx)
+
+	seq := &types.Sequencer{
+		TxBase: types.TxBase{Type: types.TxBaseTypeSequencer, Height: 1, Hash: types.HexToHash("0x02")},
+		Issuer: from,
+	}
+	seq.AccountNonce = 1
+	return &ConfirmBatch{
+		Seq:      seq,
+		Batch:    map[types.Address]*BatchDetail{from: {TxList: txlist}},
+		TxHashes: &types.Hashes{tx.Hash},
+	}
+}
+
+func initTestPushDag(t *testing.T, dag *Dag, balance map[types.Address]*math.BigInt) {
+	genesis := newUnsignedSequencer(0, 0).(*types.Sequencer)
+	genesis.SetHash(genesis.CalcTxHash())
+	if err := dag.Init(genesis, balance); err != nil {
+		t.Fatalf("init dag error: %v", err)
+	}
+}
+
+func TestDagPushStateRoot(t *testing.T) {
+	db := ogdb.NewMemDatabase()
+	dag := newTestPushDag(t, db)
+
+	alice := types.HexToAddress("0x0a")
+	bob := types.HexToAddress("0x0b")
+	initTestPushDag(t, dag, map[types.Address]*math.BigInt{alice: math.NewBigInt(100)})
+
+	batch := newTestPushBatch(alice, bob, 10)
+	root, receiptsRoot, err := dag.PreConfirm(batch)
+	if err != nil {
+		t.Fatalf("pre confirm error: %v", err)
+	}
+	if b := dag.GetBalance(bob).GetInt64(); b != 0 {
+		t.Fatalf("pre confirm should not change the state, bob has %d", b)
+	}
+
+	// a sequencer with wrong root is rejected and leaves no changes.
+	batch.Seq.StateRoot = types.HexToHash("0xff")
+	if err := dag.Push(batch); err == nil {
+		t.Fatal("expected error on state root mismatch")
+	}
+	if b := dag.GetBalance(bob).GetInt64(); b != 0 {
+		t.Fatalf("rejected batch should not change the state, bob has %d", b)
+	}
+	if dag.GetTx(batch.Seq.GetTxHash()) != nil {
+		t.Fatal("rejected sequencer should not be stored")
+	}
+
+	// so is a sequencer with wrong receipts root.
+	batch.Seq.StateRoot = root
+	batch.Seq.ReceiptsRoot = types.HexToHash("0xff")
+	if err := dag.Push(batch); err == nil {
+		t.Fatal("expected error on receipts root mismatch")
+	}
+	if b := dag.GetBalance(bob).GetInt64(); b != 0 {
+		t.Fatalf("rejected batch should not change the state, bob has %d", b)
+	}
+
+	batch.Seq.ReceiptsRoot = receiptsRoot
+	if err := dag.Push(batch); err != nil {
+		t.Fatalf("push error: %v", err)
+	}
+	if b := dag.GetBalance(bob).GetInt64(); b != 10 {
+		t.Fatalf("bob should have 10, got %d", b)
+	}
+	dag.Stop()
+
+	// the committed state is opened again after restart.
+	dag = newTestPushDag(t, db)
+	defer dag.Stop()
+	if !dag.LoadLastState() {
+		t.Fatal("failed to load last state")
+	}
+	if b := dag.GetBalance(alice).GetInt64(); b != 90 {
+		t.Fatalf("alice should have 90 after restart, got %d", b)
+	}
+}
+
+func TestDagRepairLastState(t *testing.T) {
+	db := ogdb.NewMemDatabase()
+	dag := newTestPushDag(t, db)
+
+	alice := types.HexToAddress("0x0a")
+	bob := types.HexToAddress("0x0b")
+	initTestPushDag(t, dag, map[types.Address]*math.BigInt{alice: math.NewBigInt(100)})
+	batch := newTestPushBatch(alice, bob, 10)
+	root, receiptsRoot, err := dag.PreConfirm(batch)
+	if err != nil {
+		t.Fatalf("pre confirm error: %v", err)
+	}
+	batch.Seq.StateRoot = root
+	batch.Seq.ReceiptsRoot = receiptsRoot
+	if err := dag.Push(batch); err != nil {
+		t.Fatalf("push error: %v", err)
+	}
+	dag.Stop()
+
+	// simulate a sequencer whose ledger data are written but the state
+	// trie is lost.
+	tx := newTestStakingTx(types.TxBaseTypeNormal, alice, bob, 10)
+	tx.Hash = types.HexToHash("0x03")
+	tx.AccountNonce = 2
+	seq := &types.Sequencer{
+		TxBase:    types.TxBase{Type: types.TxBaseTypeSequencer, Height: 2, Hash: types.HexToHash("0x04"), AccountNonce: 3},
+		Issuer:    alice,
+		StateRoot: types.HexToHash("0xdead"),
+	}
+	acc := NewAccessor(db)
+	for _, txi := range []types.Txi{tx, seq} {
+		acc.WriteTxHashByNonce(db, txi.Sender(), txi.GetNonce(), txi.GetTxHash())
+		acc.WriteTransaction(db, txi)
+	}
+	acc.WriteIndexedTxHashs(db, 2, &types.Hashes{tx.Hash})
+	acc.WriteSequencerByHeight(db, seq)
+	acc.WriteLatestSequencer(db, seq)
+
+	dag = newTestPushDag(t, db)
+	defer dag.Stop()
+	if !dag.LoadLastState() {
+		t.Fatal("failed to load last state")
+	}
+	if h := dag.LatestSequencer().Height; h != 1 {
+		t.Fatalf("latest sequencer should be repaired to height 1, got %d", h)
+	}
+	if acc.ReadLatestSequencer().Height != 1 {
+		t.Fatal("latest sequencer in db is not repaired")
+	}
+	if dag.GetTx(tx.Hash) != nil || dag.GetTxByNonce(alice, 2) != nil {
+		t.Fatal("tx of the partial sequencer should be removed")
+	}
+	if dag.GetSequencerByHeight(2) != nil {
+		t.Fatal("partial sequencer should be removed")
+	}
+	if b := dag.GetBalance(bob).GetInt64(); b != 10 {
+		t.Fatalf("bob should have 10, got %d", b)
+	}
+}
+
+func TestDagRollBack(t *testing.T) {
+	db := ogdb.NewMemDatabase()
+	dag := newTestPushDag(t, db)
+	defer dag.Stop()
+
+	alice := types.HexToAddress("0x0a")
+	bob := types.HexToAddress("0x0b")
+	initTestPushDag(t, dag, map[types.Address]*math.BigInt{alice: math.NewBigInt(100)})
+	batch := newTestPushBatch(alice, bob, 10)
+	root, receiptsRoot, err := dag.PreConfirm(batch)
+	if err != nil {
+		t.Fatalf("pre confirm error: %v", err)
+	}
+	batch.Seq.StateRoot = root
+	batch.Seq.ReceiptsRoot = receiptsRoot
+	if err := dag.Push(batch); err != nil {
+		t.Fatalf("push error: %v", err)
+	}
+
+	if err := dag.RollBack(2); err == nil {
+		t.Fatal("expected error on rolling back above latest height")
+	}
+	if err := dag.RollBack(0); err != nil {
+		t.Fatalf("roll back error: %v", err)
+	}
+	if h := dag.LatestSequencer().Height; h != 0 {
+		t.Fatalf("latest sequencer should be height 0, got %d", h)
+	}
+	if dag.GetSequencerByHeight(1) != nil || dag.GetTx(batch.Seq.GetTxHash()) != nil {
+		t.Fatal("sequencer above height should be removed")
+	}
+	txHash := (*batch.TxHashes)[0]
+	if dag.GetTx(txHash) != nil || dag.GetTxByNonce(alice, 0) != nil || dag.GetReceipt(txHash) != nil {
+		t.Fatal("tx above height should be removed")
+	}
+	if b := dag.GetBalance(alice).GetInt64(); b != 100 {
+		t.Fatalf("alice should have 100 after roll back, got %d", b)
+	}
+
+	// the same sequencer can be pushed again.
+	if err := dag.Push(batch); err != nil {
+		t.Fatalf("push after roll back error: %v", err)
+	}
+	if b := dag.GetBalance(bob).GetInt64(); b != 10 {
+		t.Fatalf("bob should have 10, got %d", b)
+	}
+}
+
+func TestDagStateAtHeight(t *testing.T) {
+	db := ogdb.NewMemDatabase()
+	dag := newTestPushDag(t, db)
+	defer dag.Stop()
+
+	alice := types.HexToAddress("0x0a")
+	bob := types.HexToAddress("0x0b")
+	initTestPushDag(t, dag, map[types.Address]*math.BigInt{alice: math.NewBigInt(100)})
+	batch := newTestPushBatch(alice, bob, 10)
+	root, receiptsRoot, err := dag.PreConfirm(batch)
+	if err != nil {
+		t.Fatalf("pre confirm error: %v", err)
+	}
+	batch.Seq.StateRoot = root
+	batch.Seq.ReceiptsRoot = receiptsRoot
+	if err := dag.Push(batch); err != nil {
+		t.Fatalf("push error: %v", err)
+	}
+
+	if r, err := dag.StateRootAt(1); err != nil || r != root {
+		t.Fatalf("state root of height 1 should be %s, got %s, err %v", root.Hex(), r.Hex(), err)
+	}
+	if b, err := dag.GetBalanceAt(bob, 0); err != nil || b.GetInt64() != 0 {
+		t.Fatalf("bob should have 0 at height 0, got %v, err %v", b, err)
+	}
+	if b, err := dag.GetBalanceAt(bob, 1); err != nil || b.GetInt64() != 10 {
+		t.Fatalf("bob should have 10 at height 1, got %v, err %v", b, err)
+	}
+	if _, err := dag.GetLatestNonceAt(bob, 0); err != types.ErrNonceNotExist {
+		t.Fatalf("bob should not exist at height 0, err %v", err)
+	}
+	if _, err := dag.GetBalanceAt(bob, 2); err == nil {
+		t.Fatal("expected error on height above latest")
+	}
+
+	// the roots above the rolled back height are removed.
+	if err := dag.RollBack(0); err != nil {
+		t.Fatalf("roll back error: %v", err)
+	}
+	if _, err := dag.StateRootAt(1); err == nil {
+		t.Fatal("expected error on rolled back height")
+	}
+	if _, err := dag.accessor.ReadStateRoot(1); err == nil {
+		t.Fatal("state root of rolled back height should be removed")
+	}
+}
+
+func TestSortConfirmTxs(t *testing.T) {
+	alice := types.HexToAddress("0x0a")
+	bob := types.HexToAddress("0x0b")
+	newTx := func(from types.Address, nonce uint64, hash string, parents ...types.Hash) *types.Tx {
+		tx := newTestStakingTx(types.TxBaseTypeNormal, from, bob, 0)
+		tx.Hash = types.HexToHash(hash)
+		tx.AccountNonce = nonce
+		tx.ParentsHash = parents
+		return tx
+	}
+	// a3 goes after its parent b9, a1 goes after a3 by nonce even though
+	// it has a smaller hash.
+	b9 := newTx(bob, 1, "0x09")
+	b2 := newTx(bob, 2, "0x02", b9.Hash)
+	a3 := newTx(alice, 1, "0x03", b9.Hash)
+	a1 := newTx(alice, 2, "0x01")
+	elders := map[types.Hash]types.Txi{}
+	for _, tx := range []*types.Tx{b9, b2, a3, a1} {
+		elders[tx.Hash] = tx
+	}
+	expected := types.Hashes{b9.Hash, b2.Hash, a3.Hash, a1.Hash}
+	for i := 0; i < 10; i++ {
+		order, err := sortConfirmTxs(elders)
+		if err != nil {
+			t.Fatalf("sort error: %v", err)
+		}
+		if len(order) != len(expected) {
+			t.Fatalf("expected %d txs, got %d", len(expected), len(order))
+		}
+		for j := range order {
+			if order[j] != expected[j] {
+				t.Fatalf("wrong order at %d: got %s, expected %s", j, order[j].Hex(), expected[j].Hex())
+			}
+		}
+	}
+
+	// the parent of a tx has a higher nonce of the same sender.
+	a3.ParentsHash = []types.Hash{a1.Hash}
+	if _, err := sortConfirmTxs(elders); err == nil {
+		t.Fatal("expected error on conflicting parents and nonces")
+	}
+
+	// a batch must be executed in nonce order of each sender.
+	txlist := NewTxList()
+	txlist.put(a3)
+	txlist.put(a1)
+	batch := &ConfirmBatch{
+		Batch:    map[types.Address]*BatchDetail{alice: {TxList: txlist}},
+		TxHashes: &types.Hashes{a1.Hash, a3.Hash},
+	}
+	if _, err := batch.orderedTxs(); err == nil {
+		t.Fatal("expected error on txs out of nonce order")
+	}
+	batch.TxHashes = &types.Hashes{a3.Hash}
+	if _, err := batch.orderedTxs(); err == nil {
+		t.Fatal("expected error on missing tx hash")
+	}
+}
@@ -87,7 +87,7 @@ func TestDagLoadGenesis(t *testing.T) {
 
 	acc := core.NewAccessor(db)
 	genesis, _ := core.DefaultGenesis(crypto.CryptoTypeSecp256k1)
-	err := acc.WriteGenesis(genesis)
+	err := acc.WriteGenesis(db, genesis)
 	if err != nil {
 		t.Fatalf("can't write genesis into db: %v", err)
 	}
@@ -147,6 +147,11 @@ func TestDagPush(t *testing.T) {
 	cb.Batch = batch
 	cb.TxHashes = hashes
 
+	seq.StateRoot, seq.ReceiptsRoot, err = dag.PreConfirm(cb)
+	if err != nil {
+		t.Fatalf("pre confirm failed: %v", err)
+	}
+
 	err = dag.Push(cb)
 	if err != nil {
 		t.Fatalf("push confirm batch to dag failed: %v", err)
@@ -187,6 +192,59 @@ func TestDagPush(t *testing.T) {
 
 }
 
+func TestDagImportDelegates(t *testing.T) {
+	t.Parallel()
+
+	dag, genesis, finish := newTestDag(t, "TestDagImportDelegates")
+	defer finish()
+
+	want, err := dag.GetDelegatesAt(genesis.StateRoot)
+	if err != nil {
+		t.Fatalf("get genesis delegates failed: %v", err)
+	}
+	// receipts returns the receipts of txs and seq signed in seq.
+	receipts := func(seq *types.Sequencer, txs ...types.Txi) core.ReceiptSet {
+		set := core.ReceiptSet{}
+		var ordered []*core.Receipt
+		for _, txi := range append(txs, seq) {
+			receipt := core.NewReceipt(txi.GetTxHash(), core.ReceiptStatusTxSuccess, "", types.Address{})
+			set[txi.GetTxHash().Hex()] = receipt
+			ordered = append(ordered, receipt)
+		}
+		seq.ReceiptsRoot = core.ReceiptsRoot(ordered)
+		return set
+	}
+
+	// the state of imported sequencers is not built, the delegates are
+	// carried over while no staking tx is confirmed.
+	seq1 := newTestSeq(1)
+	seq1.ParentsHash = []types.Hash{genesis.GetTxHash()}
+	seq1.StateRoot = types.HexToHash("0x01")
+	if err := dag.Import(seq1, nil, receipts(seq1)); err != nil {
+		t.Fatalf("import seq1 failed: %v", err)
+	}
+	delegates, err := dag.GetDelegatesAt(seq1.StateRoot)
+	if err != nil {
+		t.Fatalf("get delegates at seq1 failed: %v", err)
+	}
+	if fmt.Sprint(delegates) != fmt.Sprint(want) {
+		t.Fatalf("delegates at seq1 are %v, should be %v", delegates, want)
+	}
+
+	vote := newTestDagTx(0)
+	vote.Type = types.TxBaseTypeVote
+	vote.ParentsHash = []types.Hash{seq1.GetTxHash()}
+	seq2 := newTestSeq(2)
+	seq2.ParentsHash = []types.Hash{vote.GetTxHash()}
+	seq2.StateRoot = types.HexToHash("0x02")
+	if err := dag.Import(seq2, types.Txs{vote}, receipts(seq2, vote)); err != nil {
+		t.Fatalf("import seq2 failed: %v", err)
+	}
+	if _, err := dag.GetDelegatesAt(seq2.StateRoot); err == nil {
+		t.Fatalf("delegates at seq2 should be unknown after a staking tx")
+	}
+}
+
 func TestDagProcess(t *testing.T) {
 	t.Parallel()
 
@@ -206,6 +264,7 @@ func TestDagProcess(t *testing.T) {
 
 	createTx := &types.Tx{}
 	createTx.From = addr
+	createTx.GasLimit = 1000000
 	createTx.Value = math.NewBigInt(0)
 	createTx.Data, err = hex.DecodeString(contractCode)
 	if err != nil {
@@ -230,10 +289,11 @@ func TestDagProcess(t *testing.T) {
 	calldata := "e5aa3d58"
 	callTx := &types.Tx{}
 	callTx.From = addr
+	callTx.GasLimit = 1000000
 	callTx.Value = math.NewBigInt(0)
 	callTx.To = contractAddr
 	callTx.Data, _ = hex.DecodeString(calldata)
-	ret, err = dag.ProcessTransaction(callTx)
+	ret, _, err = dag.ProcessTransaction(callTx)
 	if err != nil {
 		t.Fatalf("error during contract calling: %v", err)
 	}
@@ -247,15 +307,16 @@ func TestDagProcess(t *testing.T) {
 	setdata := "60fe47b10000000000000000000000000000000000000000000000000000000000000064"
 	setTx := &types.Tx{}
 	setTx.From = addr
+	setTx.GasLimit = 1000000
 	setTx.Value = math.NewBigInt(0)
 	setTx.To = contractAddr
 	setTx.Data, _ = hex.DecodeString(setdata)
-	ret, err = dag.ProcessTransaction(setTx)
+	ret, _, err = dag.ProcessTransaction(setTx)
 	if err != nil {
 		t.Fatalf("error during contract setting: %v", err)
 	}
 	// get i and check if it is changed
-	ret, err = dag.ProcessTransaction(callTx)
+	ret, _, err = dag.ProcessTransaction(callTx)
 	if err != nil {
 		t.Fatalf("error during contract calling: %v", err)
 	}
@@ -269,9 +330,11 @@ func TestDagProcess(t *testing.T) {
 	transferValue := int64(10)
 	payTx := &types.Tx{}
 	payTx.From = addr
+	payTx.GasLimit = 1000000
 	payTx.Value = math.NewBigInt(transferValue)
 	payTx.To = contractAddr
-	ret, err = dag.ProcessTransaction(payTx)
+	senderBalance := stdb.GetBalance(addr).GetInt64()
+	ret, _, err = dag.ProcessTransaction(payTx)
 	if err != nil {
 		t.Fatalf("error during contract setting: %v", err)
 	}
@@ -279,4 +342,8 @@ func TestDagProcess(t *testing.T) {
 	if blc.GetInt64() != transferValue {
 		t.Fatalf("the value is not tranferred to contract, should be: %d, get: %d", transferValue, blc.GetInt64())
 	}
+	// gas is free here, the sender pays the value only once.
+	if b := stdb.GetBalance(addr).GetInt64(); b != senderBalance-transferValue {
+		t.Fatalf("sender balance should be %d, get: %d", senderBalance-transferValue, b)
+	}
 }
@@ -0,0 +1,227 @@
+package core
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/annchain/OG/types"
+)
+
+// LedgerIssue is a piece of ledger data found missing or broken in db.
+type LedgerIssue struct {
+	Height uint64
+	Issue  string
+}
+
+func (i LedgerIssue) String() string {
+	return fmt.Sprintf("height %d: %s", i.Height, i.Issue)
+}
+
+// CheckLedger checks that every height in [from, to] has its sequencer,
+// the hashes of the txs it confirms and the txs themselves, and the state
+// root and confirm time written along with them.
+func (da *Accessor) CheckLedger(from, to uint64) []LedgerIssue {
+	var issues []LedgerIssue
+	for height := from; height <= to; height++ {
+		issues = append(issues, da.checkHeight(height)...)
+		// to may be the max uint64.
+		if height == to {
+			break
+		}
+	}
+	return issues
+}
+
+func (da *Accessor) checkHeight(height uint64) []LedgerIssue {
+	var issues []LedgerIssue
+	report := func(format string, args ...interface{}) {
+		issues = append(issues, LedgerIssue{Height: height, Issue: fmt.Sprintf(format, args...)})
+	}
+
+	seq, err := da.ReadSequencerByHeight(height)
+	if err != nil {
+		report("sequencer index: %v", err)
+		return issues
+	}
+	if seq.Height != height {
+		report("sequencer index holds sequencer %s of height %d", seq.GetTxHash().Hex(), seq.Height)
+	}
+	if da.ReadTransaction(seq.GetTxHash()) == nil {
+		report("sequencer %s not found", seq.GetTxHash().Hex())
+	}
+	root, err := da.ReadStateRoot(height)
+	if err != nil {
+		report("%v", err)
+	} else if root != seq.StateRoot {
+		report("state root %s mismatches sequencer's %s", root.Hex(), seq.StateRoot.Hex())
+	}
+	// genesis confirms nothing.
+	if height == 0 {
+		return issues
+	}
+
+	// the tx hashes are indexed only if there are any, the confirm time
+	// tells how many there should be.
+	cf := da.readConfirmTime(height)
+	if cf == nil {
+		report("confirm time not found")
+	}
+	hashes, err := da.ReadIndexedTxHashs(height)
+	if err != nil {
+		if cf == nil || cf.TxNum > 0 {
+			report("indexed tx hashes: %v", err)
+		}
+		return issues
+	}
+	if cf != nil && cf.TxNum != uint64(len(*hashes)) {
+		report("%d tx hashes indexed, %d confirmed", len(*hashes), cf.TxNum)
+	}
+	for _, hash := range *hashes {
+		tx := da.ReadTransaction(hash)
+		if tx == nil {
+			report("tx %s not found", hash.Hex())
+			continue
+		}
+		if tx.GetHeight() != height {
+			report("tx %s is of height %d", hash.Hex(), tx.GetHeight())
+		}
+	}
+	return issues
+}
+
+// OrphanKey is a key in db that is not reachable from the ledger.
+type OrphanKey struct {
+	Key    []byte
+	Reason string
+}
+
+// KeyReport is the result of a scan over all the keys in db.
+type KeyReport struct {
+	// Counts is the number of keys of each kind, named after the prefix.
+	Counts  map[string]int
+	Orphans []OrphanKey
+}
+
+// heightKeyPrefixes are the prefixes of the data indexed by sequencer
+// height.
+var heightKeyPrefixes = [][]byte{
+	prefixSeqHeightKey,
+	prefixTxIndexKey,
+	prefixReceiptKey,
+	prefixLogBloomKey,
+	prefixStateRootKey,
+	prefixConfirmtime,
+}
+
+// ScanKeys goes through all the keys in db and reports the ones orphaned:
+// data of the heights above the latest sequencer, txs not confirmed by any
+// sequencer, nonce indexes of missing txs, and keys of unknown kinds.
+// Keys of HashLength are state trie nodes or contract codes, which are
+// counted but not checked, and so are the preimages.
+//
+// The hashes confirmed by every height are loaded into memory first, and
+// the node must be stopped while scanning.
+func (da *Accessor) ScanKeys() (*KeyReport, error) {
+	latest := da.ReadLatestSequencer()
+	if latest == nil {
+		return nil, fmt.Errorf("latest sequencer not found")
+	}
+	confirmed := make(map[types.Hash]struct{})
+	for height := uint64(0); height <= latest.Height; height++ {
+		if seq, err := da.ReadSequencerByHeight(height); err == nil {
+			confirmed[seq.GetTxHash()] = struct{}{}
+		}
+		if hashes, err := da.ReadIndexedTxHashs(height); err == nil {
+			for _, hash := range *hashes {
+				confirmed[hash] = struct{}{}
+			}
+		}
+	}
+
+	report := &KeyReport{Counts: make(map[string]int)}
+	orphan := func(key []byte, format string, args ...interface{}) {
+		report.Orphans = append(report.Orphans, OrphanKey{
+			Key:    append([]byte{}, key...),
+			Reason: fmt.Sprintf(format, args...),
+		})
+	}
+	it := da.db.NewIterator()
+	defer it.Release()
+	for it.Next() {
+		key := it.Key()
+		kind := keyKind(key)
+		report.Counts[kind]++
+
+		switch {
+		case kind == "unknown":
+			orphan(key, "unknown key")
+		case isHeightKind(kind):
+			if len(key) != len(kind)+8 {
+				orphan(key, "malformed %s key", kind)
+				continue
+			}
+			if height := binary.BigEndian.Uint64(key[len(kind):]); height > latest.Height {
+				orphan(key, "%s of height %d above latest %d", kind, height, latest.Height)
+			}
+		case kind == string(prefixTransactionKey):
+			if len(key) != len(prefixTransactionKey)+types.HashLength {
+				orphan(key, "malformed tx key")
+				continue
+			}
+			hash := types.BytesToHash(key[len(prefixTransactionKey):])
+			if _, ok := confirmed[hash]; !ok {
+				orphan(key, "tx %s not confirmed by any sequencer", hash.Hex())
+			}
+		case kind == string(prefixTxHashFlowKey):
+			hash := types.BytesToHash(it.Value())
+			if _, ok := confirmed[hash]; !ok {
+				orphan(key, "nonce index of tx %s not confirmed by any sequencer", hash.Hex())
+			}
+		}
+	}
+	if err := it.Error(); err != nil {
+		return report, fmt.Errorf("iterate db error: %v", err)
+	}
+	return report, nil
+}
+
+// preimageKeyPrefix is the prefix trie.Database stores the preimages of
+// the secure trie keys with.
+var preimageKeyPrefix = []byte("secure-key-")
+
+// keyKind names the kind of key after its prefix. No ledger key is of
+// HashLength, so the hash of a trie node can't be taken as a prefix.
+func keyKind(key []byte) string {
+	if len(key) == types.HashLength {
+		return "state"
+	}
+	if bytes.HasPrefix(key, preimageKeyPrefix) {
+		return "preimage"
+	}
+	for _, single := range [][]byte{prefixGenesisKey, prefixLatestSeqKey, prefixLightHeadKey} {
+		if bytes.Equal(key, single) {
+			return string(single)
+		}
+	}
+	for _, prefix := range [][]byte{
+		prefixSeqHeightKey, prefixTxIndexKey, prefixReceiptKey, prefixLogBloomKey,
+		prefixStateRootKey, prefixConfirmtime, prefixLightHeaderKey,
+		prefixTransactionKey, prefixTxHashFlowKey, prefixAddrLatestNonceKey,
+		prefixAddressBalanceKey,
+	} {
+		if bytes.HasPrefix(key, prefix) {
+			return string(prefix)
+		}
+	}
+	return "unknown"
+}
+
+func isHeightKind(kind string) bool {
+	for _, prefix := range heightKeyPrefixes {
+		if kind == string(prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/annchain/OG/common/math"
+	"github.com/annchain/OG/ogdb"
+	"github.com/annchain/OG/types"
+)
+
+func TestAccessorCheck(t *testing.T) {
+	db := ogdb.NewMemDatabase()
+	dag := newTestPushDag(t, db)
+	defer dag.Stop()
+
+	alice := types.HexToAddress("0x0a")
+	bob := types.HexToAddress("0x0b")
+	initTestPushDag(t, dag, map[types.Address]*math.BigInt{alice: math.NewBigInt(100)})
+	batch := newTestPushBatch(alice, bob, 10)
+	root, receiptsRoot, err := dag.PreConfirm(batch)
+	if err != nil {
+		t.Fatalf("pre confirm error: %v", err)
+	}
+	batch.Seq.StateRoot = root
+	batch.Seq.ReceiptsRoot = receiptsRoot
+	if err := dag.Push(batch); err != nil {
+		t.Fatalf("push error: %v", err)
+	}
+	pushTestSequencers(t, dag, alice, 3)
+
+	accessor := NewAccessor(db)
+	if issues := accessor.CheckLedger(0, 3); len(issues) != 0 {
+		t.Fatalf("expected no issues, got %v", issues)
+	}
+	report, err := accessor.ScanKeys()
+	if err != nil {
+		t.Fatalf("scan keys error: %v", err)
+	}
+	if len(report.Orphans) != 0 {
+		t.Fatalf("expected no orphans, got %v", report.Orphans)
+	}
+	if report.Counts[string(prefixSeqHeightKey)] != 4 || report.Counts["state"] == 0 {
+		t.Fatalf("wrong key counts %v", report.Counts)
+	}
+
+	// break the ledger and leave some keys behind.
+	accessor.DeleteTransaction(types.HexToHash("0x01"))
+	accessor.DeleteSequencerByHeight(3)
+	db.Put(seqHeightKey(7), []byte{1})
+	db.Put(transactionKey(types.HexToHash("0x99")), []byte{1})
+	db.Put([]byte("zz"), []byte{1})
+
+	issues := accessor.CheckLedger(0, 3)
+	if len(issues) != 2 || issues[0].Height != 1 || issues[1].Height != 3 {
+		t.Fatalf("expected issues at height 1 and 3, got %v", issues)
+	}
+	report, err = accessor.ScanKeys()
+	if err != nil {
+		t.Fatalf("scan keys error: %v", err)
+	}
+	// the sequencer not indexed any more and its nonce index are orphaned
+	// too.
+	var reasons []string
+	for _, orphan := range report.Orphans {
+		reasons = append(reasons, orphan.Reason)
+	}
+	if len(reasons) != 5 {
+		t.Fatalf("expected 5 orphans, got %v", reasons)
+	}
+	for i, want := range []string{"height 7 above latest 3", "unknown key", "nonce index"} {
+		found := false
+		for _, reason := range reasons {
+			found = found || strings.Contains(reason, want)
+		}
+		if !found {
+			t.Fatalf("orphan %d %q not reported in %v", i, want, reasons)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package core
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/annchain/OG/core/state"
+	"github.com/annchain/OG/types"
+)
+
+// DelegateStorageAddress is the system account whose storage keeps the
+// delegate set of dpos consensus. The layout of the storage is:
+//
+//	slot 0     - the number of delegates n.
+//	slot 1..n  - the address of each delegate, in proposing order.
+var DelegateStorageAddress = types.HexToAddress("0x000000000000000000000000000000000000d01e")
+
+func listSlot(i uint64) types.Hash {
+	return types.BigToHash(new(big.Int).SetUint64(i))
+}
+
+// readAddressList reads the address list kept in the storage of owner,
+// slot 0 is the length of the list and slot 1..n are the items. The list
+// is rejected if it is longer than max.
+func readAddressList(sd *state.StateDB, owner types.Address, max uint64) ([]types.Address, error) {
+	return decodeAddressList(func(key types.Hash) types.Hash {
+		return sd.GetState(owner, key)
+	}, max)
+}
+
+// decodeAddressList decodes an address list of at most max items from the
+// storage values returned by get. The length is checked before anything is
+// allocated for the list, as it may come from a peer.
+func decodeAddressList(get func(key types.Hash) types.Hash, max uint64) ([]types.Address, error) {
+	length := get(listSlot(0)).Big()
+	if !length.IsUint64() || length.Uint64() > max {
+		return nil, fmt.Errorf("address list of length %s exceeds the max %d", length, max)
+	}
+	count := length.Uint64()
+	addrs := make([]types.Address, 0, count)
+	for i := uint64(1); i <= count; i++ {
+		value := get(listSlot(i))
+		addrs = append(addrs, types.BytesToAddress(value.Bytes[types.HashLength-types.AddressLength:]))
+	}
+	return addrs, nil
+}
+
+// writeAddressList replaces the address list kept in the storage of owner.
+func writeAddressList(sd *state.StateDB, owner types.Address, addrs []types.Address) {
+	oldCount := sd.GetState(owner, listSlot(0)).Big().Uint64()
+	for i, addr := range addrs {
+		sd.SetState(owner, listSlot(uint64(i+1)), types.BytesToHash(addr.ToBytes()))
+	}
+	// clear the slots left by the old list.
+	for i := uint64(len(addrs)) + 1; i <= oldCount; i++ {
+		sd.SetState(owner, listSlot(i), types.Hash{})
+	}
+	sd.SetState(owner, listSlot(0), types.BigToHash(new(big.Int).SetUint64(uint64(len(addrs)))))
+}
+
+// appendAddressList appends addr to the address list kept in the storage
+// of owner.
+func appendAddressList(sd *state.StateDB, owner types.Address, addr types.Address) {
+	count := sd.GetState(owner, listSlot(0)).Big().Uint64() + 1
+	sd.SetState(owner, listSlot(count), types.BytesToHash(addr.ToBytes()))
+	sd.SetState(owner, listSlot(0), types.BigToHash(new(big.Int).SetUint64(count)))
+}
+
+// readDelegates reads the current delegate set of at most max delegates
+// from statedb.
+func readDelegates(sd *state.StateDB, max int) ([]types.Address, error) {
+	return readAddressList(sd, DelegateStorageAddress, uint64(max))
+}
+
+// DelegateSlots returns the storage keys of DelegateStorageAddress which
+// keep a delegate set of at most max delegates. Light nodes ask the proofs
+// of them to learn the delegates at a state root.
+func DelegateSlots(max int) []types.Hash {
+	keys := make([]types.Hash, 0, max+1)
+	for i := 0; i <= max; i++ {
+		keys = append(keys, listSlot(uint64(i)))
+	}
+	return keys
+}
+
+// DecodeDelegates decodes the delegate set of at most max delegates from
+// the storage values of DelegateStorageAddress returned by get.
+func DecodeDelegates(get func(key types.Hash) types.Hash, max int) ([]types.Address, error) {
+	return decodeAddressList(get, uint64(max))
+}
+
+// writeDelegates replaces the delegate set in statedb.
+func writeDelegates(sd *state.StateDB, delegates []types.Address) {
+	writeAddressList(sd, DelegateStorageAddress, delegates)
+}
@@ -0,0 +1,205 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/annchain/OG/types"
+	log "github.com/sirupsen/logrus"
+)
+
+// An exported chain file starts with chainFileMagic and the version of the
+// format, followed by the records of genesis and every sequencer in
+// ascending heights. The record of a height is the msgp encoded sequencer
+// and then the msgp encoded txs it confirms, each prefixed with its length
+// as a big endian uint32.
+var chainFileMagic = []byte("ogchain")
+
+const (
+	chainFileVersion = 1
+	// maxChainRecordSize guards against allocating for a broken length.
+	maxChainRecordSize = 256 * 1024 * 1024
+)
+
+// ExportChain writes genesis and the sequencers up to height to, with the
+// txs confirmed by each of them, into w.
+func ExportChain(dag *Dag, w io.Writer, to uint64) error {
+	if latest := dag.LatestSequencer(); latest == nil || latest.Height < to {
+		return fmt.Errorf("height %d is above the latest sequencer", to)
+	}
+	bw := bufio.NewWriter(w)
+	header := make([]byte, len(chainFileMagic)+4)
+	copy(header, chainFileMagic)
+	binary.BigEndian.PutUint32(header[len(chainFileMagic):], chainFileVersion)
+	if _, err := bw.Write(header); err != nil {
+		return err
+	}
+	for height := uint64(0); height <= to; height++ {
+		seq := dag.GetSequencerByHeight(height)
+		if seq == nil {
+			return fmt.Errorf("sequencer of height %d not found", height)
+		}
+		txs := dag.GetTxsByNumber(height)
+		if txs == nil {
+			txs = types.Txs{}
+		}
+		if err := writeChainRecord(bw, seq); err != nil {
+			return fmt.Errorf("write sequencer of height %d error: %v", height, err)
+		}
+		if err := writeChainRecord(bw, txs); err != nil {
+			return fmt.Errorf("write txs of height %d error: %v", height, err)
+		}
+		if height%1000 == 0 {
+			log.WithField("height", height).Info("exported height")
+		}
+	}
+	return bw.Flush()
+}
+
+type msgpMarshaler interface {
+	MarshalMsg([]byte) ([]byte, error)
+}
+
+func writeChainRecord(w io.Writer, m msgpMarshaler) error {
+	data, err := m.MarshalMsg(make([]byte, 4))
+	if err != nil {
+		return err
+	}
+	binary.BigEndian.PutUint32(data, uint32(len(data)-4))
+	_, err = w.Write(data)
+	return err
+}
+
+// ChainReader reads the sequencers and txs from an exported chain file.
+type ChainReader struct {
+	r *bufio.Reader
+}
+
+// NewChainReader checks the header of the exported chain file.
+func NewChainReader(r io.Reader) (*ChainReader, error) {
+	br := bufio.NewReader(r)
+	header := make([]byte, len(chainFileMagic)+4)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return nil, fmt.Errorf("read header error: %v", err)
+	}
+	if !bytes.Equal(header[:len(chainFileMagic)], chainFileMagic) {
+		return nil, fmt.Errorf("not an exported chain file")
+	}
+	if version := binary.BigEndian.Uint32(header[len(chainFileMagic):]); version != chainFileVersion {
+		return nil, fmt.Errorf("unsupported chain file version %d", version)
+	}
+	return &ChainReader{r: br}, nil
+}
+
+// Read reads the next sequencer and the txs it confirms. It returns io.EOF
+// at the end of the file.
+func (cr *ChainReader) Read() (*types.Sequencer, types.Txs, error) {
+	data, err := cr.readRecord()
+	if err != nil {
+		return nil, nil, err
+	}
+	var seq types.Sequencer
+	if _, err := seq.UnmarshalMsg(data); err != nil {
+		return nil, nil, fmt.Errorf("unmarshal sequencer error: %v", err)
+	}
+	data, err = cr.readRecord()
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, nil, fmt.Errorf("read txs of height %d error: %v", seq.Height, err)
+	}
+	var txs types.Txs
+	if _, err := txs.UnmarshalMsg(data); err != nil {
+		return nil, nil, fmt.Errorf("unmarshal txs of height %d error: %v", seq.Height, err)
+	}
+	return &seq, txs, nil
+}
+
+func (cr *ChainReader) readRecord() ([]byte, error) {
+	var size [4]byte
+	if _, err := io.ReadFull(cr.r, size[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, fmt.Errorf("truncated record")
+		}
+		return nil, err
+	}
+	n := binary.BigEndian.Uint32(size[:])
+	if n > maxChainRecordSize {
+		return nil, fmt.Errorf("record size %d is too large", n)
+	}
+	data := make([]byte, n)
+	if _, err := io.ReadFull(cr.r, data); err != nil {
+		return nil, fmt.Errorf("truncated record: %v", err)
+	}
+	return data, nil
+}
+
+// ImportChain replays the sequencers in an exported chain file onto the
+// dag of pool. Every tx and sequencer is checked by verify, then the
+// sequencer is confirmed through pool and pushed to the dag like the ones
+// received from the network. The genesis of the file must be the one of
+// the dag, and the heights already in the dag are skipped so that an
+// interrupted import can be run again, as long as their sequencers are
+// the ones in the dag. The consensus engine of pool must be set to check
+// the proposers. It returns the number of heights imported.
+func ImportChain(pool *TxPool, r io.Reader, verify func(types.Txi) error) (int, error) {
+	if pool.Consensus == nil {
+		return 0, fmt.Errorf("no consensus engine to verify the sequencers")
+	}
+	cr, err := NewChainReader(r)
+	if err != nil {
+		return 0, err
+	}
+	genesis, _, err := cr.Read()
+	if err != nil {
+		return 0, fmt.Errorf("read genesis error: %v", err)
+	}
+	if genesis.Height != 0 {
+		return 0, fmt.Errorf("chain file starts at height %d instead of genesis", genesis.Height)
+	}
+	if local := pool.dag.Genesis(); local.GetTxHash() != genesis.GetTxHash() {
+		return 0, fmt.Errorf("genesis %s mismatches local genesis %s", genesis.GetTxHash().Hex(), local.GetTxHash().Hex())
+	}
+
+	head := pool.dag.LatestSequencer().Height
+	imported := 0
+	for {
+		seq, txs, err := cr.Read()
+		if err == io.EOF {
+			return imported, nil
+		}
+		if err != nil {
+			return imported, err
+		}
+		if seq.Height <= head {
+			local := pool.dag.GetSequencerByHeight(seq.Height)
+			if local == nil {
+				return imported, fmt.Errorf("sequencer at height %d not found in the dag", seq.Height)
+			}
+			if local.GetTxHash() != seq.GetTxHash() {
+				return imported, fmt.Errorf("chain file forks from the dag at height %d: sequencer %s mismatches local %s",
+					seq.Height, seq.GetTxHash().Hex(), local.GetTxHash().Hex())
+			}
+			continue
+		}
+		for _, tx := range txs {
+			if err := verify(tx); err != nil {
+				return imported, fmt.Errorf("invalid tx %s at height %d: %v", tx.GetTxHash().Hex(), seq.Height, err)
+			}
+		}
+		if err := verify(seq); err != nil {
+			return imported, fmt.Errorf("invalid sequencer at height %d: %v", seq.Height, err)
+		}
+		if err := pool.ImportSequencer(seq, txs); err != nil {
+			return imported, fmt.Errorf("import height %d error: %v", seq.Height, err)
+		}
+		imported++
+		if seq.Height%1000 == 0 {
+			log.WithField("height", seq.Height).Info("imported height")
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/annchain/OG/common/math"
+	"github.com/annchain/OG/consensus"
+	"github.com/annchain/OG/ogdb"
+	"github.com/annchain/OG/types"
+)
+
+// acceptEngine accepts every sequencer.
+type acceptEngine struct {
+	consensus.ConsensusEngine
+}
+
+func (acceptEngine) VerifySequencer(seq *types.Sequencer, prev *types.Sequencer) error {
+	return nil
+}
+
+func newTestImportPool(dag *Dag) *TxPool {
+	pool := NewTxPool(DefaultTxPoolConfig(), dag)
+	pool.Consensus = acceptEngine{}
+	return pool
+}
+
+func TestExportImportChain(t *testing.T) {
+	alice := types.HexToAddress("0x0a")
+	bob := types.HexToAddress("0x0b")
+	balance := map[types.Address]*math.BigInt{alice: math.NewBigInt(100)}
+
+	src := newTestPushDag(t, ogdb.NewMemDatabase())
+	defer src.Stop()
+	initTestPushDag(t, src, balance)
+	// genesis accounts start from nonce 1 in the pool.
+	tx := newTestStakingTx(types.TxBaseTypeNormal, alice, bob, 10)
+	tx.Hash = types.HexToHash("0x01")
+	tx.AccountNonce = 1
+	txlist := NewTxList()
+	txlist.put(tx)
+	seq := &types.Sequencer{
+		TxBase: types.TxBase{Type: types.TxBaseTypeSequencer, Height: 1, Hash: types.HexToHash("0x02"), AccountNonce: 2},
+		Issuer: alice,
+	}
+	batch := &ConfirmBatch{
+		Seq:      seq,
+		Batch:    map[types.Address]*BatchDetail{alice: {TxList: txlist}},
+		TxHashes: &types.Hashes{tx.Hash},
+	}
+	root, receiptsRoot, err := src.PreConfirm(batch)
+	if err != nil {
+		t.Fatalf("pre confirm error: %v", err)
+	}
+	batch.Seq.StateRoot = root
+	batch.Seq.ReceiptsRoot = receiptsRoot
+	if err := src.Push(batch); err != nil {
+		t.Fatalf("push error: %v", err)
+	}
+	pushTestSequencers(t, src, bob, 4)
+
+	var partial, full bytes.Buffer
+	if err := ExportChain(src, &partial, 2); err != nil {
+		t.Fatalf("export error: %v", err)
+	}
+	if err := ExportChain(src, &full, 4); err != nil {
+		t.Fatalf("export error: %v", err)
+	}
+	if err := ExportChain(src, &bytes.Buffer{}, 5); err == nil {
+		t.Fatal("expected error on exporting above latest")
+	}
+
+	dst := newTestPushDag(t, ogdb.NewMemDatabase())
+	defer dst.Stop()
+	initTestPushDag(t, dst, balance)
+	verified := 0
+	verify := func(types.Txi) error {
+		verified++
+		return nil
+	}
+	// the proposers can't be checked without a consensus engine.
+	if _, err := ImportChain(NewTxPool(DefaultTxPoolConfig(), dst), bytes.NewReader(full.Bytes()), verify); err == nil {
+		t.Fatal("expected error on importing without a consensus engine")
+	}
+	pool := newTestImportPool(dst)
+
+	// the second import resumes from the height the first one stopped at.
+	for i, test := range []struct {
+		file    []byte
+		applied int
+	}{
+		{partial.Bytes(), 2},
+		{full.Bytes(), 2},
+		{full.Bytes(), 0},
+	} {
+		n, err := ImportChain(pool, bytes.NewReader(test.file), verify)
+		if err != nil {
+			t.Fatalf("import %d error: %v", i, err)
+		}
+		if n != test.applied {
+			t.Fatalf("import %d applied %d heights, expected %d", i, n, test.applied)
+		}
+	}
+	if verified != 5 {
+		t.Fatalf("verified %d txs, expected 5", verified)
+	}
+	if dst.LatestSequencer().Height != 4 || dst.GetBalance(bob).GetInt64() != 10 {
+		t.Fatalf("wrong ledger imported at height %d", dst.LatestSequencer().Height)
+	}
+	for h := uint64(0); h <= 4; h++ {
+		want, _ := src.StateRootAt(h)
+		if got, _ := dst.StateRootAt(h); got != want {
+			t.Fatalf("state root of height %d mismatch, got %s expected %s", h, got.Hex(), want.Hex())
+		}
+	}
+
+	// a file of another fork is refused at the first height that differs.
+	fork := newTestPushDag(t, ogdb.NewMemDatabase())
+	defer fork.Stop()
+	initTestPushDag(t, fork, balance)
+	pushTestSequencers(t, fork, bob, 4)
+	var forked bytes.Buffer
+	if err := ExportChain(fork, &forked, 4); err != nil {
+		t.Fatalf("export error: %v", err)
+	}
+	if _, err := ImportChain(pool, bytes.NewReader(forked.Bytes()), verify); err == nil || !strings.Contains(err.Error(), "forks from the dag at height 1") {
+		t.Fatalf("expected fork error at height 1, got %v", err)
+	}
+
+	// a tx rejected by verify stops the import.
+	other := newTestPushDag(t, ogdb.NewMemDatabase())
+	defer other.Stop()
+	initTestPushDag(t, other, balance)
+	_, err = ImportChain(newTestImportPool(other), bytes.NewReader(full.Bytes()), func(types.Txi) error {
+		return fmt.Errorf("bad signature")
+	})
+	if err == nil || other.LatestSequencer().Height != 0 {
+		t.Fatal("expected import to stop at the invalid tx")
+	}
+
+	// broken files are refused.
+	if _, err := ImportChain(pool, bytes.NewReader([]byte("ogchain")), verify); err == nil {
+		t.Fatal("expected error on truncated header")
+	}
+	if _, err := ImportChain(pool, bytes.NewReader(full.Bytes()[:full.Len()-1]), verify); err == nil {
+		t.Fatal("expected error on truncated record")
+	}
+}
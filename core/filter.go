@@ -0,0 +1,154 @@
+package core
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/annchain/OG/types"
+	ethtypes "github.com/annchain/OG/vm/eth/core/types"
+	vmtypes "github.com/annchain/OG/vm/types"
+)
+
+// MaxFilterHeightRange is the max number of sequencers a log filter may
+// scan in one query.
+const MaxFilterHeightRange = 10000
+
+// LogFilter selects the logs in sequencers from FromHeight to ToHeight,
+// both included. A log matches if it is emitted by any of Addresses, and
+// for each position i, its i-th topic is any of Topics[i]. An empty
+// Addresses or Topics[i] matches anything.
+type LogFilter struct {
+	FromHeight uint64
+	ToHeight   uint64
+	Addresses  []types.Address
+	Topics     [][]types.Hash
+}
+
+// LogsBloom builds the bloom of the addresses and topics of logs.
+func LogsBloom(logs []*vmtypes.Log) ethtypes.Bloom {
+	bin := new(big.Int)
+	for _, l := range logs {
+		bin.Or(bin, ethtypes.Bloom9(l.Address.ToBytes()))
+		for _, topic := range l.Topics {
+			bin.Or(bin, ethtypes.Bloom9(topic.ToBytes()))
+		}
+	}
+	return ethtypes.BytesToBloom(bin.Bytes())
+}
+
+// bloomContains checks if data may be in bloom.
+func bloomContains(bloom ethtypes.Bloom, data []byte) bool {
+	cmp := ethtypes.Bloom9(data)
+	return new(big.Int).And(bloom.Big(), cmp).Cmp(cmp) == 0
+}
+
+// mayMatch checks the bloom of a sequencer to see if it may have logs
+// matching the filter.
+func (f *LogFilter) mayMatch(bloom ethtypes.Bloom) bool {
+	if len(f.Addresses) > 0 {
+		found := false
+		for _, addr := range f.Addresses {
+			if bloomContains(bloom, addr.ToBytes()) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for _, sub := range f.Topics {
+		if len(sub) == 0 {
+			continue
+		}
+		found := false
+		for _, topic := range sub {
+			if bloomContains(bloom, topic.ToBytes()) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Match checks if the log matches the filter. The heights are not checked.
+func (f *LogFilter) Match(l *vmtypes.Log) bool {
+	if len(f.Addresses) > 0 {
+		found := false
+		for _, addr := range f.Addresses {
+			if addr == l.Address {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(f.Topics) > len(l.Topics) {
+		return false
+	}
+	for i, sub := range f.Topics {
+		if len(sub) == 0 {
+			continue
+		}
+		found := false
+		for _, topic := range sub {
+			if topic == l.Topics[i] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// FilterLogs returns the confirmed logs matching the filter, ordered by
+// sequencer height and then log index.
+func (dag *Dag) FilterLogs(f LogFilter) ([]*vmtypes.Log, error) {
+	dag.mu.RLock()
+	defer dag.mu.RUnlock()
+
+	if f.ToHeight > dag.latestSequencer.Height {
+		f.ToHeight = dag.latestSequencer.Height
+	}
+	if f.FromHeight > f.ToHeight {
+		return nil, fmt.Errorf("from height %d is larger than to height %d", f.FromHeight, f.ToHeight)
+	}
+	if f.ToHeight-f.FromHeight >= MaxFilterHeightRange {
+		return nil, fmt.Errorf("height range exceeds the limit %d", MaxFilterHeightRange)
+	}
+
+	var logs []*vmtypes.Log
+	for height := f.FromHeight; height <= f.ToHeight; height++ {
+		bloom, ok := dag.accessor.ReadLogBloom(height)
+		if !ok || !f.mayMatch(bloom) {
+			continue
+		}
+		receipts, err := dag.accessor.ReadReceipts(height)
+		if err != nil {
+			return nil, err
+		}
+		var seqLogs []*vmtypes.Log
+		for _, receipt := range receipts {
+			for _, l := range receipt.Logs {
+				if f.Match(l) {
+					seqLogs = append(seqLogs, l)
+				}
+			}
+		}
+		sort.Slice(seqLogs, func(i, j int) bool {
+			return seqLogs[i].Index < seqLogs[j].Index
+		})
+		logs = append(logs, seqLogs...)
+	}
+	return logs, nil
+}
@@ -0,0 +1,87 @@
+package core
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/annchain/OG/common/math"
+	"github.com/annchain/OG/ogdb"
+	"github.com/annchain/OG/types"
+)
+
+func TestDagFilterLogs(t *testing.T) {
+	dag := newTestPushDag(t, ogdb.NewMemDatabase())
+	defer dag.Stop()
+
+	alice := types.HexToAddress("0x0a")
+	initTestPushDag(t, dag, map[types.Address]*math.BigInt{alice: math.NewBigInt(1000000)})
+
+	// init code storing 42 in memory and emitting it with one topic:
+	// PUSH1 42 PUSH1 0 MSTORE PUSH32 topic PUSH1 32 PUSH1 0 LOG1 STOP
+	topic := types.HexToHash("0xabcdef")
+	code := []byte{0x60, 0x2a, 0x60, 0x00, 0x52, 0x7f}
+	code = append(code, topic.ToBytes()...)
+	code = append(code, 0x60, 0x20, 0x60, 0x00, 0xa1, 0x00)
+
+	batch := newTestPushBatch(alice, types.Address{}, 0)
+	tx := batch.Batch[alice].TxList.get(0).(*types.Tx)
+	tx.Data = code
+	tx.GasLimit = 100000
+	root, receiptsRoot, err := dag.PreConfirm(batch)
+	if err != nil {
+		t.Fatalf("pre confirm error: %v", err)
+	}
+	batch.Seq.StateRoot = root
+	batch.Seq.ReceiptsRoot = receiptsRoot
+	if err := dag.Push(batch); err != nil {
+		t.Fatalf("push error: %v", err)
+	}
+
+	receipt := dag.GetReceipt(tx.GetTxHash())
+	if receipt == nil || receipt.Status != ReceiptStatusTxSuccess {
+		t.Fatalf("contract creation failed: %v", receipt)
+	}
+	if len(receipt.Logs) != 1 {
+		t.Fatalf("expected 1 log in receipt, got %d", len(receipt.Logs))
+	}
+	l := receipt.Logs[0]
+	if l.Address != receipt.ContractAddress || l.TxHash != tx.GetTxHash() || l.SequenceID != 1 {
+		t.Fatalf("wrong log fields: %+v", l)
+	}
+	if !bytes.Equal(l.Data, types.BigToHash(math.NewBigInt(42).Value).ToBytes()) {
+		t.Fatalf("wrong log data %x", l.Data)
+	}
+
+	cases := []struct {
+		filter LogFilter
+		num    int
+	}{
+		{LogFilter{ToHeight: 1}, 1},
+		{LogFilter{ToHeight: 1, Addresses: []types.Address{receipt.ContractAddress}}, 1},
+		{LogFilter{ToHeight: 1, Topics: [][]types.Hash{{types.HexToHash("0x01"), topic}}}, 1},
+		{LogFilter{ToHeight: 1, Topics: [][]types.Hash{{}, {topic}}}, 0},
+		{LogFilter{ToHeight: 1, Addresses: []types.Address{alice}}, 0},
+		{LogFilter{ToHeight: 1, Topics: [][]types.Hash{{types.HexToHash("0x01")}}}, 0},
+		{LogFilter{ToHeight: 0}, 0},
+	}
+	for i, c := range cases {
+		logs, err := dag.FilterLogs(c.filter)
+		if err != nil {
+			t.Fatalf("case %d: filter error: %v", i, err)
+		}
+		if len(logs) != c.num {
+			t.Fatalf("case %d: expected %d logs, got %d", i, c.num, len(logs))
+		}
+	}
+	if _, err := dag.FilterLogs(LogFilter{FromHeight: 2, ToHeight: 3}); err == nil {
+		t.Fatal("expected error on range above latest height")
+	}
+
+	// the bloom is removed together with the sequencer.
+	if err := dag.RollBack(0); err != nil {
+		t.Fatalf("roll back error: %v", err)
+	}
+	if _, ok := dag.accessor.ReadLogBloom(1); ok {
+		t.Fatal("log bloom should be removed by roll back")
+	}
+}
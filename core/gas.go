@@ -0,0 +1,120 @@
+package core
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/annchain/OG/common/math"
+	"github.com/annchain/OG/types"
+	"github.com/annchain/OG/vm/eth/params"
+)
+
+// isContractTx checks if tx creates or calls a contract. Only contract
+// txs consume gas, plain transfers and staking txs are free.
+func isContractTx(tx *types.Tx) bool {
+	return !tx.Type.IsStaking() && len(tx.Data) != 0
+}
+
+// IntrinsicGas computes the gas a contract tx consumes before the vm
+// runs, which is charged for the tx itself and every byte of its data.
+func IntrinsicGas(data []byte, contractCreation bool) (uint64, error) {
+	gas := params.TxGas
+	if contractCreation {
+		gas = params.TxGasContractCreation
+	}
+	var nz uint64
+	for _, b := range data {
+		if b != 0 {
+			nz++
+		}
+	}
+	z := uint64(len(data)) - nz
+	if nz > 0 {
+		if (^uint64(0)-gas)/params.TxDataNonZeroGas < nz {
+			return 0, fmt.Errorf("gas uint64 overflow")
+		}
+		gas += nz * params.TxDataNonZeroGas
+	}
+	if z > 0 {
+		if (^uint64(0)-gas)/params.TxDataZeroGas < z {
+			return 0, fmt.Errorf("gas uint64 overflow")
+		}
+		gas += z * params.TxDataZeroGas
+	}
+	return gas, nil
+}
+
+// gasFee returns gas * price.
+func gasFee(gas uint64, price *math.BigInt) *math.BigInt {
+	fee := new(big.Int).SetUint64(gas)
+	return math.NewBigIntFromBigInt(fee.Mul(fee, price.Value))
+}
+
+// txCost returns the most a tx may spend from its sender, which is the
+// value plus the up-front gas cost of contract txs.
+func txCost(txi types.Txi) *math.BigInt {
+	tx, ok := txi.(*types.Tx)
+	if !ok || !isContractTx(tx) {
+		return txi.GetValue()
+	}
+	cost := gasFee(tx.GasLimit, tx.GetGasPrice())
+	cost.Value.Add(cost.Value, tx.Value.Value)
+	return cost
+}
+
+// EstimateGas finds the lowest gas limit that tx executes successfully
+// with, by simulating it on the latest state. The gas limit of tx is
+// taken as the upper bound, or DefaultGasLimit if it is not set. Txs
+// not calling contracts need no gas.
+func (dag *Dag) EstimateGas(tx *types.Tx) (uint64, error) {
+	if !isContractTx(tx) {
+		return 0, nil
+	}
+	sim := *tx
+	hi := tx.GasLimit
+	if hi == 0 {
+		hi = DefaultGasLimit
+	}
+	// the sender can't pay for more gas than its balance allows.
+	if price := tx.GetGasPrice(); price.Value.Sign() > 0 {
+		allowance := new(big.Int).Sub(dag.GetBalance(tx.From).Value, tx.GetValue().Value)
+		if allowance.Sign() < 0 {
+			return 0, fmt.Errorf("insufficient balance for value %s", tx.GetValue())
+		}
+		allowance.Div(allowance, price.Value)
+		if allowance.IsUint64() && allowance.Uint64() < hi {
+			hi = allowance.Uint64()
+		}
+	}
+	executable := func(gas uint64) (*Receipt, error) {
+		sim.GasLimit = gas
+		_, receipt, err := dag.SimulateTx(&sim)
+		return receipt, err
+	}
+
+	receipt, err := executable(hi)
+	if err != nil {
+		return 0, err
+	}
+	if receipt.Status != ReceiptStatusTxSuccess {
+		return 0, fmt.Errorf("tx fails with gas limit %d: %s", hi, receipt.ProcessResult)
+	}
+	lo, err := IntrinsicGas(tx.Data, tx.To.Bytes == emptyAddress.Bytes)
+	if err != nil {
+		return 0, err
+	}
+	lo--
+	for lo+1 < hi {
+		mid := lo + (hi-lo)/2
+		receipt, err := executable(mid)
+		if err != nil {
+			return 0, err
+		}
+		if receipt.Status == ReceiptStatusTxSuccess {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	return hi, nil
+}
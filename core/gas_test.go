@@ -0,0 +1,126 @@
+package core
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/annchain/OG/common/math"
+	"github.com/annchain/OG/ogdb"
+	"github.com/annchain/OG/types"
+)
+
+func TestDagProcessContractGas(t *testing.T) {
+	dag := newTestPushDag(t, ogdb.NewMemDatabase())
+	defer dag.Stop()
+
+	alice := types.HexToAddress("0x0a")
+	coinbase := types.HexToAddress("0x0c")
+	initTestPushDag(t, dag, map[types.Address]*math.BigInt{alice: math.NewBigInt(1000000000)})
+	sd := dag.StateDatabase()
+
+	// evm contract bytecode of vm/vm_test/contracts/setter.sol
+	code, _ := hex.DecodeString("6060604052341561000f57600080fd5b600a60008190555060006001819055506102078061002e6000396000f300606060405260043610610062576000357c0100000000000000000000000000000000000000000000000000000000900463ffffffff1680631c0f72e11461006b57806360fe47b114610094578063c605f76c146100b7578063e5aa3d5814610145575b34600181905550005b341561007657600080fd5b61007e61016e565b6040518082815260200191505060405180910390f35b341561009f57600080fd5b6100b56004808035906020019091905050610174565b005b34156100c257600080fd5b6100ca61017e565b6040518080602001828103825283818151815260200191508051906020019080838360005b8381101561010a5780820151818401526020810190506100ef565b50505050905090810190601f1680156101375780820380516001836020036101000a031916815260200191505b509250505060405180910390f35b341561015057600080fd5b6101586101c1565b6040518082815260200191505060405180910390f35b60015481565b8060008190555050565b6101866101c7565b6040805190810160405280600a81526020017f68656c6c6f576f726c6400000000000000000000000000000000000000000000815250905090565b60005481565b6020604051908101604052806000815250905600a165627a7a723058208e1bdbeee227900e60082cfcc0e44d400385e8811ae77ac6d7f3b72f630f04170029")
+	intrinsic, err := IntrinsicGas(code, true)
+	if err != nil {
+		t.Fatalf("intrinsic gas error: %v", err)
+	}
+	newCreateTx := func(gasLimit uint64) *types.Tx {
+		tx := newTestStakingTx(types.TxBaseTypeNormal, alice, types.Address{}, 0)
+		tx.Data = code
+		tx.GasLimit = gasLimit
+		tx.GasPrice = math.NewBigInt(2)
+		return tx
+	}
+
+	// a tx not covering its intrinsic gas pays nothing.
+	_, receipt, err := dag.processTransaction(sd, newCreateTx(intrinsic-1), coinbase)
+	if err != nil {
+		t.Fatalf("process error: %v", err)
+	}
+	if receipt.Status != ReceiptStatusOVMFailed || receipt.GasUsed != 0 {
+		t.Fatalf("expected failure without gas used, got status %d, gas used %d", receipt.Status, receipt.GasUsed)
+	}
+	if b := sd.GetBalance(alice).GetInt64(); b != 1000000000 {
+		t.Fatalf("alice should pay nothing, got balance %d", b)
+	}
+
+	// the gas used is paid to coinbase and the rest is refunded.
+	_, receipt, err = dag.processTransaction(sd, newCreateTx(1000000), coinbase)
+	if err != nil {
+		t.Fatalf("process error: %v", err)
+	}
+	if receipt.Status != ReceiptStatusTxSuccess {
+		t.Fatalf("contract creation failed: %s", receipt.ProcessResult)
+	}
+	if receipt.GasUsed <= intrinsic || receipt.GasUsed >= 1000000 {
+		t.Fatalf("unexpected gas used %d", receipt.GasUsed)
+	}
+	fee := int64(receipt.GasUsed) * 2
+	if b := sd.GetBalance(coinbase).GetInt64(); b != fee {
+		t.Fatalf("coinbase should get %d, got %d", fee, b)
+	}
+	if b := sd.GetBalance(alice).GetInt64(); b != 1000000000-fee {
+		t.Fatalf("alice should have %d, got %d", 1000000000-fee, b)
+	}
+
+	// a failed execution consumes all its gas and leaves no contract.
+	_, receipt, err = dag.processTransaction(sd, newCreateTx(intrinsic+100), coinbase)
+	if err != nil {
+		t.Fatalf("process error: %v", err)
+	}
+	if receipt.Status != ReceiptStatusOVMFailed || receipt.GasUsed != intrinsic+100 {
+		t.Fatalf("expected out of gas, got status %d, gas used %d", receipt.Status, receipt.GasUsed)
+	}
+	if b := sd.GetBalance(coinbase).GetInt64(); b != fee+int64(intrinsic+100)*2 {
+		t.Fatalf("coinbase should get the fee of failed tx, got %d", b)
+	}
+}
+
+func TestDagEstimateGas(t *testing.T) {
+	dag := newTestPushDag(t, ogdb.NewMemDatabase())
+	defer dag.Stop()
+
+	alice := types.HexToAddress("0x0a")
+	initTestPushDag(t, dag, map[types.Address]*math.BigInt{alice: math.NewBigInt(1000000000)})
+	root := dag.StateDatabase().IntermediateRoot()
+
+	// init code storing 42 in slot 0: PUSH1 42 PUSH1 0 SSTORE STOP
+	tx := newTestStakingTx(types.TxBaseTypeNormal, alice, types.Address{}, 0)
+	tx.Data = []byte{0x60, 0x2a, 0x60, 0x00, 0x55, 0x00}
+	tx.GasPrice = math.NewBigInt(1)
+
+	gas, err := dag.EstimateGas(tx)
+	if err != nil {
+		t.Fatalf("estimate gas error: %v", err)
+	}
+	intrinsic, _ := IntrinsicGas(tx.Data, true)
+	if gas <= intrinsic {
+		t.Fatalf("estimated gas %d should be larger than intrinsic gas %d", gas, intrinsic)
+	}
+
+	sim := *tx
+	sim.GasLimit = gas
+	_, receipt, err := dag.SimulateTx(&sim)
+	if err != nil || receipt.Status != ReceiptStatusTxSuccess {
+		t.Fatalf("tx should succeed with the estimated gas, err: %v, receipt: %v", err, receipt)
+	}
+	sim.GasLimit = gas - 1
+	_, receipt, err = dag.SimulateTx(&sim)
+	if err != nil || receipt.Status == ReceiptStatusTxSuccess {
+		t.Fatalf("tx should fail with less gas, err: %v, receipt: %v", err, receipt)
+	}
+
+	// simulation leaves no change on the state.
+	if r := dag.StateDatabase().IntermediateRoot(); r != root {
+		t.Fatalf("state changed by simulation, root %s, expected %s", r.Hex(), root.Hex())
+	}
+	if b := dag.GetBalance(alice).GetInt64(); b != 1000000000 {
+		t.Fatalf("alice's balance changed by simulation: %d", b)
+	}
+
+	// a plain transfer needs no gas.
+	gas, err = dag.EstimateGas(newTestStakingTx(types.TxBaseTypeNormal, alice, types.HexToAddress("0x0b"), 0))
+	if err != nil || gas != 0 {
+		t.Fatalf("expected no gas for transfer, got %d, err: %v", gas, err)
+	}
+}
@@ -1,13 +1,23 @@
 package core
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+
 	"github.com/annchain/OG/account"
 	"github.com/annchain/OG/common"
 	"github.com/annchain/OG/common/crypto"
+	"github.com/annchain/OG/common/hexutil"
 	"github.com/annchain/OG/common/math"
+	"github.com/annchain/OG/core/state"
+	"github.com/annchain/OG/ogdb"
 	"github.com/annchain/OG/types"
 	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
 )
 
 const MaxAccountCount = 200
@@ -32,6 +42,194 @@ func DefaultGenesis(cryptoType crypto.CryptoType) (*types.Sequencer, map[types.A
 	return seq.(*types.Sequencer), balance
 }
 
+// DefaultGenesisDelegates returns the delegate set of the default genesis,
+// which is the first sample account.
+func DefaultGenesisDelegates(cryptoType crypto.CryptoType) []types.Address {
+	accounts := GetSampleAccounts(cryptoType)
+	return []types.Address{accounts[0].Address}
+}
+
+// SampleGenesis returns the genesis of DefaultGenesis as a genesis file
+// would describe it, with delegates as the validators.
+func SampleGenesis(cryptoType crypto.CryptoType, delegates []types.Address) *Genesis {
+	seq, balance := DefaultGenesis(cryptoType)
+	g := &Genesis{
+		Timestamp:  seq.Timestamp,
+		Validators: delegates,
+		Alloc:      make(map[types.Address]GenesisAccount, len(balance)),
+		PublicKey:  seq.PublicKey,
+		Signature:  seq.Signature,
+	}
+	for addr, value := range balance {
+		g.Alloc[addr] = GenesisAccount{Balance: value.String()}
+	}
+	return g
+}
+
+// ChainParamsStorageAddress is the system account whose storage keeps the
+// chain parameters of genesis, so that they are covered by the genesis
+// state root and hash. The layout of the storage is:
+//
+//	slot 0 - the chain id.
+//	slot 1 - the max tx hash.
+//	slot 2 - the max mined hash.
+var ChainParamsStorageAddress = types.HexToAddress("0x000000000000000000000000000000000000d020")
+
+// Genesis describes the genesis of a network, read from a genesis file.
+type Genesis struct {
+	// ChainID is the network id of the chain, the one in config is used if
+	// zero.
+	ChainID uint64 `json:"chain_id"`
+	// Timestamp of the genesis sequencer in milliseconds.
+	Timestamp int64 `json:"timestamp"`
+	// MaxTxHash and MaxMinedHash are the difficulty targets of the txs,
+	// the ones in config are used if not set.
+	MaxTxHash    *types.Hash `json:"max_tx_hash,omitempty"`
+	MaxMinedHash *types.Hash `json:"max_mined_hash,omitempty"`
+	// Validators is the initial delegate set.
+	Validators []types.Address                  `json:"validators"`
+	Alloc      map[types.Address]GenesisAccount `json:"alloc"`
+	// PublicKey and Signature are kept by the genesis sequencer, they make
+	// a different genesis hash for each network.
+	PublicKey hexutil.Bytes `json:"public_key,omitempty"`
+	Signature hexutil.Bytes `json:"signature,omitempty"`
+}
+
+// GenesisAccount is the state of an account in genesis.
+type GenesisAccount struct {
+	// Balance is a decimal or 0x prefixed hex number.
+	Balance string                    `json:"balance"`
+	Nonce   uint64                    `json:"nonce,omitempty"`
+	Code    hexutil.Bytes             `json:"code,omitempty"`
+	Storage map[types.Hash]types.Hash `json:"storage,omitempty"`
+}
+
+// LoadGenesis reads a genesis file in json, or in toml if the file name
+// ends with .toml.
+func LoadGenesis(path string) (*Genesis, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	// the toml file is decoded into a map and then converted into json,
+	// so that both are decoded by the same json tags.
+	if filepath.Ext(path) == ".toml" {
+		v := viper.New()
+		v.SetConfigType("toml")
+		if err := v.ReadConfig(bytes.NewReader(data)); err != nil {
+			return nil, fmt.Errorf("parse genesis file error: %v", err)
+		}
+		data, err = json.Marshal(v.AllSettings())
+		if err != nil {
+			return nil, fmt.Errorf("parse genesis file error: %v", err)
+		}
+	}
+	var g Genesis
+	if err := json.Unmarshal(data, &g); err != nil {
+		return nil, fmt.Errorf("parse genesis file error: %v", err)
+	}
+	if err := g.validate(); err != nil {
+		return nil, fmt.Errorf("invalid genesis file: %v", err)
+	}
+	return &g, nil
+}
+
+func (g *Genesis) validate() error {
+	if len(g.Validators) == 0 {
+		return fmt.Errorf("no validators")
+	}
+	for addr, account := range g.Alloc {
+		if _, err := account.balance(); err != nil {
+			return fmt.Errorf("account %s: %v", addr.Hex(), err)
+		}
+	}
+	return nil
+}
+
+func (a GenesisAccount) balance() (*big.Int, error) {
+	if a.Balance == "" {
+		return new(big.Int), nil
+	}
+	balance, ok := math.ParseBig256(a.Balance)
+	if !ok || balance.Sign() < 0 {
+		return nil, fmt.Errorf("invalid balance %q", a.Balance)
+	}
+	return balance, nil
+}
+
+// Sequencer returns the genesis sequencer, whose hash covers the root of
+// the genesis state.
+func (g *Genesis) Sequencer() (*types.Sequencer, error) {
+	root, err := g.StateRoot()
+	if err != nil {
+		return nil, err
+	}
+	seq := newUnsignedSequencer(0, 0).(*types.Sequencer)
+	seq.Timestamp = g.Timestamp
+	seq.PublicKey = common.CopyBytes(g.PublicKey)
+	seq.Signature = common.CopyBytes(g.Signature)
+	seq.StateRoot = root
+	seq.SetHash(seq.CalcTxHash())
+	return seq, nil
+}
+
+// apply writes the allocations, the validators and the chain parameters
+// into sd.
+func (g *Genesis) apply(sd *state.StateDB) error {
+	for addr, account := range g.Alloc {
+		balance, err := account.balance()
+		if err != nil {
+			return fmt.Errorf("account %s: %v", addr.Hex(), err)
+		}
+		sd.SetBalance(addr, math.NewBigIntFromBigInt(balance))
+		if account.Nonce > 0 {
+			sd.SetNonce(addr, account.Nonce)
+		}
+		if len(account.Code) > 0 {
+			sd.SetCode(addr, account.Code)
+		}
+		for key, value := range account.Storage {
+			sd.SetState(addr, key, value)
+		}
+	}
+	writeDelegates(sd, g.Validators)
+
+	sd.SetState(ChainParamsStorageAddress, listSlot(0), types.BigToHash(new(big.Int).SetUint64(g.ChainID)))
+	if g.MaxTxHash != nil {
+		sd.SetState(ChainParamsStorageAddress, listSlot(1), *g.MaxTxHash)
+	}
+	if g.MaxMinedHash != nil {
+		sd.SetState(ChainParamsStorageAddress, listSlot(2), *g.MaxMinedHash)
+	}
+	return nil
+}
+
+// StateRoot computes the root of the genesis state in memory.
+func (g *Genesis) StateRoot() (types.Hash, error) {
+	sd, err := state.NewStateDB(state.DefaultStateDBConfig(), state.NewDatabase(ogdb.NewMemDatabase()), types.Hash{})
+	if err != nil {
+		return types.Hash{}, err
+	}
+	defer sd.Stop()
+	if err := g.apply(sd); err != nil {
+		return types.Hash{}, err
+	}
+	return sd.Commit()
+}
+
+// Check returns an error if seq is not the genesis sequencer of g. The
+// hash is enough to tell as it covers the genesis state root.
+func (g *Genesis) Check(seq *types.Sequencer) error {
+	want, err := g.Sequencer()
+	if err != nil {
+		return err
+	}
+	if seq.GetTxHash() != want.GetTxHash() {
+		return fmt.Errorf("genesis hash %s mismatches %s of the genesis file", seq.GetTxHash().Hex(), want.GetTxHash().Hex())
+	}
+	return nil
+}
+
 func GetSampleAccounts(cryptoType crypto.CryptoType) []*account.SampleAccount {
 	var accounts []*account.SampleAccount
 	if cryptoType == crypto.CryptoTypeSecp256k1 {
@@ -2,8 +2,12 @@ package core
 
 import (
 	"github.com/annchain/OG/common/crypto"
+	"github.com/annchain/OG/ogdb"
 	"github.com/annchain/OG/types"
 	"github.com/sirupsen/logrus"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -12,3 +16,120 @@ func TestName(t *testing.T) {
 	logrus.Info(a)
 	DefaultGenesis(crypto.CryptoTypeSecp256k1)
 }
+
+func writeTestGenesisFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("write genesis file error: %v", err)
+	}
+	return path
+}
+
+func TestLoadGenesis(t *testing.T) {
+	dir, err := ioutil.TempDir("", "og_genesis_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	jsonFile := writeTestGenesisFile(t, dir, "genesis.json", `{
+		"chain_id": 7,
+		"timestamp": 1000,
+		"max_tx_hash": "0x0fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+		"validators": ["0x0000000000000000000000000000000000000001"],
+		"alloc": {
+			"0x000000000000000000000000000000000000000a": {"balance": "1000"},
+			"0x000000000000000000000000000000000000000b": {"balance": "0x10", "nonce": 2, "code": "0x6001", "storage": {"0x0000000000000000000000000000000000000000000000000000000000000001": "0x0000000000000000000000000000000000000000000000000000000000000002"}}
+		}
+	}`)
+	tomlFile := writeTestGenesisFile(t, dir, "genesis.toml", `
+chain_id = 7
+timestamp = 1000
+max_tx_hash = "0x0fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"
+validators = ["0x0000000000000000000000000000000000000001"]
+[alloc.0x000000000000000000000000000000000000000a]
+balance = "1000"
+[alloc.0x000000000000000000000000000000000000000b]
+balance = "0x10"
+nonce = 2
+code = "0x6001"
+[alloc.0x000000000000000000000000000000000000000b.storage]
+0x0000000000000000000000000000000000000000000000000000000000000001 = "0x0000000000000000000000000000000000000000000000000000000000000002"
+`)
+	g, err := LoadGenesis(jsonFile)
+	if err != nil {
+		t.Fatalf("load json genesis error: %v", err)
+	}
+	gt, err := LoadGenesis(tomlFile)
+	if err != nil {
+		t.Fatalf("load toml genesis error: %v", err)
+	}
+	if g.ChainID != 7 || *g.MaxTxHash != types.HexToHash("0x0fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff") {
+		t.Fatalf("wrong genesis %+v", g)
+	}
+	root, err := g.StateRoot()
+	if err != nil {
+		t.Fatalf("state root error: %v", err)
+	}
+	if rt, _ := gt.StateRoot(); rt != root {
+		t.Fatalf("toml genesis has a different state root %s from json %s", rt.Hex(), root.Hex())
+	}
+
+	dag := newTestPushDag(t, ogdb.NewMemDatabase())
+	defer dag.Stop()
+	if err := dag.InitGenesis(g); err != nil {
+		t.Fatalf("init genesis error: %v", err)
+	}
+	genesis := dag.Genesis()
+	if genesis.StateRoot != root || genesis.Timestamp != 1000 {
+		t.Fatalf("wrong genesis sequencer %s", genesis.Dump())
+	}
+	if genesis.CalcTxHash() != genesis.GetTxHash() {
+		t.Fatalf("genesis hash %s mismatches its content", genesis.GetTxHash().Hex())
+	}
+	if err := g.Check(genesis); err != nil {
+		t.Fatalf("check genesis error: %v", err)
+	}
+	bob := types.HexToAddress("0x000000000000000000000000000000000000000b")
+	if b := dag.GetBalance(bob).GetInt64(); b != 16 {
+		t.Fatalf("bob should have 16, got %d", b)
+	}
+	if v := dag.GetState(bob, types.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000001")); v != types.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000002") {
+		t.Fatalf("wrong storage %s", v.Hex())
+	}
+	if d := dag.GetDelegates(); len(d) != 1 || d[0] != types.HexToAddress("0x0000000000000000000000000000000000000001") {
+		t.Fatalf("wrong delegates %v", d)
+	}
+
+	// so do other chain parameters.
+	g.ChainID = 8
+	if err := g.Check(genesis); err == nil {
+		t.Fatal("expected error on chain id mismatch")
+	}
+	g.ChainID = 7
+	g.MaxTxHash = nil
+	if err := g.Check(genesis); err == nil {
+		t.Fatal("expected error on max tx hash mismatch")
+	}
+	g.MaxTxHash = gt.MaxTxHash
+	if err := g.Check(genesis); err != nil {
+		t.Fatalf("check genesis error: %v", err)
+	}
+
+	// another allocation makes another genesis hash.
+	g.Alloc[bob] = GenesisAccount{Balance: "17"}
+	if err := g.Check(genesis); err == nil {
+		t.Fatal("expected error on allocation mismatch")
+	}
+	g.Timestamp = 1
+	g.Signature = []byte{1}
+	if err := g.Check(genesis); err == nil {
+		t.Fatal("expected error on hash mismatch")
+	}
+
+	for _, bad := range []string{`{"alloc": {}}`, `{"validators": ["0x0000000000000000000000000000000000000001"], "alloc": {"0x000000000000000000000000000000000000000a": {"balance": "-1"}}}`, `{`} {
+		if _, err := LoadGenesis(writeTestGenesisFile(t, dir, "bad.json", bad)); err == nil {
+			t.Fatalf("expected error on genesis %s", bad)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/annchain/OG/common"
+	"github.com/annchain/OG/ogdb"
+	"github.com/annchain/OG/trie"
+	"github.com/annchain/OG/types"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// GCModeArchive flushes the state of every sequencer into db, so the
+	// state of any height can be queried.
+	GCModeArchive = "archive"
+	// GCModePrune keeps the state of the latest sequencers and of the
+	// checkpoints only, the rest are garbage collected in memory.
+	GCModePrune = "prune"
+
+	DefaultStateKeepRecent         = 128
+	DefaultStateCheckpointInterval = 1024
+	DefaultTrieCacheMB             = 256
+)
+
+// stateRoot is the state root committed by the sequencer at height.
+type stateRoot struct {
+	height uint64
+	root   types.Hash
+}
+
+// statePruner keeps the state tries of the latest sequencers in the memory
+// of trie database instead of flushing every one of them into db. A root is
+// referenced when it is committed and dereferenced once it is keepRecent
+// sequencers old, which garbage collects the nodes not reachable from any
+// newer root. The roots at the checkpoint heights are flushed into db and
+// kept forever.
+//
+// The nodes already in db are never deleted, so a pruning dag only stops
+// the growth of the state and doesn't shrink an archive db.
+type statePruner struct {
+	triedb             *trie.Database
+	keepRecent         uint64
+	checkpointInterval uint64
+	cacheLimit         common.StorageSize
+
+	recent []stateRoot // Roots referenced in memory, in ascending heights
+}
+
+func newStatePruner(triedb *trie.Database, conf DagConfig) *statePruner {
+	return &statePruner{
+		triedb:             triedb,
+		keepRecent:         conf.StateKeepRecent,
+		checkpointInterval: conf.StateCheckpointInterval,
+		cacheLimit:         common.StorageSize(conf.TrieCacheMB) * 1024 * 1024,
+	}
+}
+
+// commit keeps the state root of the sequencer at height in memory, the
+// root is flushed into db if height is a checkpoint. Roots older than the
+// latest keepRecent ones are dereferenced. The trie nodes are flushed
+// from the oldest one if their size goes over the cache limit.
+func (p *statePruner) commit(height uint64, root types.Hash) error {
+	// roots left by a failed push of the same height.
+	p.truncate(height - 1)
+
+	p.triedb.Reference(root, types.Hash{})
+	p.recent = append(p.recent, stateRoot{height: height, root: root})
+	if height%p.checkpointInterval == 0 {
+		if err := p.triedb.Commit(root, false); err != nil {
+			return fmt.Errorf("flush checkpoint state of height %d error: %v", height, err)
+		}
+		log.WithField("height", height).Debug("flushed checkpoint state")
+	}
+	for len(p.recent) > 0 && p.recent[0].height+p.keepRecent <= height {
+		p.triedb.Dereference(p.recent[0].root, types.Hash{})
+		p.recent = p.recent[1:]
+	}
+	if size, _ := p.triedb.Size(); size > p.cacheLimit {
+		if err := p.triedb.Cap(p.cacheLimit - ogdb.IdealBatchSize); err != nil {
+			return fmt.Errorf("flush trie cache error: %v", err)
+		}
+	}
+	return nil
+}
+
+// truncate dereferences the roots above height, which are rolled back.
+func (p *statePruner) truncate(height uint64) {
+	for len(p.recent) > 0 && p.recent[len(p.recent)-1].height > height {
+		p.triedb.Dereference(p.recent[len(p.recent)-1].root, types.Hash{})
+		p.recent = p.recent[:len(p.recent)-1]
+	}
+}
+
+// flush writes the state of the latest sequencer into db, so that it can
+// be opened again after restart. The state kept in memory only is lost on
+// a crash, and the ledger is repaired back to the latest state in db.
+func (p *statePruner) flush() error {
+	if len(p.recent) == 0 {
+		return nil
+	}
+	latest := p.recent[len(p.recent)-1]
+	if err := p.triedb.Commit(latest.root, false); err != nil {
+		return fmt.Errorf("flush state of height %d error: %v", latest.height, err)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/annchain/OG/common/math"
+	"github.com/annchain/OG/core/state"
+	"github.com/annchain/OG/ogdb"
+	"github.com/annchain/OG/types"
+)
+
+// pushTestSequencers pushes sequencers confirming no tx from height 1 to
+// top, each of them moves the nonce of the issuer.
+func pushTestSequencers(t *testing.T, dag *Dag, issuer types.Address, top uint64) {
+	for h := dag.LatestSequencer().Height + 1; h <= top; h++ {
+		seq := &types.Sequencer{
+			TxBase: types.TxBase{Type: types.TxBaseTypeSequencer, Height: h, Hash: types.BytesToHash(encodeUint64(h))},
+			Issuer: issuer,
+		}
+		seq.AccountNonce = h
+		batch := &ConfirmBatch{Seq: seq, Batch: map[types.Address]*BatchDetail{}}
+		root, receiptsRoot, err := dag.PreConfirm(batch)
+		if err != nil {
+			t.Fatalf("pre confirm height %d error: %v", h, err)
+		}
+		seq.StateRoot = root
+		seq.ReceiptsRoot = receiptsRoot
+		if err := dag.Push(batch); err != nil {
+			t.Fatalf("push height %d error: %v", h, err)
+		}
+	}
+}
+
+func TestDagPruneState(t *testing.T) {
+	conf := DefaultDagConfig()
+	conf.GCMode = GCModePrune
+	conf.StateKeepRecent = 4
+	conf.StateCheckpointInterval = 8
+	db := ogdb.NewMemDatabase()
+	dag, err := NewDag(conf, state.DefaultStateDBConfig(), db, nil)
+	if err != nil {
+		t.Fatalf("create dag error: %v", err)
+	}
+
+	alice := types.HexToAddress("0x0a")
+	initTestPushDag(t, dag, map[types.Address]*math.BigInt{alice: math.NewBigInt(100)})
+	pushTestSequencers(t, dag, alice, 18)
+
+	// the recent states and the checkpoints are kept, the rest are pruned.
+	for h := uint64(0); h <= 18; h++ {
+		_, err := dag.GetLatestNonceAt(alice, h)
+		kept := h > 18-4 || h%8 == 0
+		if kept && err != nil {
+			t.Fatalf("state of height %d should be kept, err %v", h, err)
+		}
+		if !kept && err == nil {
+			t.Fatalf("state of height %d should be pruned", h)
+		}
+	}
+	// only the checkpoints are in db before stop.
+	root, _ := dag.StateRootAt(18)
+	if has, _ := db.Has(root.ToBytes()); has {
+		t.Fatal("state of latest height should not be flushed before stop")
+	}
+	root, _ = dag.StateRootAt(16)
+	if has, _ := db.Has(root.ToBytes()); !has {
+		t.Fatal("state of checkpoint should be flushed")
+	}
+
+	// roll back within the recent states and push again.
+	if err := dag.RollBack(16); err != nil {
+		t.Fatalf("roll back error: %v", err)
+	}
+	pushTestSequencers(t, dag, alice, 18)
+	dag.Stop()
+
+	// the latest state is flushed on stop and opened again.
+	dag, err = NewDag(conf, state.DefaultStateDBConfig(), db, nil)
+	if err != nil {
+		t.Fatalf("create dag error: %v", err)
+	}
+	defer dag.Stop()
+	if !dag.LoadLastState() {
+		t.Fatal("load last state failed")
+	}
+	if h := dag.LatestSequencer().Height; h != 18 {
+		t.Fatalf("latest height should be 18 after restart, got %d", h)
+	}
+	if nonce, err := dag.GetLatestNonce(alice); err != nil || nonce != 18 {
+		t.Fatalf("nonce of alice should be 18, got %d, err %v", nonce, err)
+	}
+}
+
+func TestDagPruneCrash(t *testing.T) {
+	conf := DefaultDagConfig()
+	conf.GCMode = GCModePrune
+	conf.StateKeepRecent = 4
+	conf.StateCheckpointInterval = 8
+	db := ogdb.NewMemDatabase()
+	dag, err := NewDag(conf, state.DefaultStateDBConfig(), db, nil)
+	if err != nil {
+		t.Fatalf("create dag error: %v", err)
+	}
+	alice := types.HexToAddress("0x0a")
+	initTestPushDag(t, dag, map[types.Address]*math.BigInt{alice: math.NewBigInt(100)})
+	pushTestSequencers(t, dag, alice, 10)
+
+	// a crash loses the states in memory, the ledger goes back to the
+	// latest checkpoint.
+	restarted, err := NewDag(conf, state.DefaultStateDBConfig(), db, nil)
+	if err != nil {
+		t.Fatalf("create dag error: %v", err)
+	}
+	defer restarted.Stop()
+	if !restarted.LoadLastState() {
+		t.Fatal("load last state failed")
+	}
+	if h := restarted.LatestSequencer().Height; h != 8 {
+		t.Fatalf("latest height should be the checkpoint 8 after crash, got %d", h)
+	}
+	pushTestSequencers(t, restarted, alice, 10)
+}
@@ -1,9 +1,13 @@
 package core
 
 import (
+	"bytes"
+	"encoding/binary"
 	"fmt"
 
+	"github.com/annchain/OG/common/crypto/sha3"
 	"github.com/annchain/OG/types"
+	vmtypes "github.com/annchain/OG/vm/types"
 )
 
 type ReceiptStatus int
@@ -12,6 +16,7 @@ const (
 	ReceiptStatusSeqSuccess ReceiptStatus = iota
 	ReceiptStatusTxSuccess
 	ReceiptStatusOVMFailed
+	ReceiptStatusStakingFailed
 )
 
 //go:generate msgp
@@ -22,6 +27,8 @@ type Receipt struct {
 	Status          ReceiptStatus
 	ProcessResult   string
 	ContractAddress types.Address
+	GasUsed         uint64
+	Logs            vmtypes.Logs
 }
 
 func NewReceipt(hash types.Hash, status ReceiptStatus, pResult string, addr types.Address) *Receipt {
@@ -39,9 +46,80 @@ func (r *Receipt) ToJsonMap() map[string]string {
 	jm["status"] = fmt.Sprintf("%d", r.Status)
 	jm["result"] = r.ProcessResult
 	jm["contractAddress"] = r.ContractAddress.Hex()
+	jm["gasUsed"] = fmt.Sprintf("%d", r.GasUsed)
 
 	return jm
 }
 
 //msgp:tuple ReceiptSet
 type ReceiptSet map[string]*Receipt
+
+// ReceiptsRoot hashes receipts, the receipts of the txs confirmed by a
+// sequencer and of the sequencer itself in execution order, which the
+// sequencer commits to. The tx hashes and the positions of the logs are
+// left out, they are told by the order and by the sequencer.
+func ReceiptsRoot(receipts []*Receipt) types.Hash {
+	var buf bytes.Buffer
+	writeBytes := func(b []byte) {
+		binary.Write(&buf, binary.BigEndian, uint64(len(b)))
+		buf.Write(b)
+	}
+	for _, r := range receipts {
+		binary.Write(&buf, binary.BigEndian, int64(r.Status))
+		writeBytes([]byte(r.ProcessResult))
+		buf.Write(r.ContractAddress.Bytes[:])
+		binary.Write(&buf, binary.BigEndian, r.GasUsed)
+		binary.Write(&buf, binary.BigEndian, uint64(len(r.Logs)))
+		for _, l := range r.Logs {
+			buf.Write(l.Address.Bytes[:])
+			binary.Write(&buf, binary.BigEndian, uint64(len(l.Topics)))
+			for _, topic := range l.Topics {
+				buf.Write(topic.Bytes[:])
+			}
+			writeBytes(l.Data)
+		}
+	}
+	var root types.Hash
+	result := sha3.Sum256(buf.Bytes())
+	root.MustSetBytes(result[0:], types.PaddingNone)
+	return root
+}
+
+// verifyReceipts checks that receipts are the receipts of seq and the txs
+// txHashes it confirms, in execution order. There must be exactly one
+// receipt for each of them, the logs must point to the tx and seq, and
+// the receipts must hash to the receipts root signed in seq.
+func verifyReceipts(seq *types.Sequencer, txHashes types.Hashes, receipts ReceiptSet) error {
+	if len(receipts) != len(txHashes)+1 {
+		return fmt.Errorf("receipts number mismatch, expect %d, get %d", len(txHashes)+1, len(receipts))
+	}
+	ordered := make([]*Receipt, 0, len(receipts))
+	check := func(hash types.Hash, index int) error {
+		receipt, ok := receipts[hash.Hex()]
+		if !ok || receipt == nil {
+			return fmt.Errorf("missing receipt of tx %s", hash.Hex())
+		}
+		if receipt.TxHash != hash {
+			return fmt.Errorf("receipt of tx %s has tx hash %s", hash.Hex(), receipt.TxHash.Hex())
+		}
+		for _, l := range receipt.Logs {
+			if l.TxHash != hash || l.TxIndex != uint(index) || l.SequenceID != seq.Height || l.BlockHash != seq.GetTxHash() {
+				return fmt.Errorf("log of tx %s is not emitted by this tx", hash.Hex())
+			}
+		}
+		ordered = append(ordered, receipt)
+		return nil
+	}
+	for i, hash := range txHashes {
+		if err := check(hash, i); err != nil {
+			return err
+		}
+	}
+	if err := check(seq.GetTxHash(), len(txHashes)); err != nil {
+		return err
+	}
+	if root := ReceiptsRoot(ordered); root != seq.ReceiptsRoot {
+		return fmt.Errorf("receipts root %s mismatches %s of the sequencer", root.Hex(), seq.ReceiptsRoot.Hex())
+	}
+	return nil
+}
@@ -11,8 +11,8 @@ func (z *Receipt) DecodeMsg(dc *msgp.Reader) (err error) {
 	if err != nil {
 		return
 	}
-	if zb0001 != 4 {
-		err = msgp.ArrayError{Wanted: 4, Got: zb0001}
+	if zb0001 != 6 {
+		err = msgp.ArrayError{Wanted: 6, Got: zb0001}
 		return
 	}
 	err = z.TxHash.DecodeMsg(dc)
@@ -35,13 +35,21 @@ func (z *Receipt) DecodeMsg(dc *msgp.Reader) (err error) {
 	if err != nil {
 		return
 	}
+	z.GasUsed, err = dc.ReadUint64()
+	if err != nil {
+		return
+	}
+	err = z.Logs.DecodeMsg(dc)
+	if err != nil {
+		return
+	}
 	return
 }
 
 // EncodeMsg implements msgp.Encodable
 func (z *Receipt) EncodeMsg(en *msgp.Writer) (err error) {
-	// array header, size 4
-	err = en.Append(0x94)
+	// array header, size 6
+	err = en.Append(0x96)
 	if err != nil {
 		return
 	}
@@ -61,14 +69,22 @@ func (z *Receipt) EncodeMsg(en *msgp.Writer) (err error) {
 	if err != nil {
 		return
 	}
+	err = en.WriteUint64(z.GasUsed)
+	if err != nil {
+		return
+	}
+	err = z.Logs.EncodeMsg(en)
+	if err != nil {
+		return
+	}
 	return
 }
 
 // MarshalMsg implements msgp.Marshaler
 func (z *Receipt) MarshalMsg(b []byte) (o []byte, err error) {
 	o = msgp.Require(b, z.Msgsize())
-	// array header, size 4
-	o = append(o, 0x94)
+	// array header, size 6
+	o = append(o, 0x96)
 	o, err = z.TxHash.MarshalMsg(o)
 	if err != nil {
 		return
@@ -79,6 +95,11 @@ func (z *Receipt) MarshalMsg(b []byte) (o []byte, err error) {
 	if err != nil {
 		return
 	}
+	o = msgp.AppendUint64(o, z.GasUsed)
+	o, err = z.Logs.MarshalMsg(o)
+	if err != nil {
+		return
+	}
 	return
 }
 
@@ -89,8 +110,8 @@ func (z *Receipt) UnmarshalMsg(bts []byte) (o []byte, err error) {
 	if err != nil {
 		return
 	}
-	if zb0001 != 4 {
-		err = msgp.ArrayError{Wanted: 4, Got: zb0001}
+	if zb0001 != 6 {
+		err = msgp.ArrayError{Wanted: 6, Got: zb0001}
 		return
 	}
 	bts, err = z.TxHash.UnmarshalMsg(bts)
@@ -113,13 +134,21 @@ func (z *Receipt) UnmarshalMsg(bts []byte) (o []byte, err error) {
 	if err != nil {
 		return
 	}
+	z.GasUsed, bts, err = msgp.ReadUint64Bytes(bts)
+	if err != nil {
+		return
+	}
+	bts, err = z.Logs.UnmarshalMsg(bts)
+	if err != nil {
+		return
+	}
 	o = bts
 	return
 }
 
 // Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
 func (z *Receipt) Msgsize() (s int) {
-	s = 1 + z.TxHash.Msgsize() + msgp.IntSize + msgp.StringPrefixSize + len(z.ProcessResult) + z.ContractAddress.Msgsize()
+	s = 1 + z.TxHash.Msgsize() + msgp.IntSize + msgp.StringPrefixSize + len(z.ProcessResult) + z.ContractAddress.Msgsize() + msgp.Uint64Size + z.Logs.Msgsize()
 	return
 }
 
@@ -0,0 +1,78 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/annchain/OG/common/math"
+	"github.com/annchain/OG/ogdb"
+	"github.com/annchain/OG/types"
+)
+
+func TestDagImportReceipts(t *testing.T) {
+	src := newTestPushDag(t, ogdb.NewMemDatabase())
+	defer src.Stop()
+	dst := newTestPushDag(t, ogdb.NewMemDatabase())
+	defer dst.Stop()
+
+	alice := types.HexToAddress("0x0a")
+	balance := map[types.Address]*math.BigInt{alice: math.NewBigInt(1000000)}
+	initTestPushDag(t, src, balance)
+	initTestPushDag(t, dst, balance)
+
+	// init code emitting one log: PUSH1 0 PUSH1 0 LOG0 STOP
+	batch := newTestPushBatch(alice, types.Address{}, 0)
+	tx := batch.Batch[alice].TxList.get(0).(*types.Tx)
+	tx.Data = []byte{0x60, 0x00, 0x60, 0x00, 0xa0, 0x00}
+	tx.GasLimit = 100000
+	root, receiptsRoot, err := src.PreConfirm(batch)
+	if err != nil {
+		t.Fatalf("pre confirm error: %v", err)
+	}
+	batch.Seq.StateRoot = root
+	batch.Seq.ReceiptsRoot = receiptsRoot
+	if err := src.Push(batch); err != nil {
+		t.Fatalf("push error: %v", err)
+	}
+	receipts := src.GetReceipts(1)
+	if len(receipts) != 2 || len(receipts[tx.GetTxHash().Hex()].Logs) != 1 {
+		t.Fatalf("source should have the receipts of tx and seq with one log, get %v", receipts)
+	}
+
+	// receipts not matching the txs are rejected.
+	missing := ReceiptSet{batch.Seq.GetTxHash().Hex(): receipts[batch.Seq.GetTxHash().Hex()]}
+	if err := dst.Import(batch.Seq, types.Txs{tx}, missing); err == nil {
+		t.Fatalf("import should fail with the receipt of tx missing")
+	}
+	forged := src.GetReceipts(1)
+	forged[tx.GetTxHash().Hex()].Logs[0].BlockHash = types.HexToHash("0x03")
+	if err := dst.Import(batch.Seq, types.Txs{tx}, forged); err == nil {
+		t.Fatalf("import should fail with a log of another sequencer")
+	}
+	// receipts not hashing to the receipts root of the sequencer are
+	// rejected.
+	forged = src.GetReceipts(1)
+	forged[tx.GetTxHash().Hex()].Logs[0].Data = []byte{0x01}
+	if err := dst.Import(batch.Seq, types.Txs{tx}, forged); err == nil {
+		t.Fatalf("import should fail with a forged log")
+	}
+	forged = src.GetReceipts(1)
+	forged[tx.GetTxHash().Hex()].Status = ReceiptStatusOVMFailed
+	if err := dst.Import(batch.Seq, types.Txs{tx}, forged); err == nil {
+		t.Fatalf("import should fail with a forged status")
+	}
+
+	if err := dst.Import(batch.Seq, types.Txs{tx}, receipts); err != nil {
+		t.Fatalf("import error: %v", err)
+	}
+	receipt := dst.GetReceipt(tx.GetTxHash())
+	if receipt == nil || receipt.Status != ReceiptStatusTxSuccess || len(receipt.Logs) != 1 {
+		t.Fatalf("imported receipt mismatch, get %v", receipt)
+	}
+	logs, err := dst.FilterLogs(LogFilter{FromHeight: 1, ToHeight: 1})
+	if err != nil {
+		t.Fatalf("filter logs error: %v", err)
+	}
+	if len(logs) != 1 || logs[0].TxHash != tx.GetTxHash() {
+		t.Fatalf("imported log should be found by the filter, get %v", logs)
+	}
+}
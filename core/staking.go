@@ -0,0 +1,195 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/annchain/OG/common/crypto/sha3"
+	"github.com/annchain/OG/common/math"
+	"github.com/annchain/OG/core/state"
+	"github.com/annchain/OG/types"
+)
+
+const (
+	// DefaultMaxDelegates is the default size of the active delegate set.
+	DefaultMaxDelegates = 21
+	// DefaultMinDelegates is the default number of candidates with enough
+	// stake needed to replace the genesis delegates.
+	DefaultMinDelegates = 3
+	// DefaultMinStake is the default stake a candidate needs to be elected.
+	DefaultMinStake = 10000
+)
+
+// VoteStorageAddress is the system account whose storage keeps the
+// candidates and votes of dpos consensus. The layout of the storage is:
+//
+//	slot 0                      - the number of candidates n.
+//	slot 1..n                   - the address of each candidate, in registration order.
+//	sha3("candidate", c)        - 1 if c is a registered candidate.
+//	sha3("tally", c)            - the total stake voted to candidate c.
+//	sha3("vote", voter, c)      - the stake voted by voter to candidate c.
+var VoteStorageAddress = types.HexToAddress("0x000000000000000000000000000000000000d01f")
+
+// Candidate is a registered delegate candidate and the total stake voted
+// to it.
+type Candidate struct {
+	Address types.Address
+	Stake   *math.BigInt
+}
+
+func storageKey(parts ...[]byte) types.Hash {
+	result := sha3.Sum256(bytes.Join(parts, nil))
+	return types.BytesToHash(result[:])
+}
+
+func candidateKey(candidate types.Address) types.Hash {
+	return storageKey([]byte("candidate"), candidate.ToBytes())
+}
+
+func tallyKey(candidate types.Address) types.Hash {
+	return storageKey([]byte("tally"), candidate.ToBytes())
+}
+
+func voteKey(voter types.Address, candidate types.Address) types.Hash {
+	return storageKey([]byte("vote"), voter.ToBytes(), candidate.ToBytes())
+}
+
+func getStake(sd *state.StateDB, key types.Hash) *big.Int {
+	return sd.GetState(VoteStorageAddress, key).Big()
+}
+
+func setStake(sd *state.StateDB, key types.Hash, stake *big.Int) {
+	sd.SetState(VoteStorageAddress, key, types.BigToHash(stake))
+}
+
+// isCandidate checks if addr is a registered candidate.
+func isCandidate(sd *state.StateDB, addr types.Address) bool {
+	return sd.GetState(VoteStorageAddress, candidateKey(addr)).Big().Sign() != 0
+}
+
+// readVote reads the stake voted by voter to candidate.
+func readVote(sd *state.StateDB, voter types.Address, candidate types.Address) *math.BigInt {
+	return math.NewBigIntFromBigInt(getStake(sd, voteKey(voter, candidate)))
+}
+
+// readCandidates reads all the registered candidates in registration order.
+func readCandidates(sd *state.StateDB) []Candidate {
+	// the candidate list has no limit.
+	addrs, _ := readAddressList(sd, VoteStorageAddress, ^uint64(0))
+	candidates := make([]Candidate, 0, len(addrs))
+	for _, addr := range addrs {
+		candidates = append(candidates, Candidate{
+			Address: addr,
+			Stake:   math.NewBigIntFromBigInt(getStake(sd, tallyKey(addr))),
+		})
+	}
+	return candidates
+}
+
+// verifyStakingTx checks if a staking tx can be applied to current state.
+func verifyStakingTx(sd *state.StateDB, tx *types.Tx) error {
+	switch tx.Type {
+	case types.TxBaseTypeCandidate:
+		if isCandidate(sd, tx.From) {
+			return fmt.Errorf("%s is already a candidate", tx.From.Hex())
+		}
+		return checkStakeBalance(sd, tx)
+	case types.TxBaseTypeVote:
+		if tx.Value.Value.Sign() <= 0 {
+			return fmt.Errorf("vote value must be positive")
+		}
+		if !isCandidate(sd, tx.To) {
+			return fmt.Errorf("%s is not a candidate", tx.To.Hex())
+		}
+		return checkStakeBalance(sd, tx)
+	case types.TxBaseTypeUnvote:
+		if tx.Value.Value.Sign() != 0 {
+			return fmt.Errorf("unvote value must be zero")
+		}
+		if getStake(sd, voteKey(tx.From, tx.To)).Sign() == 0 {
+			return fmt.Errorf("%s has no vote for %s", tx.From.Hex(), tx.To.Hex())
+		}
+	default:
+		return fmt.Errorf("not a staking tx: %s", tx.Type)
+	}
+	return nil
+}
+
+// checkStakeBalance checks if the sender has enough balance to lock the
+// stake of tx.
+func checkStakeBalance(sd *state.StateDB, tx *types.Tx) error {
+	if tx.Value.Value.Sign() < 0 {
+		return fmt.Errorf("stake must not be negative")
+	}
+	if balance := sd.GetBalance(tx.From); balance.Value.Cmp(tx.Value.Value) < 0 {
+		return fmt.Errorf("balance %s of %s is not enough for stake %s", balance, tx.From.Hex(), tx.Value)
+	}
+	return nil
+}
+
+// processStakingTx applies a staking tx to statedb. The stake of the
+// voter is locked in VoteStorageAddress until it is unvoted.
+func processStakingTx(sd *state.StateDB, tx *types.Tx) error {
+	if err := verifyStakingTx(sd, tx); err != nil {
+		return err
+	}
+	switch tx.Type {
+	case types.TxBaseTypeCandidate:
+		sd.SetState(VoteStorageAddress, candidateKey(tx.From), types.BigToHash(big.NewInt(1)))
+		appendAddressList(sd, VoteStorageAddress, tx.From)
+		addVote(sd, tx.From, tx.From, tx.Value.Value)
+		sd.SubBalance(tx.From, tx.Value)
+	case types.TxBaseTypeVote:
+		addVote(sd, tx.From, tx.To, tx.Value.Value)
+		sd.SubBalance(tx.From, tx.Value)
+	case types.TxBaseTypeUnvote:
+		stake := getStake(sd, voteKey(tx.From, tx.To))
+		addVote(sd, tx.From, tx.To, new(big.Int).Neg(stake))
+		sd.AddBalance(tx.From, math.NewBigIntFromBigInt(stake))
+	}
+	return nil
+}
+
+func addVote(sd *state.StateDB, voter types.Address, candidate types.Address, value *big.Int) {
+	if value.Sign() == 0 {
+		return
+	}
+	vKey := voteKey(voter, candidate)
+	setStake(sd, vKey, new(big.Int).Add(getStake(sd, vKey), value))
+	tKey := tallyKey(candidate)
+	setStake(sd, tKey, new(big.Int).Add(getStake(sd, tKey), value))
+}
+
+// electDelegates replaces the delegate set with the top max candidates
+// ranked by stake. Only the candidates with at least minStake are elected,
+// and the delegate set, the genesis one at first, is left untouched until
+// there are min of them. So a few cheap candidates can't take over the
+// proposing of the chain.
+func electDelegates(sd *state.StateDB, min int, max int, minStake uint64) {
+	threshold := new(big.Int).SetUint64(minStake)
+	var elected []Candidate
+	for _, c := range readCandidates(sd) {
+		if c.Stake.Value.Sign() > 0 && c.Stake.Value.Cmp(threshold) >= 0 {
+			elected = append(elected, c)
+		}
+	}
+	if len(elected) == 0 || len(elected) < min {
+		return
+	}
+	sort.SliceStable(elected, func(i, j int) bool {
+		if cmp := elected[i].Stake.Value.Cmp(elected[j].Stake.Value); cmp != 0 {
+			return cmp > 0
+		}
+		return bytes.Compare(elected[i].Address.ToBytes(), elected[j].Address.ToBytes()) < 0
+	})
+	if len(elected) > max {
+		elected = elected[:max]
+	}
+	delegates := make([]types.Address, 0, len(elected))
+	for _, c := range elected {
+		delegates = append(delegates, c.Address)
+	}
+	writeDelegates(sd, delegates)
+}
@@ -0,0 +1,189 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/annchain/OG/common/math"
+	"github.com/annchain/OG/core/state"
+	"github.com/annchain/OG/ogdb"
+	"github.com/annchain/OG/types"
+)
+
+func newTestStakingStateDB(t *testing.T) *state.StateDB {
+	sd, err := state.NewStateDB(state.DefaultStateDBConfig(), state.NewDatabase(ogdb.NewMemDatabase()), types.Hash{})
+	if err != nil {
+		t.Fatalf("create statedb error: %v", err)
+	}
+	return sd
+}
+
+func newTestStakingTx(txType types.TxBaseType, from types.Address, to types.Address, value int64) *types.Tx {
+	return &types.Tx{
+		TxBase: types.TxBase{Type: txType},
+		From:   from,
+		To:     to,
+		Value:  math.NewBigInt(value),
+	}
+}
+
+func TestStakingVote(t *testing.T) {
+	sd := newTestStakingStateDB(t)
+	defer sd.Stop()
+
+	alice := types.HexToAddress("0x0a")
+	bob := types.HexToAddress("0x0b")
+	voter := types.HexToAddress("0x0c")
+	for _, addr := range []types.Address{alice, bob, voter} {
+		sd.SetBalance(addr, math.NewBigInt(1000))
+	}
+	genesisDelegate := types.HexToAddress("0x01")
+	writeDelegates(sd, []types.Address{genesisDelegate})
+
+	// nobody is voted, the genesis delegates stay.
+	electDelegates(sd, 1, 1, 1)
+	if d, _ := readDelegates(sd, 2); len(d) != 1 || d[0] != genesisDelegate {
+		t.Fatalf("delegates should not change without candidates, got %v", d)
+	}
+
+	steps := []struct {
+		tx *types.Tx
+		ok bool
+	}{
+		{newTestStakingTx(types.TxBaseTypeVote, voter, alice, 100), false},
+		{newTestStakingTx(types.TxBaseTypeCandidate, alice, alice, 100), true},
+		{newTestStakingTx(types.TxBaseTypeCandidate, alice, alice, 100), false},
+		{newTestStakingTx(types.TxBaseTypeCandidate, bob, bob, 50), true},
+		{newTestStakingTx(types.TxBaseTypeVote, voter, bob, 0), false},
+		{newTestStakingTx(types.TxBaseTypeVote, voter, bob, 300), true},
+		{newTestStakingTx(types.TxBaseTypeUnvote, voter, alice, 0), false},
+	}
+	for i, step := range steps {
+		err := processStakingTx(sd, step.tx)
+		if step.ok && err != nil {
+			t.Fatalf("step %d: unexpected error: %v", i, err)
+		}
+		if !step.ok && err == nil {
+			t.Fatalf("step %d: expected error", i)
+		}
+	}
+
+	candidates := readCandidates(sd)
+	if len(candidates) != 2 {
+		t.Fatalf("expected 2 candidates, got %d", len(candidates))
+	}
+	if candidates[0].Address != alice || candidates[0].Stake.Value.Int64() != 100 {
+		t.Errorf("wrong candidate %s with stake %s", candidates[0].Address.Hex(), candidates[0].Stake)
+	}
+	if candidates[1].Address != bob || candidates[1].Stake.Value.Int64() != 350 {
+		t.Errorf("wrong candidate %s with stake %s", candidates[1].Address.Hex(), candidates[1].Stake)
+	}
+	if b := sd.GetBalance(voter).Value.Int64(); b != 700 {
+		t.Errorf("voter balance should be 700, got %d", b)
+	}
+
+	electDelegates(sd, 1, 1, 1)
+	if d, _ := readDelegates(sd, 2); len(d) != 1 || d[0] != bob {
+		t.Fatalf("bob should be elected, got %v", d)
+	}
+
+	// unvote returns all the stake to the voter.
+	if err := processStakingTx(sd, newTestStakingTx(types.TxBaseTypeUnvote, voter, bob, 0)); err != nil {
+		t.Fatalf("unvote error: %v", err)
+	}
+	if v := readVote(sd, voter, bob).Value.Int64(); v != 0 {
+		t.Errorf("vote should be cleared, got %d", v)
+	}
+	if b := sd.GetBalance(voter).Value.Int64(); b != 1000 {
+		t.Errorf("voter balance should be 1000, got %d", b)
+	}
+
+	electDelegates(sd, 1, 2, 1)
+	if d, _ := readDelegates(sd, 2); len(d) != 2 || d[0] != alice || d[1] != bob {
+		t.Fatalf("alice and bob should be elected in order, got %v", d)
+	}
+}
+
+func TestReadDelegatesLimit(t *testing.T) {
+	sd := newTestStakingStateDB(t)
+	defer sd.Stop()
+
+	delegates := []types.Address{types.HexToAddress("0x01"), types.HexToAddress("0x02")}
+	writeDelegates(sd, delegates)
+	if d, err := readDelegates(sd, 2); err != nil || len(d) != 2 {
+		t.Fatalf("expected 2 delegates, got %v, err: %v", d, err)
+	}
+	if _, err := readDelegates(sd, 1); err == nil {
+		t.Fatalf("expected error for a delegate set exceeding the max")
+	}
+	// a huge length is rejected before anything is allocated.
+	sd.SetState(DelegateStorageAddress, listSlot(0), types.HexToHash("0xffffffffffffffffffffffffffffffff"))
+	if _, err := readDelegates(sd, DefaultMaxDelegates); err == nil {
+		t.Fatalf("expected error for a huge delegate set length")
+	}
+}
+
+func TestElectDelegatesThreshold(t *testing.T) {
+	sd := newTestStakingStateDB(t)
+	defer sd.Stop()
+
+	alice := types.HexToAddress("0x0a")
+	bob := types.HexToAddress("0x0b")
+	for _, addr := range []types.Address{alice, bob} {
+		sd.SetBalance(addr, math.NewBigInt(1000))
+	}
+	genesisDelegate := types.HexToAddress("0x01")
+	writeDelegates(sd, []types.Address{genesisDelegate})
+
+	// a candidate below the min stake is never elected.
+	if err := processStakingTx(sd, newTestStakingTx(types.TxBaseTypeCandidate, alice, alice, 1)); err != nil {
+		t.Fatalf("register alice error: %v", err)
+	}
+	electDelegates(sd, 1, 21, 100)
+	if d, _ := readDelegates(sd, 21); len(d) != 1 || d[0] != genesisDelegate {
+		t.Fatalf("candidate below min stake should not be elected, got %v", d)
+	}
+
+	// one candidate with enough stake is not enough to replace the
+	// genesis delegates.
+	if err := processStakingTx(sd, newTestStakingTx(types.TxBaseTypeVote, alice, alice, 200)); err != nil {
+		t.Fatalf("vote alice error: %v", err)
+	}
+	electDelegates(sd, 2, 21, 100)
+	if d, _ := readDelegates(sd, 21); len(d) != 1 || d[0] != genesisDelegate {
+		t.Fatalf("genesis delegates should stay with a single candidate, got %v", d)
+	}
+
+	if err := processStakingTx(sd, newTestStakingTx(types.TxBaseTypeCandidate, bob, bob, 100)); err != nil {
+		t.Fatalf("register bob error: %v", err)
+	}
+	electDelegates(sd, 2, 21, 100)
+	if d, _ := readDelegates(sd, 21); len(d) != 2 || d[0] != alice || d[1] != bob {
+		t.Fatalf("alice and bob should be elected in order, got %v", d)
+	}
+}
+
+func TestStakingBalance(t *testing.T) {
+	sd := newTestStakingStateDB(t)
+	defer sd.Stop()
+
+	alice := types.HexToAddress("0x0a")
+	voter := types.HexToAddress("0x0c")
+	sd.SetBalance(alice, math.NewBigInt(100))
+	sd.SetBalance(voter, math.NewBigInt(100))
+
+	if err := processStakingTx(sd, newTestStakingTx(types.TxBaseTypeCandidate, alice, alice, 101)); err == nil {
+		t.Fatalf("expected error for a stake over the balance")
+	}
+	if isCandidate(sd, alice) {
+		t.Fatalf("alice should not be registered")
+	}
+	if err := processStakingTx(sd, newTestStakingTx(types.TxBaseTypeCandidate, alice, alice, 100)); err != nil {
+		t.Fatalf("register alice error: %v", err)
+	}
+	if err := processStakingTx(sd, newTestStakingTx(types.TxBaseTypeVote, voter, alice, 101)); err == nil {
+		t.Fatalf("expected error for a vote over the balance")
+	}
+	if b := sd.GetBalance(voter).Value.Int64(); b != 100 {
+		t.Errorf("voter balance should stay 100, got %d", b)
+	}
+}
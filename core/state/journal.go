@@ -223,17 +223,13 @@ func (ch refundChange) dirtied() *types.Address {
 }
 
 func (ch addLogChange) revert(s *StateDB) {
-	// TODO
-	// Log not implemented yet. To avoid the error, commment
-	// this function.
-
-	// logs := s.logs[ch.txhash]
-	// if len(logs) == 1 {
-	// 	delete(s.logs, ch.txhash)
-	// } else {
-	// 	s.logs[ch.txhash] = logs[:len(logs)-1]
-	// }
-	// s.logSize--
+	logs := s.logs[ch.txhash]
+	if len(logs) == 1 {
+		delete(s.logs, ch.txhash)
+	} else {
+		s.logs[ch.txhash] = logs[:len(logs)-1]
+	}
+	s.logSize--
 }
 
 func (ch addLogChange) dirtied() *types.Address {
@@ -0,0 +1,159 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/annchain/OG/common/crypto"
+	"github.com/annchain/OG/common/math"
+	"github.com/annchain/OG/ogdb"
+	"github.com/annchain/OG/trie"
+	"github.com/annchain/OG/types"
+)
+
+// ProofList collects the nodes put by Trie.Prove.
+type ProofList [][]byte
+
+func (p *ProofList) Put(key []byte, value []byte) error {
+	*p = append(*p, value)
+	return nil
+}
+
+// AccountResult is the account of an address in the state trie at a root,
+// along with the merkle proofs of the account and the requested storage
+// keys of it.
+type AccountResult struct {
+	Address      types.Address
+	AccountProof [][]byte
+	Balance      *math.BigInt
+	Nonce        uint64
+	StorageHash  types.Hash
+	CodeHash     []byte
+	StorageProof []StorageResult
+}
+
+// StorageResult is the value of a storage key with its merkle proof in the
+// storage trie of an account.
+type StorageResult struct {
+	Key   types.Hash
+	Value types.Hash
+	Proof [][]byte
+}
+
+// GetAccountProof returns the account of addr in the state trie at root
+// with the merkle proofs of the account and of keys in its storage trie.
+// The proof of a missing account or key proves its absence, a missing
+// account has an empty balance, storage and code.
+func GetAccountProof(db Database, root types.Hash, addr types.Address, keys []types.Hash) (*AccountResult, error) {
+	tr, err := db.OpenTrie(root)
+	if err != nil {
+		return nil, fmt.Errorf("open state trie %s error: %v", root.Hex(), err)
+	}
+	result := &AccountResult{
+		Address:      addr,
+		Balance:      math.NewBigInt(0),
+		StorageHash:  emptyStateRoot,
+		CodeHash:     emptyCodeHash.ToBytes(),
+		StorageProof: make([]StorageResult, len(keys)),
+	}
+	var proof ProofList
+	if err := tr.Prove(crypto.Keccak256(addr.ToBytes()), 0, &proof); err != nil {
+		return nil, fmt.Errorf("prove account %s error: %v", addr.Hex(), err)
+	}
+	result.AccountProof = proof
+	for i, key := range keys {
+		result.StorageProof[i] = StorageResult{Key: key, Proof: [][]byte{}}
+	}
+
+	data, err := tr.TryGet(addr.ToBytes())
+	if err != nil {
+		return nil, fmt.Errorf("get account %s error: %v", addr.Hex(), err)
+	}
+	if data == nil {
+		return result, nil
+	}
+	var account Account
+	if _, err := account.UnmarshalMsg(data); err != nil {
+		return nil, fmt.Errorf("decode account %s error: %v", addr.Hex(), err)
+	}
+	result.Balance, result.Nonce = account.Balance, account.Nonce
+	result.StorageHash, result.CodeHash = account.Root, account.CodeHash
+	if len(keys) == 0 || account.Root == emptyStateRoot {
+		return result, nil
+	}
+	st, err := db.OpenStorageTrie(crypto.Keccak256Hash(addr.ToBytes()), account.Root)
+	if err != nil {
+		return nil, fmt.Errorf("open storage trie of %s error: %v", addr.Hex(), err)
+	}
+	for i, key := range keys {
+		var proof ProofList
+		if err := st.Prove(crypto.Keccak256(key.ToBytes()), 0, &proof); err != nil {
+			return nil, fmt.Errorf("prove storage %s of %s error: %v", key.Hex(), addr.Hex(), err)
+		}
+		value, err := st.TryGet(key.ToBytes())
+		if err != nil {
+			return nil, fmt.Errorf("get storage %s of %s error: %v", key.Hex(), addr.Hex(), err)
+		}
+		result.StorageProof[i].Value = types.BytesToHash(value)
+		result.StorageProof[i].Proof = proof
+	}
+	return result, nil
+}
+
+// GetProof returns the merkle proof of the account of addr in the state
+// trie at root, followed by the proofs of keys in the storage trie of the
+// account. The proof of a missing account or key proves its absence.
+func GetProof(db Database, root types.Hash, addr types.Address, keys []types.Hash) ([][]byte, error) {
+	result, err := GetAccountProof(db, root, addr, keys)
+	if err != nil {
+		return nil, err
+	}
+	proof := result.AccountProof
+	for _, storage := range result.StorageProof {
+		proof = append(proof, storage.Proof...)
+	}
+	return proof, nil
+}
+
+// VerifyAccountProof checks the proof against the state root and returns
+// the account of addr in it, or nil if the proof shows addr is not in the
+// state.
+func VerifyAccountProof(root types.Hash, addr types.Address, proof [][]byte) (*Account, error) {
+	data, _, err := trie.VerifyProof(root, crypto.Keccak256(addr.ToBytes()), newProofDB(proof))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var account Account
+	if _, err := account.UnmarshalMsg(data); err != nil {
+		return nil, fmt.Errorf("decode account error: %v", err)
+	}
+	if account.Address != addr {
+		return nil, fmt.Errorf("proven account is %s, not %s", account.Address.Hex(), addr.Hex())
+	}
+	return &account, nil
+}
+
+// VerifyStorageProof checks the proof against the storage root of an
+// account and returns the value of key in it.
+func VerifyStorageProof(storageRoot types.Hash, key types.Hash, proof [][]byte) (types.Hash, error) {
+	if storageRoot == emptyStateRoot {
+		return types.Hash{}, nil
+	}
+	value, _, err := trie.VerifyProof(storageRoot, crypto.Keccak256(key.ToBytes()), newProofDB(proof))
+	if err != nil {
+		return types.Hash{}, err
+	}
+	return types.BytesToHash(value), nil
+}
+
+// newProofDB puts the proof nodes into a db keyed by their hashes, which
+// is where trie.VerifyProof looks them up.
+func newProofDB(proof [][]byte) *ogdb.MemDatabase {
+	db := ogdb.NewMemDatabase()
+	for _, node := range proof {
+		db.Put(crypto.Keccak256(node), node)
+	}
+	return db
+}
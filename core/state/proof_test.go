@@ -0,0 +1,119 @@
+package state_test
+
+import (
+	"testing"
+
+	"github.com/annchain/OG/common/math"
+	"github.com/annchain/OG/core/state"
+	"github.com/annchain/OG/ogdb"
+	"github.com/annchain/OG/types"
+)
+
+func TestStateProof(t *testing.T) {
+	t.Parallel()
+
+	db := state.NewDatabase(ogdb.NewMemDatabase())
+	sdb, err := state.NewStateDB(state.DefaultStateDBConfig(), db, types.Hash{})
+	if err != nil {
+		t.Fatalf("create StateDB error: %v", err)
+	}
+	defer sdb.Stop()
+
+	addr1 := types.HexToAddress(testAddress)
+	addr2 := types.HexToAddress("0x2b5d53f433b7e4a4f853a01e987f977497dda262")
+	missing := types.HexToAddress("0x0b")
+	sdb.SetBalance(addr1, math.NewBigInt(100))
+	sdb.SetBalance(addr2, math.NewBigInt(200))
+	sdb.SetNonce(addr2, 3)
+	sdb.SetState(addr2, storageKey1, storageValue1)
+	sdb.SetState(addr2, storageKey2, storageValue2)
+
+	root, err := sdb.Commit()
+	if err != nil {
+		t.Fatalf("commit state error: %v", err)
+	}
+
+	proof, err := state.GetProof(db, root, addr2, []types.Hash{storageKey2, types.HexToHash("0x0c")})
+	if err != nil {
+		t.Fatalf("get proof error: %v", err)
+	}
+	account, err := state.VerifyAccountProof(root, addr2, proof)
+	if err != nil {
+		t.Fatalf("verify account proof error: %v", err)
+	}
+	if account == nil || account.Balance.GetInt64() != 200 || account.Nonce != 3 {
+		t.Fatalf("proven account mismatch, get %v", account)
+	}
+	value, err := state.VerifyStorageProof(account.Root, storageKey2, proof)
+	if err != nil {
+		t.Fatalf("verify storage proof error: %v", err)
+	}
+	if value != storageValue2 {
+		t.Fatalf("proven storage should be %s, get %s", storageValue2.Hex(), value.Hex())
+	}
+	value, err = state.VerifyStorageProof(account.Root, types.HexToHash("0x0c"), proof)
+	if err != nil || value != (types.Hash{}) {
+		t.Fatalf("missing storage should be proven empty, get %s, err %v", value.Hex(), err)
+	}
+
+	// the proof of a missing account proves its absence.
+	proof, err = state.GetProof(db, root, missing, nil)
+	if err != nil {
+		t.Fatalf("get proof error: %v", err)
+	}
+	if account, err := state.VerifyAccountProof(root, missing, proof); err != nil || account != nil {
+		t.Fatalf("missing account should be proven absent, get %v, err %v", account, err)
+	}
+
+	// a proof doesn't verify against another root or with nodes removed.
+	proof, _ = state.GetProof(db, root, addr1, nil)
+	if _, err := state.VerifyAccountProof(types.HexToHash("0x0d"), addr1, proof); err == nil {
+		t.Fatalf("proof should not verify against another root")
+	}
+	if _, err := state.VerifyAccountProof(root, addr1, proof[:len(proof)-1]); err == nil {
+		t.Fatalf("proof should not verify with the last node removed")
+	}
+}
+
+func TestAccountProof(t *testing.T) {
+	t.Parallel()
+
+	db := state.NewDatabase(ogdb.NewMemDatabase())
+	sdb, err := state.NewStateDB(state.DefaultStateDBConfig(), db, types.Hash{})
+	if err != nil {
+		t.Fatalf("create StateDB error: %v", err)
+	}
+	defer sdb.Stop()
+
+	addr := types.HexToAddress(testAddress)
+	sdb.SetBalance(addr, math.NewBigInt(300))
+	sdb.SetNonce(addr, 5)
+	sdb.SetState(addr, storageKey1, storageValue1)
+	root, err := sdb.Commit()
+	if err != nil {
+		t.Fatalf("commit state error: %v", err)
+	}
+
+	missingKey := types.HexToHash("0x0c")
+	result, err := state.GetAccountProof(db, root, addr, []types.Hash{storageKey1, missingKey})
+	if err != nil {
+		t.Fatalf("get account proof error: %v", err)
+	}
+	if result.Balance.GetInt64() != 300 || result.Nonce != 5 || len(result.StorageProof) != 2 {
+		t.Fatalf("account result mismatch, get %v", result)
+	}
+	account, err := state.VerifyAccountProof(root, addr, result.AccountProof)
+	if err != nil || account == nil || account.Root != result.StorageHash {
+		t.Fatalf("account proof should verify with the storage hash, get %v, err %v", account, err)
+	}
+	// every storage proof verifies alone against the storage hash.
+	for _, storage := range result.StorageProof {
+		value, err := state.VerifyStorageProof(result.StorageHash, storage.Key, storage.Proof)
+		if err != nil || value != storage.Value {
+			t.Fatalf("storage %s should be proven %s, get %s, err %v", storage.Key.Hex(), storage.Value.Hex(), value.Hex(), err)
+		}
+	}
+	if result.StorageProof[0].Value != storageValue1 || result.StorageProof[1].Value != (types.Hash{}) {
+		t.Fatalf("storage values mismatch, get %v", result.StorageProof)
+	}
+}
@@ -113,12 +113,12 @@ func (s *StateObject) GetCommittedState(db Database, key types.Hash) types.Hash
 	// load state from trie db.
 	b, err := s.openTrie(db).TryGet(key.ToBytes())
 	if err != nil {
-		log.Errorf("get from trie db error: %v, key: %x", key.ToBytes())
+		log.Errorf("get from trie db error: %v, key: %x", err, key.ToBytes())
 		s.setError(err)
-	} else {
-		s.committedStorage[key] = value
+		return types.Hash{}
 	}
 	value = types.BytesToHash(b)
+	s.committedStorage[key] = value
 	return value
 }
 
@@ -202,6 +202,13 @@ func (s *StateObject) updateTrie(db Database) {
 	}
 }
 
+// updateRoot applies the dirty storage to the storage trie and updates
+// the storage root without committing the trie.
+func (s *StateObject) updateRoot(db Database) {
+	s.updateTrie(db)
+	s.data.Root = s.trie.Hash()
+}
+
 func (s *StateObject) CommitStorage(db Database) error {
 	s.updateTrie(db)
 	if s.dbErr != nil {
@@ -53,6 +53,12 @@ type StateDB struct {
 	trie Trie
 
 	refund uint64
+	// logs keeps the logs of txs processed since last commit. thash and
+	// txIndex are the hash and index of the tx being processed.
+	logs    map[types.Hash][]*vmtypes.Log
+	logSize uint
+	thash   types.Hash
+	txIndex int
 	// journal records every action which will change statedb's data
 	// and it's for VM term revert only.
 	journal     *journal
@@ -72,9 +78,10 @@ type StateDB struct {
 	mu sync.RWMutex
 }
 
-func NewStateDB(conf StateDBConfig, db Database) (*StateDB, error) {
-	initRoot := types.Hash{}
-	tr, err := db.OpenTrie(initRoot)
+// NewStateDB opens a StateDB on the state trie identified by root. An
+// empty root means a brand new state.
+func NewStateDB(conf StateDBConfig, db Database, root types.Hash) (*StateDB, error) {
+	tr, err := db.OpenTrie(root)
 	if err != nil {
 		return nil, err
 	}
@@ -85,6 +92,7 @@ func NewStateDB(conf StateDBConfig, db Database) (*StateDB, error) {
 		states:   make(map[types.Address]*StateObject),
 		dirtyset: make(map[types.Address]struct{}),
 		beats:    make(map[types.Address]time.Time),
+		logs:     make(map[types.Hash][]*vmtypes.Log),
 		journal:  newJournal(),
 		close:    make(chan struct{}),
 	}
@@ -93,6 +101,25 @@ func NewStateDB(conf StateDBConfig, db Database) (*StateDB, error) {
 	return sd, nil
 }
 
+// Reset drops all the uncommitted changes and cached states, then reopens
+// the state trie at root.
+func (sd *StateDB) Reset(root types.Hash) error {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+
+	tr, err := sd.db.OpenTrie(root)
+	if err != nil {
+		return err
+	}
+	sd.trie = tr
+	sd.states = make(map[types.Address]*StateObject)
+	sd.dirtyset = make(map[types.Address]struct{})
+	sd.beats = make(map[types.Address]time.Time)
+	sd.clearJournalAndRefund()
+	sd.clearLogs()
+	return nil
+}
+
 func (sd *StateDB) Stop() {
 	close(sd.close)
 }
@@ -419,9 +446,33 @@ func (sd *StateDB) HasSuicided(addr types.Address) bool {
 	return stobj.suicided
 }
 
+// Prepare sets the hash and index of the tx to be processed, the logs
+// added afterwards belong to this tx.
+func (sd *StateDB) Prepare(thash types.Hash, ti int) {
+	sd.thash = thash
+	sd.txIndex = ti
+}
+
 func (sd *StateDB) AddLog(l *vmtypes.Log) {
-	// TODO
-	// Not implemented yet
+	sd.journal.append(addLogChange{txhash: sd.thash})
+
+	l.TxHash = sd.thash
+	l.TxIndex = uint(sd.txIndex)
+	l.Index = sd.logSize
+	sd.logs[sd.thash] = append(sd.logs[sd.thash], l)
+	sd.logSize++
+}
+
+// GetLogs returns the logs added by tx since last commit.
+func (sd *StateDB) GetLogs(hash types.Hash) []*vmtypes.Log {
+	return sd.logs[hash]
+}
+
+func (sd *StateDB) clearLogs() {
+	sd.logs = make(map[types.Hash][]*vmtypes.Log)
+	sd.logSize = 0
+	sd.thash = types.Hash{}
+	sd.txIndex = 0
 }
 
 func (sd *StateDB) AddPreimage(h types.Hash, b []byte) {
@@ -494,6 +545,28 @@ func (sd *StateDB) Commit() (types.Hash, error) {
 	return sd.commit()
 }
 
+// IntermediateRoot computes the root of current state with all the dirty
+// data applied. Unlike Commit, nothing is written into trie db.
+func (sd *StateDB) IntermediateRoot() types.Hash {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+
+	for addr := range sd.journal.dirties {
+		sd.dirtyset[addr] = struct{}{}
+	}
+	for addr, state := range sd.states {
+		if _, isdirty := sd.dirtyset[addr]; !isdirty {
+			continue
+		}
+		state.updateRoot(sd.db)
+		data, _ := state.Encode()
+		if err := sd.trie.TryUpdate(addr.ToBytes(), data); err != nil {
+			log.Errorf("update state trie error: %v", err)
+		}
+	}
+	return sd.trie.Hash()
+}
+
 // commit tries to save dirty data to memory trie db.
 //
 // Note that commit doesn't hold any StateDB locks.
@@ -541,6 +614,7 @@ func (sd *StateDB) commit() (types.Hash, error) {
 	})
 
 	sd.clearJournalAndRefund()
+	sd.clearLogs()
 	return rootHash, err
 }
 
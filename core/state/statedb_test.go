@@ -12,7 +12,7 @@ import (
 
 func newTestStateDB(t *testing.T) *state.StateDB {
 	db := ogdb.NewMemDatabase()
-	stdb, err := state.NewStateDB(state.DefaultStateDBConfig(), state.NewDatabase(db))
+	stdb, err := state.NewStateDB(state.DefaultStateDBConfig(), state.NewDatabase(db), types.Hash{})
 	if err != nil {
 		t.Errorf("create StateDB error: %v", err)
 	}
@@ -62,6 +62,11 @@ func TestStateStorage(t *testing.T) {
 	if st2.Hex() != storageValue2.Hex() {
 		t.Fatalf("value2 is not committed, should be %s, get %s", st2.Hex(), storageValue2.Hex())
 	}
+	// the value loaded from trie db is cached, read it again.
+	st2 = stdb.GetState(addr, storageKey2)
+	if st2.Hex() != storageValue2.Hex() {
+		t.Fatalf("value2 is not cached, should be %s, get %s", st2.Hex(), storageValue2.Hex())
+	}
 
 }
 
@@ -99,3 +104,44 @@ func TestStateWorkFlow(t *testing.T) {
 	}
 
 }
+
+func TestStateIntermediateRoot(t *testing.T) {
+	t.Parallel()
+
+	addr := types.HexToAddress(testAddress)
+	testblc := int64(666)
+
+	stdb := newTestStateDB(t)
+	stdb.SetBalance(addr, math.NewBigInt(testblc))
+	stdb.SetState(addr, storageKey1, storageValue1)
+
+	preview := stdb.IntermediateRoot()
+	root, err := stdb.Commit()
+	if err != nil {
+		t.Fatalf("commit statedb error: %v", err)
+	}
+	if preview != root {
+		t.Fatalf("intermediate root %s is not the committed root %s", preview.Hex(), root.Hex())
+	}
+
+	// reopen the state at root.
+	reopened, err := state.NewStateDB(state.DefaultStateDBConfig(), stdb.Database(), root)
+	if err != nil {
+		t.Fatalf("reopen statedb error: %v", err)
+	}
+	if blc := reopened.GetBalance(addr); blc.GetInt64() != testblc {
+		t.Fatalf("the balance in reopened statedb is not correct. shoud be: %d, get: %d", testblc, blc.GetInt64())
+	}
+	if st := reopened.GetState(addr, storageKey1); st != storageValue1 {
+		t.Fatalf("the storage in reopened statedb is not correct. shoud be: %s, get: %s", storageValue1.Hex(), st.Hex())
+	}
+
+	// uncommitted changes are dropped by reset.
+	stdb.SetBalance(addr, math.NewBigInt(testblc+1))
+	if err := stdb.Reset(root); err != nil {
+		t.Fatalf("reset statedb error: %v", err)
+	}
+	if blc := stdb.GetBalance(addr); blc.GetInt64() != testblc {
+		t.Fatalf("the balance after reset is not correct. shoud be: %d, get: %d", testblc, blc.GetInt64())
+	}
+}
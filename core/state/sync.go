@@ -0,0 +1,25 @@
+package state
+
+import (
+	"github.com/annchain/OG/trie"
+	"github.com/annchain/OG/types"
+)
+
+// NewStateSync creates a scheduler to download the state trie at root,
+// together with the storage tries and codes of all the accounts in it.
+func NewStateSync(root types.Hash, database trie.DatabaseReader) *trie.Sync {
+	var syncer *trie.Sync
+	callback := func(leaf []byte, parent types.Hash) error {
+		var account Account
+		if _, err := account.UnmarshalMsg(leaf); err != nil {
+			return err
+		}
+		if account.Root != emptyStateRoot {
+			syncer.AddSubTrie(account.Root, 64, parent, nil)
+		}
+		syncer.AddRawEntry(types.BytesToHash(account.CodeHash), 64, parent)
+		return nil
+	}
+	syncer = trie.NewSync(root, database, callback)
+	return syncer
+}
@@ -0,0 +1,91 @@
+package state_test
+
+import (
+	"testing"
+
+	"github.com/annchain/OG/common/math"
+	"github.com/annchain/OG/core/state"
+	"github.com/annchain/OG/ogdb"
+	"github.com/annchain/OG/trie"
+	"github.com/annchain/OG/types"
+)
+
+func TestStateSync(t *testing.T) {
+	t.Parallel()
+
+	// build the source state with balances, storage and code.
+	srcDb := ogdb.NewMemDatabase()
+	src, err := state.NewStateDB(state.DefaultStateDBConfig(), state.NewDatabase(srcDb), types.Hash{})
+	if err != nil {
+		t.Fatalf("create StateDB error: %v", err)
+	}
+	defer src.Stop()
+
+	addr1 := types.HexToAddress(testAddress)
+	addr2 := types.HexToAddress("0x2b5d53f433b7e4a4f853a01e987f977497dda262")
+	code := []byte{0x60, 0x01, 0x60, 0x00, 0x55}
+	src.SetBalance(addr1, math.NewBigInt(100))
+	src.SetBalance(addr2, math.NewBigInt(200))
+	src.SetNonce(addr2, 3)
+	src.SetState(addr2, storageKey1, storageValue1)
+	src.SetState(addr2, storageKey2, storageValue2)
+	src.SetCode(addr2, code)
+
+	root, err := src.Commit()
+	if err != nil {
+		t.Fatalf("commit state error: %v", err)
+	}
+	if err := src.Database().TrieDB().Commit(root, false); err != nil {
+		t.Fatalf("flush trie error: %v", err)
+	}
+
+	// download everything into an empty db.
+	dstDb := ogdb.NewMemDatabase()
+	sched := state.NewStateSync(root, dstDb)
+	for queue := sched.Missing(0); len(queue) > 0; queue = sched.Missing(0) {
+		results := make([]trie.SyncResult, len(queue))
+		for i, hash := range queue {
+			data, err := srcDb.Get(hash.ToBytes())
+			if err != nil {
+				t.Fatalf("node %s is not in source db: %v", hash.Hex(), err)
+			}
+			results[i] = trie.SyncResult{Hash: hash, Data: data}
+		}
+		if _, index, err := sched.Process(results); err != nil {
+			t.Fatalf("process result %d error: %v", index, err)
+		}
+		if _, err := sched.Commit(dstDb); err != nil {
+			t.Fatalf("commit synced nodes error: %v", err)
+		}
+	}
+	if pending := sched.Pending(); pending != 0 {
+		t.Fatalf("%d nodes are still pending", pending)
+	}
+
+	dst, err := state.NewStateDB(state.DefaultStateDBConfig(), state.NewDatabase(dstDb), root)
+	if err != nil {
+		t.Fatalf("open synced state error: %v", err)
+	}
+	defer dst.Stop()
+
+	if balance := dst.GetBalance(addr1); balance.GetInt64() != 100 {
+		t.Fatalf("balance of addr1 should be 100, get %s", balance)
+	}
+	if balance := dst.GetBalance(addr2); balance.GetInt64() != 200 {
+		t.Fatalf("balance of addr2 should be 200, get %s", balance)
+	}
+	if nonce := dst.GetNonce(addr2); nonce != 3 {
+		t.Fatalf("nonce of addr2 should be 3, get %d", nonce)
+	}
+	if value := dst.GetState(addr2, storageKey2); value != storageValue2 {
+		t.Fatalf("storage of addr2 should be %s, get %s", storageValue2.Hex(), value.Hex())
+	}
+	if c := dst.GetCode(addr2); string(c) != string(code) {
+		t.Fatalf("code of addr2 should be %x, get %x", code, c)
+	}
+
+	// an existing state needs nothing.
+	if missing := state.NewStateSync(root, dstDb).Missing(0); len(missing) != 0 {
+		t.Fatalf("synced state should not miss any node, get %d", len(missing))
+	}
+}
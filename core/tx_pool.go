@@ -10,6 +10,7 @@ import (
 	"math/rand"
 
 	"github.com/annchain/OG/common/math"
+	"github.com/annchain/OG/consensus"
 	"github.com/annchain/OG/types"
 	log "github.com/sirupsen/logrus"
 )
@@ -70,6 +71,13 @@ type TxPool struct {
 	conf TxPoolConfig
 	dag  *Dag
 
+	// Consensus verifies if a sequencer is issued by the right proposer.
+	// Sequencers are not checked by consensus if it is nil.
+	Consensus consensus.ConsensusEngine
+	// CatchingUp reports if the node is catching up with its peers, the
+	// sequencers received then are not checked against their deadlines.
+	CatchingUp func() bool
+
 	queue    chan *txEvent // queue stores txs that need to validate later
 	tips     *TxMap        // tips stores all the tips
 	badtxs   *TxMap
@@ -84,6 +92,7 @@ type TxPool struct {
 
 	onNewTxReceived      map[string]chan types.Txi       // for notifications of new txs.
 	OnBatchConfirmed     []chan map[types.Hash]types.Txi // for notifications of confirmation.
+	OnSequencerConfirmed []chan *types.Sequencer         // for notifications of confirmed sequencers.
 	OnNewLatestSequencer []chan bool                     //for broadcasting new latest sequencer to record height
 	txNum                uint32
 	maxWeight            uint64
@@ -172,14 +181,50 @@ func (pool *TxPool) Init(genesis *types.Sequencer) {
 	pool.mu.Lock()
 	defer pool.mu.Unlock()
 
+	pool.init(genesis)
+	log.Infof("TxPool finish init")
+}
+
+func (pool *TxPool) init(genesis *types.Sequencer) {
 	genesisEnvelope := &txEnvelope{}
 	genesisEnvelope.tx = genesis
 	genesisEnvelope.status = TxStatusTip
 	genesisEnvelope.txType = TxTypeGenesis
 	pool.txLookup.Add(genesisEnvelope)
 	pool.tips.Add(genesis)
+}
 
-	log.Infof("TxPool finish init")
+// RollBack rolls the dag back to sequencer at height. All the txs in pool
+// are dropped since they may depend on the removed ones, and the sequencer
+// at height becomes the only tip.
+func (pool *TxPool) RollBack(height uint64) error {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	if err := pool.dag.RollBack(height); err != nil {
+		return err
+	}
+	pool.clearAll()
+	pool.init(pool.dag.LatestSequencer())
+	return nil
+}
+
+// Import writes seq and the txs it confirms into dag without executing
+// them, see Dag.Import. Like RollBack, all the txs in pool are dropped and
+// seq becomes the only tip.
+func (pool *TxPool) Import(seq *types.Sequencer, txs types.Txs, receipts ReceiptSet) error {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	if err := pool.dag.Import(seq, txs, receipts); err != nil {
+		return err
+	}
+	pool.clearAll()
+	pool.init(seq)
+	for _, c := range pool.OnNewLatestSequencer {
+		c <- true
+	}
+	return nil
 }
 
 func (pool *TxPool) Name() string {
@@ -594,8 +639,9 @@ func (pool *TxPool) isBadTx(tx *types.Tx) TxQuality {
 		originBalance := pool.dag.GetBalance(tx.Sender())
 		stateFrom = NewBalanceState(originBalance)
 	}
-	// if tx's value is larger than its balance, return fatal.
-	if tx.Value.Value.Cmp(stateFrom.OriginBalance().Value) > 0 {
+	// if tx's value and gas cost is larger than its balance, return fatal.
+	cost := txCost(tx)
+	if cost.Value.Cmp(stateFrom.OriginBalance().Value) > 0 {
 		log.WithField("tx", tx).Tracef("fatal tx, tx's value larger than balance")
 		return TxQualityIsFatal
 	}
@@ -603,12 +649,20 @@ func (pool *TxPool) isBadTx(tx *types.Tx) TxQuality {
 	// 	+ ( the value that 'from' newly spent )
 	// 	> ( balance of 'from' in db )
 	totalspent := math.NewBigInt(0)
-	if totalspent.Value.Add(stateFrom.spent.Value, tx.Value.Value).Cmp(
+	if totalspent.Value.Add(stateFrom.spent.Value, cost.Value).Cmp(
 		stateFrom.originBalance.Value) > 0 {
 		log.WithField("tx", tx).Tracef("bad tx, total spent larget than balance")
 		return TxQualityIsBad
 	}
 
+	// check if the staking tx is applicable to the candidates and votes.
+	if tx.Type.IsStaking() {
+		if err := pool.dag.VerifyStakingTx(tx); err != nil {
+			log.WithField("tx", tx).WithError(err).Tracef("fatal tx, staking tx not applicable")
+			return TxQualityIsFatal
+		}
+	}
+
 	return TxQualityIsGood
 }
 
@@ -621,6 +675,9 @@ func (pool *TxPool) confirm(seq *types.Sequencer) error {
 	if checkErr != nil {
 		return checkErr
 	}
+	if err := pool.checkDeadline(seq); err != nil {
+		return err
+	}
 	// get sequencer's unconfirmed elders
 	elders, errElders := pool.seekElders(seq)
 	if errElders != nil {
@@ -653,6 +710,9 @@ func (pool *TxPool) confirm(seq *types.Sequencer) error {
 	for _, c := range pool.OnBatchConfirmed {
 		c <- elders
 	}
+	for _, c := range pool.OnSequencerConfirmed {
+		c <- seq
+	}
 	for _, c := range pool.OnNewLatestSequencer {
 		c <- true
 	}
@@ -661,6 +721,47 @@ func (pool *TxPool) confirm(seq *types.Sequencer) error {
 	return nil
 }
 
+// ImportSequencer verifies seq and the txs it confirms, read from an
+// exported chain, and pushes them to the dag the way confirm does with the
+// elders found in the pool. The pool itself is left untouched, so it is
+// only for replaying a chain offline. The slot deadlines are not checked
+// as the sequencers are long past their slots.
+func (pool *TxPool) ImportSequencer(seq *types.Sequencer, txs types.Txs) error {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	if err := pool.isBadSeq(seq); err != nil {
+		return err
+	}
+	elders := make(map[types.Hash]types.Txi, len(txs))
+	for _, tx := range txs {
+		elders[tx.GetTxHash()] = tx
+	}
+	batch, err := pool.verifyConfirmBatch(seq, elders)
+	if err != nil {
+		return err
+	}
+	return pool.dag.Push(batch)
+}
+
+// PreConfirm computes the state root and the receipts root after seq
+// confirms all its unconfirmed elders in the pool. The parents of seq must
+// be set.
+func (pool *TxPool) PreConfirm(seq *types.Sequencer) (stateRoot types.Hash, receiptsRoot types.Hash, err error) {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	elders, err := pool.seekElders(seq)
+	if err != nil {
+		return types.Hash{}, types.Hash{}, err
+	}
+	batch, err := pool.verifyConfirmBatch(seq, elders)
+	if err != nil {
+		return types.Hash{}, types.Hash{}, err
+	}
+	return pool.dag.PreConfirm(batch)
+}
+
 // isBadSeq checks if a sequencer is correct.
 func (pool *TxPool) isBadSeq(seq *types.Sequencer) error {
 	// check if the nonce is duplicate
@@ -668,6 +769,24 @@ func (pool *TxPool) isBadSeq(seq *types.Sequencer) error {
 	if seqindag != nil {
 		return fmt.Errorf("bad seq,duplicate nonce %d found in dag", seq.GetNonce())
 	}
+	// check if the sequencer is issued by the right proposer
+	if pool.Consensus != nil {
+		if err := pool.Consensus.VerifySequencer(seq, pool.dag.LatestSequencer()); err != nil {
+			return fmt.Errorf("bad seq, rejected by consensus: %v", err)
+		}
+	}
+	return nil
+}
+
+// checkDeadline checks if seq is received within its slot, unless the
+// node is catching up with its peers.
+func (pool *TxPool) checkDeadline(seq *types.Sequencer) error {
+	if pool.Consensus == nil || (pool.CatchingUp != nil && pool.CatchingUp()) {
+		return nil
+	}
+	if err := pool.Consensus.CheckDeadline(seq, pool.dag.LatestSequencer()); err != nil {
+		return fmt.Errorf("bad seq, rejected by consensus: %v", err)
+	}
 	return nil
 }
 
@@ -730,7 +849,7 @@ func (pool *TxPool) verifyConfirmBatch(seq *types.Sequencer, elders map[types.Ha
 				batch[tx.From] = batchFrom
 			}
 			batchFrom.TxList.put(tx)
-			batchFrom.Neg.Value.Add(batchFrom.Neg.Value, tx.Value.Value)
+			batchFrom.Neg.Value.Add(batchFrom.Neg.Value, txCost(tx).Value)
 
 			batchTo, okTo := batch[tx.To]
 			if !okTo {
@@ -763,16 +882,10 @@ func (pool *TxPool) verifyConfirmBatch(seq *types.Sequencer, elders map[types.Ha
 		}
 	}
 
-	// construct tx hashes
-	var txhashes types.Hashes
-	for _, hash := range pool.getHashOrder() {
-		elder, in := elders[hash]
-		if !in {
-			continue
-		}
-		if elder.GetType() == types.TxBaseTypeNormal {
-			txhashes = append(txhashes, hash)
-		}
+	// construct tx hashes in the order every node executes them.
+	txhashes, err := sortConfirmTxs(elders)
+	if err != nil {
+		return nil, err
 	}
 
 	cb := &ConfirmBatch{}
@@ -1,10 +1,12 @@
 package core_test
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/annchain/OG/common/crypto"
 	"github.com/annchain/OG/common/math"
+	"github.com/annchain/OG/consensus"
 	"github.com/annchain/OG/core"
 	"github.com/annchain/OG/core/state"
 	"github.com/annchain/OG/og"
@@ -185,7 +187,7 @@ func TestPoolConfirm(t *testing.T) {
 	var err error
 
 	// sequencer's parents are normal txs
-	tx0 := newTestPoolTx(0)
+	tx0 := newTestPoolTx(1)
 	tx0.ParentsHash = []types.Hash{genesis.GetTxHash()}
 	pool.AddLocalTx(tx0)
 
@@ -193,7 +195,7 @@ func TestPoolConfirm(t *testing.T) {
 	// tx3 := newTestPoolBadTx()
 	// pool.AddLocalTx(tx3)
 
-	tx1 := newTestPoolTx(1)
+	tx1 := newTestPoolTx(2)
 	tx1.ParentsHash = []types.Hash{genesis.GetTxHash()}
 	pool.AddLocalTx(tx1)
 
@@ -202,15 +204,18 @@ func TestPoolConfirm(t *testing.T) {
 		tx0.GetTxHash(),
 		tx1.GetTxHash(),
 	}
+	seq.StateRoot, seq.ReceiptsRoot, err = pool.PreConfirm(seq)
+	if err != nil {
+		t.Fatalf("pre confirm failed: %v", err)
+	}
 	err = pool.AddLocalTx(seq)
 	if err != nil {
 		t.Fatalf("add seq to pool failed: %v", err)
 	}
-	if pool.Get(seq.GetTxHash()) == nil {
-		t.Fatalf("sequencer is not added into pool")
-	}
-	if status := pool.GetStatus(seq.GetTxHash()); status != core.TxStatusTip {
-		t.Fatalf("sequencer's status is not tip but %s after added", status.String())
+	// the pool is cleared on confirm, leaving the sequencer as the only tip.
+	tips := pool.GetAllTips()
+	if len(tips) != 1 || tips[seq.GetTxHash()] == nil {
+		t.Fatalf("sequencer is not the only tip after confirmed")
 	}
 	if pool.Get(tx0.GetTxHash()) != nil {
 		t.Fatalf("tx0 is not removed from pool")
@@ -267,3 +272,50 @@ func TestPoolConfirm(t *testing.T) {
 	// }
 
 }
+
+// lateEngine accepts every sequencer but finds all of them late.
+type lateEngine struct {
+	consensus.ConsensusEngine
+}
+
+func (lateEngine) VerifySequencer(seq *types.Sequencer, prev *types.Sequencer) error {
+	return nil
+}
+
+func (lateEngine) CheckDeadline(seq *types.Sequencer, prev *types.Sequencer) error {
+	return fmt.Errorf("late")
+}
+
+func TestPoolConfirmDeadline(t *testing.T) {
+	t.Parallel()
+
+	pool, dag, genesis, finish := newTestTxPool(t)
+	defer finish()
+
+	catchingUp := false
+	pool.Consensus = lateEngine{}
+	pool.CatchingUp = func() bool { return catchingUp }
+
+	seq := newTestSeq(1)
+	seq.ParentsHash = []types.Hash{genesis.GetTxHash()}
+	var err error
+	seq.StateRoot, seq.ReceiptsRoot, err = pool.PreConfirm(seq)
+	if err != nil {
+		t.Fatalf("pre confirm failed: %v", err)
+	}
+	if err = pool.AddRemoteTx(seq); err == nil {
+		t.Fatalf("late sequencer should be rejected")
+	}
+	if dag.LatestSequencer().Height != 0 {
+		t.Fatalf("late sequencer is confirmed")
+	}
+
+	// sequencers caught up with are received after their slots.
+	catchingUp = true
+	if err = pool.AddRemoteTx(seq); err != nil {
+		t.Fatalf("add seq while catching up failed: %v", err)
+	}
+	if dag.LatestSequencer().GetTxHash() != seq.GetTxHash() {
+		t.Fatalf("seq is not confirmed while catching up")
+	}
+}
@@ -254,6 +254,11 @@ func (c *AutoClient) doSampleSequencer(force bool) bool {
 		}
 	}
 
+	if !c.Delegate.IsProposer(me.Address, time.Now().UnixNano()/int64(time.Millisecond)) {
+		logrus.WithField("id", c.MyAccountIndex).Trace("not my turn to generate seq")
+		return false
+	}
+
 	seq, err := c.Delegate.GenerateSequencer(SeqRequest{
 		Issuer:     me.Address,
 		Height:     c.Delegate.GetLatestDagSequencer().Height + 1,
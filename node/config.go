@@ -4,6 +4,7 @@ import (
 	"crypto/ecdsa"
 	"encoding/hex"
 	"fmt"
+	"github.com/annchain/OG/account/keystore"
 	"github.com/annchain/OG/common/crypto"
 	"github.com/annchain/OG/p2p"
 	"github.com/annchain/OG/p2p/discover"
@@ -17,9 +18,11 @@ import (
 )
 
 const (
-	datadirPrivateKey = "nodekey" // Path within the datadir to the node's private key
+	datadirPrivateKey = "nodekey"  // Path within the datadir to the node's private key
+	datadirKeyStore   = "keystore" // Path within the datadir to the key files of accounts
 	defaultMaxPeers   = 50
 	defaultNetworkId  = 1
+	defaultAdminPort  = "8004" // Port of the admin API of the rpc on localhost
 )
 
 func getNodePrivKey() *ecdsa.PrivateKey {
@@ -67,6 +70,20 @@ func getNodePrivKey() *ecdsa.PrivateKey {
 	return key
 }
 
+// getKeyStore opens the keystore in keystore.dir, or in the datadir if not
+// set.
+func getKeyStore() *keystore.KeyStore {
+	dir := viper.GetString("keystore.dir")
+	if dir == "" {
+		dir = resolvePath(datadirKeyStore)
+	}
+	scryptN, scryptP := keystore.StandardScryptN, keystore.StandardScryptP
+	if viper.GetBool("keystore.light_scrypt") {
+		scryptN, scryptP = keystore.LightScryptN, keystore.LightScryptP
+	}
+	return keystore.NewKeyStore(dir, scryptN, scryptP)
+}
+
 // ResolvePath resolves path in the instance directory.
 func resolvePath(path string) string {
 	datadir := viper.GetString("datadir")
@@ -5,6 +5,7 @@ import (
 
 	"github.com/annchain/OG/common/crypto"
 	"github.com/annchain/OG/common/math"
+	"github.com/annchain/OG/consensus"
 	"github.com/annchain/OG/core"
 	"github.com/annchain/OG/og"
 	"github.com/annchain/OG/types"
@@ -19,11 +20,16 @@ type TxRequest struct {
 	Nonce      uint64
 }
 
+// ErrNotProposer is returned when the issuer is not scheduled to propose
+// the sequencer at the moment.
+var ErrNotProposer = fmt.Errorf("issuer is not the proposer of current slot")
+
 type Delegate struct {
 	TxCreator         *og.TxCreator
 	TxBuffer          *og.TxBuffer
 	TxPool            *core.TxPool
 	Dag               *core.Dag
+	Consensus         consensus.ConsensusEngine
 	OnNewTxiGenerated []chan types.Txi
 }
 
@@ -47,9 +53,14 @@ type SeqRequest struct {
 }
 
 func (c *Delegate) GenerateSequencer(r SeqRequest) (seq types.Txi, err error) {
-	seq = c.TxCreator.NewSignedSequencer(r.Issuer, r.Height, r.Nonce, r.PrivateKey)
+	seq = c.TxCreator.NewUnsignedSequencer(r.Issuer, r.Height, r.Nonce)
+	// an out-of-turn sequencer will never pass the graph verification.
+	if !c.IsProposer(r.Issuer, seq.(*types.Sequencer).Timestamp) {
+		err = ErrNotProposer
+		return
+	}
 	logrus.WithField("seq", seq).Infof("sequencer generated")
-	if ok := c.TxCreator.SealTx(seq); !ok {
+	if ok := c.TxCreator.SealSequencer(seq.(*types.Sequencer), r.PrivateKey); !ok {
 		logrus.Warn("delegate failed to seal seq")
 		err = fmt.Errorf("delegate failed to seal seq")
 		return
@@ -59,6 +70,20 @@ func (c *Delegate) GenerateSequencer(r SeqRequest) (seq types.Txi, err error) {
 	return
 }
 
+// IsProposer checks if addr is allowed by consensus to issue the next
+// sequencer at timestamp.
+func (c *Delegate) IsProposer(addr types.Address, timestamp int64) bool {
+	if c.Consensus == nil {
+		return true
+	}
+	proposer, err := c.Consensus.Proposer(c.Dag.LatestSequencer(), timestamp)
+	if err != nil {
+		logrus.WithError(err).Debug("failed to get proposer")
+		return false
+	}
+	return proposer == addr
+}
+
 func (c *Delegate) GetLatestAccountNonce(addr types.Address) (uint64, error) {
 	noncePool, errPool := c.TxPool.GetLatestNonce(addr)
 	if errPool == nil {
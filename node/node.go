@@ -1,6 +1,7 @@
 package node
 
 import (
+	"github.com/annchain/OG/consensus"
 	"github.com/annchain/OG/consensus/dpos"
 	"github.com/annchain/OG/og"
 	"github.com/annchain/OG/rpc"
@@ -48,11 +49,24 @@ func NewNode() *Node {
 		maxPeers = defaultMaxPeers
 	}
 	if viper.GetBool("rpc.enabled") {
-		rpcServer = rpc.NewRpcServer(viper.GetString("rpc.port"))
+		adminPort := viper.GetString("rpc.admin_port")
+		if adminPort == "" {
+			adminPort = defaultAdminPort
+		}
+		rpcServer = rpc.NewRpcServer(viper.GetString("rpc.port"), rpc.AdminConfig{
+			Port:     adminPort,
+			KeyStore: viper.GetBool("keystore.enabled"),
+			RollBack: viper.GetBool("rpc.rollback_enabled"),
+		})
 		n.Components = append(n.Components, rpcServer)
 	}
 	bootNode := viper.GetBool("p2p.bootstrap_node")
 
+	genesis, err := og.LoadGenesis(cryptoType)
+	if err != nil {
+		logrus.WithError(err).Fatalf("Error occurred while loading genesis")
+		panic(fmt.Sprintf("Error occurred while loading genesis %v", err))
+	}
 	networkId := viper.GetInt64("p2p.network_id")
 	if networkId == 0 {
 		networkId = defaultNetworkId
@@ -62,6 +76,7 @@ func NewNode() *Node {
 		og.OGConfig{
 			NetworkId:  uint64(networkId),
 			CryptoType: cryptoType,
+			Genesis:    genesis,
 		},
 	)
 
@@ -87,9 +102,33 @@ func NewNode() *Node {
 	// Setup crypto algorithm
 	signer := crypto.NewSigner(cryptoType)
 	types.Signer = signer
+
+	var consensusEngine consensus.ConsensusEngine
+	switch viper.GetString("consensus") {
+	case "dpos":
+		dposConfig := dpos.DefaultDposConfig()
+		if viper.IsSet("dpos.slot_duration_ms") {
+			dposConfig.SlotDurationMs = viper.GetInt64("dpos.slot_duration_ms")
+		}
+		if viper.IsSet("dpos.max_clock_drift_ms") {
+			dposConfig.MaxClockDriftMs = viper.GetInt64("dpos.max_clock_drift_ms")
+		}
+		dposEngine := dpos.NewDpos(dposConfig, org.Dag)
+		consensusEngine = dposEngine
+		n.Components = append(n.Components, dposEngine)
+	case "pos":
+		//todo
+	case "pow":
+		//todo
+	default:
+		panic("Unknown consensus algorithm: " + viper.GetString("consensus"))
+	}
+	org.TxPool.Consensus = consensusEngine
+
 	graphVerifier := &og.GraphVerifier{
-		Dag:    org.Dag,
-		TxPool: org.TxPool,
+		Dag:       org.Dag,
+		TxPool:    org.TxPool,
+		Consensus: consensusEngine,
 		//Buffer: txBuffer,
 	}
 
@@ -124,23 +163,61 @@ func NewNode() *Node {
 	org.TxBuffer = txBuffer
 	n.Components = append(n.Components, txBuffer)
 
+	// fast sync downloads the state of a recent sequencer instead of
+	// executing all the sequencers, it only works on an empty dag.
+	syncMode := downloader.FullSync
+	if mode := viper.GetString("hub.sync_mode"); mode != "" {
+		if err := syncMode.UnmarshalText([]byte(mode)); err != nil {
+			panic(err)
+		}
+	}
+	if syncMode == downloader.FastSync && org.Dag.LatestSequencer().Height > 0 {
+		logrus.Warn("dag not empty, fast sync disabled")
+		syncMode = downloader.FullSync
+	}
+	// light sync keeps the verified sequencer headers only and asks the
+	// full peers for the merkle proofs of the state it is queried for.
+	// fast sync checks the sequencers it doesn't execute the same way.
+	var (
+		lightClient            *og.LightClient
+		lightChain             downloader.LightChain
+		verifySeq              func(seq *types.Sequencer, prev *types.Sequencer) error
+		nodeStatusDataProvider og.NodeStatusDataProvider = org
+	)
+	if syncMode == downloader.LightSync || syncMode == downloader.FastSync {
+		if consensusEngine == nil {
+			panic(fmt.Sprintf("%s sync needs a consensus engine to verify the headers", syncMode))
+		}
+		lightClient = og.NewLightClient(org, hub, consensusEngine, txFormatVerifier)
+		if viper.IsSet("dpos.max_delegates") {
+			lightClient.MaxDelegates = viper.GetInt("dpos.max_delegates")
+		}
+	}
+	if syncMode == downloader.LightSync {
+		lightChain = lightClient
+		nodeStatusDataProvider = lightClient
+	}
+	if syncMode == downloader.FastSync {
+		verifySeq = lightClient.VerifySequencer
+	}
+
 	syncManager := syncer.NewSyncManager(syncer.SyncManagerConfig{
-		Mode:           downloader.FullSync,
+		Mode:           syncMode,
 		ForceSyncCycle: uint(viper.GetInt("hub.sync_cycle_ms")),
 		BootstrapNode:  bootNode,
-	}, hub, org)
+	}, hub, nodeStatusDataProvider)
 
-	downloaderInstance := downloader.New(downloader.FullSync, org.Dag, hub.RemovePeer, syncBuffer.AddTxs)
+	downloaderInstance := downloader.New(syncMode, org.Dag, lightChain, hub.RemovePeer, syncBuffer.AddTxs, org.TxPool.Import, verifySeq)
 	heighter := func() uint64 {
 		return org.Dag.LatestSequencer().Height
 	}
 	hub.Fetcher = fetcher.New(org.Dag.GetSequencerByHash, heighter, syncBuffer.AddTxs, hub.RemovePeer)
 	syncManager.CatchupSyncer = &syncer.CatchupSyncer{
 		PeerProvider:           hub,
-		NodeStatusDataProvider: org,
+		NodeStatusDataProvider: nodeStatusDataProvider,
 		Hub:           hub,
 		Downloader:    downloaderInstance,
-		SyncMode:      downloader.FullSync,
+		SyncMode:      syncMode,
 		BootStrapNode: bootNode,
 	}
 	syncManager.CatchupSyncer.Init()
@@ -177,6 +254,7 @@ func NewNode() *Node {
 		heighter, syncManager.CatchupSyncer.CacheNewTxEnabled)
 	org.TxPool.OnNewLatestSequencer = append(org.TxPool.OnNewLatestSequencer, org.NewLatestSequencerCh,
 		syncManager.IncrementalSyncer.NewLatestSequencerCh)
+	org.TxPool.CatchingUp = syncManager.CatchupSyncer.CatchingUp
 	m.NewSequencerHandler = syncManager.IncrementalSyncer
 	m.NewTxsHandler = syncManager.IncrementalSyncer
 	m.NewTxHandler = syncManager.IncrementalSyncer
@@ -194,14 +272,18 @@ func NewNode() *Node {
 	n.Components = append(n.Components, syncManager)
 
 	messageHandler32 := &og.IncomingMessageHandlerOG32{
-		Hub: hub,
-		Og:  org,
+		Hub:         hub,
+		Og:          org,
+		LightClient: lightClient,
 	}
 
 	mr32 := &og.MessageRouterOG32{
 		GetNodeDataMsgHandler: messageHandler32,
 		GetReceiptsMsgHandler: messageHandler32,
+		ReceiptsMsgHandler:    messageHandler32,
 		NodeDataMsgHandler:    messageHandler32,
+		GetProofMsgHandler:    messageHandler32,
+		ProofMsgHandler:       messageHandler32,
 	}
 	// Setup Hub
 	SetupCallbacks(m, hub)
@@ -218,6 +300,7 @@ func NewNode() *Node {
 		MaxMinedHash:       types.HexToHash(viper.GetString("max_mined_hash")),
 		DebugNodeId:        viper.GetInt("debug.node_id"),
 		GraphVerifier:      graphVerifier,
+		StateRootProvider:  org.TxPool,
 	}
 
 	// TODO: move to (embeded) client. It is not part of OG
@@ -241,6 +324,7 @@ func NewNode() *Node {
 		TxBuffer:  txBuffer,
 		Dag:       org.Dag,
 		TxCreator: txCreator,
+		Consensus: consensusEngine,
 	}
 
 	delegate.OnNewTxiGenerated = append(delegate.OnNewTxiGenerated, txBuffer.SelfGeneratedNewTxChan)
@@ -258,18 +342,6 @@ func NewNode() *Node {
 	hub.OnNewPeerConnected = append(hub.OnNewPeerConnected, syncManager.CatchupSyncer.NewPeerConnectedEventListener)
 	//init msg requst id
 	og.MsgCountInit()
-	switch viper.GetString("consensus") {
-	case "dpos":
-		//todo
-		consensus := dpos.NewDpos(org.Dag, &types.Address{})
-		n.Components = append(n.Components, consensus)
-	case "pos":
-		//todo
-	case "pow":
-		//todo
-	default:
-		panic("Unknown consensus algorithm: " + viper.GetString("consensus"))
-	}
 
 	// DataLoader
 	dataLoader := &og.DataLoader{
@@ -302,12 +374,18 @@ func NewNode() *Node {
 		rpcServer.C.SyncerManager = syncManager
 		rpcServer.C.AutoTxCli = autoClientManager
 		rpcServer.C.PerformanceMonitor = pm
+		rpcServer.C.LightClient = lightClient
+		if viper.GetBool("keystore.enabled") {
+			rpcServer.C.KeyStore = getKeyStore()
+		}
 	}
 	if viper.GetBool("websocket.enabled") {
 		wsServer := wserver.NewServer(fmt.Sprintf(":%d", viper.GetInt("websocket.port")))
 		n.Components = append(n.Components, wsServer)
 		org.TxPool.RegisterOnNewTxReceived(wsServer.NewTxReceivedChan, "wsServer.NewTxReceivedChan")
 		org.TxPool.OnBatchConfirmed = append(org.TxPool.OnBatchConfirmed, wsServer.BatchConfirmedChan)
+		org.TxPool.OnSequencerConfirmed = append(org.TxPool.OnSequencerConfirmed, wsServer.SequencerConfirmedChan)
+		wsServer.Ledger = org.Dag
 		pm.Register(wsServer)
 	}
 
@@ -384,4 +462,7 @@ func SetupCallbacksOG32(m *og.MessageRouterOG32, hub *og.Hub) {
 	hub.CallbackRegistryOG32[og.GetNodeDataMsg] = m.RouteGetNodeDataMsg
 	hub.CallbackRegistryOG32[og.NodeDataMsg] = m.RouteNodeDataMsg
 	hub.CallbackRegistryOG32[og.GetReceiptsMsg] = m.RouteGetReceiptsMsg
+	hub.CallbackRegistryOG32[og.ReceiptsMsg] = m.RouteReceiptsMsg
+	hub.CallbackRegistryOG32[og.GetProofMsg] = m.RouteGetProofMsg
+	hub.CallbackRegistryOG32[og.ProofMsg] = m.RouteProofMsg
 }
@@ -3,7 +3,9 @@ package downloader
 import (
 	"errors"
 	"fmt"
+	"github.com/annchain/OG/core"
 	"github.com/annchain/OG/metrics"
+	"github.com/annchain/OG/trie"
 	"github.com/annchain/OG/types"
 	"github.com/sirupsen/logrus"
 	"sync"
@@ -76,9 +78,12 @@ type Downloader struct {
 	rttEstimate   uint64 // Round trip time to target for download requests
 	rttConfidence uint64 // Confidence in the estimated RTT (unit: millionths to allow atomic ops)
 
-	dag IDag
+	dag        IDag
+	lightchain LightChain // Keeps the verified headers in light sync
 
 	insertTxs insertTxsFn
+	importTxs importTxsFn // Writes the sequencers up to the fast sync pivot without executing them
+	verifySeq verifySeqFn // Checks the sequencers up to the fast sync pivot before they are written
 	// Callbacks
 	dropPeer peerDropFn // Drops a peer for misbehaving
 
@@ -109,18 +114,37 @@ type Downloader struct {
 	quitLock sync.RWMutex  // Lock to prevent double closes
 
 	// Testing hooks
-	syncInitHook    func(uint64, uint64)           // Method to call upon initiating a new sync run
-	bodyFetchHook   func([]*types.SequencerHeader) // Method to call upon starting a block body fetch
-	chainInsertHook func([]*fetchResult)           // Method to call upon inserting a chain of blocks (possibly in multiple invocations)
+	syncInitHook     func(uint64, uint64)           // Method to call upon initiating a new sync run
+	bodyFetchHook    func([]*types.SequencerHeader) // Method to call upon starting a block body fetch
+	receiptFetchHook func([]*types.SequencerHeader) // Method to call upon starting a receipt fetch
+	chainInsertHook  func([]*fetchResult)           // Method to call upon inserting a chain of blocks (possibly in multiple invocations)
 }
 
 type IDag interface {
 	LatestSequencer() *types.Sequencer
 	GetSequencer(hash types.Hash, id uint64) *types.Sequencer
+	NewStateSync(root types.Hash) *trie.Sync
+	CommitStateSync(sched *trie.Sync) (int, error)
+}
+
+// LightChain encapsulates functions required to synchronise a light chain,
+// which keeps only the sequencer headers.
+type LightChain interface {
+	// HasHeader verifies a header's presence in the local chain.
+	HasHeader(hash types.Hash, height uint64) bool
+
+	// CurrentHeader retrieves the head header from the local chain.
+	CurrentHeader() *types.SequencerHeader
+
+	// InsertHeaderChain verifies and inserts a batch of headers into the
+	// local chain, returning the index of the failed header on error.
+	InsertHeaderChain(headers []*types.SequencerHeader) (int, error)
 }
 
 // New creates a new downloader to fetch hashes and blocks from remote peers.
-func New(mode SyncMode, dag IDag, dropPeer peerDropFn, insertTxs insertTxsFn) *Downloader {
+// lightchain is only used in light sync and verifySeq in fast sync, they
+// may be nil otherwise.
+func New(mode SyncMode, dag IDag, lightchain LightChain, dropPeer peerDropFn, insertTxs insertTxsFn, importTxs importTxsFn, verifySeq verifySeqFn) *Downloader {
 
 	dl := &Downloader{
 		mode:          mode,
@@ -129,8 +153,11 @@ func New(mode SyncMode, dag IDag, dropPeer peerDropFn, insertTxs insertTxsFn) *D
 		rttEstimate:   uint64(rttMaxEstimate),
 		rttConfidence: uint64(1000000),
 		dag:           dag,
+		lightchain:    lightchain,
 		dropPeer:      dropPeer,
 		insertTxs:     insertTxs,
+		importTxs:     importTxs,
+		verifySeq:     verifySeq,
 		headerCh:      make(chan dataPack, 1),
 		bodyCh:        make(chan dataPack, 1),
 		receiptCh:     make(chan dataPack, 1),
@@ -300,28 +327,23 @@ func (d *Downloader) syncWithPeer(p *peerConnection, hash types.Hash, seqId uint
 	}
 
 	//ancestor is smaller than our height
-	ourHeight := d.dag.LatestSequencer().Number()
+	ourHeight := d.localHeight()
 	if ourHeight > origin {
 		origin = ourHeight
 	}
-	// Ensure our origin point is below any fast sync pivot point
+	// Fast sync downloads the state of the pivot instead of executing the
+	// sequencers up to it. The pivot must be above our origin, otherwise
+	// everything is executed as in full sync.
 	pivot := uint64(0)
-	if d.mode == FastSync {
-		if height <= uint64(fsMinFullBlocks) {
-			origin = 0
-		} else {
-			pivot = height - uint64(fsMinFullBlocks)
-			if pivot <= origin {
-				origin = pivot - 1
-			}
-		}
+	if d.mode == FastSync && height > uint64(fsMinFullBlocks) && height-uint64(fsMinFullBlocks) > origin {
+		pivot = height - uint64(fsMinFullBlocks)
 	}
 	d.committed = 1
-	if d.mode == FastSync && pivot != 0 {
+	if pivot != 0 {
 		d.committed = 0
 	}
 	// Initiate the sync using a concurrent header and content retrieval algorithm
-	d.queue.Prepare(origin+1, d.mode)
+	d.queue.Prepare(origin+1, d.mode, pivot)
 	if d.syncInitHook != nil {
 		d.syncInitHook(origin, height)
 	}
@@ -329,11 +351,11 @@ func (d *Downloader) syncWithPeer(p *peerConnection, hash types.Hash, seqId uint
 	fetchers := []func() error{
 		func() error { return d.fetchHeaders(p, origin+1, pivot) }, // Headers are always retrieved
 		func() error { return d.fetchBodies(origin + 1) },          // Bodies are retrieved during normal and fast sync
-		// Receipts are retrieved during fast sync
+		func() error { return d.fetchReceipts(origin + 1) },        // Receipts are retrieved during fast sync
 		func() error { return d.processHeaders(origin+1, pivot, seqId) },
 	}
 	if d.mode == FastSync {
-		fetchers = append(fetchers, func() error { return d.processFastSyncContent(latest) })
+		fetchers = append(fetchers, func() error { return d.processFastSyncContent(pivot) })
 	} else if d.mode == FullSync {
 		fetchers = append(fetchers, d.processFullSyncContent)
 	}
@@ -461,12 +483,7 @@ func (d *Downloader) fetchHeight(p *peerConnection) (*types.SequencerHeader, err
 // the head links match), we do a binary search to find the common ancestor.
 func (d *Downloader) findAncestor(p *peerConnection, height uint64) (uint64, error) {
 	// Figure out the valid ancestor range to prevent rewrite attacks
-	floor, ceil := int64(-1), d.dag.LatestSequencer().Number()
-	if d.mode == FullSync {
-		ceil = d.dag.LatestSequencer().Number()
-	} else if d.mode == FastSync {
-		ceil = d.dag.LatestSequencer().Number()
-	}
+	floor, ceil := int64(-1), d.localHeight()
 	if ceil >= MaxForkAncestry {
 		floor = int64(ceil - MaxForkAncestry)
 	}
@@ -528,7 +545,7 @@ func (d *Downloader) findAncestor(p *peerConnection, height uint64) (uint64, err
 					continue
 				}
 				// Otherwise check if we already know the header or not
-				if d.mode == FullSync && d.dag.GetSequencer(headers[i].GetHash(), headers[i].SequencerId()) != nil {
+				if d.hasHeader(headers[i]) {
 					number, hash = headers[i].SequencerId(), headers[i].GetHash()
 
 					// If every header is known, even future ones, the peer straight out lied about its head
@@ -594,7 +611,7 @@ func (d *Downloader) findAncestor(p *peerConnection, height uint64) (uint64, err
 				arrived = true
 
 				// Modify the search interval based on the response
-				if d.mode == FullSync && d.dag.GetSequencer(headers[0].GetHash(), headers[0].SequencerId()) == nil {
+				if (d.mode == FullSync || d.mode == LightSync) && !d.hasHeader(headers[0]) {
 					end = check
 					break
 				}
@@ -825,6 +842,34 @@ func (d *Downloader) fetchBodies(from uint64) error {
 	return err
 }
 
+// fetchReceipts iteratively downloads the scheduled receipts, taking any
+// available peers, reserving a chunk of receipts for each, waiting for delivery
+// and also periodically checking for timeouts.
+func (d *Downloader) fetchReceipts(from uint64) error {
+	log.WithField("origin", from).Debug("Downloading receipts")
+
+	var (
+		deliver = func(packet dataPack) (int, error) {
+			pack := packet.(*receiptPack)
+			return d.queue.DeliverReceipts(pack.peerID, pack.receipts)
+		}
+		expire   = func() map[string]int { return d.queue.ExpireReceipts(d.requestTTL()) }
+		fetch    = func(p *peerConnection, req *fetchRequest) error { return p.FetchReceipts(req) }
+		capacity = func(p *peerConnection) int { return p.ReceiptCapacity(d.requestRTT()) }
+		setIdle  = func(p *peerConnection, accepted int) { p.SetReceiptsIdle(accepted) }
+	)
+	err := d.fetchParts(errCancelReceiptFetch, d.receiptCh, deliver, d.receiptWakeCh, expire,
+		d.queue.PendingReceipts, d.queue.InFlightReceipts, d.queue.ShouldThrottleReceipts, d.queue.ReserveReceipts,
+		d.receiptFetchHook, fetch, d.queue.CancelReceipts, capacity, d.peers.ReceiptIdlePeers, setIdle, "receipts")
+
+	if err != nil {
+		log.WithError(err).Warn("Receipt download terminated")
+	} else {
+		log.Debug("Receipt download terminated")
+	}
+	return err
+}
+
 // fetchParts iteratively downloads scheduled block parts, taking any available
 // peers, reserving a chunk of fetch requests for each, waiting for delivery and
 // also periodically checking for timeouts.
@@ -970,7 +1015,7 @@ func (d *Downloader) fetchParts(errCancel error, deliveryCh chan dataPack, deliv
 					break
 				}
 				_, id := peer.peer.Head()
-				if id < d.dag.LatestSequencer().Number() {
+				if id < d.localHeight() {
 					log.WithField("peer head ", id).WithField("peer", peer.id).Debug("peer head is behind")
 					continue
 				}
@@ -1036,7 +1081,7 @@ func (d *Downloader) processHeaders(origin uint64, pivot uint64, seqId uint64) e
 			// Terminate header processing if we synced up
 			if len(headers) == 0 {
 				// Notify everyone that headers are fully processed
-				for _, ch := range []chan bool{d.bodyWakeCh} {
+				for _, ch := range []chan bool{d.bodyWakeCh, d.receiptWakeCh} {
 					select {
 					case ch <- false:
 					case <-d.cancelCh:
@@ -1055,11 +1100,8 @@ func (d *Downloader) processHeaders(origin uint64, pivot uint64, seqId uint64) e
 				// L: Sync begins, and finds common ancestor at 11
 				// L: Request new headers up from 11 (R's TD was higher, it must have something)
 				// R: Nothing to give
-				if d.mode != LightSync {
-					head := d.dag.LatestSequencer()
-					if !gotHeaders && head.Number() > seqId {
-						return errStallingPeer
-					}
+				if !gotHeaders && d.localHeight() > seqId {
+					return errStallingPeer
 				}
 				// Disable any rollback and return
 				return nil
@@ -1081,6 +1123,14 @@ func (d *Downloader) processHeaders(origin uint64, pivot uint64, seqId uint64) e
 				}
 				chunk := headers[:limit]
 
+				// Light chains verify and keep the headers only
+				if d.mode == LightSync {
+					if n, err := d.lightchain.InsertHeaderChain(chunk); err != nil {
+						log.WithError(err).WithField("number", chunk[n].SequencerId()).WithField(
+							"hash", chunk[n].GetHash()).Warn("Invalid header encountered")
+						return errInvalidChain
+					}
+				}
 				// Unless we're doing light chains, schedule the headers for associated content retrieval
 				if d.mode == FullSync || d.mode == FastSync {
 					// If we've reached the allowed number of pending headers, stall a bit
@@ -1103,7 +1153,7 @@ func (d *Downloader) processHeaders(origin uint64, pivot uint64, seqId uint64) e
 			}
 
 			// Signal the content downloaders of the availablility of new tasks
-			for _, ch := range []chan bool{d.bodyWakeCh} {
+			for _, ch := range []chan bool{d.bodyWakeCh, d.receiptWakeCh} {
 				select {
 				case ch <- true:
 				default:
@@ -1113,6 +1163,27 @@ func (d *Downloader) processHeaders(origin uint64, pivot uint64, seqId uint64) e
 	}
 }
 
+// localHeight returns the height of the local chain, which is the head of
+// the light chain in light sync.
+func (d *Downloader) localHeight() uint64 {
+	if d.mode == LightSync {
+		return d.lightchain.CurrentHeader().SequencerId()
+	}
+	return d.dag.LatestSequencer().Number()
+}
+
+// hasHeader checks if header is in the local chain. Fast sync starts from
+// an empty dag and never finds one.
+func (d *Downloader) hasHeader(header *types.SequencerHeader) bool {
+	switch d.mode {
+	case FullSync:
+		return d.dag.GetSequencer(header.GetHash(), header.SequencerId()) != nil
+	case LightSync:
+		return d.lightchain.HasHeader(header.GetHash(), header.SequencerId())
+	}
+	return false
+}
+
 // processFullSyncContent takes fetch results from the queue and imports them into the chain.
 func (d *Downloader) processFullSyncContent() error {
 	for {
@@ -1160,60 +1231,71 @@ func (d *Downloader) importBlockResults(results []*fetchResult) error {
 	return nil
 }
 
-// processFastSyncContent takes fetch results from the queue and writes them to the
-// database. It also controls the synchronisation of state nodes of the pivot block.
-func (d *Downloader) processFastSyncContent(latest *types.SequencerHeader) error {
-
-	// Figure out the ideal pivot block. Note, that this goalpost may move if the
-	// sync takes long enough for the chain head to move significantly.
-	pivot := uint64(0)
-	if height := latest.SequencerId(); height > uint64(fsMinFullBlocks) {
-		pivot = height - uint64(fsMinFullBlocks)
-	}
-	// To cater for moving pivot points, track the pivot block and subsequently
-	// accumulated download results separately.
-	var (
-		oldPivot *fetchResult   // Locked in pivot block, might change eventually
-		oldTail  []*fetchResult // Downloaded content after the pivot
-	)
+// processFastSyncContent takes fetch results from the queue and imports them
+// into the dag. The sequencers up to the pivot are written without being
+// executed, with the state trie of the pivot downloaded from the peers
+// before the pivot itself. The ones above the pivot are executed as in
+// full sync.
+func (d *Downloader) processFastSyncContent(pivot uint64) error {
 	for {
-		// Wait for the next batch of downloaded data to be available, and if the pivot
-		// block became stale, move the goalpost
-		results := d.queue.Results(oldPivot == nil) // Block if we're not monitoring pivot staleness
+		results := d.queue.Results(true)
 		if len(results) == 0 {
-			// If pivot sync is done, stop
-			if oldPivot == nil {
-				return nil
-			}
-			// If sync failed, stop
-			select {
-			case <-d.cancelCh:
-				return nil
-			default:
-			}
+			return nil
 		}
 		if d.chainInsertHook != nil {
 			d.chainInsertHook(results)
 		}
-		if oldPivot != nil {
-			results = append(append([]*fetchResult{oldPivot}, oldTail...), results...)
-		}
-		// Split around the pivot block and process the two sides via fast/full sync
 		if atomic.LoadInt32(&d.committed) == 0 {
-			latest = results[len(results)-1].Header
-			if height := latest.SequencerId(); height > pivot+2*uint64(fsMinFullBlocks) {
-				log.WithField("old", pivot).WithField("new", height-uint64(fsMinFullBlocks)).Warn("Pivot became stale, moving")
-				pivot = height - uint64(fsMinFullBlocks)
+			var err error
+			if results, err = d.commitFastSyncResults(results, pivot); err != nil {
+				return err
 			}
 		}
-
-		// Fast sync done, pivot commit done, full import
 		if err := d.importBlockResults(results); err != nil {
 			return err
 		}
 	}
 }
 
+// commitFastSyncResults imports the results up to the pivot without
+// executing them and returns the rest. As they are not executed, each
+// sequencer is checked by verifySeq on top of the previous one before it
+// is written, and the state root of the pivot is not synced before the
+// pivot passes the check.
+func (d *Downloader) commitFastSyncResults(results []*fetchResult, pivot uint64) ([]*fetchResult, error) {
+	for i, result := range results {
+		select {
+		case <-d.quitCh:
+			return nil, errCancelContentProcessing
+		default:
+		}
+		height := result.Header.SequencerId()
+		if height > pivot {
+			return results[i:], nil
+		}
+		seq := result.Sequencer
+		if seq.GetTxHash() != result.Header.GetHash() {
+			return nil, fmt.Errorf("sequencer %s mismatches header %s", seq, result.Header)
+		}
+		if err := d.verifySeq(seq, d.dag.LatestSequencer()); err != nil {
+			return nil, fmt.Errorf("verify sequencer %s error: %v", seq, err)
+		}
+		if height == pivot {
+			if err := d.syncState(result.Sequencer.StateRoot); err != nil {
+				return nil, err
+			}
+		}
+		if err := d.importTxs(result.Sequencer, result.Transactions, result.Receipts); err != nil {
+			return nil, err
+		}
+		if height == pivot {
+			atomic.StoreInt32(&d.committed, 1)
+			log.WithField("pivot", pivot).Info("Fast sync committed the pivot")
+		}
+	}
+	return nil, nil
+}
+
 // DeliverHeaders injects a new batch of block headers received from a remote
 // node into the download schedule.
 func (d *Downloader) DeliverHeaders(id string, headers []*types.SequencerHeader) (err error) {
@@ -1225,6 +1307,11 @@ func (d *Downloader) DeliverBodies(id string, transactions [][]*types.Tx, sequen
 	return d.deliver(id, d.bodyCh, &bodyPack{id, transactions, sequencers}, bodyInMeter, bodyDropMeter)
 }
 
+// DeliverReceipts injects a new batch of receipts received from a remote node.
+func (d *Downloader) DeliverReceipts(id string, receipts []core.ReceiptSet) (err error) {
+	return d.deliver(id, d.receiptCh, &receiptPack{id, receipts}, receiptInMeter, receiptDropMeter)
+}
+
 // DeliverNodeData injects a new batch of node state data received from a remote node.
 func (d *Downloader) DeliverNodeData(id string, data [][]byte) (err error) {
 	return d.deliver(id, d.stateCh, &statePack{id, data}, stateInMeter, stateDropMeter)
@@ -1,6 +1,7 @@
 package downloader
 
 import (
+	"fmt"
 	"github.com/annchain/OG/common/crypto"
 	"github.com/annchain/OG/core"
 	"github.com/annchain/OG/ogdb"
@@ -63,7 +64,7 @@ func newTester() *downloadTester {
 		peerMissingStates: make(map[string]map[types.Hash]bool),
 	}
 
-	tester.downloader = New(FullSync, nil, nil, nil)
+	tester.downloader = New(FullSync, nil, nil, nil, nil, nil, nil)
 
 	return tester
 }
@@ -84,3 +85,56 @@ func TestHeaderEuqual(t *testing.T) {
 	}
 	t.Log("head", head, " seqHead", seqHead, "equal")
 }
+
+// fastSyncTestDag is the dag of a fast sync test, whose latest sequencer
+// is the last imported one.
+type fastSyncTestDag struct {
+	IDag
+	latest *types.Sequencer
+}
+
+func (dag *fastSyncTestDag) LatestSequencer() *types.Sequencer {
+	return dag.latest
+}
+
+func TestCommitFastSyncVerify(t *testing.T) {
+	dag := &fastSyncTestDag{latest: &types.Sequencer{}}
+	var results []*fetchResult
+	for height := uint64(1); height <= 2; height++ {
+		seq := &types.Sequencer{}
+		seq.Height = height
+		seq.Hash = types.HexToHash(fmt.Sprintf("0x%x", height))
+		results = append(results, &fetchResult{Header: seq.GetHead(), Sequencer: seq})
+	}
+	importTxs := func(seq *types.Sequencer, txs types.Txs, receipts core.ReceiptSet) error {
+		dag.latest = seq
+		return nil
+	}
+	// the pivot fails the check, so its state is never synced.
+	verifySeq := func(seq *types.Sequencer, prev *types.Sequencer) error {
+		if seq.Height != prev.Height+1 {
+			t.Fatalf("sequencer %d is checked on top of %d", seq.Height, prev.Height)
+		}
+		if seq.Height == 2 {
+			return fmt.Errorf("bad sequencer")
+		}
+		return nil
+	}
+	d := New(FastSync, dag, nil, nil, nil, importTxs, verifySeq)
+
+	if _, err := d.commitFastSyncResults(results, 2); err == nil {
+		t.Fatal("expected the bad pivot to be refused")
+	}
+	if dag.latest.Height != 1 {
+		t.Fatalf("expected sequencers up to height 1 imported, got %d", dag.latest.Height)
+	}
+	if d.committed != 0 {
+		t.Fatal("the bad pivot is committed")
+	}
+
+	// a sequencer not matching its header is refused before the check.
+	results[1].Sequencer = results[0].Sequencer
+	if _, err := d.commitFastSyncResults(results[1:], 2); err == nil {
+		t.Fatal("expected the sequencer mismatching its header to be refused")
+	}
+}
@@ -46,10 +46,10 @@ var (
 type peerConnection struct {
 	id string // Unique identifier of the peer
 
-	headerIdle int32 // Current header activity state of the peer (idle = 0, active = 1)
-	blockIdle  int32 // Current block activity state of the peer (idle = 0, active = 1)
-	//receiptIdle int32 // Current receipt activity state of the peer (idle = 0, active = 1)
-	stateIdle int32 // Current node data activity state of the peer (idle = 0, active = 1)
+	headerIdle  int32 // Current header activity state of the peer (idle = 0, active = 1)
+	blockIdle   int32 // Current block activity state of the peer (idle = 0, active = 1)
+	receiptIdle int32 // Current receipt activity state of the peer (idle = 0, active = 1)
+	stateIdle   int32 // Current node data activity state of the peer (idle = 0, active = 1)
 
 	headerThroughput  float64 // Number of headers measured to be retrievable per second
 	blockThroughput   float64 // Number of blocks (bodies) measured to be retrievable per second
@@ -82,6 +82,7 @@ type Peer interface {
 	RequestBodies(seqHashs []types.Hash) error
 	//RequestTxsByHash(hash types.Hash, id uint64) error
 	RequestNodeData([]types.Hash) error
+	RequestReceipts(from uint64, amount int) error
 }
 
 // newPeerConnection creates a new downloader peer.
@@ -103,6 +104,7 @@ func (p *peerConnection) Reset() {
 
 	atomic.StoreInt32(&p.headerIdle, 0)
 	atomic.StoreInt32(&p.blockIdle, 0)
+	atomic.StoreInt32(&p.receiptIdle, 0)
 
 	p.headerThroughput = 0
 	p.blockThroughput = 0
@@ -152,6 +154,24 @@ func (p *peerConnection) FetchBodies(request *fetchRequest) error {
 	return nil
 }
 
+// FetchReceipts sends a receipt retrieval request to the remote peer. The
+// headers of request are consecutive, so they are asked as a height range.
+func (p *peerConnection) FetchReceipts(request *fetchRequest) error {
+	// Sanity check the protocol version
+	if p.version < 32 {
+		panic(fmt.Sprintf("receipt fetch [og/32+] requested on eth/%d", p.version))
+	}
+	// Short circuit if the peer is already fetching
+	if !atomic.CompareAndSwapInt32(&p.receiptIdle, 0, 1) {
+		return errAlreadyFetching
+	}
+	p.receiptStarted = time.Now()
+
+	go p.peer.RequestReceipts(request.Headers[0].SequencerId(), len(request.Headers))
+
+	return nil
+}
+
 // FetchNodeData sends a node state data retrieval request to the remote peer.
 func (p *peerConnection) FetchNodeData(hashes []types.Hash) error {
 	// Sanity check the protocol version
@@ -190,6 +210,13 @@ func (p *peerConnection) SetBodiesIdle(delivered int) {
 	p.setIdle(p.blockStarted, delivered, &p.blockThroughput, &p.blockIdle)
 }
 
+// SetReceiptsIdle sets the peer to idle, allowing it to execute new receipt
+// retrieval requests. Its estimated receipt retrieval throughput is updated
+// with that measured just now.
+func (p *peerConnection) SetReceiptsIdle(delivered int) {
+	p.setIdle(p.receiptStarted, delivered, &p.receiptThroughput, &p.receiptIdle)
+}
+
 // SetNodeDataIdle sets the peer to idle, allowing it to execute new state trie
 // data retrieval requests. Its estimated state retrieval throughput is updated
 // with that measured just now.
@@ -420,6 +447,20 @@ func (ps *peerSet) BodyIdlePeers() ([]*peerConnection, int) {
 	return ps.idlePeers(31, 33, idle, throughput)
 }
 
+// ReceiptIdlePeers retrieves a flat list of all the currently receipt-idle peers
+// within the active peer set, ordered by their reputation.
+func (ps *peerSet) ReceiptIdlePeers() ([]*peerConnection, int) {
+	idle := func(p *peerConnection) bool {
+		return atomic.LoadInt32(&p.receiptIdle) == 0
+	}
+	throughput := func(p *peerConnection) float64 {
+		p.lock.RLock()
+		defer p.lock.RUnlock()
+		return p.receiptThroughput
+	}
+	return ps.idlePeers(32, 33, idle, throughput)
+}
+
 // NodeDataIdlePeers retrieves a flat list of all the currently node-data-idle
 // peers within the active peer set, ordered by their reputation.
 func (ps *peerSet) NodeDataIdlePeers() ([]*peerConnection, int) {
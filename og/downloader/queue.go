@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/annchain/OG/common"
+	"github.com/annchain/OG/core"
 	"github.com/annchain/OG/metrics"
 	"github.com/annchain/OG/types"
 	"gopkg.in/karalabe/cookiejar.v2/collections/prque"
@@ -39,11 +40,13 @@ type fetchResult struct {
 	Header       *types.SequencerHeader
 	Transactions types.Txs
 	Sequencer    *types.Sequencer
+	Receipts     core.ReceiptSet
 }
 
 // queue represents hashes that are either need fetching or are being fetched
 type queue struct {
-	mode SyncMode // Synchronisation mode to decide on the block parts to schedule for fetching
+	mode  SyncMode // Synchronisation mode to decide on the block parts to schedule for fetching
+	pivot uint64   // Fast sync pivot, the receipts are fetched for the sequencers up to it
 
 	// Headers are "special", they download in batches, supported by a skeleton chain
 	headerHead      types.Hash                        // [OG/31] Hash of the last queued header to verify order
@@ -103,6 +106,7 @@ func (q *queue) Reset() {
 
 	q.closed = false
 	q.mode = FullSync
+	q.pivot = 0
 
 	q.headerHead = types.Hash{}
 	q.headerPendPool = make(map[string]*fetchRequest)
@@ -314,7 +318,7 @@ func (q *queue) Schedule(headers []*types.SequencerHeader, from uint64) []*types
 		q.blockTaskPool[hash] = header
 		q.blockTaskQueue.Push(header, -float32(header.SequencerId()))
 
-		if q.mode == FastSync {
+		if q.fetchReceipts(header) {
 			q.receiptTaskPool[hash] = header
 			q.receiptTaskQueue.Push(header, -float32(header.SequencerId()))
 		}
@@ -438,6 +442,40 @@ func (q *queue) ReserveBodies(p *peerConnection, count int) (*fetchRequest, bool
 	return q.reserveHeaders(p, count, q.blockTaskPool, q.blockTaskQueue, q.blockPendPool, q.blockDonePool, isNoop)
 }
 
+// ReserveReceipts reserves a set of receipt fetches for the given peer,
+// skipping any previously failed downloads. Receipts are asked by a range
+// of heights, so only the consecutive headers from the first one are kept
+// in the request and the rest go back to the task queue.
+func (q *queue) ReserveReceipts(p *peerConnection, count int) (*fetchRequest, bool, error) {
+	isNoop := func(header *types.SequencerHeader) bool {
+		return false
+	}
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	request, progress, err := q.reserveHeaders(p, count, q.receiptTaskPool, q.receiptTaskQueue, q.receiptPendPool, q.receiptDonePool, isNoop)
+	if request == nil {
+		return request, progress, err
+	}
+	for i := 1; i < len(request.Headers); i++ {
+		if request.Headers[i].SequencerId() != request.Headers[0].SequencerId()+uint64(i) {
+			for _, header := range request.Headers[i:] {
+				q.receiptTaskQueue.Push(header, -float32(header.SequencerId()))
+			}
+			request.Headers = request.Headers[:i]
+			break
+		}
+	}
+	return request, progress, err
+}
+
+// fetchReceipts checks if the receipts of header are downloaded, which is
+// the case for the sequencers up to the pivot in fast sync, as they are
+// not executed.
+func (q *queue) fetchReceipts(header *types.SequencerHeader) bool {
+	return q.mode == FastSync && header.SequencerId() <= q.pivot
+}
+
 // reserveHeaders reserves a set of data download operations for a given peer,
 // skipping any previously failed ones. This method is a generic version used
 // by the individual special reservation functions.
@@ -475,7 +513,7 @@ func (q *queue) reserveHeaders(p *peerConnection, count int, taskPool map[types.
 		}
 		if q.resultCache[index] == nil {
 			components := 1
-			if q.mode == FastSync {
+			if q.fetchReceipts(header) {
 				components = 2
 			}
 			q.resultCache[index] = &fetchResult{
@@ -593,6 +631,15 @@ func (q *queue) ExpireBodies(timeout time.Duration) map[string]int {
 	return q.expire(timeout, q.blockPendPool, q.blockTaskQueue, bodyTimeoutMeter)
 }
 
+// ExpireReceipts checks for in flight receipt requests that exceeded a timeout
+// allowance, canceling them and returning the responsible peers for penalisation.
+func (q *queue) ExpireReceipts(timeout time.Duration) map[string]int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	return q.expire(timeout, q.receiptPendPool, q.receiptTaskQueue, receiptTimeoutMeter)
+}
+
 // expire is the generic check that move expired tasks from a pending pool back
 // into a task pool, returning all entities caught with expired tasks.
 //
@@ -740,6 +787,27 @@ func (q *queue) DeliverBodies(id string, txLists [][]*types.Tx, sequencers []*ty
 	return q.deliver(id, q.blockTaskPool, q.blockTaskQueue, q.blockPendPool, q.blockDonePool, bodyReqTimer, len(txLists), reconstruct)
 }
 
+// DeliverReceipts injects a receipt retrieval response into the results queue.
+// The method returns the number of receipt sets accepted from the delivery and
+// also wakes any threads waiting for data delivery. A receipt set is only
+// accepted if it has the receipt of the requested sequencer itself, the
+// receipts of the txs are checked when the sequencer is imported.
+func (q *queue) DeliverReceipts(id string, receiptList []core.ReceiptSet) (int, error) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	reconstruct := func(header *types.SequencerHeader, index int, result *fetchResult) error {
+		receipt, ok := receiptList[index][header.GetHash().Hex()]
+		if !ok || receipt == nil || receipt.TxHash != header.GetHash() {
+			log.WithField("requested header", header.StringFull()).Warn("receipt of requested sequencer is missing")
+			return errInvalidReceipt
+		}
+		result.Receipts = receiptList[index]
+		return nil
+	}
+	return q.deliver(id, q.receiptTaskPool, q.receiptTaskQueue, q.receiptPendPool, q.receiptDonePool, receiptReqTimer, len(receiptList), reconstruct)
+}
+
 // deliver injects a data retrieval response into the results queue.
 //
 // Note, this method expects the queue lock to be already held for writing. The
@@ -819,7 +887,7 @@ func (q *queue) deliver(id string, taskPool map[types.Hash]*types.SequencerHeade
 
 // Prepare configures the result cache to allow accepting and caching inbound
 // fetch results.
-func (q *queue) Prepare(offset uint64, mode SyncMode) {
+func (q *queue) Prepare(offset uint64, mode SyncMode, pivot uint64) {
 	q.lock.Lock()
 	defer q.lock.Unlock()
 
@@ -828,4 +896,5 @@ func (q *queue) Prepare(offset uint64, mode SyncMode) {
 		q.resultOffset = offset
 	}
 	q.mode = mode
+	q.pivot = pivot
 }
@@ -0,0 +1,149 @@
+package downloader
+
+import (
+	"time"
+
+	"github.com/annchain/OG/common/crypto"
+	"github.com/annchain/OG/trie"
+	"github.com/annchain/OG/types"
+)
+
+// stateReq is a batch of state nodes requested from a peer.
+type stateReq struct {
+	peer  *peerConnection
+	items map[types.Hash]struct{} // Hashes of the requested nodes not delivered yet
+	time  time.Time               // Time when the request was sent
+}
+
+// syncState downloads the state trie at root from the peers through
+// GetNodeData and NodeData. Every node is checked against the hash it is
+// requested by, and is written into db only after all its children are,
+// so an interrupted state sync never leaves a root without its children.
+func (d *Downloader) syncState(root types.Hash) error {
+	sched := d.dag.NewStateSync(root)
+	if sched.Pending() == 0 {
+		return nil
+	}
+	log.WithField("root", root.Hex()).Info("State sync starting")
+
+	var (
+		tasks   = make(map[types.Hash]struct{}) // Missing nodes not assigned to any peer
+		active  = make(map[string]*stateReq)    // In-flight requests by peer id
+		written int
+	)
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for sched.Pending() > 0 {
+		for _, hash := range sched.Missing(0) {
+			tasks[hash] = struct{}{}
+		}
+		d.assignStateTasks(tasks, active)
+		if len(active) == 0 && len(tasks) > 0 {
+			return errPeersUnavailable
+		}
+
+		select {
+		case <-d.cancelCh:
+			return errCancelStateFetch
+
+		case packet := <-d.stateCh:
+			req := active[packet.PeerId()]
+			if req == nil {
+				// timed out already, its items are assigned to others.
+				continue
+			}
+			delete(active, packet.PeerId())
+
+			results := make([]trie.SyncResult, 0, packet.Items())
+			for _, blob := range packet.(*statePack).states {
+				hash := crypto.Keccak256Hash(blob)
+				if _, ok := req.items[hash]; !ok {
+					continue
+				}
+				delete(req.items, hash)
+				results = append(results, trie.SyncResult{Hash: hash, Data: blob})
+			}
+			// the nodes not delivered are asked again. A partial delivery
+			// may be cut by the response size limit, only a peer
+			// delivering nothing is known to lack them.
+			for hash := range req.items {
+				if len(results) == 0 {
+					req.peer.MarkLacking(hash)
+				}
+				tasks[hash] = struct{}{}
+			}
+			req.peer.SetNodeDataIdle(len(results))
+
+			if _, index, err := sched.Process(results); err != nil {
+				log.WithError(err).WithField("hash", results[index].Hash.Hex()).Warn("Failed to process state node")
+				return err
+			}
+			n, err := d.dag.CommitStateSync(sched)
+			if err != nil {
+				return err
+			}
+			written += n
+			log.WithField("written", written).WithField("pending", sched.Pending()).Debug("Imported new state entries")
+
+		case <-ticker.C:
+			if d.peers.Len() == 0 {
+				return errNoPeers
+			}
+			for id, req := range active {
+				if time.Since(req.time) < d.requestTTL() {
+					continue
+				}
+				log.WithField("peer", id).Debug("State data delivery timed out")
+				for hash := range req.items {
+					tasks[hash] = struct{}{}
+				}
+				req.peer.SetNodeDataIdle(0)
+				delete(active, id)
+			}
+		}
+	}
+	log.WithField("root", root.Hex()).WithField("written", written).Info("State sync finished")
+	return nil
+}
+
+// assignStateTasks sends the missing nodes in tasks to the idle peers
+// which don't lack them.
+func (d *Downloader) assignStateTasks(tasks map[types.Hash]struct{}, active map[string]*stateReq) {
+	peers, _ := d.peers.NodeDataIdlePeers()
+	for _, p := range peers {
+		if len(tasks) == 0 {
+			return
+		}
+		if _, ok := active[p.id]; ok {
+			continue
+		}
+		req := &stateReq{
+			peer:  p,
+			items: make(map[types.Hash]struct{}),
+			time:  time.Now(),
+		}
+		capacity := p.NodeDataCapacity(d.requestRTT())
+		hashes := make([]types.Hash, 0, capacity)
+		for hash := range tasks {
+			if len(hashes) >= capacity {
+				break
+			}
+			if p.Lacks(hash) {
+				continue
+			}
+			hashes = append(hashes, hash)
+			req.items[hash] = struct{}{}
+		}
+		if len(hashes) == 0 {
+			continue
+		}
+		if err := p.FetchNodeData(hashes); err != nil {
+			continue
+		}
+		for _, hash := range hashes {
+			delete(tasks, hash)
+		}
+		active[p.id] = req
+	}
+}
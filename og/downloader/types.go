@@ -2,6 +2,7 @@ package downloader
 
 import (
 	"fmt"
+	"github.com/annchain/OG/core"
 	"github.com/annchain/OG/types"
 )
 
@@ -10,6 +11,14 @@ type peerDropFn func(id string)
 
 type insertTxsFn func(seq *types.Sequencer, txs types.Txs) error
 
+// importTxsFn is a callback type for writing a sequencer with its txs and
+// the downloaded receipts without executing them.
+type importTxsFn func(seq *types.Sequencer, txs types.Txs, receipts core.ReceiptSet) error
+
+// verifySeqFn is a callback type for checking a sequencer written by fast
+// sync without being executed, on top of the previous sequencer prev.
+type verifySeqFn func(seq *types.Sequencer, prev *types.Sequencer) error
+
 // dataPack is a data message returned by a peer for some query.
 type dataPack interface {
 	PeerId() string
@@ -52,6 +61,17 @@ func (p *bodyPack) Items() int {
 }
 func (p *bodyPack) Stats() string { return fmt.Sprintf("%d", len(p.transactions)) }
 
+// receiptPack is a batch of receipts returned by a peer, one receipt set
+// for each of the requested sequencers.
+type receiptPack struct {
+	peerID   string
+	receipts []core.ReceiptSet
+}
+
+func (p *receiptPack) PeerId() string { return p.peerID }
+func (p *receiptPack) Items() int     { return len(p.receipts) }
+func (p *receiptPack) Stats() string  { return fmt.Sprintf("%d", len(p.receipts)) }
+
 // statePack is a batch of states returned by a peer.
 type statePack struct {
 	peerID string
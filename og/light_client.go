@@ -0,0 +1,292 @@
+package og
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/annchain/OG/common/math"
+	"github.com/annchain/OG/consensus"
+	"github.com/annchain/OG/core"
+	"github.com/annchain/OG/core/state"
+	"github.com/annchain/OG/types"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultProofTimeout is the time a light node waits for a proof from a
+// peer before asking the next one.
+const DefaultProofTimeout = 5 * time.Second
+
+// LightClient keeps the sequencer headers of a light node and answers the
+// state queries with merkle proofs from full peers. Fast sync uses it to
+// check the sequencers it doesn't execute the same way as the headers.
+//
+// A header is trusted once it is signed by the delegate scheduled for it,
+// with the delegate set proven at the state root of the previous trusted
+// header. The trust goes back to the genesis of the local dag, so every
+// proof checked against the state root of a trusted header is trusted.
+type LightClient struct {
+	Og        *Og
+	Hub       *Hub
+	Consensus consensus.ConsensusEngine
+	Verifier  *TxFormatVerifier
+
+	MaxDelegates int           // Max size of the delegate set, as many delegate slots are proven
+	Timeout      time.Duration // Time to wait for the proof from a peer
+
+	insertMu sync.Mutex // Serializes the header insertions
+	mu       sync.Mutex
+	pending  map[uint32]*proofRequest
+}
+
+type proofRequest struct {
+	peerId string
+	ch     chan [][]byte
+}
+
+func NewLightClient(og *Og, hub *Hub, consensus consensus.ConsensusEngine, verifier *TxFormatVerifier) *LightClient {
+	return &LightClient{
+		Og:           og,
+		Hub:          hub,
+		Consensus:    consensus,
+		Verifier:     verifier,
+		MaxDelegates: core.DefaultMaxDelegates,
+		Timeout:      DefaultProofTimeout,
+		pending:      make(map[uint32]*proofRequest),
+	}
+}
+
+// CurrentHeader returns the latest verified header.
+func (l *LightClient) CurrentHeader() *types.SequencerHeader {
+	return l.Og.Dag.LightHead()
+}
+
+// HasHeader checks if the header of hash at height is verified.
+func (l *LightClient) HasHeader(hash types.Hash, height uint64) bool {
+	header := l.Og.Dag.GetLightHeader(height)
+	return header != nil && header.Hash == hash
+}
+
+// InsertHeaderChain verifies the headers one by one on top of the current
+// head and stores the verified ones. Headers not above the head are
+// skipped if they are the verified ones, there is no rollback for a light
+// node. The index of the failed header is returned on error.
+func (l *LightClient) InsertHeaderChain(headers []*types.SequencerHeader) (int, error) {
+	l.insertMu.Lock()
+	defer l.insertMu.Unlock()
+
+	var (
+		prev     = l.CurrentHeader()
+		verified []*types.SequencerHeader
+		index    int
+		err      error
+	)
+	for index = 0; index < len(headers); index++ {
+		header := headers[index]
+		if header.Height <= prev.Height {
+			if header.CalcHash() != header.Hash || !l.HasHeader(header.Hash, header.Height) {
+				err = fmt.Errorf("header %s conflicts with the verified one", header)
+				break
+			}
+			continue
+		}
+		if err = l.verifyHeader(header, prev); err != nil {
+			break
+		}
+		verified = append(verified, header)
+		prev = header
+	}
+	if werr := l.Og.Dag.WriteLightHeaders(verified); werr != nil && err == nil {
+		return len(headers) - 1, werr
+	}
+	if len(verified) > 0 {
+		logrus.WithField("head", prev).WithField("count", len(verified)).Debug("inserted light headers")
+	}
+	return index, err
+}
+
+// VerifySequencer checks a sequencer which fast sync writes without
+// executing it: its hash is recomputed, then it is checked as a header on
+// top of prev.
+func (l *LightClient) VerifySequencer(seq *types.Sequencer, prev *types.Sequencer) error {
+	if !l.Verifier.VerifyHash(seq) {
+		return fmt.Errorf("invalid hash of sequencer %s", seq)
+	}
+	return l.verifyHeader(seq.GetHead(), prev.GetHead())
+}
+
+// verifyHeader checks if header is signed by the delegate scheduled for
+// it, with the delegate set proven at the state root of prev. The hash is
+// recomputed first, as it is what the stored header is looked up by.
+func (l *LightClient) verifyHeader(header *types.SequencerHeader, prev *types.SequencerHeader) error {
+	if header.CalcHash() != header.Hash {
+		return fmt.Errorf("invalid hash of header %s", header)
+	}
+	if !l.Verifier.VerifyHeaderSignature(header) {
+		return fmt.Errorf("invalid signature of header %s", header)
+	}
+	delegates, err := l.delegates(prev.StateRoot)
+	if err != nil {
+		return fmt.Errorf("get delegates at %s error: %v", prev, err)
+	}
+	return l.Consensus.VerifyHeader(header, prev, delegates)
+}
+
+// delegates returns the delegate set at state root, read from the local
+// state if it is there, as the genesis state. Otherwise it is proven by the
+// peers, with the MaxDelegates slots the set is kept in.
+func (l *LightClient) delegates(root types.Hash) ([]types.Address, error) {
+	if delegates, err := l.Og.Dag.GetDelegatesAt(root); err == nil {
+		return delegates, nil
+	}
+	keys := core.DelegateSlots(l.MaxDelegates)
+	values, err := l.getStorage(root, core.DelegateStorageAddress, keys)
+	if err != nil {
+		return nil, err
+	}
+	return core.DecodeDelegates(func(key types.Hash) types.Hash {
+		return values[key]
+	}, l.MaxDelegates)
+}
+
+// GetBalance returns the balance of addr at the latest verified header.
+func (l *LightClient) GetBalance(addr types.Address) (*math.BigInt, error) {
+	account, err := l.getAccount(l.CurrentHeader().StateRoot, addr)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return math.NewBigInt(0), nil
+	}
+	return account.Balance, nil
+}
+
+// GetLatestNonce returns the latest nonce of addr at the latest verified
+// header, or types.ErrNonceNotExist if the account doesn't exist.
+func (l *LightClient) GetLatestNonce(addr types.Address) (uint64, error) {
+	account, err := l.getAccount(l.CurrentHeader().StateRoot, addr)
+	if err != nil {
+		return 0, err
+	}
+	if account == nil {
+		return 0, types.ErrNonceNotExist
+	}
+	return account.Nonce, nil
+}
+
+// GetState returns the storage value of key of addr at the latest verified
+// header.
+func (l *LightClient) GetState(addr types.Address, key types.Hash) (types.Hash, error) {
+	values, err := l.getStorage(l.CurrentHeader().StateRoot, addr, []types.Hash{key})
+	if err != nil {
+		return types.Hash{}, err
+	}
+	return values[key], nil
+}
+
+// getAccount returns the account of addr proven at state root, or nil if
+// addr is proven not in the state.
+func (l *LightClient) getAccount(root types.Hash, addr types.Address) (*state.Account, error) {
+	var account *state.Account
+	err := l.requestProof(root, addr, nil, func(proof [][]byte) (err error) {
+		account, err = state.VerifyAccountProof(root, addr, proof)
+		return err
+	})
+	return account, err
+}
+
+// getStorage returns the storage values of keys of addr proven at state
+// root. The values of a missing account are all empty.
+func (l *LightClient) getStorage(root types.Hash, addr types.Address, keys []types.Hash) (map[types.Hash]types.Hash, error) {
+	values := make(map[types.Hash]types.Hash, len(keys))
+	err := l.requestProof(root, addr, keys, func(proof [][]byte) error {
+		account, err := state.VerifyAccountProof(root, addr, proof)
+		if err != nil || account == nil {
+			return err
+		}
+		for _, key := range keys {
+			value, err := state.VerifyStorageProof(account.Root, key, proof)
+			if err != nil {
+				return fmt.Errorf("verify storage %s error: %v", key.Hex(), err)
+			}
+			values[key] = value
+		}
+		return nil
+	})
+	return values, err
+}
+
+// requestProof asks the peers in turn for the proof of addr and keys at
+// state root, until the proof from one of them passes verify.
+func (l *LightClient) requestProof(root types.Hash, addr types.Address, keys []types.Hash, verify func(proof [][]byte) error) error {
+	for _, p := range l.Hub.peers.Peers() {
+		if p.version < OG32 {
+			continue
+		}
+		msg := &types.MessageGetProof{
+			Root:      root,
+			Address:   addr,
+			Keys:      keys,
+			RequestId: MsgCounter.Get(),
+		}
+		proof, err := l.fetchProof(p.id, msg)
+		if err == nil {
+			err = verify(proof)
+		}
+		if err == nil {
+			return nil
+		}
+		logrus.WithError(err).WithField("peer", p.id).WithField("address", addr.Hex()).Debug("get proof failed")
+	}
+	return fmt.Errorf("no peer served a valid proof of %s at %s", addr.Hex(), root.Hex())
+}
+
+func (l *LightClient) fetchProof(peerId string, msg *types.MessageGetProof) ([][]byte, error) {
+	req := &proofRequest{peerId: peerId, ch: make(chan [][]byte, 1)}
+	l.mu.Lock()
+	l.pending[msg.RequestId] = req
+	l.mu.Unlock()
+	defer func() {
+		l.mu.Lock()
+		delete(l.pending, msg.RequestId)
+		l.mu.Unlock()
+	}()
+
+	if err := l.Hub.SendToPeer(peerId, GetProofMsg, msg); err != nil {
+		return nil, err
+	}
+	select {
+	case proof := <-req.ch:
+		return proof, nil
+	case <-time.After(l.Timeout):
+		return nil, fmt.Errorf("proof request timed out")
+	}
+}
+
+// DeliverProof hands the proof from a peer to the request waiting for it.
+func (l *LightClient) DeliverProof(requestId uint32, peerId string, proof [][]byte) {
+	l.mu.Lock()
+	req := l.pending[requestId]
+	l.mu.Unlock()
+	if req == nil || req.peerId != peerId {
+		logrus.WithField("peer", peerId).WithField("requestId", requestId).Debug("unrequested proof")
+		return
+	}
+	select {
+	case req.ch <- proof:
+	default:
+	}
+}
+
+// GetCurrentNodeStatus reports the latest verified header as the head, so
+// the catchup syncer of a light node follows the headers.
+func (l *LightClient) GetCurrentNodeStatus() StatusData {
+	status := l.Og.GetCurrentNodeStatus()
+	head := l.CurrentHeader()
+	status.CurrentBlock, status.CurrentId = head.Hash, head.Height
+	return status
+}
+
+func (l *LightClient) GetHeight() uint64 {
+	return l.CurrentHeader().Height
+}
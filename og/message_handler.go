@@ -81,13 +81,12 @@ func (h *IncomingMessageHandler) HandleFetchByHashRequest(syncRequest *types.Mes
 					if txi == nil {
 						continue
 					}
-					if txi.GetType() == types.TxBaseTypeNormal {
-						tx := txi.(*types.Tx)
+					switch tx := txi.(type) {
+					case *types.Tx:
 						txs = append(txs, tx.RawTx())
-					} else {
+					case *types.Sequencer:
 						//index = append(index, uint32(len(txs)))
-						seq := txi.(*types.Sequencer)
-						seqs = append(seqs, seq.RawSequencer())
+						seqs = append(seqs, tx.RawSequencer())
 					}
 				}
 			}
@@ -1,24 +1,129 @@
 package og
 
+import (
+	"github.com/annchain/OG/core"
+	"github.com/annchain/OG/og/downloader"
+	"github.com/annchain/OG/types"
+)
+
 // IncomingMessageHandler is the default handler of all incoming messages for OG
 type IncomingMessageHandlerOG32 struct {
-	Og  *Og
-	Hub *Hub
+	Og          *Og
+	Hub         *Hub
+	LightClient *LightClient // Receives the proofs in light and fast sync mode, nil otherwise
+}
+
+// HandleGetNodeDataMsg serves the state trie nodes and contract codes
+// requested by a fast syncing peer. Unknown hashes are skipped, the
+// requester checks every item against the hash it asked for.
+func (h *IncomingMessageHandlerOG32) HandleGetNodeDataMsg(msgReq *types.MessageGetNodeData, peerId string) {
+	var msgRes types.MessageNodeData
+	var bytes int
+
+	for _, hash := range msgReq.Hashes {
+		if bytes >= softResponseLimit {
+			msgLog.Debug("reached softResponseLimit")
+			break
+		}
+		if len(msgRes.Data) >= downloader.MaxStateFetch {
+			msgLog.Debug("reached MaxStateFetch")
+			break
+		}
+		data, err := h.Og.Dag.GetStateNode(hash)
+		if err != nil || len(data) == 0 {
+			continue
+		}
+		bytes += len(data)
+		msgRes.Data = append(msgRes.Data, types.RawData(data))
+	}
+	msgRes.RequestedId = msgReq.RequestId
+	h.Hub.SendToPeer(peerId, NodeDataMsg, &msgRes)
+}
+
+func (h *IncomingMessageHandlerOG32) HandleNodeDataMsg(msg *types.MessageNodeData, peerId string) {
+	data := make([][]byte, len(msg.Data))
+	for i, d := range msg.Data {
+		data[i] = d
+	}
+	// Deliver all to the downloader
+	if err := h.Hub.Downloader.DeliverNodeData(peerId, data); err != nil {
+		msgLog.WithError(err).Debug("Failed to deliver node state data")
+	}
 }
 
-func (h *IncomingMessageHandlerOG32) HandleGetNodeDataMsg(peerId string) {
-	msgLog.Warn("got GetNodeDataMsg ")
-	//todo
-	//p.SendNodeData(nil)
-	msgLog.Debug("need send node data")
+// HandleGetReceiptsMsg serves the receipts of the requested range of
+// sequencers, one encoded receipt set for each height. It stops at the
+// first sequencer without receipts, so the response may be shorter.
+func (h *IncomingMessageHandlerOG32) HandleGetReceiptsMsg(msgReq *types.MessageGetReceipts, peerId string) {
+	var msgRes types.MessageReceipts
+	var bytes int
+
+	for i := uint64(0); i < msgReq.Amount; i++ {
+		if bytes >= softResponseLimit {
+			msgLog.Debug("reached softResponseLimit")
+			break
+		}
+		if len(msgRes.Receipts) >= downloader.MaxReceiptFetch {
+			msgLog.Debug("reached MaxReceiptFetch")
+			break
+		}
+		receipts := h.Og.Dag.GetReceipts(msgReq.From + i)
+		if receipts == nil {
+			break
+		}
+		data, err := receipts.MarshalMsg(nil)
+		if err != nil {
+			msgLog.WithError(err).Warn("encode receipts error")
+			break
+		}
+		bytes += len(data)
+		msgRes.Receipts = append(msgRes.Receipts, types.RawData(data))
+	}
+	msgRes.RequestedId = msgReq.RequestId
+	h.Hub.SendToPeer(peerId, ReceiptsMsg, &msgRes)
 }
 
-func (h *IncomingMessageHandlerOG32) HandleNodeDataMsg(peerId string) {
+func (h *IncomingMessageHandlerOG32) HandleReceiptsMsg(msg *types.MessageReceipts, peerId string) {
+	receipts := make([]core.ReceiptSet, 0, len(msg.Receipts))
+	for _, data := range msg.Receipts {
+		var set core.ReceiptSet
+		if _, err := set.UnmarshalMsg(data); err != nil {
+			msgLog.WithError(err).WithField("peer", peerId).Warn("decode receipts error")
+			break
+		}
+		receipts = append(receipts, set)
+	}
 	// Deliver all to the downloader
-	if err := h.Hub.Downloader.DeliverNodeData(peerId, nil); err != nil {
-		msgLog.Debug("Failed to deliver node state data", "err", err)
+	if err := h.Hub.Downloader.DeliverReceipts(peerId, receipts); err != nil {
+		msgLog.WithError(err).Debug("Failed to deliver receipts")
+	}
+}
+
+// HandleGetProofMsg serves the merkle proof of an account and its storage
+// keys at the requested state root to a light node. The proof is empty if
+// the state root is unknown, the requester then asks another peer.
+func (h *IncomingMessageHandlerOG32) HandleGetProofMsg(msgReq *types.MessageGetProof, peerId string) {
+	var msgRes types.MessageProof
+
+	proof, err := h.Og.Dag.GetProof(msgReq.Root, msgReq.Address, msgReq.Keys)
+	if err != nil {
+		msgLog.WithError(err).WithField("root", msgReq.Root.Hex()).Debug("get proof error")
+	}
+	for _, node := range proof {
+		msgRes.Proof = append(msgRes.Proof, types.RawData(node))
 	}
+	msgRes.RequestedId = msgReq.RequestId
+	h.Hub.SendToPeer(peerId, ProofMsg, &msgRes)
 }
-func (h *IncomingMessageHandlerOG32) HandleGetReceiptsMsg(peerId string) {
 
+func (h *IncomingMessageHandlerOG32) HandleProofMsg(msg *types.MessageProof, peerId string) {
+	if h.LightClient == nil {
+		msgLog.WithField("peer", peerId).Debug("no proof requested, proof ignored")
+		return
+	}
+	proof := make([][]byte, len(msg.Proof))
+	for i, node := range msg.Proof {
+		proof[i] = node
+	}
+	h.LightClient.DeliverProof(msg.RequestedId, peerId, proof)
 }
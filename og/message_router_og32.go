@@ -1,21 +1,38 @@
 package og
 
+import "github.com/annchain/OG/types"
+
 type MessageRouterOG32 struct {
 	GetNodeDataMsgHandler GetNodeDataMsgHandler
 	NodeDataMsgHandler    NodeDataMsgHandler
 	GetReceiptsMsgHandler GetReceiptsMsgHandler
+	ReceiptsMsgHandler    ReceiptsMsgHandler
+	GetProofMsgHandler    GetProofMsgHandler
+	ProofMsgHandler       ProofMsgHandler
 }
 
 type GetNodeDataMsgHandler interface {
-	HandleGetNodeDataMsg(peerId string)
+	HandleGetNodeDataMsg(msg *types.MessageGetNodeData, peerId string)
 }
 
 type NodeDataMsgHandler interface {
-	HandleNodeDataMsg(peerId string)
+	HandleNodeDataMsg(msg *types.MessageNodeData, peerId string)
 }
 
 type GetReceiptsMsgHandler interface {
-	HandleGetReceiptsMsg(peerId string)
+	HandleGetReceiptsMsg(msg *types.MessageGetReceipts, peerId string)
+}
+
+type ReceiptsMsgHandler interface {
+	HandleReceiptsMsg(msg *types.MessageReceipts, peerId string)
+}
+
+type GetProofMsgHandler interface {
+	HandleGetProofMsg(msg *types.MessageGetProof, peerId string)
+}
+
+type ProofMsgHandler interface {
+	HandleProofMsg(msg *types.MessageProof, peerId string)
 }
 
 func (m *MessageRouterOG32) Start() {
@@ -30,13 +47,25 @@ func (m *MessageRouterOG32) Name() string {
 }
 
 func (m *MessageRouterOG32) RouteGetNodeDataMsg(msg *P2PMessage) {
-	m.GetNodeDataMsgHandler.HandleGetNodeDataMsg(msg.SourceID)
+	m.GetNodeDataMsgHandler.HandleGetNodeDataMsg(msg.Message.(*types.MessageGetNodeData), msg.SourceID)
 }
 
 func (m *MessageRouterOG32) RouteNodeDataMsg(msg *P2PMessage) {
-	m.NodeDataMsgHandler.HandleNodeDataMsg(msg.SourceID)
+	m.NodeDataMsgHandler.HandleNodeDataMsg(msg.Message.(*types.MessageNodeData), msg.SourceID)
 }
 
 func (m *MessageRouterOG32) RouteGetReceiptsMsg(msg *P2PMessage) {
-	m.GetReceiptsMsgHandler.HandleGetReceiptsMsg(msg.SourceID)
+	m.GetReceiptsMsgHandler.HandleGetReceiptsMsg(msg.Message.(*types.MessageGetReceipts), msg.SourceID)
+}
+
+func (m *MessageRouterOG32) RouteReceiptsMsg(msg *P2PMessage) {
+	m.ReceiptsMsgHandler.HandleReceiptsMsg(msg.Message.(*types.MessageReceipts), msg.SourceID)
+}
+
+func (m *MessageRouterOG32) RouteGetProofMsg(msg *P2PMessage) {
+	m.GetProofMsgHandler.HandleGetProofMsg(msg.Message.(*types.MessageGetProof), msg.SourceID)
+}
+
+func (m *MessageRouterOG32) RouteProofMsg(msg *P2PMessage) {
+	m.ProofMsgHandler.HandleProofMsg(msg.Message.(*types.MessageProof), msg.SourceID)
 }
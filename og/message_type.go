@@ -22,7 +22,7 @@ var ProtocolName = "og"
 var ProtocolVersions = []uint{OG32, OG31}
 
 // ProtocolLengths are the number of implemented message corresponding to different protocol versions.
-var ProtocolLengths = []uint64{18, 15}
+var ProtocolLengths = []uint64{21, 15}
 
 const ProtocolMaxMsgSize = 10 * 1024 * 1024 // Maximum cap on the size of a protocol message
 
@@ -57,6 +57,9 @@ const (
 	GetNodeDataMsg
 	NodeDataMsg
 	GetReceiptsMsg
+	ReceiptsMsg
+	GetProofMsg
+	ProofMsg
 )
 
 type SendingType uint8
@@ -73,7 +76,8 @@ func (mt MessageType) String() string {
 		"MessageTypeNewTx", "MessageTypeNewSequencer", "MessageTypeNewTxs", "MessageTypeLatestSequencer",
 		"MessageTypeBodiesRequest", "MessageTypeBodiesResponse", "MessageTypeTxsRequest",
 		"MessageTypeTxsResponse", "MessageTypeHeaderRequest", "MessageTypeHeaderResponse",
-		"GetNodeDataMsg", "NodeDataMsg", "GetReceiptsMsg",
+		"GetNodeDataMsg", "NodeDataMsg", "GetReceiptsMsg", "ReceiptsMsg",
+		"GetProofMsg", "ProofMsg",
 	}[int(mt)]
 }
 
@@ -98,7 +102,9 @@ func (m *P2PMessage) calculateHash() {
 	if m.MessageType == MessageTypeBodiesRequest || m.MessageType == MessageTypeFetchByHashRequest ||
 		m.MessageType == MessageTypeTxsRequest || m.MessageType == MessageTypeHeaderRequest ||
 		m.MessageType == MessageTypeSequencerHeader || m.MessageType == MessageTypeHeaderResponse ||
-		m.MessageType == MessageTypeBodiesResponse {
+		m.MessageType == MessageTypeBodiesResponse || m.MessageType == GetNodeDataMsg ||
+		m.MessageType == NodeDataMsg || m.MessageType == GetReceiptsMsg || m.MessageType == ReceiptsMsg ||
+		m.MessageType == GetProofMsg || m.MessageType == ProofMsg {
 		data = append(data, []byte(m.SourceID+"hi")...)
 	} else if m.MessageType == MessageTypeNewTx {
 		msg := m.Message.(*types.MessageNewTx)
@@ -222,6 +228,19 @@ func (p *P2PMessage) GetMessage() error {
 		p.Message = &types.MessageHeaderRequest{}
 	case MessageTypeHeaderResponse:
 		p.Message = &types.MessageHeaderResponse{}
+
+	case GetNodeDataMsg:
+		p.Message = &types.MessageGetNodeData{}
+	case NodeDataMsg:
+		p.Message = &types.MessageNodeData{}
+	case GetReceiptsMsg:
+		p.Message = &types.MessageGetReceipts{}
+	case ReceiptsMsg:
+		p.Message = &types.MessageReceipts{}
+	case GetProofMsg:
+		p.Message = &types.MessageGetProof{}
+	case ProofMsg:
+		p.Message = &types.MessageProof{}
 	default:
 		return fmt.Errorf("unkown mssage type %v ", p.MessageType)
 	}
@@ -45,6 +45,8 @@ func (og *Og) GetHeight() uint64 {
 type OGConfig struct {
 	NetworkId  uint64
 	CryptoType crypto.CryptoType
+	// Genesis is the one returned by LoadGenesis, it is loaded if nil.
+	Genesis *core.Genesis
 }
 
 func DefaultOGConfig() OGConfig {
@@ -70,7 +72,16 @@ func NewOg(config OGConfig) (*Og, error) {
 	if derr != nil {
 		return nil, derr
 	}
-	dagconfig := core.DagConfig{}
+	dagconfig := core.DagConfig{
+		GenesisDelegates:        genesisDelegates(config.CryptoType),
+		MaxDelegates:            viper.GetInt("dpos.max_delegates"),
+		MinDelegates:            viper.GetInt("dpos.min_delegates"),
+		MinStake:                uint64(viper.GetInt64("dpos.min_stake")),
+		GCMode:                  viper.GetString("dag.gc_mode"),
+		StateKeepRecent:         uint64(viper.GetInt64("dag.state_keep_recent")),
+		StateCheckpointInterval: uint64(viper.GetInt64("dag.state_checkpoint_interval")),
+		TrieCacheMB:             viper.GetInt("dag.trie_cache_mb"),
+	}
 	statedbConfig := state.StateDBConfig{
 		PurgeTimer:     time.Duration(viper.GetInt("statedb.purge_timer_s")),
 		BeatExpireTime: time.Second * time.Duration(viper.GetInt("statedb.beat_expire_time_s")),
@@ -94,12 +105,19 @@ func NewOg(config OGConfig) (*Og, error) {
 	og.TxPool = core.NewTxPool(txpoolconfig, og.Dag)
 
 	// initialize
+	genesis := config.Genesis
+	if genesis == nil {
+		if genesis, derr = LoadGenesis(config.CryptoType); derr != nil {
+			return nil, derr
+		}
+	}
 	if !og.Dag.LoadLastState() {
-		// TODO use config to load the genesis
-		seq, balance := core.DefaultGenesis(config.CryptoType)
-		if err := og.Dag.Init(seq, balance); err != nil {
+		if err := og.Dag.InitGenesis(genesis); err != nil {
 			return nil, err
 		}
+	} else if err := genesis.Check(og.Dag.Genesis()); err != nil {
+		// refuse to run a ledger of another network.
+		return nil, err
 	}
 	seq := og.Dag.LatestSequencer()
 	if seq == nil {
@@ -163,11 +181,81 @@ func CreateDB() (ogdb.Database, error) {
 		cache := viper.GetInt("leveldb.cache")
 		handles := viper.GetInt("leveldb.handles")
 		return ogdb.NewLevelDB(path, cache, handles)
+	case "boltdb":
+		path := viper.GetString("boltdb.path")
+		noSync := viper.GetBool("boltdb.no_sync")
+		return ogdb.NewBoltDB(path, noSync)
 	default:
 		return ogdb.NewMemDatabase(), nil
 	}
 }
 
+// LoadGenesis returns the genesis of the network, which is read from the
+// genesis file set by genesis.path, or the sample genesis with the
+// delegates in dpos.genesis_delegates if there is no genesis file. Its
+// chain parameters are reconciled with config by ApplyChainParams.
+func LoadGenesis(cryptoType crypto.CryptoType) (*core.Genesis, error) {
+	var genesis *core.Genesis
+	if path := viper.GetString("genesis.path"); path != "" {
+		var err error
+		if genesis, err = core.LoadGenesis(path); err != nil {
+			return nil, err
+		}
+	} else {
+		genesis = core.SampleGenesis(cryptoType, genesisDelegates(cryptoType))
+	}
+	if err := ApplyChainParams(genesis); err != nil {
+		return nil, err
+	}
+	return genesis, nil
+}
+
+// ApplyChainParams reconciles the chain id and the difficulty targets of
+// genesis with config. The ones set in genesis take the place of the ones
+// in config, and the ones not set are taken from config, so that the
+// genesis hash always covers the parameters the node runs with.
+func ApplyChainParams(genesis *core.Genesis) error {
+	networkId := uint64(viper.GetInt64("p2p.network_id"))
+	switch {
+	case genesis.ChainID == 0 && networkId == 0:
+		genesis.ChainID = DefaultOGConfig().NetworkId
+	case genesis.ChainID == 0:
+		genesis.ChainID = networkId
+	case networkId != 0 && networkId != genesis.ChainID:
+		return fmt.Errorf("network id %d mismatches chain id %d of genesis", networkId, genesis.ChainID)
+	}
+	viper.Set("p2p.network_id", genesis.ChainID)
+
+	if genesis.MaxTxHash == nil && viper.GetString("max_tx_hash") != "" {
+		hash := types.HexToHash(viper.GetString("max_tx_hash"))
+		genesis.MaxTxHash = &hash
+	}
+	if genesis.MaxMinedHash == nil && viper.GetString("max_mined_hash") != "" {
+		hash := types.HexToHash(viper.GetString("max_mined_hash"))
+		genesis.MaxMinedHash = &hash
+	}
+	if genesis.MaxTxHash != nil {
+		viper.Set("max_tx_hash", genesis.MaxTxHash.Hex())
+	}
+	if genesis.MaxMinedHash != nil {
+		viper.Set("max_mined_hash", genesis.MaxMinedHash.Hex())
+	}
+	return nil
+}
+
+// genesisDelegates returns the delegates in dpos.genesis_delegates, or the
+// ones of the sample genesis if not set.
+func genesisDelegates(cryptoType crypto.CryptoType) []types.Address {
+	if !viper.IsSet("dpos.genesis_delegates") {
+		return core.DefaultGenesisDelegates(cryptoType)
+	}
+	var delegates []types.Address
+	for _, addr := range viper.GetStringSlice("dpos.genesis_delegates") {
+		delegates = append(delegates, types.HexToAddress(addr))
+	}
+	return delegates
+}
+
 func GetOldDb() (ogdb.Database, error) {
 	switch viper.GetString("db.name") {
 	case "leveldb":
@@ -175,6 +263,10 @@ func GetOldDb() (ogdb.Database, error) {
 		cache := viper.GetInt("leveldb.cache")
 		handles := viper.GetInt("leveldb.handles")
 		return ogdb.NewLevelDB(path, cache, handles)
+	case "boltdb":
+		path := viper.GetString("boltdb.path") + "test"
+		noSync := viper.GetBool("boltdb.no_sync")
+		return ogdb.NewBoltDB(path, noSync)
 	default:
 		return ogdb.NewMemDatabase(), nil
 	}
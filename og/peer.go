@@ -199,26 +199,27 @@ func (p *peer) AsyncSendMessage(msg *P2PMessage) {
 	}
 }
 
-// SendNodeDataRLP sends a batch of arbitrary internal data, corresponding to the
-// hashes requested.
-func (p *peer) SendNodeData(data []byte) error {
-	return p.sendRawMessage(NodeDataMsg, data)
-}
-
 // RequestNodeData fetches a batch of arbitrary data from a node's known state
 // data, corresponding to the specified hashes.
 func (p *peer) RequestNodeData(hashes []types.Hash) error {
 	msgLog.WithField("count", len(hashes)).Debug("Fetching batch of state data")
-	hashsStruct := types.Hashes(hashes)
-	b, _ := hashsStruct.MarshalMsg(nil)
-	return p.sendRawMessage(GetNodeDataMsg, b)
+	msg := &types.MessageGetNodeData{
+		Hashes:    hashes,
+		RequestId: MsgCounter.Get(),
+	}
+	return p.sendRequest(GetNodeDataMsg, msg)
 }
 
-// RequestReceipts fetches a batch of transaction receipts from a remote node.
-func (p *peer) RequestReceipts(hashes types.Hashes) error {
-	msgLog.WithField("count", len(hashes)).Debug("Fetching batch of receipts")
-	b, _ := hashes.MarshalMsg(nil)
-	return p.sendRawMessage(GetReceiptsMsg, b)
+// RequestReceipts fetches the receipts of the amount sequencers from height
+// from of a remote node.
+func (p *peer) RequestReceipts(from uint64, amount int) error {
+	msgLog.WithField("from", from).WithField("count", amount).Debug("Fetching batch of receipts")
+	msg := &types.MessageGetReceipts{
+		From:      from,
+		Amount:    uint64(amount),
+		RequestId: MsgCounter.Get(),
+	}
+	return p.sendRequest(GetReceiptsMsg, msg)
 }
 
 // RequestHeadersByHash fetches a batch of blocks' headers corresponding to the
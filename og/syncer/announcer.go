@@ -17,16 +17,14 @@ func NewAnnouncer(messageSender MessageSender) *Announcer {
 
 //BroadcastNewTx brodcast newly created txi message
 func (m *Announcer) BroadcastNewTx(txi types.Txi) {
-	txType := txi.GetType()
-	if txType == types.TxBaseTypeNormal {
-		tx := txi.(*types.Tx)
+	switch tx := txi.(type) {
+	case *types.Tx:
 		msgTx := types.MessageNewTx{RawTx: tx.RawTx()}
 		m.messageSender.BroadcastMessage(og.MessageTypeNewTx, &msgTx)
-	} else if txType == types.TxBaseTypeSequencer {
-		seq := txi.(*types.Sequencer)
-		msgTx := types.MessageNewSequencer{RawSequencer: seq.RawSequencer()}
+	case *types.Sequencer:
+		msgTx := types.MessageNewSequencer{RawSequencer: tx.RawSequencer()}
 		m.messageSender.BroadcastMessage(og.MessageTypeNewSequencer, &msgTx)
-	} else {
-		log.Warn("never come here, unknown tx type", txType)
+	default:
+		log.Warn("never come here, unknown tx type", txi.GetType())
 	}
 }
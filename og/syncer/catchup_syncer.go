@@ -152,6 +152,11 @@ func (c *CatchupSyncer) loopSync() {
 	}
 }
 
+// CatchingUp reports if the node is syncing the sequencers it missed.
+func (c *CatchupSyncer) CatchingUp() bool {
+	return c.isSyncing()
+}
+
 func (c *CatchupSyncer) isSyncing() bool {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -178,6 +183,10 @@ func (c *CatchupSyncer) unsetSyncFlag() {
 }
 
 func (c *CatchupSyncer) CacheNewTxEnabled() bool {
+	// light nodes keep the headers only.
+	if c.SyncMode == downloader.LightSync {
+		return false
+	}
 	if c.getWorkState() == Stopped {
 		return true
 	}
@@ -219,6 +228,10 @@ func (c *CatchupSyncer) syncToLatest() error {
 			log.WithError(err).Warn("catchup sync failed")
 			return err
 		}
+		if c.SyncMode == downloader.FastSync {
+			log.Info("fast sync finished, switching to full sync")
+			c.SyncMode = downloader.FullSync
+		}
 		logrus.WithField("seqId", seqId).Debug("finished downloader synchronize")
 		//bpHash, seqId, err = c.PeerProvider.GetPeerHead(bpId)
 		//if err != nil {
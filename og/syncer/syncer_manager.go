@@ -130,6 +130,12 @@ func (s *SyncManager) loopSync() {
 			case Stopped:
 				// catch up already done. now it is up to date. start incremental
 				s.Status = SyncStatusIncremental
+				// light nodes don't take txs, they keep following the
+				// headers by catchup sync.
+				if s.CatchupSyncer.SyncMode == downloader.LightSync {
+					s.NotifyUpToDateEvent(true)
+					continue
+				}
 				s.IncrementalSyncer.EnableEvent <- true
 				// <-ffchan.NewTimeoutSender(s.IncrementalSyncer.EnableEvent, true, "IncrementalSyncerEnable", 1000).C
 				s.NotifyUpToDateEvent(true)
@@ -15,6 +15,13 @@ type TipGenerator interface {
 	GetRandomTips(n int) (v []types.Txi)
 }
 
+// StateRootProvider computes the state root and the receipts root a
+// sequencer should commit to once it confirms its parents. Usually it is
+// tx pool.
+type StateRootProvider interface {
+	PreConfirm(seq *types.Sequencer) (stateRoot types.Hash, receiptsRoot types.Hash, err error)
+}
+
 // TxCreator creates tx and do the signing and mining
 type TxCreator struct {
 	Signer             crypto.Signer
@@ -25,6 +32,7 @@ type TxCreator struct {
 	MaxConnectingTries int          // Max number of times to find a pair of parents. If exceeded, try another nonce.
 	DebugNodeId        int          // Only for debug. This value indicates tx sender and is temporarily saved to tx.height
 	GraphVerifier      Verifier     // To verify the graph structure
+	StateRootProvider  StateRootProvider
 }
 
 func (m *TxCreator) NewUnsignedTx(from types.Address, to types.Address, value *math.BigInt, accountNonce uint64) types.Txi {
@@ -40,18 +48,20 @@ func (m *TxCreator) NewUnsignedTx(from types.Address, to types.Address, value *m
 	return &tx
 }
 
-func (m *TxCreator) NewTxWithSeal(from types.Address, to types.Address, value *math.BigInt, data []byte,
-	nonce uint64, pubkey crypto.PublicKey, sig crypto.Signature) (tx types.Txi, err error) {
+func (m *TxCreator) NewTxWithSeal(txType types.TxBaseType, from types.Address, to types.Address, value *math.BigInt, data []byte,
+	gasLimit uint64, gasPrice *math.BigInt, nonce uint64, pubkey crypto.PublicKey, sig crypto.Signature) (tx types.Txi, err error) {
 	tx = &types.Tx{
 		From: from,
 		// TODO
 		// should consider the case that to is nil. (contract creation)
-		To:    to,
-		Value: value,
-		Data:  data,
+		To:       to,
+		Value:    value,
+		Data:     data,
+		GasLimit: gasLimit,
+		GasPrice: gasPrice,
 		TxBase: types.TxBase{
 			AccountNonce: nonce,
-			Type:         types.TxBaseTypeNormal,
+			Type:         txType,
 		},
 	}
 	tx.GetBase().Signature = sig.Bytes
@@ -82,7 +92,8 @@ func (m *TxCreator) NewSignedTx(from types.Address, to types.Address, value *mat
 
 func (m *TxCreator) NewUnsignedSequencer(issuer types.Address, Height uint64, accountNonce uint64) types.Txi {
 	tx := types.Sequencer{
-		Issuer: issuer,
+		Issuer:    issuer,
+		Timestamp: time.Now().UnixNano() / int64(time.Millisecond),
 		TxBase: types.TxBase{
 			AccountNonce: accountNonce,
 			Type:         types.TxBaseTypeSequencer,
@@ -198,3 +209,68 @@ func (m *TxCreator) SealTx(tx types.Txi) (ok bool) {
 	}).Debugf("total time for mining")
 	return true
 }
+
+// SealSequencer seals a sequencer and signs it with privateKey. The state
+// root of a sequencer depends on the txs it confirms, so unlike SealTx,
+// parents are picked up first, then the state root is filled in and the
+// sequencer is signed before mining.
+func (m *TxCreator) SealSequencer(seq *types.Sequencer, privateKey crypto.PrivateKey) (ok bool) {
+	if privateKey.Type != m.Signer.GetCryptoType() {
+		panic("crypto type mismatch")
+	}
+	connectionTries := 0
+	minedNonce := uint64(0)
+
+	timeStart := time.Now()
+	timeout := time.NewTimer(time.Minute * 5)
+	defer timeout.Stop()
+	respChan := make(chan uint64)
+	defer close(respChan)
+	for {
+		connectionTries++
+		txs := m.TipGenerator.GetRandomTips(2)
+		if len(txs) == 0 {
+			// Impossible. At least genesis is there
+			logrus.Warn("at least genesis is there. Wait for loading")
+			time.Sleep(time.Second * 2)
+			continue
+		}
+		parentHashes := make([]types.Hash, len(txs))
+		for i, parent := range txs {
+			parentHashes[i] = parent.GetTxHash()
+		}
+		seq.ParentsHash = parentHashes
+
+		root, receiptsRoot, err := m.StateRootProvider.PreConfirm(seq)
+		if err != nil {
+			logrus.WithError(err).Debug("failed to compute state root, try other parents")
+			if connectionTries >= m.MaxConnectingTries {
+				return false
+			}
+			continue
+		}
+		seq.StateRoot = root
+		seq.ReceiptsRoot = receiptsRoot
+		// do sign work
+		signature := m.Signer.Sign(privateKey, seq.SignatureTargets())
+		seq.Signature = signature.Bytes
+		seq.PublicKey = m.Signer.PubKey(privateKey).Bytes
+
+		go m.Miner.StartMine(seq, m.MaxMinedHash, minedNonce+1, respChan)
+		select {
+		case minedNonce = <-respChan:
+			seq.MineNonce = minedNonce
+		case <-timeout.C:
+			return false
+		}
+		if _, ok := m.tryConnect(seq, txs); ok {
+			break
+		}
+	}
+	logrus.WithFields(logrus.Fields{
+		"elapsedns":  time.Since(timeStart).Nanoseconds(),
+		"nonce":      minedNonce,
+		"re-connect": connectionTries,
+	}).Debugf("total time for sealing sequencer")
+	return true
+}
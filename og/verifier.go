@@ -2,6 +2,7 @@ package og
 
 import (
 	"github.com/annchain/OG/common/crypto"
+	"github.com/annchain/OG/consensus"
 	"github.com/annchain/OG/types"
 	"github.com/sirupsen/logrus"
 )
@@ -75,10 +76,24 @@ func (v *TxFormatVerifier) VerifySourceAddress(t types.Txi) bool {
 	}
 }
 
+// VerifyHeaderSignature checks if a sequencer header is signed by its
+// issuer, which proves the state root in it is committed by the issuer.
+func (v *TxFormatVerifier) VerifyHeaderSignature(header *types.SequencerHeader) bool {
+	pubKey := crypto.PublicKeyFromBytes(v.CryptoType, header.PublicKey)
+	if header.Issuer.Bytes != v.Signer.Address(pubKey).Bytes {
+		return false
+	}
+	return v.Signer.Verify(
+		pubKey,
+		crypto.Signature{Type: v.CryptoType, Bytes: header.Signature},
+		header.SignatureTargets())
+}
+
 // GraphVerifier verifies if the tx meets the OG hash and graph standards.
 type GraphVerifier struct {
-	Dag    IDag
-	TxPool ITxPool
+	Dag       IDag
+	TxPool    ITxPool
+	Consensus consensus.ConsensusEngine // Sequencers are not checked by consensus if it is nil
 	//Buffer *TxBuffer
 }
 
@@ -153,8 +168,8 @@ func (v *GraphVerifier) getMyPreviousTx(currentTx types.Txi) (previousTx types.T
 				}
 			}
 
-			switch txi.GetType() {
-			case types.TxBaseTypeNormal:
+			switch txi.(type) {
+			case *types.Tx:
 				// may be somewhere else
 				// enqueue header more if we are still in the temp area
 				if archived {
@@ -167,7 +182,7 @@ func (v *GraphVerifier) getMyPreviousTx(currentTx types.Txi) (previousTx types.T
 					}
 				}
 				continue
-			case types.TxBaseTypeSequencer:
+			case *types.Sequencer:
 				// nothing to do, since all txs before seq should already be archived
 			}
 		} else {
@@ -248,6 +263,7 @@ func (v *GraphVerifier) getPreviousSequencer(currentSeq *types.Sequencer) (previ
 // A6: [My job] Node cannot reference two un-ordered nodes as its parents
 // B1: [My job] Nodes that are confirmed by at least N (=2) sequencers cannot be referenced.
 // B2: [My job] Two layer hash validation
+// C1: [My job] Sequencers must be issued by the proposer that consensus scheduled
 // Basically Verify checks whether txs are in their nonce order
 func (v *GraphVerifier) Verify(txi types.Txi) (ok bool) {
 	ok = false
@@ -265,6 +281,10 @@ func (v *GraphVerifier) Verify(txi types.Txi) (ok bool) {
 		logrus.WithField("tx", txi).Debug("tx failed on graph B1")
 		return
 	}
+	if ok = v.verifyC1(txi); !ok {
+		logrus.WithField("tx", txi).Debug("tx failed on consensus C1")
+		return
+	}
 	return true
 }
 
@@ -307,6 +327,23 @@ func (v *GraphVerifier) verifyB1(txi types.Txi) bool {
 	return true
 }
 
+func (v *GraphVerifier) verifyC1(txi types.Txi) bool {
+	seq, isSeq := txi.(*types.Sequencer)
+	if !isSeq || v.Consensus == nil {
+		return true
+	}
+	prev, ok := v.getPreviousSequencer(seq)
+	if !ok {
+		logrus.WithField("seq", seq).Debug("previous sequencer not found")
+		return false
+	}
+	if err := v.Consensus.VerifySequencer(seq, prev); err != nil {
+		logrus.WithError(err).WithField("seq", seq).Debug("sequencer rejected by consensus")
+		return false
+	}
+	return true
+}
+
 func (v *GraphVerifier) verifyWeight(txi types.Txi) bool {
 	var txis types.Txis
 	for _, pHash := range txi.Parents() {
@@ -0,0 +1,232 @@
+package ogdb
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/annchain/OG/common"
+	log "github.com/sirupsen/logrus"
+	bolt "go.etcd.io/bbolt"
+)
+
+// boltBucket is the only bucket all the pairs are kept in.
+var boltBucket = []byte("og")
+
+// boltIteratorChunk is the number of pairs a bolt iterator reads in one
+// read transaction.
+const boltIteratorChunk = 1024
+
+// BoltDB is a Database kept in a single BoltDB file. Each write is a bolt
+// transaction, so batches should be used for many writes. Bolt doesn't
+// accept empty keys.
+type BoltDB struct {
+	fn string
+	db *bolt.DB
+}
+
+type BoltDBConfig struct {
+	Path   string
+	NoSync bool
+}
+
+// NewBoltDB opens or creates the bolt file. The writes are not synced to
+// the disk one by one if noSync is set, which is faster but may lose the
+// latest writes on a system crash.
+func NewBoltDB(file string, noSync bool) (*BoltDB, error) {
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return nil, err
+	}
+	db, err := bolt.Open(file, 0600, &bolt.Options{Timeout: time.Second, NoSync: noSync})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	log.WithField("path", file).WithField("nosync", noSync).Info("Allocated bolt database")
+	return &BoltDB{fn: file, db: db}, nil
+}
+
+// Path returns the path to the database file.
+func (db *BoltDB) Path() string {
+	return db.fn
+}
+
+func (db *BoltDB) Put(key []byte, value []byte) error {
+	return db.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Put(key, value)
+	})
+}
+
+func (db *BoltDB) Has(key []byte) (bool, error) {
+	var has bool
+	err := db.db.View(func(tx *bolt.Tx) error {
+		has = tx.Bucket(boltBucket).Get(key) != nil
+		return nil
+	})
+	return has, err
+}
+
+func (db *BoltDB) Get(key []byte) ([]byte, error) {
+	var data []byte
+	err := db.db.View(func(tx *bolt.Tx) error {
+		// the value is only valid in the transaction.
+		if v := tx.Bucket(boltBucket).Get(key); v != nil {
+			data = common.CopyBytes(v)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, errors.New("not found")
+	}
+	return data, nil
+}
+
+func (db *BoltDB) Delete(key []byte) error {
+	return db.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Delete(key)
+	})
+}
+
+func (db *BoltDB) Close() {
+	if err := db.db.Close(); err != nil {
+		log.WithError(err).Error("Failed to close database")
+		return
+	}
+	log.Info("Database closed")
+}
+
+func (db *BoltDB) NewBatch() Batch {
+	return &boltBatch{db: db}
+}
+
+// NewIterator returns a iterator to iterate over the whole database content.
+func (db *BoltDB) NewIterator() Iterator {
+	return db.NewIteratorWithPrefix(nil)
+}
+
+// NewIteratorWithPrefix returns a iterator to iterate over subset of
+// database content with a particular prefix.
+//
+// A bolt read transaction held for long blocks the file from growing, so
+// the pairs are read in chunks, each in a short read transaction. The
+// writes made while iterating may or may not be seen.
+func (db *BoltDB) NewIteratorWithPrefix(prefix []byte) Iterator {
+	return &boltIterator{
+		db:     db.db,
+		prefix: common.CopyBytes(prefix),
+		next:   common.CopyBytes(prefix),
+		index:  -1,
+	}
+}
+
+type boltIterator struct {
+	db     *bolt.DB
+	prefix []byte
+	next   []byte // Key to read the next chunk from
+	done   bool   // Whether the last chunk is read
+	pairs  []kv
+	index  int
+	err    error
+}
+
+func (it *boltIterator) Next() bool {
+	if it.index+1 < len(it.pairs) {
+		it.index++
+		return true
+	}
+	if it.done || it.err != nil {
+		it.index = len(it.pairs)
+		return false
+	}
+	it.load()
+	it.index = 0
+	return len(it.pairs) > 0
+}
+
+// load reads the next chunk of pairs from it.next.
+func (it *boltIterator) load() {
+	it.pairs = it.pairs[:0]
+	it.err = it.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(boltBucket).Cursor()
+		k, v := c.Seek(it.next)
+		for ; k != nil && bytes.HasPrefix(k, it.prefix); k, v = c.Next() {
+			if len(it.pairs) == boltIteratorChunk {
+				it.next = common.CopyBytes(k)
+				return nil
+			}
+			it.pairs = append(it.pairs, kv{common.CopyBytes(k), common.CopyBytes(v)})
+		}
+		it.done = true
+		return nil
+	})
+	if it.err != nil {
+		it.pairs = it.pairs[:0]
+	}
+}
+
+func (it *boltIterator) Key() []byte {
+	if it.index < 0 || it.index >= len(it.pairs) {
+		return nil
+	}
+	return it.pairs[it.index].k
+}
+
+func (it *boltIterator) Value() []byte {
+	if it.index < 0 || it.index >= len(it.pairs) {
+		return nil
+	}
+	return it.pairs[it.index].v
+}
+
+func (it *boltIterator) Error() error { return it.err }
+
+func (it *boltIterator) Release() {
+	it.pairs, it.next = nil, nil
+	it.done = true
+}
+
+// boltBatch writes all its pairs in one bolt transaction.
+type boltBatch struct {
+	db     *BoltDB
+	writes []kv
+	size   int
+}
+
+func (b *boltBatch) Put(key, value []byte) error {
+	b.writes = append(b.writes, kv{common.CopyBytes(key), common.CopyBytes(value)})
+	b.size += len(value)
+	return nil
+}
+
+func (b *boltBatch) Write() error {
+	return b.db.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltBucket)
+		for _, kv := range b.writes {
+			if err := bucket.Put(kv.k, kv.v); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (b *boltBatch) ValueSize() int {
+	return b.size
+}
+
+func (b *boltBatch) Reset() {
+	b.writes = b.writes[:0]
+	b.size = 0
+}
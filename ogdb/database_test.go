@@ -21,10 +21,12 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strconv"
 	"sync"
 	"testing"
 
+	"github.com/annchain/OG/common"
 	"github.com/annchain/OG/ogdb"
 )
 
@@ -44,6 +46,22 @@ func newTestLDB() (*ogdb.LevelDB, func()) {
 	}
 }
 
+func newTestBoltDB() (*ogdb.BoltDB, func()) {
+	dirname, err := ioutil.TempDir(os.TempDir(), "ogdb_test_")
+	if err != nil {
+		panic("failed to create test file: " + err.Error())
+	}
+	db, err := ogdb.NewBoltDB(filepath.Join(dirname, "og.bolt"), true)
+	if err != nil {
+		panic("failed to create test database: " + err.Error())
+	}
+
+	return db, func() {
+		db.Close()
+		os.RemoveAll(dirname)
+	}
+}
+
 var test_values = []string{"", "a", "1251", "\x00123\x00"}
 
 func TestLDB_PutGet(t *testing.T) {
@@ -133,6 +151,12 @@ func TestMemoryDB_ParallelPutGet(t *testing.T) {
 	testParallelPutGet(ogdb.NewMemDatabase(), t)
 }
 
+func TestBoltDB_ParallelPutGet(t *testing.T) {
+	db, remove := newTestBoltDB()
+	defer remove()
+	testParallelPutGet(db, t)
+}
+
 func testParallelPutGet(db ogdb.Database, t *testing.T) {
 	const n = 8
 	var pending sync.WaitGroup
@@ -188,3 +212,77 @@ func testParallelPutGet(db ogdb.Database, t *testing.T) {
 	}
 	pending.Wait()
 }
+
+func TestLDB_Iterator(t *testing.T) {
+	db, remove := newTestLDB()
+	defer remove()
+	testIterator(db, t)
+}
+
+func TestMemoryDB_Iterator(t *testing.T) {
+	testIterator(ogdb.NewMemDatabase(), t)
+}
+
+func TestBoltDB_Iterator(t *testing.T) {
+	db, remove := newTestBoltDB()
+	defer remove()
+	testIterator(db, t)
+}
+
+func TestTable_Iterator(t *testing.T) {
+	db := ogdb.NewMemDatabase()
+	// keys of the other table must not be seen.
+	db.Put([]byte("tb"), []byte("other"))
+	db.Put([]byte("tzz"), []byte("other"))
+	testIterator(ogdb.NewTable(db, "ta"), t)
+}
+
+// testIterator writes n keys under two prefixes, more than a bolt iterator
+// reads at once, and checks both the full and the prefixed iterations are
+// in the key order.
+func testIterator(db ogdb.Database, t *testing.T) {
+	const n = 3000
+	batch := db.NewBatch()
+	for i := 0; i < n; i++ {
+		key := fmt.Sprintf("a%05d", i)
+		if i%2 == 1 {
+			key = fmt.Sprintf("b%05d", i)
+		}
+		if err := batch.Put([]byte(key), []byte("v"+key)); err != nil {
+			t.Fatalf("batch put failed: %v", err)
+		}
+	}
+	if err := batch.Write(); err != nil {
+		t.Fatalf("batch write failed: %v", err)
+	}
+
+	check := func(it ogdb.Iterator, prefix string, want int) {
+		defer it.Release()
+		var count int
+		var last []byte
+		for it.Next() {
+			key := it.Key()
+			if !bytes.HasPrefix(key, []byte(prefix)) {
+				t.Fatalf("iterated key %q without prefix %q", key, prefix)
+			}
+			if last != nil && bytes.Compare(last, key) >= 0 {
+				t.Fatalf("iterated key %q after %q", key, last)
+			}
+			if !bytes.Equal(it.Value(), append([]byte("v"), key...)) {
+				t.Fatalf("iterated wrong value %q of key %q", it.Value(), key)
+			}
+			last = common.CopyBytes(key)
+			count++
+		}
+		if err := it.Error(); err != nil {
+			t.Fatalf("iterate failed: %v", err)
+		}
+		if count != want {
+			t.Fatalf("iterated %d keys with prefix %q, expected %d", count, prefix, want)
+		}
+	}
+	check(db.NewIterator(), "", n)
+	check(db.NewIteratorWithPrefix([]byte("a")), "a", n/2)
+	check(db.NewIteratorWithPrefix([]byte("b")), "b", n/2)
+	check(db.NewIteratorWithPrefix([]byte("c")), "c", 0)
+}
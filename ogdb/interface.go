@@ -33,6 +33,22 @@ type Database interface {
 	Delete(key []byte) error
 	Close()
 	NewBatch() Batch
+	NewIterator() Iterator
+	NewIteratorWithPrefix(prefix []byte) Iterator
+}
+
+// Iterator iterates over the key-value pairs of a database in ascending
+// key order. It is positioned before the first pair, so Next should be
+// called before reading any pair. The slices returned by Key and Value are
+// only valid until the next call to Next. An iterator must be released
+// after use.
+type Iterator interface {
+	Next() bool
+	Key() []byte
+	Value() []byte
+	// Error returns the error met while iterating, if any.
+	Error() error
+	Release()
 }
 
 // Batch is a write-only database that commits changes to its host database
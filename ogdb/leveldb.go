@@ -26,7 +26,6 @@ import (
 	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/syndtr/goleveldb/leveldb/errors"
 	"github.com/syndtr/goleveldb/leveldb/filter"
-	"github.com/syndtr/goleveldb/leveldb/iterator"
 	"github.com/syndtr/goleveldb/leveldb/opt"
 	"github.com/syndtr/goleveldb/leveldb/util"
 
@@ -122,12 +121,13 @@ func (db *LevelDB) Delete(key []byte) error {
 	return db.db.Delete(key, nil)
 }
 
-func (db *LevelDB) NewIterator() iterator.Iterator {
+// NewIterator returns a iterator to iterate over the whole database content.
+func (db *LevelDB) NewIterator() Iterator {
 	return db.db.NewIterator(nil, nil)
 }
 
 // NewIteratorWithPrefix returns a iterator to iterate over subset of database content with a particular prefix.
-func (db *LevelDB) NewIteratorWithPrefix(prefix []byte) iterator.Iterator {
+func (db *LevelDB) NewIteratorWithPrefix(prefix []byte) Iterator {
 	return db.db.NewIterator(util.BytesPrefix(prefix), nil)
 }
 
@@ -436,6 +436,31 @@ func (dt *table) Close() {
 	// Do nothing; don't close the underlying DB.
 }
 
+// NewIterator returns a iterator over the keys in the table, with the
+// table prefix removed from them.
+func (dt *table) NewIterator() Iterator {
+	return dt.NewIteratorWithPrefix(nil)
+}
+
+func (dt *table) NewIteratorWithPrefix(prefix []byte) Iterator {
+	return &tableIterator{
+		it:     dt.db.NewIteratorWithPrefix(append([]byte(dt.prefix), prefix...)),
+		prefix: len(dt.prefix),
+	}
+}
+
+// tableIterator strips the table prefix from the keys.
+type tableIterator struct {
+	it     Iterator
+	prefix int
+}
+
+func (ti *tableIterator) Next() bool    { return ti.it.Next() }
+func (ti *tableIterator) Key() []byte   { return ti.it.Key()[ti.prefix:] }
+func (ti *tableIterator) Value() []byte { return ti.it.Value() }
+func (ti *tableIterator) Error() error  { return ti.it.Error() }
+func (ti *tableIterator) Release()      { ti.it.Release() }
+
 type tableBatch struct {
 	batch  Batch
 	prefix string
@@ -17,7 +17,9 @@
 package ogdb
 
 import (
+	"bytes"
 	"errors"
+	"sort"
 	"sync"
 
 	"github.com/annchain/OG/common"
@@ -97,6 +99,32 @@ func (db *MemDatabase) NewBatch() Batch {
 
 func (db *MemDatabase) Len() int { return len(db.db) }
 
+// NewIterator returns a iterator over a snapshot of the whole database.
+func (db *MemDatabase) NewIterator() Iterator {
+	return db.NewIteratorWithPrefix(nil)
+}
+
+// NewIteratorWithPrefix returns a iterator over a snapshot of the pairs
+// whose keys start with prefix. The writes after it is created are not
+// seen.
+func (db *MemDatabase) NewIteratorWithPrefix(prefix []byte) Iterator {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	var keys []string
+	for key := range db.db {
+		if bytes.HasPrefix([]byte(key), prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	it := &memIterator{index: -1, pairs: make([]kv, len(keys))}
+	for i, key := range keys {
+		it.pairs[i] = kv{[]byte(key), common.CopyBytes(db.db[key])}
+	}
+	return it
+}
+
 type kv struct{ k, v []byte }
 
 type memBatch struct {
@@ -105,6 +133,37 @@ type memBatch struct {
 	size   int
 }
 
+type memIterator struct {
+	pairs []kv
+	index int
+}
+
+func (it *memIterator) Next() bool {
+	if it.index >= len(it.pairs) {
+		return false
+	}
+	it.index++
+	return it.index < len(it.pairs)
+}
+
+func (it *memIterator) Key() []byte {
+	if it.index < 0 || it.index >= len(it.pairs) {
+		return nil
+	}
+	return it.pairs[it.index].k
+}
+
+func (it *memIterator) Value() []byte {
+	if it.index < 0 || it.index >= len(it.pairs) {
+		return nil
+	}
+	return it.pairs[it.index].v
+}
+
+func (it *memIterator) Error() error { return nil }
+
+func (it *memIterator) Release() { it.pairs = nil }
+
 func (b *memBatch) Put(key, value []byte) error {
 	b.writes = append(b.writes, kv{common.CopyBytes(key), common.CopyBytes(value)})
 	b.size += len(value)
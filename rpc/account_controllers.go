@@ -0,0 +1,319 @@
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/annchain/OG/account/keystore"
+	"github.com/annchain/OG/common/crypto"
+	"github.com/annchain/OG/common/math"
+	"github.com/annchain/OG/types"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// defaultUnlockDuration is how long an account is unlocked if the request
+// doesn't tell, and maxUnlockDuration is the longest it can be unlocked.
+const (
+	defaultUnlockDuration = 300 * time.Second
+	maxUnlockDuration     = time.Hour
+)
+
+// UnlockAccountRequest for RPC request
+type UnlockAccountRequest struct {
+	Address    string `json:"address"`
+	Passphrase string `json:"passphrase"`
+	// DurationS is the seconds to keep the account unlocked, 300 by
+	// default and 3600 at most.
+	DurationS *uint64 `json:"duration_s"`
+}
+
+// LockAccountRequest for RPC request
+type LockAccountRequest struct {
+	Address string `json:"address"`
+}
+
+// ImportAccountRequest for RPC request
+type ImportAccountRequest struct {
+	// Privkey is a plaintext private key, encrypted with Passphrase.
+	Privkey string `json:"privkey"`
+	// KeyJSON is a key file exported by export_account, decrypted with
+	// Passphrase and encrypted again with NewPassphrase, or Passphrase if
+	// NewPassphrase is empty.
+	KeyJSON       json.RawMessage `json:"key_json"`
+	Passphrase    string          `json:"passphrase"`
+	NewPassphrase string          `json:"new_passphrase"`
+}
+
+// ExportAccountRequest for RPC request
+type ExportAccountRequest struct {
+	Address    string `json:"address"`
+	Passphrase string `json:"passphrase"`
+	// NewPassphrase encrypts the exported key file, Passphrase by default.
+	NewPassphrase string `json:"new_passphrase"`
+}
+
+// SendTransactionRequest for RPC request
+type SendTransactionRequest struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Value string `json:"value"`
+	// Nonce is the next nonce of From by default, 0 for a new account.
+	Nonce string `json:"nonce"`
+}
+
+// keyStore returns the keystore, or responds an error if it is not enabled
+// or the request is not served by the admin router.
+func (r *RpcController) keyStore(c *gin.Context) *keystore.KeyStore {
+	if r.KeyStore == nil || !c.GetBool(adminContextKey) {
+		Response(c, http.StatusBadRequest, fmt.Errorf("keystore is not enabled"), nil)
+		return nil
+	}
+	return r.KeyStore
+}
+
+// keyStoreStatus is the http status of an error from the keystore.
+func keyStoreStatus(err error) int {
+	switch err {
+	case keystore.ErrNoMatch, keystore.ErrDecrypt, keystore.ErrLocked, keystore.ErrExists:
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// ListAccounts lists the addresses in the keystore.
+func (r *RpcController) ListAccounts(c *gin.Context) {
+	ks := r.keyStore(c)
+	if ks == nil {
+		return
+	}
+	addrs, err := ks.Accounts()
+	if err != nil {
+		Response(c, http.StatusInternalServerError, fmt.Errorf("list accounts error: %v", err), nil)
+		return
+	}
+	var accounts []string
+	for _, addr := range addrs {
+		accounts = append(accounts, addr.Hex())
+	}
+	Response(c, http.StatusOK, nil, accounts)
+}
+
+// UnlockAccount decrypts the key of an account in the keystore for
+// send_transaction to sign with.
+func (r *RpcController) UnlockAccount(c *gin.Context) {
+	var req UnlockAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("request format error: %v", err), nil)
+		return
+	}
+	addr, err := types.StringToAddress(req.Address)
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("address format error: %v", err), nil)
+		return
+	}
+	ks := r.keyStore(c)
+	if ks == nil {
+		return
+	}
+	duration := defaultUnlockDuration
+	if req.DurationS != nil {
+		maxS := uint64(maxUnlockDuration / time.Second)
+		if *req.DurationS == 0 || *req.DurationS > maxS {
+			Response(c, http.StatusBadRequest, fmt.Errorf("duration_s must be between 1 and %d", maxS), nil)
+			return
+		}
+		duration = time.Duration(*req.DurationS) * time.Second
+	}
+	if err := ks.Unlock(addr, req.Passphrase, duration); err != nil {
+		Response(c, keyStoreStatus(err), err, nil)
+		return
+	}
+	Response(c, http.StatusOK, nil, true)
+}
+
+// LockAccount drops the unlocked key of an account.
+func (r *RpcController) LockAccount(c *gin.Context) {
+	var req LockAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("request format error: %v", err), nil)
+		return
+	}
+	addr, err := types.StringToAddress(req.Address)
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("address format error: %v", err), nil)
+		return
+	}
+	ks := r.keyStore(c)
+	if ks == nil {
+		return
+	}
+	if err := ks.Lock(addr); err != nil {
+		Response(c, keyStoreStatus(err), err, nil)
+		return
+	}
+	Response(c, http.StatusOK, nil, true)
+}
+
+// ImportAccount stores a plaintext private key or an exported key file
+// into the keystore.
+func (r *RpcController) ImportAccount(c *gin.Context) {
+	var req ImportAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("request format error: %v", err), nil)
+		return
+	}
+	if req.Passphrase == "" {
+		Response(c, http.StatusBadRequest, fmt.Errorf("passphrase is required"), nil)
+		return
+	}
+	ks := r.keyStore(c)
+	if ks == nil {
+		return
+	}
+	var (
+		addr types.Address
+		err  error
+	)
+	switch {
+	case req.Privkey != "" && len(req.KeyJSON) != 0:
+		Response(c, http.StatusBadRequest, fmt.Errorf("only one of privkey and key_json can be set"), nil)
+		return
+	case req.Privkey != "":
+		priv, perr := crypto.PrivateKeyFromString(req.Privkey)
+		if perr != nil {
+			Response(c, http.StatusBadRequest, fmt.Errorf("privkey format error: %v", perr), nil)
+			return
+		}
+		addr, err = ks.Import(priv, req.Passphrase)
+	case len(req.KeyJSON) != 0:
+		newPassphrase := req.NewPassphrase
+		if newPassphrase == "" {
+			newPassphrase = req.Passphrase
+		}
+		addr, err = ks.ImportKeyJSON(req.KeyJSON, req.Passphrase, newPassphrase)
+	default:
+		Response(c, http.StatusBadRequest, fmt.Errorf("privkey or key_json is required"), nil)
+		return
+	}
+	if err != nil {
+		Response(c, keyStoreStatus(err), fmt.Errorf("import account error: %v", err), nil)
+		return
+	}
+	Response(c, http.StatusOK, nil, gin.H{
+		"address": addr.Hex(),
+	})
+}
+
+// ExportAccount returns the key file of an account, which is encrypted and
+// can be imported by import_account.
+func (r *RpcController) ExportAccount(c *gin.Context) {
+	var req ExportAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("request format error: %v", err), nil)
+		return
+	}
+	addr, err := types.StringToAddress(req.Address)
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("address format error: %v", err), nil)
+		return
+	}
+	ks := r.keyStore(c)
+	if ks == nil {
+		return
+	}
+	newPassphrase := req.NewPassphrase
+	if newPassphrase == "" {
+		newPassphrase = req.Passphrase
+	}
+	keyJSON, err := ks.Export(addr, req.Passphrase, newPassphrase)
+	if err != nil {
+		Response(c, keyStoreStatus(err), err, nil)
+		return
+	}
+	Response(c, http.StatusOK, nil, json.RawMessage(keyJSON))
+}
+
+// SendTransaction signs a tx with the unlocked key of from, then seals and
+// broadcasts it like new_transaction.
+func (r *RpcController) SendTransaction(c *gin.Context) {
+	var req SendTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("request format error: %v", err), nil)
+		return
+	}
+	from, err := types.StringToAddress(req.From)
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("from address format error: %v", err), nil)
+		return
+	}
+	to, err := types.StringToAddress(req.To)
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("to address format error: %v", err), nil)
+		return
+	}
+	value, ok := math.NewBigIntFromString(req.Value, 10)
+	if !ok || value.Value.Sign() < 0 {
+		Response(c, http.StatusBadRequest, fmt.Errorf("value format error"), nil)
+		return
+	}
+	ks := r.keyStore(c)
+	if ks == nil {
+		return
+	}
+	priv, err := ks.Key(from)
+	if err != nil {
+		Response(c, keyStoreStatus(err), err, nil)
+		return
+	}
+	if priv.Type != r.TxCreator.Signer.GetCryptoType() {
+		Response(c, http.StatusBadRequest, fmt.Errorf("crypto algorithm mismatch"), nil)
+		return
+	}
+
+	var nonce uint64
+	if req.Nonce != "" {
+		nonce, err = strconv.ParseUint(req.Nonce, 10, 64)
+		if err != nil {
+			Response(c, http.StatusBadRequest, fmt.Errorf("nonce format error"), nil)
+			return
+		}
+	} else {
+		nonce = r.nextNonce(from)
+	}
+
+	if !r.SyncerManager.IncrementalSyncer.Enabled {
+		Response(c, http.StatusOK, fmt.Errorf("tx is disabled when syncing"), nil)
+		return
+	}
+	tx := r.TxCreator.NewSignedTx(from, to, value, nonce, priv)
+	if ok := r.TxCreator.SealTx(tx); !ok {
+		Response(c, http.StatusInternalServerError, fmt.Errorf("seal tx failed"), nil)
+		return
+	}
+	logrus.WithField("tx", tx).Debugf("tx signed by keystore")
+
+	r.TxBuffer.ReceivedNewTxChan <- tx
+	Response(c, http.StatusOK, nil, tx.GetTxHash().Hex())
+}
+
+// nextNonce is the one after the latest nonce of addr in the pool or in
+// the dag, whichever is higher, or 0 if addr has no nonce in either. The
+// txs still in TxBuffer are not counted, so the nonce should be given when
+// sending txs in a row.
+func (r *RpcController) nextNonce(addr types.Address) uint64 {
+	noncePool, errPool := r.Og.TxPool.GetLatestNonce(addr)
+	nonceDag, errDag := r.Og.Dag.GetLatestNonce(addr)
+	switch {
+	case errPool != nil && errDag != nil:
+		return 0
+	case errPool != nil:
+		return nonceDag + 1
+	case errDag != nil || noncePool > nonceDag:
+		return noncePool + 1
+	}
+	return nonceDag + 1
+}
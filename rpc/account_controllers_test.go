@@ -0,0 +1,84 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/annchain/OG/account/keystore"
+	"github.com/gin-gonic/gin"
+)
+
+func TestAccountControllers(t *testing.T) {
+	dir, err := ioutil.TempDir("", "og-rpc-keystore-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	gin.SetMode(gin.TestMode)
+	r := &RpcController{KeyStore: keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)}
+	admin := r.NewAdminRouter(AdminConfig{KeyStore: true})
+	public := r.Newrouter()
+	call := func(router http.Handler, method, path, body string, status int) json.RawMessage {
+		req := httptest.NewRequest(method, "/"+path, bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var resp struct {
+			Data    json.RawMessage `json:"data"`
+			Message string          `json:"message"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%s: unmarshal response error: %v", path, err)
+		}
+		if w.Code != status {
+			t.Fatalf("%s: expected status %d, got %d: %s", path, status, w.Code, resp.Message)
+		}
+		return resp.Data
+	}
+
+	var created struct {
+		Address string `json:"address"`
+	}
+	data := call(admin, http.MethodPost, "new_account", `{"algorithm":"ed25519","passphrase":"foo"}`, http.StatusOK)
+	if err := json.Unmarshal(data, &created); err != nil {
+		t.Fatal(err)
+	}
+	var addrs []string
+	if err := json.Unmarshal(call(admin, http.MethodGet, "list_accounts", "", http.StatusOK), &addrs); err != nil {
+		t.Fatal(err)
+	}
+	if len(addrs) != 1 || addrs[0] != created.Address {
+		t.Fatalf("expected accounts [%s], got %v", created.Address, addrs)
+	}
+
+	call(admin, http.MethodPost, "unlock_account", `{"address":"`+created.Address+`","passphrase":"bar"}`, http.StatusBadRequest)
+	call(admin, http.MethodPost, "unlock_account", `{"address":"`+created.Address+`","passphrase":"foo","duration_s":0}`, http.StatusBadRequest)
+	call(admin, http.MethodPost, "unlock_account", `{"address":"`+created.Address+`","passphrase":"foo","duration_s":3601}`, http.StatusBadRequest)
+	call(admin, http.MethodPost, "unlock_account", `{"address":"`+created.Address+`","passphrase":"foo"}`, http.StatusOK)
+	call(admin, http.MethodPost, "lock_account", `{"address":"`+created.Address+`"}`, http.StatusOK)
+
+	keyJSON := call(admin, http.MethodPost, "export_account", `{"address":"`+created.Address+`","passphrase":"foo","new_passphrase":"baz"}`, http.StatusOK)
+	// the exported key is already in the keystore.
+	call(admin, http.MethodPost, "import_account", `{"key_json":`+string(keyJSON)+`,"passphrase":"baz"}`, http.StatusBadRequest)
+	call(admin, http.MethodPost, "import_account", `{"passphrase":"baz"}`, http.StatusBadRequest)
+
+	// the keystore is not served by the public router, nor to browsers.
+	call(public, http.MethodPost, "new_account", `{"algorithm":"ed25519","passphrase":"foo"}`, http.StatusBadRequest)
+	w := httptest.NewRecorder()
+	public.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/list_accounts", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("list_accounts: expected status %d on the public router, got %d", http.StatusNotFound, w.Code)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/list_accounts", nil)
+	req.Header.Set("Origin", "http://example.com")
+	w = httptest.NewRecorder()
+	admin.ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("list_accounts: expected status %d with an origin, got %d", http.StatusForbidden, w.Code)
+	}
+}
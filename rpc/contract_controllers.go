@@ -0,0 +1,197 @@
+package rpc
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/annchain/OG/common/hexutil"
+	"github.com/annchain/OG/types"
+	"github.com/annchain/OG/vm/abi"
+	"github.com/gin-gonic/gin"
+)
+
+// ContractPayloadRequest for RPC request
+type ContractPayloadRequest struct {
+	// AbiStr is the json abi of the contract.
+	AbiStr string `json:"abistr"`
+	// Method is the name or the signature of the method called, empty for
+	// the constructor.
+	Method string `json:"method"`
+	// Args is a json array of the args.
+	Args json.RawMessage `json:"args"`
+}
+
+// DecodeContractResultRequest for RPC request
+type DecodeContractResultRequest struct {
+	AbiStr string `json:"abistr"`
+	Method string `json:"method"`
+	// Result is the data in hex returned by query_contract.
+	Result string `json:"result"`
+}
+
+// DecodeContractLogsRequest for RPC request
+type DecodeContractLogsRequest struct {
+	AbiStr string `json:"abistr"`
+	// TxHash tells to decode the logs in the receipt of a tx, or Logs are
+	// decoded.
+	TxHash string        `json:"tx_hash"`
+	Logs   []LogResponse `json:"logs"`
+}
+
+// ArgResponse is a decoded arg of a method or an event.
+type ArgResponse struct {
+	Name  string      `json:"name"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// DecodedLogResponse is a log decoded by the abi. Error tells why the log
+// can't be decoded, such as it's not an event of the abi.
+type DecodedLogResponse struct {
+	LogResponse
+	Event string        `json:"event,omitempty"`
+	Args  []ArgResponse `json:"args,omitempty"`
+	Error string        `json:"error,omitempty"`
+}
+
+// ContractPayload encodes the data of a tx calling a contract method, or
+// the constructor args appended to the bytecode deploying a contract. Args
+// are given in a json array. It takes a json body on POST, or query params
+// on GET.
+func (r *RpcController) ContractPayload(c *gin.Context) {
+	var req ContractPayloadRequest
+	if c.Request.Method == http.MethodPost {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			Response(c, http.StatusBadRequest, fmt.Errorf("request format error: %v", err), nil)
+			return
+		}
+	} else {
+		req.AbiStr = c.Query("abistr")
+		req.Method = c.Query("method")
+		req.Args = json.RawMessage(c.Query("args"))
+	}
+	contractAbi, err := abi.ParseJSON([]byte(req.AbiStr))
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("abistr format error: %v", err), nil)
+		return
+	}
+	data, err := contractAbi.PackJSON(req.Method, req.Args)
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("encode error: %v", err), nil)
+		return
+	}
+	Response(c, http.StatusOK, nil, hexutil.Encode(data))
+	return
+}
+
+// DecodeContractResult decodes the data returned by a contract method.
+func (r *RpcController) DecodeContractResult(c *gin.Context) {
+	var req DecodeContractResultRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("request format error: %v", err), nil)
+		return
+	}
+	contractAbi, err := abi.ParseJSON([]byte(req.AbiStr))
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("abistr format error: %v", err), nil)
+		return
+	}
+	method, err := contractAbi.Method(req.Method)
+	if err != nil {
+		Response(c, http.StatusBadRequest, err, nil)
+		return
+	}
+	// query_contract returns the result in hex without 0x.
+	result, err := hex.DecodeString(strings.TrimPrefix(req.Result, "0x"))
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("result not hex"), nil)
+		return
+	}
+	values, err := method.Outputs.Unpack(result)
+	if err != nil {
+		if reason, rerr := abi.UnpackRevert(result); rerr == nil {
+			err = fmt.Errorf("reverted: %s", reason)
+		}
+		Response(c, http.StatusBadRequest, fmt.Errorf("decode error: %v", err), nil)
+		return
+	}
+	Response(c, http.StatusOK, nil, newArgResponses(method.Outputs, values))
+	return
+}
+
+// DecodeContractLogs decodes the logs in the receipt of a tx, or the logs
+// given as returned by query_receipt and filter_logs.
+func (r *RpcController) DecodeContractLogs(c *gin.Context) {
+	var req DecodeContractLogsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("request format error: %v", err), nil)
+		return
+	}
+	contractAbi, err := abi.ParseJSON([]byte(req.AbiStr))
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("abistr format error: %v", err), nil)
+		return
+	}
+	logs := req.Logs
+	if req.TxHash != "" {
+		hash, err := types.HexStringToHash(req.TxHash)
+		if err != nil {
+			Response(c, http.StatusBadRequest, fmt.Errorf("tx_hash format error: %v", err), nil)
+			return
+		}
+		receipt := r.Og.Dag.GetReceipt(hash)
+		if receipt == nil {
+			Response(c, http.StatusNotFound, fmt.Errorf("can't find receipt"), nil)
+			return
+		}
+		logs = newLogResponses(receipt.Logs)
+	}
+
+	decoded := make([]DecodedLogResponse, 0, len(logs))
+	for _, l := range logs {
+		dl := DecodedLogResponse{LogResponse: l}
+		if err := decodeLog(contractAbi, &dl); err != nil {
+			dl.Error = err.Error()
+		}
+		decoded = append(decoded, dl)
+	}
+	Response(c, http.StatusOK, nil, decoded)
+	return
+}
+
+func decodeLog(contractAbi *abi.ABI, dl *DecodedLogResponse) error {
+	topics := make([]types.Hash, 0, len(dl.Topics))
+	for _, topicStr := range dl.Topics {
+		topic, err := types.HexStringToHash(topicStr)
+		if err != nil {
+			return fmt.Errorf("topic format error: %v", err)
+		}
+		topics = append(topics, topic)
+	}
+	data, err := hex.DecodeString(strings.TrimPrefix(dl.Data, "0x"))
+	if err != nil {
+		return fmt.Errorf("data not hex")
+	}
+	event, values, err := contractAbi.UnpackLog(topics, data)
+	if err != nil {
+		return err
+	}
+	dl.Event = event.Sig()
+	dl.Args = newArgResponses(event.Inputs, values)
+	return nil
+}
+
+func newArgResponses(args abi.Arguments, values []interface{}) []ArgResponse {
+	ars := make([]ArgResponse, 0, len(args))
+	for i, arg := range args {
+		ars = append(ars, ArgResponse{
+			Name:  arg.Name,
+			Type:  arg.Type.String(),
+			Value: values[i],
+		})
+	}
+	return ars
+}
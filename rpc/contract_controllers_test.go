@@ -0,0 +1,91 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+const testTokenABI = `[{"constant":true,"inputs":[{"name":"","type":"address"}],"name":"balanceOf","outputs":[{"name":"","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":false,"inputs":[{"name":"_to","type":"address"},{"name":"_value","type":"uint256"}],"name":"transfer","outputs":[],"payable":false,"stateMutability":"nonpayable","type":"function"},{"anonymous":false,"inputs":[{"indexed":true,"name":"from","type":"address"},{"indexed":true,"name":"to","type":"address"},{"indexed":false,"name":"value","type":"uint256"}],"name":"Transfer","type":"event"}]`
+
+func TestContractControllers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := &RpcController{}
+	router := r.Newrouter()
+	call := func(method, path, body string, status int) json.RawMessage {
+		req := httptest.NewRequest(method, "/"+path, bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var resp struct {
+			Data    json.RawMessage `json:"data"`
+			Message string          `json:"message"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%s: unmarshal response error: %v", path, err)
+		}
+		if w.Code != status {
+			t.Fatalf("%s: expected status %d, got %d: %s", path, status, w.Code, resp.Message)
+		}
+		return resp.Data
+	}
+	abiStr := strconv.Quote(testTokenABI)
+	to := "0x9858effd232b4033e47d90003d41ec34ecaeda94"
+	expected := `"0xa9059cbb0000000000000000000000009858effd232b4033e47d90003d41ec34ecaeda9400000000000000000000000000000000000000000000000000000000000003e8"`
+
+	data := call(http.MethodPost, "contract_payload", `{"abistr":`+abiStr+`,"method":"transfer","args":["`+to+`", 1000]}`, http.StatusOK)
+	if string(data) != expected {
+		t.Fatalf("expected payload %s, got %s", expected, data)
+	}
+	query := url.Values{}
+	query.Set("abistr", testTokenABI)
+	query.Set("method", "transfer")
+	query.Set("args", `["`+to+`", "0x3e8"]`)
+	data = call(http.MethodGet, "contract_payload?"+query.Encode(), "", http.StatusOK)
+	if string(data) != expected {
+		t.Fatalf("expected payload %s, got %s", expected, data)
+	}
+	call(http.MethodPost, "contract_payload", `{"abistr":`+abiStr+`,"method":"transfer","args":["`+to+`"]}`, http.StatusBadRequest)
+	call(http.MethodPost, "contract_payload", `{"abistr":"[","method":"transfer"}`, http.StatusBadRequest)
+
+	var args []ArgResponse
+	data = call(http.MethodPost, "decode_contract_result", `{"abistr":`+abiStr+`,"method":"balanceOf","result":"00000000000000000000000000000000000000000000000000000000000003e8"}`, http.StatusOK)
+	if err := json.Unmarshal(data, &args); err != nil {
+		t.Fatal(err)
+	}
+	if len(args) != 1 || args[0].Type != "uint256" || args[0].Value != 1000.0 {
+		t.Fatalf("unexpected result %s", data)
+	}
+	call(http.MethodPost, "decode_contract_result", `{"abistr":`+abiStr+`,"method":"balanceOf","result":"03e8"}`, http.StatusBadRequest)
+
+	logs := `[{
+		"address":"0x3f2b000000000000000000000000000000000c8a",
+		"topics":["0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+			"0x000000000000000000000000643d534e15a315173a3c18cd13c9f95c7484a9bc",
+			"0x0000000000000000000000009858effd232b4033e47d90003d41ec34ecaeda94"],
+		"data":"0x00000000000000000000000000000000000000000000000000000000000003e8"
+	}, {
+		"address":"0x3f2b000000000000000000000000000000000c8a",
+		"topics":["0x0000000000000000000000000000000000000000000000000000000000000001"],
+		"data":"0x"
+	}]`
+	var decoded []DecodedLogResponse
+	data = call(http.MethodPost, "decode_contract_logs", `{"abistr":`+abiStr+`,"logs":`+logs+`}`, http.StatusOK)
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 2 {
+		t.Fatalf("expected 2 logs, got %s", data)
+	}
+	if decoded[0].Event != "Transfer(address,address,uint256)" || len(decoded[0].Args) != 3 || decoded[0].Args[1].Value != to {
+		t.Fatalf("unexpected transfer log %s", data)
+	}
+	if decoded[1].Error == "" {
+		t.Fatalf("expected error decoding unknown log, got %s", data)
+	}
+}
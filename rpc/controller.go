@@ -15,12 +15,16 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 
+	"github.com/annchain/OG/account/keystore"
 	"github.com/annchain/OG/common/crypto"
+	"github.com/annchain/OG/core"
+	"github.com/annchain/OG/core/state"
 	"github.com/annchain/OG/og"
 	"github.com/annchain/OG/og/syncer"
 	"github.com/annchain/OG/p2p"
 	"github.com/annchain/OG/performance"
 	"github.com/annchain/OG/types"
+	vmtypes "github.com/annchain/OG/vm/types"
 	"github.com/gin-gonic/gin"
 )
 
@@ -33,6 +37,8 @@ type RpcController struct {
 	PerformanceMonitor *performance.PerformanceMonitor
 	AutoTxCli          AutoTxClient
 	NewRequestChan     chan types.TxBaseType
+	LightClient        *og.LightClient // Set on light nodes, the state queries are answered by proofs
+	KeyStore           *keystore.KeyStore
 }
 
 //NodeStatus
@@ -57,18 +63,22 @@ type SequenceRequester interface {
 
 //NewTxrequest for RPC request
 type NewTxRequest struct {
+	Type      string `json:"type"`
 	Nonce     string `json:"nonce"`
 	From      string `json:"from"`
 	To        string `json:"to"`
 	Value     string `json:"value"`
 	Data      string `json:"data"`
+	GasLimit  string `json:"gas_limit"`
+	GasPrice  string `json:"gas_price"`
 	Signature string `json:"signature"`
 	Pubkey    string `json:"pubkey"`
 }
 
 //NewAccountRequest for RPC request
 type NewAccountRequest struct {
-	Algorithm string `json:"algorithm"`
+	Algorithm  string `json:"algorithm"`
+	Passphrase string `json:"passphrase"`
 }
 
 func cors(c *gin.Context) {
@@ -343,9 +353,34 @@ func (r *RpcController) Sequencer(c *gin.Context) {
 	return
 }
 
+//CandidateInfo for RPC response
+type CandidateInfo struct {
+	Address string `json:"address"`
+	Stake   string `json:"stake"`
+	Active  bool   `json:"active"`
+}
+
 func (r *RpcController) Validator(c *gin.Context) {
 	cors(c)
-	Response(c, http.StatusOK, nil, "validator")
+	delegates := r.Og.Dag.GetDelegates()
+	active := make(map[types.Address]bool)
+	activeSet := []string{}
+	for _, addr := range delegates {
+		active[addr] = true
+		activeSet = append(activeSet, addr.Hex())
+	}
+	candidates := []CandidateInfo{}
+	for _, candidate := range r.Og.Dag.GetCandidates() {
+		candidates = append(candidates, CandidateInfo{
+			Address: candidate.Address.Hex(),
+			Stake:   candidate.Stake.String(),
+			Active:  active[candidate.Address],
+		})
+	}
+	Response(c, http.StatusOK, nil, gin.H{
+		"candidates": candidates,
+		"active_set": activeSet,
+	})
 	return
 }
 
@@ -362,6 +397,11 @@ func (r *RpcController) NewTransaction(c *gin.Context) {
 		Response(c, http.StatusBadRequest, fmt.Errorf("request format error: %v", err), nil)
 		return
 	}
+	txType, err := types.ParseTxBaseType(txReq.Type)
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("type format error: %v", err), nil)
+		return
+	}
 	from, err := types.StringToAddress(txReq.From)
 	if err != nil {
 		Response(c, http.StatusBadRequest, fmt.Errorf("from address format error: %v", err), nil)
@@ -394,6 +434,23 @@ func (r *RpcController) NewTransaction(c *gin.Context) {
 		return
 	}
 
+	var gasLimit uint64
+	if txReq.GasLimit != "" {
+		gasLimit, err = strconv.ParseUint(txReq.GasLimit, 10, 64)
+		if err != nil {
+			Response(c, http.StatusBadRequest, fmt.Errorf("gas limit format error"), nil)
+			return
+		}
+	}
+	gasPrice := math.NewBigInt(0)
+	if txReq.GasPrice != "" {
+		gasPrice, ok = math.NewBigIntFromString(txReq.GasPrice, 10)
+		if !ok || gasPrice.Value.Sign() < 0 {
+			Response(c, http.StatusBadRequest, fmt.Errorf("gas price format error"), nil)
+			return
+		}
+	}
+
 	signature, err := hexutil.Decode(txReq.Signature)
 	if err != nil {
 		Response(c, http.StatusBadRequest, fmt.Errorf("signature format error"), nil)
@@ -411,7 +468,7 @@ func (r *RpcController) NewTransaction(c *gin.Context) {
 		Response(c, http.StatusOK, fmt.Errorf("crypto algorithm mismatch"), nil)
 		return
 	}
-	tx, err = r.TxCreator.NewTxWithSeal(from, to, value, data, nonce, pub, sig)
+	tx, err = r.TxCreator.NewTxWithSeal(txType, from, to, value, data, gasLimit, gasPrice, nonce, pub, sig)
 	if err != nil {
 		Response(c, http.StatusInternalServerError, fmt.Errorf("new tx failed"), nil)
 		return
@@ -446,6 +503,26 @@ func (r *RpcController) NewAccount(c *gin.Context) {
 		signer = &crypto.SignerEd25519{}
 	case "secp256k1":
 		signer = &crypto.SignerSecp256k1{}
+	default:
+		Response(c, http.StatusBadRequest, fmt.Errorf("unknown algorithm %q", txReq.Algorithm), nil)
+		return
+	}
+	// with a passphrase the key is kept in the keystore and never leaves
+	// the node.
+	if txReq.Passphrase != "" {
+		ks := r.keyStore(c)
+		if ks == nil {
+			return
+		}
+		addr, err := ks.NewAccount(signer.GetCryptoType(), txReq.Passphrase)
+		if err != nil {
+			Response(c, http.StatusInternalServerError, fmt.Errorf("new account error: %v", err), nil)
+			return
+		}
+		Response(c, http.StatusOK, nil, gin.H{
+			"address": addr.Hex(),
+		})
+		return
 	}
 	pub, priv, err := signer.RandomKeyPair()
 	if err != nil {
@@ -459,6 +536,38 @@ func (r *RpcController) NewAccount(c *gin.Context) {
 	return
 }
 
+//RollBackRequest for RPC request
+type RollBackRequest struct {
+	Height uint64 `json:"height"`
+}
+
+// RollBack reverts the ledger to an earlier sequencer height. It is
+// refused while the node is catching up with its peers.
+func (r *RpcController) RollBack(c *gin.Context) {
+	var req RollBackRequest
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("request format error"), nil)
+		return
+	}
+	if r.SyncerManager != nil && r.SyncerManager.Status != syncer.SyncStatusIncremental {
+		Response(c, http.StatusConflict, fmt.Errorf("can't roll back while syncing"), nil)
+		return
+	}
+	from := r.Og.Dag.LatestSequencer().Height
+	err = r.Og.TxPool.RollBack(req.Height)
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("roll back error: %v", err), nil)
+		return
+	}
+	logrus.WithField("from", from).WithField("to", req.Height).Warn("ledger rolled back by rpc")
+	Response(c, http.StatusOK, nil, gin.H{
+		"from": from,
+		"to":   req.Height,
+	})
+	return
+}
+
 func (r *RpcController) AutoTx(c *gin.Context) {
 	intervalStr := c.Query("interval_ms")
 	interval, err := strconv.Atoi(intervalStr)
@@ -472,6 +581,23 @@ func (r *RpcController) AutoTx(c *gin.Context) {
 	return
 }
 
+// queryHeight parses the optional height of the state query APIs, ok is
+// false if it is not given. Only the full nodes keep the earlier states.
+func (r *RpcController) queryHeight(c *gin.Context) (height uint64, ok bool, err error) {
+	heightStr := c.Query("height")
+	if heightStr == "" {
+		return 0, false, nil
+	}
+	height, err = strconv.ParseUint(heightStr, 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("height format err")
+	}
+	if r.LightClient != nil {
+		return 0, false, fmt.Errorf("state of height %d is not available on light nodes", height)
+	}
+	return height, true, nil
+}
+
 func (r *RpcController) QueryNonce(c *gin.Context) {
 	address := c.Query("address")
 	addr, err := types.StringToAddress(address)
@@ -479,6 +605,37 @@ func (r *RpcController) QueryNonce(c *gin.Context) {
 		Response(c, http.StatusBadRequest, fmt.Errorf("address format err"), nil)
 		return
 	}
+	height, atHeight, err := r.queryHeight(c)
+	if err != nil {
+		Response(c, http.StatusBadRequest, err, nil)
+		return
+	}
+	if atHeight {
+		nonce, err := r.Og.Dag.GetLatestNonceAt(addr, height)
+		if err == types.ErrNonceNotExist {
+			Response(c, http.StatusOK, nil, -1)
+			return
+		}
+		if err != nil {
+			Response(c, http.StatusBadRequest, err, nil)
+			return
+		}
+		Response(c, http.StatusOK, nil, nonce)
+		return
+	}
+	if r.LightClient != nil {
+		nonce, err := r.LightClient.GetLatestNonce(addr)
+		if err == types.ErrNonceNotExist {
+			Response(c, http.StatusOK, nil, -1)
+			return
+		}
+		if err != nil {
+			Response(c, http.StatusInternalServerError, err, nil)
+			return
+		}
+		Response(c, http.StatusOK, nil, nonce)
+		return
+	}
 	noncePool, errPool := r.Og.TxPool.GetLatestNonce(addr)
 	nonceDag, errDag := r.Og.Dag.GetLatestNonce(addr)
 	var nonce int64
@@ -501,7 +658,25 @@ func (r *RpcController) QueryBalance(c *gin.Context) {
 		Response(c, http.StatusBadRequest, fmt.Errorf("address format err"), nil)
 		return
 	}
-	b := r.Og.Dag.GetBalance(addr)
+	height, atHeight, err := r.queryHeight(c)
+	if err != nil {
+		Response(c, http.StatusBadRequest, err, nil)
+		return
+	}
+	var b *math.BigInt
+	if atHeight {
+		if b, err = r.Og.Dag.GetBalanceAt(addr, height); err != nil {
+			Response(c, http.StatusBadRequest, err, nil)
+			return
+		}
+	} else if r.LightClient != nil {
+		if b, err = r.LightClient.GetBalance(addr); err != nil {
+			Response(c, http.StatusInternalServerError, err, nil)
+			return
+		}
+	} else {
+		b = r.Og.Dag.GetBalance(addr)
+	}
 	Response(c, http.StatusOK, nil, gin.H{
 		"address": address,
 		"balance": b,
@@ -513,21 +688,137 @@ func (r *RpcController) QueryBalance(c *gin.Context) {
 	// return
 }
 
-func (r *RpcController) QueryShare(c *gin.Context) {
-	Response(c, http.StatusOK, nil, "not implemented yet")
+func (r *RpcController) QueryState(c *gin.Context) {
+	address := c.Query("address")
+	addr, err := types.StringToAddress(address)
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("address format err"), nil)
+		return
+	}
+	keyBytes, err := hexutil.Decode(c.Query("key"))
+	if err != nil || len(keyBytes) > types.HashLength {
+		Response(c, http.StatusBadRequest, fmt.Errorf("key format err"), nil)
+		return
+	}
+	key := types.BytesToHash(keyBytes)
+	height, atHeight, err := r.queryHeight(c)
+	if err != nil {
+		Response(c, http.StatusBadRequest, err, nil)
+		return
+	}
+	var value types.Hash
+	if atHeight {
+		if value, err = r.Og.Dag.GetStateAt(addr, key, height); err != nil {
+			Response(c, http.StatusBadRequest, err, nil)
+			return
+		}
+	} else if r.LightClient != nil {
+		if value, err = r.LightClient.GetState(addr, key); err != nil {
+			Response(c, http.StatusInternalServerError, err, nil)
+			return
+		}
+	} else {
+		value = r.Og.Dag.GetState(addr, key)
+	}
+	Response(c, http.StatusOK, nil, gin.H{
+		"address": address,
+		"key":     key.Hex(),
+		"value":   value.Hex(),
+	})
 	return
 }
 
-func (r *RpcController) ContractPayload(c *gin.Context) {
+// QueryProof returns the eth_getProof style account and storage proofs of
+// address in the state of the latest sequencer, or of the sequencer at the
+// optional height. The storage keys are given in keys separated by commas.
+func (r *RpcController) QueryProof(c *gin.Context) {
+	address := c.Query("address")
+	addr, err := types.StringToAddress(address)
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("address format err"), nil)
+		return
+	}
+	var keys []types.Hash
+	if keysStr := c.Query("keys"); keysStr != "" {
+		for _, k := range strings.Split(keysStr, ",") {
+			keyBytes, err := hexutil.Decode(strings.TrimSpace(k))
+			if err != nil || len(keyBytes) > types.HashLength {
+				Response(c, http.StatusBadRequest, fmt.Errorf("key format err: %s", k), nil)
+				return
+			}
+			keys = append(keys, types.BytesToHash(keyBytes))
+		}
+	}
+	if r.LightClient != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("proofs are not served by light nodes"), nil)
+		return
+	}
+	height, atHeight, err := r.queryHeight(c)
+	if err != nil {
+		Response(c, http.StatusBadRequest, err, nil)
+		return
+	}
+	var (
+		result *state.AccountResult
+		seq    *types.Sequencer
+	)
+	if atHeight {
+		result, seq, err = r.Og.Dag.GetAccountProofAt(addr, keys, height)
+	} else {
+		result, seq, err = r.Og.Dag.GetAccountProof(addr, keys)
+	}
+	if err != nil {
+		Response(c, http.StatusInternalServerError, err, nil)
+		return
+	}
+	Response(c, http.StatusOK, nil, newEthAccountProof(result, seq))
+	return
+}
+
+func (r *RpcController) QueryShare(c *gin.Context) {
 	Response(c, http.StatusOK, nil, "not implemented yet")
 	return
 }
 
 type ReceiptResponse struct {
-	TxHash          string `json:"tx_hash"`
-	Status          int    `json:"status"`
-	Result          string `json:"result"`
-	ContractAddress string `json:"contract_address"`
+	TxHash          string        `json:"tx_hash"`
+	Status          int           `json:"status"`
+	Result          string        `json:"result"`
+	ContractAddress string        `json:"contract_address"`
+	GasUsed         uint64        `json:"gas_used"`
+	Logs            []LogResponse `json:"logs"`
+}
+
+type LogResponse struct {
+	Address   string   `json:"address"`
+	Topics    []string `json:"topics"`
+	Data      string   `json:"data"`
+	SeqHeight uint64   `json:"seq_height"`
+	SeqHash   string   `json:"seq_hash"`
+	TxHash    string   `json:"tx_hash"`
+	TxIndex   uint     `json:"tx_index"`
+	LogIndex  uint     `json:"log_index"`
+}
+
+func newLogResponses(logs []*vmtypes.Log) []LogResponse {
+	lrs := make([]LogResponse, 0, len(logs))
+	for _, l := range logs {
+		lr := LogResponse{
+			Address:   l.Address.Hex(),
+			Topics:    make([]string, 0, len(l.Topics)),
+			Data:      hexutil.Encode(l.Data),
+			SeqHeight: l.SequenceID,
+			SeqHash:   l.BlockHash.Hex(),
+			TxHash:    l.TxHash.Hex(),
+			TxIndex:   l.TxIndex,
+			LogIndex:  l.Index,
+		}
+		for _, topic := range l.Topics {
+			lr.Topics = append(lr.Topics, topic.Hex())
+		}
+		lrs = append(lrs, lr)
+	}
+	return lrs
 }
 
 func (r *RpcController) QueryReceipt(c *gin.Context) {
@@ -550,22 +841,87 @@ func (r *RpcController) QueryReceipt(c *gin.Context) {
 	rr.Status = int(receipt.Status)
 	rr.Result = receipt.ProcessResult
 	rr.ContractAddress = receipt.ContractAddress.Hex()
+	rr.GasUsed = receipt.GasUsed
+	rr.Logs = newLogResponses(receipt.Logs)
 
 	Response(c, http.StatusOK, nil, rr)
 	return
 }
 
+//FilterLogsRequest for RPC request
+type FilterLogsRequest struct {
+	FromHeight uint64     `json:"from_height"`
+	ToHeight   uint64     `json:"to_height"`
+	Addresses  []string   `json:"addresses"`
+	Topics     [][]string `json:"topics"`
+}
+
+// FilterLogs returns the contract logs in a range of sequencer heights,
+// filtered by contract addresses and topics.
+func (r *RpcController) FilterLogs(c *gin.Context) {
+	var req FilterLogsRequest
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("request format error"), nil)
+		return
+	}
+	filter := core.LogFilter{
+		FromHeight: req.FromHeight,
+		ToHeight:   req.ToHeight,
+	}
+	for _, addrStr := range req.Addresses {
+		addr, err := types.StringToAddress(addrStr)
+		if err != nil {
+			Response(c, http.StatusBadRequest, fmt.Errorf("address format error: %v", err), nil)
+			return
+		}
+		filter.Addresses = append(filter.Addresses, addr)
+	}
+	for _, sub := range req.Topics {
+		var topics []types.Hash
+		for _, topicStr := range sub {
+			topic, err := types.HexStringToHash(topicStr)
+			if err != nil {
+				Response(c, http.StatusBadRequest, fmt.Errorf("topic format error: %v", err), nil)
+				return
+			}
+			topics = append(topics, topic)
+		}
+		filter.Topics = append(filter.Topics, topics)
+	}
+	logs, err := r.Og.Dag.FilterLogs(filter)
+	if err != nil {
+		Response(c, http.StatusBadRequest, err, nil)
+		return
+	}
+	Response(c, http.StatusOK, nil, newLogResponses(logs))
+	return
+}
+
 func (r *RpcController) QueryContract(c *gin.Context) {
 	addrstr := c.Query("contract_address")
 	querystr := c.Query("query_data")
 
 	addr := types.HexToAddress(addrstr)
-	query, err := hex.DecodeString(querystr)
+	query, err := hex.DecodeString(strings.TrimPrefix(querystr, "0x"))
 	if err != nil {
 		Response(c, http.StatusBadRequest, fmt.Errorf("can't decode query_data to bytes"), nil)
 		return
 	}
-	ret, errc := r.Og.Dag.CallContract(addr, query)
+	height, atHeight, err := r.queryHeight(c)
+	if err != nil {
+		Response(c, http.StatusBadRequest, err, nil)
+		return
+	}
+	var (
+		ret  []byte
+		errc error
+	)
+	if atHeight {
+		ret, errc = r.Og.Dag.CallContractAt(addr, query, height)
+	} else {
+		ret, errc = r.Og.Dag.CallContract(addr, query)
+	}
 	if errc != nil {
 		Response(c, http.StatusNotFound, fmt.Errorf("query contract error: %v", errc), nil)
 		return
@@ -703,14 +1059,11 @@ func (r *RpcController) DebugQueryContract() ([]byte, error) {
 	contractAddr := crypto.CreateAddress(from, uint64(1))
 
 	calldata := "e5aa3d58"
-	callTx := &types.Tx{}
-	callTx.From = from
-	callTx.Value = math.NewBigInt(0)
-	callTx.To = contractAddr
-	callTx.Data, _ = hex.DecodeString(calldata)
+	data, _ := hex.DecodeString(calldata)
 
-	b, _, err := r.Og.Dag.ProcessTransaction(callTx)
-	return b, err
+	// query must not change the state, otherwise the state root of the
+	// next sequencer can't be matched.
+	return r.Og.Dag.CallContract(contractAddr, data)
 }
 
 func (r *RpcController) DebugSetContract(n string) error {
@@ -769,7 +1122,7 @@ func (r *RpcController) DebugCreateTxAndSendToBuffer(from, to types.Address, val
 	sigb, _ := hex.DecodeString(sigstr)
 	sig := crypto.SignatureFromBytes(crypto.CryptoTypeSecp256k1, sigb)
 
-	tx, err := r.TxCreator.NewTxWithSeal(from, to, value, data, nonce, pub, sig)
+	tx, err := r.TxCreator.NewTxWithSeal(types.TxBaseTypeNormal, from, to, value, data, core.DefaultGasLimit, math.NewBigInt(0), nonce, pub, sig)
 	if err != nil {
 		return err
 	}
@@ -22,7 +22,18 @@ const (
 	PATH_NONCE       = "/query_nonce"
 )
 
+// requireNode skips the tests talking to a running node if there is none
+// at ROOT.
+func requireNode(t *testing.T) {
+	resp, err := http.Get(ROOT)
+	if err != nil {
+		t.Skipf("no node running at %s: %v", ROOT, err)
+	}
+	resp.Body.Close()
+}
+
 func TestNewAccount(t *testing.T) {
+	requireNode(t)
 	pri, pub, addr, err := newAccount("secp256k1")
 	if err != nil {
 		t.Error(err.Error())
@@ -71,6 +82,7 @@ func newAccount(algorithm string) (string, string, string, error) {
 	return a.Privkey, a.Pubkey, addr.String(), nil
 }
 func TestQueryNonce(t *testing.T) {
+	requireNode(t)
 	_, _, addr, err := newAccount("secp256k1")
 	if err != nil {
 		t.Error(err.Error())
@@ -107,6 +119,7 @@ func nonce(addr string) (int, error) {
 }
 
 func TestSendTx(t *testing.T) {
+	requireNode(t)
 	sendTx("secp256k1")
 }
 
@@ -161,7 +174,7 @@ func sendTx(algorithm string) {
 			"to":        tx.To.String(),
 			"value":     fmt.Sprintf("%d", tx.Value.GetInt64()),
 			"signature": hexutil.Encode(signature.Bytes),
-			"pubkey":    fromPub.PublicKeyToString(),
+			"pubkey":    fromPub.String(),
 		}
 
 		jsonData, err := json.Marshal(newTxData)
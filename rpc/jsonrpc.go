@@ -0,0 +1,729 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/annchain/OG/common/crypto"
+	"github.com/annchain/OG/common/hexutil"
+	"github.com/annchain/OG/common/math"
+	"github.com/annchain/OG/core"
+	"github.com/annchain/OG/core/state"
+	"github.com/annchain/OG/types"
+	vmtypes "github.com/annchain/OG/vm/types"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// error codes defined by JSON-RPC 2.0, and -32000 for the errors of
+// executing a method.
+const (
+	jsonrpcVersion = "2.0"
+
+	jsonrpcParseError     = -32700
+	jsonrpcInvalidRequest = -32600
+	jsonrpcMethodNotFound = -32601
+	jsonrpcInvalidParams  = -32602
+	jsonrpcInternalError  = -32603
+	jsonrpcServerError    = -32000
+)
+
+type jsonrpcRequest struct {
+	Version string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+type jsonrpcResponse struct {
+	Version string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *jsonrpcError   `json:"error,omitempty"`
+}
+
+type jsonrpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func newJsonrpcError(code int, format string, a ...interface{}) *jsonrpcError {
+	return &jsonrpcError{Code: code, Message: fmt.Sprintf(format, a...)}
+}
+
+type jsonrpcMethod func(r *RpcController, params []json.RawMessage) (interface{}, *jsonrpcError)
+
+var jsonrpcMethods = map[string]jsonrpcMethod{
+	"eth_chainId":               (*RpcController).ethChainId,
+	"net_version":               (*RpcController).netVersion,
+	"eth_blockNumber":           (*RpcController).ethBlockNumber,
+	"eth_getBalance":            (*RpcController).ethGetBalance,
+	"eth_getTransactionCount":   (*RpcController).ethGetTransactionCount,
+	"eth_call":                  (*RpcController).ethCall,
+	"eth_estimateGas":           (*RpcController).ethEstimateGas,
+	"og_sendRawTransaction":     (*RpcController).ogSendRawTransaction,
+	"eth_getTransactionReceipt": (*RpcController).ethGetTransactionReceipt,
+	"eth_getLogs":               (*RpcController).ethGetLogs,
+	"eth_getProof":              (*RpcController).ethGetProof,
+}
+
+// JsonRpc serves the ethereum style JSON-RPC 2.0 requests, either one
+// request or a batch of them in an array. A block of ethereum is a
+// sequencer here, and the block number is the sequencer height.
+func (r *RpcController) JsonRpc(c *gin.Context) {
+	body, err := ioutil.ReadAll(c.Request.Body)
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("read body error: %v", err), nil)
+		return
+	}
+	body = bytes.TrimSpace(body)
+
+	// a batch
+	if len(body) > 0 && body[0] == '[' {
+		var reqs []json.RawMessage
+		if err := json.Unmarshal(body, &reqs); err != nil {
+			c.JSON(http.StatusOK, newJsonrpcErrorResponse(nil, newJsonrpcError(jsonrpcParseError, "parse error")))
+			return
+		}
+		if len(reqs) == 0 {
+			c.JSON(http.StatusOK, newJsonrpcErrorResponse(nil, newJsonrpcError(jsonrpcInvalidRequest, "empty batch")))
+			return
+		}
+		var resps []*jsonrpcResponse
+		for _, req := range reqs {
+			if resp := r.serveJsonrpc(req); resp != nil {
+				resps = append(resps, resp)
+			}
+		}
+		// nothing is returned if all the requests are notifications.
+		if len(resps) == 0 {
+			c.Status(http.StatusOK)
+			return
+		}
+		c.JSON(http.StatusOK, resps)
+		return
+	}
+
+	resp := r.serveJsonrpc(body)
+	if resp == nil {
+		c.Status(http.StatusOK)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// serveJsonrpc serves one request. It returns nil if the request is a
+// notification, which has no id.
+func (r *RpcController) serveJsonrpc(data json.RawMessage) *jsonrpcResponse {
+	var req jsonrpcRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return newJsonrpcErrorResponse(nil, newJsonrpcError(jsonrpcParseError, "parse error"))
+	}
+	if req.Version != jsonrpcVersion || req.Method == "" {
+		return newJsonrpcErrorResponse(req.ID, newJsonrpcError(jsonrpcInvalidRequest, "invalid request"))
+	}
+
+	result, rpcErr := r.callJsonrpc(req.Method, req.Params)
+	if req.ID == nil {
+		return nil
+	}
+	if rpcErr != nil {
+		return newJsonrpcErrorResponse(req.ID, rpcErr)
+	}
+	res, err := json.Marshal(result)
+	if err != nil {
+		logrus.WithError(err).WithField("method", req.Method).Error("failed to marshal jsonrpc result")
+		return newJsonrpcErrorResponse(req.ID, newJsonrpcError(jsonrpcInternalError, "internal error"))
+	}
+	return &jsonrpcResponse{
+		Version: jsonrpcVersion,
+		ID:      req.ID,
+		Result:  res,
+	}
+}
+
+func (r *RpcController) callJsonrpc(method string, rawParams json.RawMessage) (interface{}, *jsonrpcError) {
+	m, ok := jsonrpcMethods[method]
+	if !ok {
+		return nil, newJsonrpcError(jsonrpcMethodNotFound, "method %s not found", method)
+	}
+	var params []json.RawMessage
+	if len(rawParams) != 0 && string(rawParams) != "null" {
+		if err := json.Unmarshal(rawParams, &params); err != nil {
+			return nil, newJsonrpcError(jsonrpcInvalidParams, "params must be an array")
+		}
+	}
+	return m(r, params)
+}
+
+func newJsonrpcErrorResponse(id json.RawMessage, err *jsonrpcError) *jsonrpcResponse {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	return &jsonrpcResponse{
+		Version: jsonrpcVersion,
+		ID:      id,
+		Error:   err,
+	}
+}
+
+// parseParams decodes params into args by position. The args that have
+// no param left are kept untouched, so optional args should be set to
+// their default values before.
+func parseParams(params []json.RawMessage, required int, args ...interface{}) *jsonrpcError {
+	if len(params) < required {
+		return newJsonrpcError(jsonrpcInvalidParams, "missing value for required argument %d", len(params))
+	}
+	if len(params) > len(args) {
+		return newJsonrpcError(jsonrpcInvalidParams, "too many arguments, want at most %d", len(args))
+	}
+	for i, param := range params {
+		if string(param) == "null" {
+			continue
+		}
+		if err := json.Unmarshal(param, args[i]); err != nil {
+			return newJsonrpcError(jsonrpcInvalidParams, "invalid argument %d: %v", i, err)
+		}
+	}
+	return nil
+}
+
+// blockNumber is a block number param, which is a hex height or one of
+// "earliest", "latest" and "pending". Pending is taken as latest since
+// sequencers are not produced locally, except that the txs in the pool
+// are counted in the nonce.
+type blockNumber struct {
+	latest  bool
+	pending bool
+	height  uint64
+}
+
+func (b *blockNumber) UnmarshalJSON(input []byte) error {
+	var s string
+	if err := json.Unmarshal(input, &s); err != nil {
+		return err
+	}
+	switch s {
+	case "earliest":
+		*b = blockNumber{height: 0}
+	case "latest":
+		*b = blockNumber{latest: true}
+	case "pending":
+		*b = blockNumber{latest: true, pending: true}
+	default:
+		height, err := hexutil.DecodeUint64(s)
+		if err != nil {
+			return err
+		}
+		*b = blockNumber{height: height}
+	}
+	return nil
+}
+
+func (r *RpcController) resolveHeight(b blockNumber) uint64 {
+	if b.latest {
+		return r.Og.Dag.LatestSequencer().Height
+	}
+	return b.height
+}
+
+// checkLatestState refuses the block numbers other than the latest one,
+// for the methods executing txs on the latest state only.
+func (r *RpcController) checkLatestState(b blockNumber) *jsonrpcError {
+	if b.latest || b.height == r.Og.Dag.LatestSequencer().Height {
+		return nil
+	}
+	return newJsonrpcError(jsonrpcServerError, "state of height %d is not available, only the latest state is supported", b.height)
+}
+
+// historicalHeight tells if b is a sequencer below the latest one, whose
+// state is read by height.
+func (r *RpcController) historicalHeight(b blockNumber) (uint64, bool) {
+	if b.latest {
+		return 0, false
+	}
+	return b.height, b.height != r.Og.Dag.LatestSequencer().Height
+}
+
+func (r *RpcController) ethChainId(params []json.RawMessage) (interface{}, *jsonrpcError) {
+	return hexutil.Uint64(r.Og.NetworkId), nil
+}
+
+func (r *RpcController) netVersion(params []json.RawMessage) (interface{}, *jsonrpcError) {
+	return fmt.Sprintf("%d", r.Og.NetworkId), nil
+}
+
+func (r *RpcController) ethBlockNumber(params []json.RawMessage) (interface{}, *jsonrpcError) {
+	return hexutil.Uint64(r.Og.Dag.LatestSequencer().Height), nil
+}
+
+func (r *RpcController) ethGetBalance(params []json.RawMessage) (interface{}, *jsonrpcError) {
+	var addr types.Address
+	block := blockNumber{latest: true}
+	if err := parseParams(params, 1, &addr, &block); err != nil {
+		return nil, err
+	}
+	if height, ok := r.historicalHeight(block); ok {
+		if r.LightClient != nil {
+			return nil, newJsonrpcError(jsonrpcServerError, "state of height %d is not available on light nodes", height)
+		}
+		balance, err := r.Og.Dag.GetBalanceAt(addr, height)
+		if err != nil {
+			return nil, newJsonrpcError(jsonrpcServerError, "%v", err)
+		}
+		return (*hexutil.Big)(balance.Value), nil
+	}
+	if r.LightClient != nil {
+		balance, err := r.LightClient.GetBalance(addr)
+		if err != nil {
+			return nil, newJsonrpcError(jsonrpcInternalError, "%v", err)
+		}
+		return (*hexutil.Big)(balance.Value), nil
+	}
+	return (*hexutil.Big)(r.Og.Dag.GetBalance(addr).Value), nil
+}
+
+// ethGetTransactionCount returns the nonce the next tx of the address
+// should use. The txs in the pool are counted if block is "pending".
+func (r *RpcController) ethGetTransactionCount(params []json.RawMessage) (interface{}, *jsonrpcError) {
+	var addr types.Address
+	block := blockNumber{latest: true}
+	if err := parseParams(params, 1, &addr, &block); err != nil {
+		return nil, err
+	}
+	if height, ok := r.historicalHeight(block); ok {
+		nonce, err := r.Og.Dag.GetLatestNonceAt(addr, height)
+		if err == types.ErrNonceNotExist {
+			return hexutil.Uint64(0), nil
+		}
+		if err != nil {
+			return nil, newJsonrpcError(jsonrpcServerError, "%v", err)
+		}
+		if nonce > 0 {
+			return hexutil.Uint64(nonce + 1), nil
+		}
+		if tx := r.Og.Dag.GetTxByNonce(addr, 0); tx != nil && tx.GetBase().Height <= height {
+			return hexutil.Uint64(1), nil
+		}
+		return hexutil.Uint64(0), nil
+	}
+	// nonce starts from 0, and the account may exist before sending
+	// any tx, so check the first tx for the accounts with nonce 0.
+	var count uint64
+	nonce, err := r.Og.Dag.GetLatestNonce(addr)
+	if err == nil && (nonce > 0 || r.Og.Dag.GetTxByNonce(addr, 0) != nil) {
+		count = nonce + 1
+	}
+	if block.pending {
+		if nonce, err := r.Og.TxPool.GetLatestNonce(addr); err == nil && nonce+1 > count {
+			count = nonce + 1
+		}
+	}
+	return hexutil.Uint64(count), nil
+}
+
+// callArgs is the tx of eth_call and eth_estimateGas.
+type callArgs struct {
+	From     types.Address   `json:"from"`
+	To       *types.Address  `json:"to"`
+	Gas      *hexutil.Uint64 `json:"gas"`
+	GasPrice *hexutil.Big    `json:"gasPrice"`
+	Value    *hexutil.Big    `json:"value"`
+	Data     *hexutil.Bytes  `json:"data"`
+	Input    *hexutil.Bytes  `json:"input"`
+}
+
+func (args *callArgs) toTx() *types.Tx {
+	tx := &types.Tx{
+		TxBase: types.TxBase{
+			Type: types.TxBaseTypeNormal,
+		},
+		From:     args.From,
+		Value:    math.NewBigInt(0),
+		GasPrice: math.NewBigInt(0),
+	}
+	if args.To != nil {
+		tx.To = *args.To
+	}
+	if args.Gas != nil {
+		tx.GasLimit = uint64(*args.Gas)
+	}
+	if args.GasPrice != nil {
+		tx.GasPrice = math.NewBigIntFromBigInt(args.GasPrice.ToInt())
+	}
+	if args.Value != nil {
+		tx.Value = math.NewBigIntFromBigInt(args.Value.ToInt())
+	}
+	if args.Input != nil {
+		tx.Data = *args.Input
+	} else if args.Data != nil {
+		tx.Data = *args.Data
+	}
+	return tx
+}
+
+func (r *RpcController) ethCall(params []json.RawMessage) (interface{}, *jsonrpcError) {
+	var args callArgs
+	block := blockNumber{latest: true}
+	if err := parseParams(params, 1, &args, &block); err != nil {
+		return nil, err
+	}
+	if err := r.checkLatestState(block); err != nil {
+		return nil, err
+	}
+	tx := args.toTx()
+	if tx.GasLimit == 0 {
+		tx.GasLimit = core.DefaultGasLimit
+	}
+	ret, receipt, err := r.Og.Dag.SimulateTx(tx)
+	if err != nil {
+		return nil, newJsonrpcError(jsonrpcServerError, "%v", err)
+	}
+	if receipt.Status != core.ReceiptStatusTxSuccess {
+		return nil, newJsonrpcError(jsonrpcServerError, "execution failed: %s", receipt.ProcessResult)
+	}
+	return hexutil.Bytes(ret), nil
+}
+
+func (r *RpcController) ethEstimateGas(params []json.RawMessage) (interface{}, *jsonrpcError) {
+	var args callArgs
+	block := blockNumber{latest: true}
+	if err := parseParams(params, 1, &args, &block); err != nil {
+		return nil, err
+	}
+	if err := r.checkLatestState(block); err != nil {
+		return nil, err
+	}
+	gas, err := r.Og.Dag.EstimateGas(args.toTx())
+	if err != nil {
+		return nil, newJsonrpcError(jsonrpcServerError, "%v", err)
+	}
+	return hexutil.Uint64(gas), nil
+}
+
+// ogSendRawTransaction takes the msgp encoding of a tx signed the OG way.
+// It is not eth_sendRawTransaction, as an RLP encoded ethereum tx is signed
+// over other fields than an OG tx and can't be verified here. Only the
+// fields covered by the signature are used, the parents and the hash of
+// the tx are decided by this node.
+func (r *RpcController) ogSendRawTransaction(params []json.RawMessage) (interface{}, *jsonrpcError) {
+	var data hexutil.Bytes
+	if err := parseParams(params, 1, &data); err != nil {
+		return nil, err
+	}
+	var raw types.Tx
+	if _, err := raw.UnmarshalMsg(data); err != nil {
+		return nil, newJsonrpcError(jsonrpcInvalidParams, "decode tx error: %v", err)
+	}
+	if r.TxBuffer == nil || r.SyncerManager == nil || r.SyncerManager.IncrementalSyncer == nil {
+		return nil, newJsonrpcError(jsonrpcServerError, "tx is not accepted by this node")
+	}
+	if !r.SyncerManager.IncrementalSyncer.Enabled {
+		return nil, newJsonrpcError(jsonrpcServerError, "tx is disabled when syncing")
+	}
+	cryptoType := r.TxCreator.Signer.GetCryptoType()
+	pub := crypto.PublicKeyFromBytes(cryptoType, raw.PublicKey)
+	sig := crypto.SignatureFromBytes(cryptoType, raw.Signature)
+	if raw.Value == nil {
+		raw.Value = math.NewBigInt(0)
+	}
+	tx, err := r.TxCreator.NewTxWithSeal(raw.Type, raw.From, raw.To, raw.Value, raw.Data,
+		raw.GasLimit, raw.GetGasPrice(), raw.AccountNonce, pub, sig)
+	if err != nil {
+		return nil, newJsonrpcError(jsonrpcServerError, "new tx failed: %v", err)
+	}
+	r.TxBuffer.ReceivedNewTxChan <- tx
+	return tx.GetTxHash(), nil
+}
+
+// ethReceipt is the ethereum style receipt.
+type ethReceipt struct {
+	TransactionHash   types.Hash     `json:"transactionHash"`
+	TransactionIndex  hexutil.Uint64 `json:"transactionIndex"`
+	BlockHash         types.Hash     `json:"blockHash"`
+	BlockNumber       hexutil.Uint64 `json:"blockNumber"`
+	From              types.Address  `json:"from"`
+	To                *types.Address `json:"to"`
+	CumulativeGasUsed hexutil.Uint64 `json:"cumulativeGasUsed"`
+	GasUsed           hexutil.Uint64 `json:"gasUsed"`
+	ContractAddress   *types.Address `json:"contractAddress"`
+	Logs              []*ethLog      `json:"logs"`
+	LogsBloom         hexutil.Bytes  `json:"logsBloom"`
+	Status            hexutil.Uint64 `json:"status"`
+}
+
+// ethLog is the ethereum style log.
+type ethLog struct {
+	Address          types.Address  `json:"address"`
+	Topics           []types.Hash   `json:"topics"`
+	Data             hexutil.Bytes  `json:"data"`
+	BlockNumber      hexutil.Uint64 `json:"blockNumber"`
+	BlockHash        types.Hash     `json:"blockHash"`
+	TransactionHash  types.Hash     `json:"transactionHash"`
+	TransactionIndex hexutil.Uint64 `json:"transactionIndex"`
+	LogIndex         hexutil.Uint64 `json:"logIndex"`
+	Removed          bool           `json:"removed"`
+}
+
+func newEthLogs(logs []*vmtypes.Log) []*ethLog {
+	els := make([]*ethLog, 0, len(logs))
+	for _, l := range logs {
+		topics := l.Topics
+		if topics == nil {
+			topics = []types.Hash{}
+		}
+		els = append(els, &ethLog{
+			Address:          l.Address,
+			Topics:           topics,
+			Data:             l.Data,
+			BlockNumber:      hexutil.Uint64(l.SequenceID),
+			BlockHash:        l.BlockHash,
+			TransactionHash:  l.TxHash,
+			TransactionIndex: hexutil.Uint64(l.TxIndex),
+			LogIndex:         hexutil.Uint64(l.Index),
+		})
+	}
+	return els
+}
+
+// ethGetTransactionReceipt returns null if the tx is not confirmed.
+func (r *RpcController) ethGetTransactionReceipt(params []json.RawMessage) (interface{}, *jsonrpcError) {
+	var hash types.Hash
+	if err := parseParams(params, 1, &hash); err != nil {
+		return nil, err
+	}
+	txi := r.Og.Dag.GetTx(hash)
+	tx, ok := txi.(*types.Tx)
+	if !ok {
+		return nil, nil
+	}
+	height := tx.GetHeight()
+	seq := r.Og.Dag.GetSequencerByHeight(height)
+	receipts := r.Og.Dag.GetReceipts(height)
+	hashes := r.Og.Dag.GetTxsHashesByNumber(height)
+	if seq == nil || receipts == nil || hashes == nil {
+		return nil, nil
+	}
+	receipt, ok := receipts[hash.Hex()]
+	if !ok {
+		return nil, nil
+	}
+
+	// the txs are executed in the order of the tx hashes of the sequencer.
+	index := -1
+	var cumulativeGasUsed uint64
+	for i, h := range *hashes {
+		if rec, ok := receipts[h.Hex()]; ok {
+			cumulativeGasUsed += rec.GasUsed
+		}
+		if h == hash {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil, newJsonrpcError(jsonrpcInternalError, "tx %s is not indexed in seq %d", hash.Hex(), height)
+	}
+
+	er := &ethReceipt{
+		TransactionHash:   hash,
+		TransactionIndex:  hexutil.Uint64(index),
+		BlockHash:         seq.GetTxHash(),
+		BlockNumber:       hexutil.Uint64(height),
+		From:              tx.From,
+		CumulativeGasUsed: hexutil.Uint64(cumulativeGasUsed),
+		GasUsed:           hexutil.Uint64(receipt.GasUsed),
+		Logs:              newEthLogs(receipt.Logs),
+		LogsBloom:         core.LogsBloom(receipt.Logs).Bytes(),
+	}
+	if receipt.Status == core.ReceiptStatusTxSuccess {
+		er.Status = 1
+	}
+	if tx.To.Bytes == (types.Address{}).Bytes {
+		if receipt.ContractAddress.Bytes != (types.Address{}).Bytes {
+			er.ContractAddress = &receipt.ContractAddress
+		}
+	} else {
+		er.To = &tx.To
+	}
+	return er, nil
+}
+
+// addressesParam is a single address or an array of them.
+type addressesParam []types.Address
+
+func (a *addressesParam) UnmarshalJSON(input []byte) error {
+	if len(input) > 0 && input[0] == '[' {
+		return json.Unmarshal(input, (*[]types.Address)(a))
+	}
+	var addr types.Address
+	if err := json.Unmarshal(input, &addr); err != nil {
+		return err
+	}
+	*a = addressesParam{addr}
+	return nil
+}
+
+// topicsParam is an array whose items are null, a topic, or an array of
+// topics.
+type topicsParam [][]types.Hash
+
+func (t *topicsParam) UnmarshalJSON(input []byte) error {
+	var raws []json.RawMessage
+	if err := json.Unmarshal(input, &raws); err != nil {
+		return err
+	}
+	topics := make([][]types.Hash, len(raws))
+	for i, raw := range raws {
+		switch {
+		case string(raw) == "null":
+		case len(raw) > 0 && raw[0] == '[':
+			if err := json.Unmarshal(raw, &topics[i]); err != nil {
+				return err
+			}
+		default:
+			var topic types.Hash
+			if err := json.Unmarshal(raw, &topic); err != nil {
+				return err
+			}
+			topics[i] = []types.Hash{topic}
+		}
+	}
+	*t = topics
+	return nil
+}
+
+type logFilterArgs struct {
+	FromBlock *blockNumber   `json:"fromBlock"`
+	ToBlock   *blockNumber   `json:"toBlock"`
+	BlockHash *types.Hash    `json:"blockHash"`
+	Address   addressesParam `json:"address"`
+	Topics    topicsParam    `json:"topics"`
+}
+
+func (r *RpcController) ethGetLogs(params []json.RawMessage) (interface{}, *jsonrpcError) {
+	var args logFilterArgs
+	if err := parseParams(params, 1, &args); err != nil {
+		return nil, err
+	}
+	filter := core.LogFilter{
+		Addresses: args.Address,
+		Topics:    args.Topics,
+	}
+	if args.BlockHash != nil {
+		if args.FromBlock != nil || args.ToBlock != nil {
+			return nil, newJsonrpcError(jsonrpcInvalidParams, "blockHash can't be used with fromBlock or toBlock")
+		}
+		seq := r.Og.Dag.GetSequencerByHash(*args.BlockHash)
+		if seq == nil {
+			return nil, newJsonrpcError(jsonrpcServerError, "unknown block %s", args.BlockHash.Hex())
+		}
+		filter.FromHeight = seq.Height
+		filter.ToHeight = seq.Height
+	} else {
+		latest := blockNumber{latest: true}
+		from, to := latest, latest
+		if args.FromBlock != nil {
+			from = *args.FromBlock
+		}
+		if args.ToBlock != nil {
+			to = *args.ToBlock
+		}
+		filter.FromHeight = r.resolveHeight(from)
+		filter.ToHeight = r.resolveHeight(to)
+	}
+	logs, err := r.Og.Dag.FilterLogs(filter)
+	if err != nil {
+		return nil, newJsonrpcError(jsonrpcServerError, "%v", err)
+	}
+	return newEthLogs(logs), nil
+}
+
+// ethAccountProof is the ethereum style account proof. The leaf of the
+// account proof is the msgp encoded account and the leaves of the storage
+// proofs are the 32 byte values, the trie keys are the keccak256 of the
+// address and of the storage keys. The sequencer which the state root
+// belongs to is reported as the block.
+type ethAccountProof struct {
+	Address      types.Address     `json:"address"`
+	AccountProof []hexutil.Bytes   `json:"accountProof"`
+	Balance      *hexutil.Big      `json:"balance"`
+	Nonce        hexutil.Uint64    `json:"nonce"`
+	StorageHash  types.Hash        `json:"storageHash"`
+	CodeHash     hexutil.Bytes     `json:"codeHash"`
+	StorageProof []ethStorageProof `json:"storageProof"`
+	StateRoot    types.Hash        `json:"stateRoot"`
+	BlockNumber  hexutil.Uint64    `json:"blockNumber"`
+	BlockHash    types.Hash        `json:"blockHash"`
+}
+
+type ethStorageProof struct {
+	Key   types.Hash      `json:"key"`
+	Value types.Hash      `json:"value"`
+	Proof []hexutil.Bytes `json:"proof"`
+}
+
+func toHexBytesList(list [][]byte) []hexutil.Bytes {
+	hl := make([]hexutil.Bytes, len(list))
+	for i, b := range list {
+		hl[i] = b
+	}
+	return hl
+}
+
+func newEthAccountProof(result *state.AccountResult, seq *types.Sequencer) *ethAccountProof {
+	ap := &ethAccountProof{
+		Address:      result.Address,
+		AccountProof: toHexBytesList(result.AccountProof),
+		Balance:      (*hexutil.Big)(result.Balance.Value),
+		Nonce:        hexutil.Uint64(result.Nonce),
+		StorageHash:  result.StorageHash,
+		CodeHash:     result.CodeHash,
+		StorageProof: make([]ethStorageProof, len(result.StorageProof)),
+		StateRoot:    seq.StateRoot,
+		BlockNumber:  hexutil.Uint64(seq.Height),
+		BlockHash:    seq.GetTxHash(),
+	}
+	for i, sp := range result.StorageProof {
+		ap.StorageProof[i] = ethStorageProof{
+			Key:   sp.Key,
+			Value: sp.Value,
+			Proof: toHexBytesList(sp.Proof),
+		}
+	}
+	return ap
+}
+
+// ethGetProof returns the account and storage proofs of an address in
+// the state of the latest sequencer, or of the sequencer at the given
+// height.
+func (r *RpcController) ethGetProof(params []json.RawMessage) (interface{}, *jsonrpcError) {
+	var (
+		addr types.Address
+		keys []types.Hash
+	)
+	block := blockNumber{latest: true}
+	if err := parseParams(params, 1, &addr, &keys, &block); err != nil {
+		return nil, err
+	}
+	if r.LightClient != nil {
+		return nil, newJsonrpcError(jsonrpcServerError, "proofs are not served by light nodes")
+	}
+	var (
+		result *state.AccountResult
+		seq    *types.Sequencer
+		err    error
+	)
+	if height, ok := r.historicalHeight(block); ok {
+		result, seq, err = r.Og.Dag.GetAccountProofAt(addr, keys, height)
+	} else {
+		result, seq, err = r.Og.Dag.GetAccountProof(addr, keys)
+	}
+	if err != nil {
+		return nil, newJsonrpcError(jsonrpcServerError, "%v", err)
+	}
+	return newEthAccountProof(result, seq), nil
+}
@@ -0,0 +1,142 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/annchain/OG/common/hexutil"
+	"github.com/annchain/OG/types"
+	"github.com/gin-gonic/gin"
+)
+
+func serveTestJsonrpc(t *testing.T, body string) []byte {
+	gin.SetMode(gin.TestMode)
+	r := &RpcController{}
+	router := gin.New()
+	router.POST("/", r.JsonRpc)
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected http status %d", w.Code)
+	}
+	return w.Body.Bytes()
+}
+
+func TestJsonrpcErrors(t *testing.T) {
+	cases := []struct {
+		body string
+		code int
+	}{
+		{`{"jsonrpc":"2.0","id":1,"method":"eth_`, jsonrpcParseError},
+		{`{"jsonrpc":"1.0","id":1,"method":"eth_blockNumber"}`, jsonrpcInvalidRequest},
+		{`{"jsonrpc":"2.0","id":1,"method":"eth_mining"}`, jsonrpcMethodNotFound},
+		{`{"jsonrpc":"2.0","id":1,"method":"eth_getBalance","params":{}}`, jsonrpcInvalidParams},
+		{`{"jsonrpc":"2.0","id":1,"method":"eth_getBalance","params":[]}`, jsonrpcInvalidParams},
+		{`{"jsonrpc":"2.0","id":1,"method":"eth_getBalance","params":["0x01"]}`, jsonrpcInvalidParams},
+		{`{"jsonrpc":"2.0","id":1,"method":"eth_getProof","params":["0x0b5d53f433b7e4a4f853a01e987f977497dda262","0x01"]}`, jsonrpcInvalidParams},
+		{`[]`, jsonrpcInvalidRequest},
+	}
+	for i, c := range cases {
+		var resp jsonrpcResponse
+		if err := json.Unmarshal(serveTestJsonrpc(t, c.body), &resp); err != nil {
+			t.Fatalf("case %d: unmarshal response error: %v", i, err)
+		}
+		if resp.Error == nil || resp.Error.Code != c.code {
+			t.Fatalf("case %d: expected error code %d, got %+v", i, c.code, resp.Error)
+		}
+	}
+}
+
+func TestJsonrpcSendRawTransaction(t *testing.T) {
+	data, err := (&types.Tx{}).MarshalMsg(nil)
+	if err != nil {
+		t.Fatalf("marshal tx error: %v", err)
+	}
+	cases := []struct {
+		body string
+		code int
+	}{
+		// ethereum txs can't be verified, so only the OG encoding is taken.
+		{`{"jsonrpc":"2.0","id":1,"method":"eth_sendRawTransaction","params":["0x00"]}`, jsonrpcMethodNotFound},
+		{`{"jsonrpc":"2.0","id":1,"method":"og_sendRawTransaction","params":["0x00"]}`, jsonrpcInvalidParams},
+		// a node without a syncer doesn't take txs.
+		{`{"jsonrpc":"2.0","id":1,"method":"og_sendRawTransaction","params":["` + hexutil.Encode(data) + `"]}`, jsonrpcServerError},
+	}
+	for i, c := range cases {
+		var resp jsonrpcResponse
+		if err := json.Unmarshal(serveTestJsonrpc(t, c.body), &resp); err != nil {
+			t.Fatalf("case %d: unmarshal response error: %v", i, err)
+		}
+		if resp.Error == nil || resp.Error.Code != c.code {
+			t.Fatalf("case %d: expected error code %d, got %+v", i, c.code, resp.Error)
+		}
+	}
+}
+
+func TestJsonrpcBatch(t *testing.T) {
+	body := `[
+		{"jsonrpc":"2.0","id":1,"method":"eth_mining"},
+		{"jsonrpc":"2.0","method":"eth_mining"},
+		{"jsonrpc":"2.0","id":"b","method":"eth_getLogs"}
+	]`
+	var resps []jsonrpcResponse
+	if err := json.Unmarshal(serveTestJsonrpc(t, body), &resps); err != nil {
+		t.Fatalf("unmarshal response error: %v", err)
+	}
+	// the notification has no response.
+	if len(resps) != 2 {
+		t.Fatalf("expected 2 responses, got %d", len(resps))
+	}
+	if string(resps[0].ID) != `1` || resps[0].Error.Code != jsonrpcMethodNotFound {
+		t.Fatalf("wrong response %+v", resps[0])
+	}
+	if string(resps[1].ID) != `"b"` || resps[1].Error.Code != jsonrpcInvalidParams {
+		t.Fatalf("wrong response %+v", resps[1])
+	}
+
+	if resp := serveTestJsonrpc(t, `[{"jsonrpc":"2.0","method":"eth_mining"}]`); len(resp) != 0 {
+		t.Fatalf("expected no response for notifications, got %s", resp)
+	}
+}
+
+func TestJsonrpcParams(t *testing.T) {
+	addr := types.HexToAddress("0x01")
+	topic := types.HexToHash("0x02")
+	input := `{
+		"fromBlock":"earliest",
+		"toBlock":"0x10",
+		"address":"` + addr.Hex() + `",
+		"topics":[null,"` + topic.Hex() + `",["` + topic.Hex() + `","` + topic.Hex() + `"]]
+	}`
+	var args logFilterArgs
+	if err := json.Unmarshal([]byte(input), &args); err != nil {
+		t.Fatalf("unmarshal filter error: %v", err)
+	}
+	if args.FromBlock.latest || args.FromBlock.height != 0 || args.ToBlock.height != 16 {
+		t.Fatalf("wrong block numbers %+v %+v", args.FromBlock, args.ToBlock)
+	}
+	if len(args.Address) != 1 || args.Address[0] != addr {
+		t.Fatalf("wrong addresses %v", args.Address)
+	}
+	if len(args.Topics) != 3 || len(args.Topics[0]) != 0 || len(args.Topics[1]) != 1 || len(args.Topics[2]) != 2 {
+		t.Fatalf("wrong topics %v", args.Topics)
+	}
+
+	var pending blockNumber
+	if err := json.Unmarshal([]byte(`"pending"`), &pending); err != nil || !pending.latest || !pending.pending {
+		t.Fatalf("wrong pending block number %+v, err: %v", pending, err)
+	}
+
+	var call callArgs
+	if err := json.Unmarshal([]byte(`{"to":"`+addr.Hex()+`","gas":"0x5208","value":"0x1","input":"0x0102"}`), &call); err != nil {
+		t.Fatalf("unmarshal call error: %v", err)
+	}
+	tx := call.toTx()
+	if tx.To != addr || tx.GasLimit != 21000 || tx.Value.GetInt64() != 1 || !bytes.Equal(tx.Data, []byte{1, 2}) {
+		t.Fatalf("wrong tx %s", tx.Dump())
+	}
+}
@@ -13,6 +13,8 @@ import (
 func (rpc *RpcController) Newrouter() *gin.Engine {
 	router := gin.New()
 	router.GET("/", rpc.writeListOfEndpoints)
+	// ethereum style JSON-RPC 2.0
+	router.POST("/", rpc.JsonRpc)
 	// init paths here
 	router.GET("/ping", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
@@ -39,10 +41,16 @@ func (rpc *RpcController) Newrouter() *gin.Engine {
 	router.GET("query", rpc.Query)
 	router.GET("query_nonce", rpc.QueryNonce)
 	router.GET("query_balance", rpc.QueryBalance)
+	router.GET("query_state", rpc.QueryState)
+	router.GET("query_proof", rpc.QueryProof)
 	router.GET("query_share", rpc.QueryShare)
 	router.GET("contract_payload", rpc.ContractPayload)
+	router.POST("contract_payload", rpc.ContractPayload)
+	router.POST("decode_contract_result", rpc.DecodeContractResult)
+	router.POST("decode_contract_logs", rpc.DecodeContractLogs)
 	router.GET("query_receipt", rpc.QueryReceipt)
 	router.GET("query_contract", rpc.QueryContract)
+	router.POST("filter_logs", rpc.FilterLogs)
 
 	router.GET("debug", rpc.Debug)
 	router.GET("tps", rpc.Tps)
@@ -53,6 +61,38 @@ func (rpc *RpcController) Newrouter() *gin.Engine {
 
 }
 
+// adminContextKey marks the requests served by the admin router.
+const adminContextKey = "admin"
+
+// NewAdminRouter routes the admin API enabled by admin. It is served on
+// localhost only, and refuses requests from browsers, which are told by
+// the Origin header.
+func (rpc *RpcController) NewAdminRouter(admin AdminConfig) *gin.Engine {
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if c.GetHeader("Origin") != "" {
+			Response(c, http.StatusForbidden, fmt.Errorf("admin API is not served to browsers"), nil)
+			c.Abort()
+			return
+		}
+		c.Set(adminContextKey, true)
+	})
+	if admin.KeyStore {
+		// keystore API
+		router.POST("new_account", rpc.NewAccount)
+		router.GET("list_accounts", rpc.ListAccounts)
+		router.POST("unlock_account", rpc.UnlockAccount)
+		router.POST("lock_account", rpc.LockAccount)
+		router.POST("import_account", rpc.ImportAccount)
+		router.POST("export_account", rpc.ExportAccount)
+		router.POST("send_transaction", rpc.SendTransaction)
+	}
+	if admin.RollBack {
+		router.POST("rollback", rpc.RollBack)
+	}
+	return router
+}
+
 // writes a list of available rpc endpoints as an html page
 func (rpc *RpcController) writeListOfEndpoints(c *gin.Context) {
 
@@ -75,16 +115,18 @@ func (rpc *RpcController) writeListOfEndpoints(c *gin.Context) {
 
 		// query API
 		"query":            "query",
-		"query_nonce":      "address",
-		"query_balance":    "address",
+		"query_nonce":      "address, height",
+		"query_balance":    "address, height",
+		"query_state":      "address, key, height",
+		"query_proof":      "address, keys, height",
 		"query_share":      "pubkey",
-		"contract_payload": "payload, abistr",
+		"contract_payload": "abistr, method, args",
 
 		"query_receipt":  "hash",
 		"transaction":    "hash",
 		"transactions":   "seq_id,address",
 		"confirm":        "hash",
-		"query_contract": "tx",
+		"query_contract": "contract_address, query_data, height",
 
 		// debug
 		"debug": "f",
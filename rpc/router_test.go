@@ -0,0 +1,31 @@
+package rpc
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRollBackRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := &RpcController{}
+	for _, tc := range []struct {
+		name   string
+		router *gin.Engine
+		status int
+	}{
+		{"public", r.Newrouter(), http.StatusNotFound},
+		{"admin without rollback", r.NewAdminRouter(AdminConfig{KeyStore: true}), http.StatusNotFound},
+		// the malformed body is refused before touching the ledger.
+		{"admin", r.NewAdminRouter(AdminConfig{RollBack: true}), http.StatusBadRequest},
+	} {
+		w := httptest.NewRecorder()
+		tc.router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/rollback", bytes.NewBufferString("{")))
+		if w.Code != tc.status {
+			t.Fatalf("%s: expected status %d, got %d", tc.name, tc.status, w.Code)
+		}
+	}
+}
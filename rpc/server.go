@@ -16,9 +16,26 @@ type RpcServer struct {
 	server *http.Server
 	port   string
 	C      *RpcController
+	// adminServer serves the admin router on localhost, nil if no admin
+	// API is enabled.
+	adminServer *http.Server
 }
 
-func NewRpcServer(port string) *RpcServer {
+// AdminConfig enables the admin API, which is served on localhost:Port
+// only, apart from the public routes.
+type AdminConfig struct {
+	Port string
+	// KeyStore serves the keystore routes and send_transaction.
+	KeyStore bool
+	// RollBack serves the rollback route.
+	RollBack bool
+}
+
+func (a AdminConfig) enabled() bool {
+	return a.KeyStore || a.RollBack
+}
+
+func NewRpcServer(port string, admin AdminConfig) *RpcServer {
 	c := RpcController{}
 	router := c.Newrouter()
 	server := &http.Server{
@@ -32,6 +49,12 @@ func NewRpcServer(port string) *RpcServer {
 		server: server,
 		C:      &c,
 	}
+	if admin.enabled() {
+		rpc.adminServer = &http.Server{
+			Addr:    "127.0.0.1:" + admin.Port,
+			Handler: c.NewAdminRouter(admin),
+		}
+	}
 	return rpc
 }
 
@@ -43,6 +66,15 @@ func (srv *RpcServer) Start() {
 			logrus.WithError(err).Fatalf("error in Http server")
 		}
 	}()
+	if srv.adminServer == nil {
+		return
+	}
+	logrus.Infof("listening admin Http on %s", srv.adminServer.Addr)
+	go func() {
+		if err := srv.adminServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logrus.WithError(err).Fatalf("error in admin Http server")
+		}
+	}()
 }
 
 func (srv *RpcServer) Stop() {
@@ -51,6 +83,11 @@ func (srv *RpcServer) Stop() {
 	if err := srv.server.Shutdown(ctx); err != nil {
 		logrus.WithError(err).Error("error while shutting down the Http server")
 	}
+	if srv.adminServer != nil {
+		if err := srv.adminServer.Shutdown(ctx); err != nil {
+			logrus.WithError(err).Error("error while shutting down the admin Http server")
+		}
+	}
 	logrus.Infof("http server Stopped")
 }
 
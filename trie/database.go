@@ -269,12 +269,7 @@ func (db *Database) dereference(child types.Hash, parent types.Hash) {
 	node.parents--
 	if node.parents == 0 {
 		// Remove the node from the flush-list
-		if child == db.oldest {
-			db.oldest = node.flushNext
-		} else {
-			db.nodes[node.flushPrev].flushNext = node.flushNext
-			db.nodes[node.flushNext].flushPrev = node.flushPrev
-		}
+		db.unlink(child, node)
 		// Dereference all children and delete the node
 		for hash := range node.children {
 			db.dereference(hash, child)
@@ -491,12 +486,7 @@ func (db *Database) uncache(hash types.Hash) {
 		return
 	}
 	// Node still exists, remove it from the flush-list
-	if hash == db.oldest {
-		db.oldest = node.flushNext
-	} else {
-		db.nodes[node.flushPrev].flushNext = node.flushNext
-		db.nodes[node.flushNext].flushPrev = node.flushPrev
-	}
+	db.unlink(hash, node)
 	// Uncache the node's subtries and remove the node itself too
 	for child := range node.children {
 		db.uncache(child)
@@ -505,6 +495,22 @@ func (db *Database) uncache(hash types.Hash) {
 	db.nodesSize -= common.StorageSize(types.HashLength + len(node.blob))
 }
 
+// unlink removes node from the flush-list, moving the list endpoints if
+// it is one of them.
+func (db *Database) unlink(hash types.Hash, node *cachedNode) {
+	switch hash {
+	case db.oldest:
+		db.oldest = node.flushNext
+		db.nodes[node.flushNext].flushPrev = types.Hash{}
+	case db.newest:
+		db.newest = node.flushPrev
+		db.nodes[node.flushPrev].flushNext = types.Hash{}
+	default:
+		db.nodes[node.flushPrev].flushNext = node.flushNext
+		db.nodes[node.flushNext].flushPrev = node.flushPrev
+	}
+}
+
 // Size returns the current storage size of the memory cache in front of the
 // persistent database layer.
 func (db *Database) Size() (common.StorageSize, common.StorageSize) {
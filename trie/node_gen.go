@@ -108,9 +108,9 @@ func (z *FullNode) UnmarshalMsg(bts []byte) (o []byte, err error) {
 			return
 		}
 		if z.Children[za0001] == nil {
-			return
+			continue
 		}
-		bts, err = z.Children[za0001].UnmarshalMsg(bts)
+		z.Children[za0001], bts, err = unmarshalNode(z.Children[za0001], bts)
 		if err != nil {
 			return
 		}
@@ -288,9 +288,10 @@ func (z *ShortNode) UnmarshalMsg(bts []byte) (o []byte, err error) {
 		return
 	}
 	if z.Val == nil {
+		o = bts
 		return
 	}
-	bts, err = z.Val.UnmarshalMsg(bts)
+	z.Val, bts, err = unmarshalNode(z.Val, bts)
 	if err != nil {
 		return
 	}
@@ -382,6 +383,23 @@ func (z ValueNode) Msgsize() (s int) {
 	return
 }
 
+// unmarshalNode unmarshals the node n initiated by GenNode. HashNode and
+// ValueNode are byte slices whose value receivers can't keep the result,
+// so they are read directly.
+func unmarshalNode(n Node, bts []byte) (Node, []byte, error) {
+	switch n.(type) {
+	case HashNode:
+		b, o, err := msgp.ReadBytesBytes(bts, nil)
+		return HashNode(b), o, err
+	case ValueNode:
+		b, o, err := msgp.ReadBytesBytes(bts, nil)
+		return ValueNode(b), o, err
+	default:
+		o, err := n.UnmarshalMsg(bts)
+		return n, o, err
+	}
+}
+
 // convert nodetype to byte
 func nodetypebyte(t nodetype) byte {
 	return ([]byte(strconv.Itoa(int(t))))[0]
@@ -53,6 +53,10 @@ func (t *Trie) Prove(key []byte, fromLevel uint, proofDb ogdb.Putter) error {
 			tn = n.Children[key[0]]
 			key = key[1:]
 			nodes = append(nodes, n)
+		case ValueNode:
+			// empty children are decoded as empty value nodes, a value
+			// before the end of key means the trie doesn't contain it.
+			tn = nil
 		case HashNode:
 			var err error
 			tn, err = t.resolveHash(n, nil)
@@ -105,6 +109,10 @@ func (t *SecureTrie) Prove(key []byte, fromLevel uint, proofDb ogdb.Putter) erro
 // key in a trie with the given root hash. VerifyProof returns an error if the
 // proof contains invalid trie nodes or the wrong value.
 func VerifyProof(rootHash types.Hash, key []byte, proofDb DatabaseReader) (value []byte, nodes int, err error) {
+	// an empty trie contains no key, there is no node to prove it.
+	if rootHash == emptyRoot || rootHash == (types.Hash{}) {
+		return nil, 0, nil
+	}
 	key = keybytesToHex(key)
 	wantHash := rootHash
 	for i := 0; ; i++ {
@@ -123,7 +131,7 @@ func VerifyProof(rootHash types.Hash, key []byte, proofDb DatabaseReader) (value
 			return nil, i, nil
 		case HashNode:
 			key = keyrest
-			copy(wantHash.ToBytes(), cld)
+			wantHash = types.BytesToHash(cld)
 		case ValueNode:
 			return cld, i + 1, nil
 		}
@@ -277,9 +277,10 @@ func (s *Sync) children(req *request, object Node) ([]*request, error) {
 	// Iterate over the children, and request all unknown ones
 	requests := make([]*request, 0, len(children))
 	for _, child := range children {
-		// Notify any external watcher of a new key/value node
+		// Notify any external watcher of a new key/value node. Full nodes
+		// keep an empty value node in their value slot.
 		if req.callback != nil {
-			if node, ok := (child.node).(ValueNode); ok {
+			if node, ok := (child.node).(ValueNode); ok && len(node) > 0 {
 				if err := req.callback(node, req.hash); err != nil {
 					return nil, err
 				}
@@ -1,15 +1,32 @@
 package types
 
 import (
+	"bytes"
+	"encoding/binary"
 	"fmt"
 	"strings"
+
+	"github.com/annchain/OG/common/crypto/sha3"
 )
 
 //go:generate msgp
 
+// SequencerHeader is the part of a sequencer a light node downloads. It
+// carries the state and receipts roots and the issuer's signature over
+// them, so the roots can be trusted without the txs confirmed by the
+// sequencer. BaseHash is the hash of the tx base of the sequencer, which
+// covers its parents, so that Hash can be recomputed from the header.
 type SequencerHeader struct {
-	Hash   Hash
-	Height uint64
+	Hash         Hash
+	BaseHash     Hash
+	Height       uint64
+	Issuer       Address
+	AccountNonce uint64
+	Timestamp    int64
+	StateRoot    Hash
+	ReceiptsRoot Hash
+	PublicKey    []byte
+	Signature    []byte
 }
 
 type SequencerHeaders []*SequencerHeader
@@ -26,6 +43,29 @@ func (s *SequencerHeader) GetHeight() uint64 {
 	return s.Height
 }
 
+// SignatureTargets returns the bytes signed by the issuer, which are the
+// same as the signature targets of the sequencer.
+func (s *SequencerHeader) SignatureTargets() []byte {
+	var buf bytes.Buffer
+
+	panicIfError(binary.Write(&buf, binary.BigEndian, s.AccountNonce))
+	panicIfError(binary.Write(&buf, binary.BigEndian, s.Issuer.Bytes))
+	panicIfError(binary.Write(&buf, binary.BigEndian, s.Height))
+	panicIfError(binary.Write(&buf, binary.BigEndian, s.Timestamp))
+	panicIfError(binary.Write(&buf, binary.BigEndian, s.StateRoot.Bytes))
+	panicIfError(binary.Write(&buf, binary.BigEndian, s.ReceiptsRoot.Bytes))
+
+	return buf.Bytes()
+}
+
+// CalcHash computes the hash of the sequencer the header is taken from,
+// the same way as Sequencer.CalcTxHash.
+func (s *SequencerHeader) CalcHash() (hash Hash) {
+	result := sha3.Sum256(append(s.BaseHash.ToBytes(), s.SignatureTargets()...))
+	hash.MustSetBytes(result[0:], PaddingNone)
+	return
+}
+
 func (s *SequencerHeader) String() string {
 	if s == nil {
 		return fmt.Sprintf("nil")
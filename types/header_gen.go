@@ -27,11 +27,51 @@ func (z *SequencerHeader) DecodeMsg(dc *msgp.Reader) (err error) {
 			if err != nil {
 				return
 			}
+		case "BaseHash":
+			err = z.BaseHash.DecodeMsg(dc)
+			if err != nil {
+				return
+			}
 		case "Height":
 			z.Height, err = dc.ReadUint64()
 			if err != nil {
 				return
 			}
+		case "Issuer":
+			err = z.Issuer.DecodeMsg(dc)
+			if err != nil {
+				return
+			}
+		case "AccountNonce":
+			z.AccountNonce, err = dc.ReadUint64()
+			if err != nil {
+				return
+			}
+		case "Timestamp":
+			z.Timestamp, err = dc.ReadInt64()
+			if err != nil {
+				return
+			}
+		case "StateRoot":
+			err = z.StateRoot.DecodeMsg(dc)
+			if err != nil {
+				return
+			}
+		case "ReceiptsRoot":
+			err = z.ReceiptsRoot.DecodeMsg(dc)
+			if err != nil {
+				return
+			}
+		case "PublicKey":
+			z.PublicKey, err = dc.ReadBytes(z.PublicKey)
+			if err != nil {
+				return
+			}
+		case "Signature":
+			z.Signature, err = dc.ReadBytes(z.Signature)
+			if err != nil {
+				return
+			}
 		default:
 			err = dc.Skip()
 			if err != nil {
@@ -44,9 +84,9 @@ func (z *SequencerHeader) DecodeMsg(dc *msgp.Reader) (err error) {
 
 // EncodeMsg implements msgp.Encodable
 func (z *SequencerHeader) EncodeMsg(en *msgp.Writer) (err error) {
-	// map header, size 2
+	// map header, size 10
 	// write "Hash"
-	err = en.Append(0x82, 0xa4, 0x48, 0x61, 0x73, 0x68)
+	err = en.Append(0x8a, 0xa4, 0x48, 0x61, 0x73, 0x68)
 	if err != nil {
 		return
 	}
@@ -54,6 +94,15 @@ func (z *SequencerHeader) EncodeMsg(en *msgp.Writer) (err error) {
 	if err != nil {
 		return
 	}
+	// write "BaseHash"
+	err = en.Append(0xa8, 0x42, 0x61, 0x73, 0x65, 0x48, 0x61, 0x73, 0x68)
+	if err != nil {
+		return
+	}
+	err = z.BaseHash.EncodeMsg(en)
+	if err != nil {
+		return
+	}
 	// write "Height"
 	err = en.Append(0xa6, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74)
 	if err != nil {
@@ -63,22 +112,121 @@ func (z *SequencerHeader) EncodeMsg(en *msgp.Writer) (err error) {
 	if err != nil {
 		return
 	}
+	// write "Issuer"
+	err = en.Append(0xa6, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72)
+	if err != nil {
+		return
+	}
+	err = z.Issuer.EncodeMsg(en)
+	if err != nil {
+		return
+	}
+	// write "AccountNonce"
+	err = en.Append(0xac, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x4e, 0x6f, 0x6e, 0x63, 0x65)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint64(z.AccountNonce)
+	if err != nil {
+		return
+	}
+	// write "Timestamp"
+	err = en.Append(0xa9, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70)
+	if err != nil {
+		return
+	}
+	err = en.WriteInt64(z.Timestamp)
+	if err != nil {
+		return
+	}
+	// write "StateRoot"
+	err = en.Append(0xa9, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x74)
+	if err != nil {
+		return
+	}
+	err = z.StateRoot.EncodeMsg(en)
+	if err != nil {
+		return
+	}
+	// write "ReceiptsRoot"
+	err = en.Append(0xac, 0x52, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x73, 0x52, 0x6f, 0x6f, 0x74)
+	if err != nil {
+		return
+	}
+	err = z.ReceiptsRoot.EncodeMsg(en)
+	if err != nil {
+		return
+	}
+	// write "PublicKey"
+	err = en.Append(0xa9, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79)
+	if err != nil {
+		return
+	}
+	err = en.WriteBytes(z.PublicKey)
+	if err != nil {
+		return
+	}
+	// write "Signature"
+	err = en.Append(0xa9, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65)
+	if err != nil {
+		return
+	}
+	err = en.WriteBytes(z.Signature)
+	if err != nil {
+		return
+	}
 	return
 }
 
 // MarshalMsg implements msgp.Marshaler
 func (z *SequencerHeader) MarshalMsg(b []byte) (o []byte, err error) {
 	o = msgp.Require(b, z.Msgsize())
-	// map header, size 2
+	// map header, size 10
 	// string "Hash"
-	o = append(o, 0x82, 0xa4, 0x48, 0x61, 0x73, 0x68)
+	o = append(o, 0x8a, 0xa4, 0x48, 0x61, 0x73, 0x68)
 	o, err = z.Hash.MarshalMsg(o)
 	if err != nil {
 		return
 	}
+	// string "BaseHash"
+	o = append(o, 0xa8, 0x42, 0x61, 0x73, 0x65, 0x48, 0x61, 0x73, 0x68)
+	o, err = z.BaseHash.MarshalMsg(o)
+	if err != nil {
+		return
+	}
 	// string "Height"
 	o = append(o, 0xa6, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74)
 	o = msgp.AppendUint64(o, z.Height)
+	// string "Issuer"
+	o = append(o, 0xa6, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72)
+	o, err = z.Issuer.MarshalMsg(o)
+	if err != nil {
+		return
+	}
+	// string "AccountNonce"
+	o = append(o, 0xac, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x4e, 0x6f, 0x6e, 0x63, 0x65)
+	o = msgp.AppendUint64(o, z.AccountNonce)
+	// string "Timestamp"
+	o = append(o, 0xa9, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70)
+	o = msgp.AppendInt64(o, z.Timestamp)
+	// string "StateRoot"
+	o = append(o, 0xa9, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x74)
+	o, err = z.StateRoot.MarshalMsg(o)
+	if err != nil {
+		return
+	}
+	// string "ReceiptsRoot"
+	o = append(o, 0xac, 0x52, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x73, 0x52, 0x6f, 0x6f, 0x74)
+	o, err = z.ReceiptsRoot.MarshalMsg(o)
+	if err != nil {
+		return
+	}
+	// string "PublicKey"
+	o = append(o, 0xa9, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79)
+	o = msgp.AppendBytes(o, z.PublicKey)
+	// string "Signature"
+	o = append(o, 0xa9, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65)
+	o = msgp.AppendBytes(o, z.Signature)
 	return
 }
 
@@ -103,11 +251,51 @@ func (z *SequencerHeader) UnmarshalMsg(bts []byte) (o []byte, err error) {
 			if err != nil {
 				return
 			}
+		case "BaseHash":
+			bts, err = z.BaseHash.UnmarshalMsg(bts)
+			if err != nil {
+				return
+			}
 		case "Height":
 			z.Height, bts, err = msgp.ReadUint64Bytes(bts)
 			if err != nil {
 				return
 			}
+		case "Issuer":
+			bts, err = z.Issuer.UnmarshalMsg(bts)
+			if err != nil {
+				return
+			}
+		case "AccountNonce":
+			z.AccountNonce, bts, err = msgp.ReadUint64Bytes(bts)
+			if err != nil {
+				return
+			}
+		case "Timestamp":
+			z.Timestamp, bts, err = msgp.ReadInt64Bytes(bts)
+			if err != nil {
+				return
+			}
+		case "StateRoot":
+			bts, err = z.StateRoot.UnmarshalMsg(bts)
+			if err != nil {
+				return
+			}
+		case "ReceiptsRoot":
+			bts, err = z.ReceiptsRoot.UnmarshalMsg(bts)
+			if err != nil {
+				return
+			}
+		case "PublicKey":
+			z.PublicKey, bts, err = msgp.ReadBytesBytes(bts, z.PublicKey)
+			if err != nil {
+				return
+			}
+		case "Signature":
+			z.Signature, bts, err = msgp.ReadBytesBytes(bts, z.Signature)
+			if err != nil {
+				return
+			}
 		default:
 			bts, err = msgp.Skip(bts)
 			if err != nil {
@@ -121,7 +309,7 @@ func (z *SequencerHeader) UnmarshalMsg(bts []byte) (o []byte, err error) {
 
 // Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
 func (z *SequencerHeader) Msgsize() (s int) {
-	s = 1 + 5 + z.Hash.Msgsize() + 7 + msgp.Uint64Size
+	s = 1 + 5 + z.Hash.Msgsize() + 9 + z.BaseHash.Msgsize() + 7 + msgp.Uint64Size + 7 + z.Issuer.Msgsize() + 13 + msgp.Uint64Size + 10 + msgp.Int64Size + 10 + z.StateRoot.Msgsize() + 13 + z.ReceiptsRoot.Msgsize() + 10 + msgp.BytesPrefixSize + len(z.PublicKey) + 10 + msgp.BytesPrefixSize + len(z.Signature)
 	return
 }
 
@@ -167,11 +355,51 @@ func (z *SequencerHeaders) DecodeMsg(dc *msgp.Reader) (err error) {
 					if err != nil {
 						return
 					}
+				case "BaseHash":
+					err = (*z)[zb0001].BaseHash.DecodeMsg(dc)
+					if err != nil {
+						return
+					}
 				case "Height":
 					(*z)[zb0001].Height, err = dc.ReadUint64()
 					if err != nil {
 						return
 					}
+				case "Issuer":
+					err = (*z)[zb0001].Issuer.DecodeMsg(dc)
+					if err != nil {
+						return
+					}
+				case "AccountNonce":
+					(*z)[zb0001].AccountNonce, err = dc.ReadUint64()
+					if err != nil {
+						return
+					}
+				case "Timestamp":
+					(*z)[zb0001].Timestamp, err = dc.ReadInt64()
+					if err != nil {
+						return
+					}
+				case "StateRoot":
+					err = (*z)[zb0001].StateRoot.DecodeMsg(dc)
+					if err != nil {
+						return
+					}
+				case "ReceiptsRoot":
+					err = (*z)[zb0001].ReceiptsRoot.DecodeMsg(dc)
+					if err != nil {
+						return
+					}
+				case "PublicKey":
+					(*z)[zb0001].PublicKey, err = dc.ReadBytes((*z)[zb0001].PublicKey)
+					if err != nil {
+						return
+					}
+				case "Signature":
+					(*z)[zb0001].Signature, err = dc.ReadBytes((*z)[zb0001].Signature)
+					if err != nil {
+						return
+					}
 				default:
 					err = dc.Skip()
 					if err != nil {
@@ -197,9 +425,9 @@ func (z SequencerHeaders) EncodeMsg(en *msgp.Writer) (err error) {
 				return
 			}
 		} else {
-			// map header, size 2
+			// map header, size 10
 			// write "Hash"
-			err = en.Append(0x82, 0xa4, 0x48, 0x61, 0x73, 0x68)
+			err = en.Append(0x8a, 0xa4, 0x48, 0x61, 0x73, 0x68)
 			if err != nil {
 				return
 			}
@@ -207,6 +435,15 @@ func (z SequencerHeaders) EncodeMsg(en *msgp.Writer) (err error) {
 			if err != nil {
 				return
 			}
+			// write "BaseHash"
+			err = en.Append(0xa8, 0x42, 0x61, 0x73, 0x65, 0x48, 0x61, 0x73, 0x68)
+			if err != nil {
+				return
+			}
+			err = z[zb0004].BaseHash.EncodeMsg(en)
+			if err != nil {
+				return
+			}
 			// write "Height"
 			err = en.Append(0xa6, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74)
 			if err != nil {
@@ -216,6 +453,69 @@ func (z SequencerHeaders) EncodeMsg(en *msgp.Writer) (err error) {
 			if err != nil {
 				return
 			}
+			// write "Issuer"
+			err = en.Append(0xa6, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72)
+			if err != nil {
+				return
+			}
+			err = z[zb0004].Issuer.EncodeMsg(en)
+			if err != nil {
+				return
+			}
+			// write "AccountNonce"
+			err = en.Append(0xac, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x4e, 0x6f, 0x6e, 0x63, 0x65)
+			if err != nil {
+				return
+			}
+			err = en.WriteUint64(z[zb0004].AccountNonce)
+			if err != nil {
+				return
+			}
+			// write "Timestamp"
+			err = en.Append(0xa9, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70)
+			if err != nil {
+				return
+			}
+			err = en.WriteInt64(z[zb0004].Timestamp)
+			if err != nil {
+				return
+			}
+			// write "StateRoot"
+			err = en.Append(0xa9, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x74)
+			if err != nil {
+				return
+			}
+			err = z[zb0004].StateRoot.EncodeMsg(en)
+			if err != nil {
+				return
+			}
+			// write "ReceiptsRoot"
+			err = en.Append(0xac, 0x52, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x73, 0x52, 0x6f, 0x6f, 0x74)
+			if err != nil {
+				return
+			}
+			err = z[zb0004].ReceiptsRoot.EncodeMsg(en)
+			if err != nil {
+				return
+			}
+			// write "PublicKey"
+			err = en.Append(0xa9, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79)
+			if err != nil {
+				return
+			}
+			err = en.WriteBytes(z[zb0004].PublicKey)
+			if err != nil {
+				return
+			}
+			// write "Signature"
+			err = en.Append(0xa9, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65)
+			if err != nil {
+				return
+			}
+			err = en.WriteBytes(z[zb0004].Signature)
+			if err != nil {
+				return
+			}
 		}
 	}
 	return
@@ -229,16 +529,52 @@ func (z SequencerHeaders) MarshalMsg(b []byte) (o []byte, err error) {
 		if z[zb0004] == nil {
 			o = msgp.AppendNil(o)
 		} else {
-			// map header, size 2
+			// map header, size 10
 			// string "Hash"
-			o = append(o, 0x82, 0xa4, 0x48, 0x61, 0x73, 0x68)
+			o = append(o, 0x8a, 0xa4, 0x48, 0x61, 0x73, 0x68)
 			o, err = z[zb0004].Hash.MarshalMsg(o)
 			if err != nil {
 				return
 			}
+			// string "BaseHash"
+			o = append(o, 0xa8, 0x42, 0x61, 0x73, 0x65, 0x48, 0x61, 0x73, 0x68)
+			o, err = z[zb0004].BaseHash.MarshalMsg(o)
+			if err != nil {
+				return
+			}
 			// string "Height"
 			o = append(o, 0xa6, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74)
 			o = msgp.AppendUint64(o, z[zb0004].Height)
+			// string "Issuer"
+			o = append(o, 0xa6, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72)
+			o, err = z[zb0004].Issuer.MarshalMsg(o)
+			if err != nil {
+				return
+			}
+			// string "AccountNonce"
+			o = append(o, 0xac, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x4e, 0x6f, 0x6e, 0x63, 0x65)
+			o = msgp.AppendUint64(o, z[zb0004].AccountNonce)
+			// string "Timestamp"
+			o = append(o, 0xa9, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70)
+			o = msgp.AppendInt64(o, z[zb0004].Timestamp)
+			// string "StateRoot"
+			o = append(o, 0xa9, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x74)
+			o, err = z[zb0004].StateRoot.MarshalMsg(o)
+			if err != nil {
+				return
+			}
+			// string "ReceiptsRoot"
+			o = append(o, 0xac, 0x52, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x73, 0x52, 0x6f, 0x6f, 0x74)
+			o, err = z[zb0004].ReceiptsRoot.MarshalMsg(o)
+			if err != nil {
+				return
+			}
+			// string "PublicKey"
+			o = append(o, 0xa9, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79)
+			o = msgp.AppendBytes(o, z[zb0004].PublicKey)
+			// string "Signature"
+			o = append(o, 0xa9, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65)
+			o = msgp.AppendBytes(o, z[zb0004].Signature)
 		}
 	}
 	return
@@ -286,11 +622,51 @@ func (z *SequencerHeaders) UnmarshalMsg(bts []byte) (o []byte, err error) {
 					if err != nil {
 						return
 					}
+				case "BaseHash":
+					bts, err = (*z)[zb0001].BaseHash.UnmarshalMsg(bts)
+					if err != nil {
+						return
+					}
 				case "Height":
 					(*z)[zb0001].Height, bts, err = msgp.ReadUint64Bytes(bts)
 					if err != nil {
 						return
 					}
+				case "Issuer":
+					bts, err = (*z)[zb0001].Issuer.UnmarshalMsg(bts)
+					if err != nil {
+						return
+					}
+				case "AccountNonce":
+					(*z)[zb0001].AccountNonce, bts, err = msgp.ReadUint64Bytes(bts)
+					if err != nil {
+						return
+					}
+				case "Timestamp":
+					(*z)[zb0001].Timestamp, bts, err = msgp.ReadInt64Bytes(bts)
+					if err != nil {
+						return
+					}
+				case "StateRoot":
+					bts, err = (*z)[zb0001].StateRoot.UnmarshalMsg(bts)
+					if err != nil {
+						return
+					}
+				case "ReceiptsRoot":
+					bts, err = (*z)[zb0001].ReceiptsRoot.UnmarshalMsg(bts)
+					if err != nil {
+						return
+					}
+				case "PublicKey":
+					(*z)[zb0001].PublicKey, bts, err = msgp.ReadBytesBytes(bts, (*z)[zb0001].PublicKey)
+					if err != nil {
+						return
+					}
+				case "Signature":
+					(*z)[zb0001].Signature, bts, err = msgp.ReadBytesBytes(bts, (*z)[zb0001].Signature)
+					if err != nil {
+						return
+					}
 				default:
 					bts, err = msgp.Skip(bts)
 					if err != nil {
@@ -311,7 +687,7 @@ func (z SequencerHeaders) Msgsize() (s int) {
 		if z[zb0004] == nil {
 			s += msgp.NilSize
 		} else {
-			s += 1 + 5 + z[zb0004].Hash.Msgsize() + 7 + msgp.Uint64Size
+			s += 1 + 5 + z[zb0004].Hash.Msgsize() + 9 + z[zb0004].BaseHash.Msgsize() + 7 + msgp.Uint64Size + 7 + z[zb0004].Issuer.Msgsize() + 13 + msgp.Uint64Size + 10 + msgp.Int64Size + 10 + z[zb0004].StateRoot.Msgsize() + 13 + z[zb0004].ReceiptsRoot.Msgsize() + 10 + msgp.BytesPrefixSize + len(z[zb0004].PublicKey) + 10 + msgp.BytesPrefixSize + len(z[zb0004].Signature)
 		}
 	}
 	return
@@ -300,4 +300,67 @@ func (m *MessageBodiesResponse) String() string {
 	return fmt.Sprintf("bodies len : %d, reuqestedId :%d", len(m.Bodies), m.RequestedId)
 }
 
+//msgp:tuple MessageGetNodeData
+type MessageGetNodeData struct {
+	Hashes    Hashes
+	RequestId uint32 //avoid msg drop
+}
+
+func (m *MessageGetNodeData) String() string {
+	return m.Hashes.String() + fmt.Sprintf(" requestId :%d", m.RequestId)
+}
+
+//msgp:tuple MessageNodeData
+type MessageNodeData struct {
+	Data        []RawData
+	RequestedId uint32 //avoid msg drop
+}
+
+func (m *MessageNodeData) String() string {
+	return fmt.Sprintf("node data len : %d, reuqestedId :%d", len(m.Data), m.RequestedId)
+}
+
+//msgp:tuple MessageGetReceipts
+type MessageGetReceipts struct {
+	From      uint64 // height of the first sequencer
+	Amount    uint64 // number of sequencers from From
+	RequestId uint32 //avoid msg drop
+}
+
+func (m *MessageGetReceipts) String() string {
+	return fmt.Sprintf("from: %d, amount: %d, requestId :%d", m.From, m.Amount, m.RequestId)
+}
+
+//msgp:tuple MessageReceipts
+type MessageReceipts struct {
+	Receipts    []RawData // encoded receipts of each sequencer, in height order
+	RequestedId uint32    //avoid msg drop
+}
+
+func (m *MessageReceipts) String() string {
+	return fmt.Sprintf("receipts len : %d, reuqestedId :%d", len(m.Receipts), m.RequestedId)
+}
+
+//msgp:tuple MessageGetProof
+type MessageGetProof struct {
+	Root      Hash    // state root the proof is against
+	Address   Address // account to prove
+	Keys      Hashes  // storage keys of the account to prove
+	RequestId uint32  //avoid msg drop
+}
+
+func (m *MessageGetProof) String() string {
+	return fmt.Sprintf("root: %s, address: %s, keys: [%s], requestId :%d", m.Root.Hex(), m.Address.Hex(), m.Keys.String(), m.RequestId)
+}
+
+//msgp:tuple MessageProof
+type MessageProof struct {
+	Proof       []RawData // trie nodes proving the account and its storage keys
+	RequestedId uint32    //avoid msg drop
+}
+
+func (m *MessageProof) String() string {
+	return fmt.Sprintf("proof len : %d, reuqestedId :%d", len(m.Proof), m.RequestedId)
+}
+
 type RawData []byte
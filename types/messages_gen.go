@@ -559,6 +559,294 @@ func (z *MessageBodyData) Msgsize() (s int) {
 	return
 }
 
+// DecodeMsg implements msgp.Decodable
+func (z *MessageGetNodeData) DecodeMsg(dc *msgp.Reader) (err error) {
+	var zb0001 uint32
+	zb0001, err = dc.ReadArrayHeader()
+	if err != nil {
+		return
+	}
+	if zb0001 != 2 {
+		err = msgp.ArrayError{Wanted: 2, Got: zb0001}
+		return
+	}
+	err = z.Hashes.DecodeMsg(dc)
+	if err != nil {
+		return
+	}
+	z.RequestId, err = dc.ReadUint32()
+	if err != nil {
+		return
+	}
+	return
+}
+
+// EncodeMsg implements msgp.Encodable
+func (z *MessageGetNodeData) EncodeMsg(en *msgp.Writer) (err error) {
+	// array header, size 2
+	err = en.Append(0x92)
+	if err != nil {
+		return
+	}
+	err = z.Hashes.EncodeMsg(en)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint32(z.RequestId)
+	if err != nil {
+		return
+	}
+	return
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z *MessageGetNodeData) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	// array header, size 2
+	o = append(o, 0x92)
+	o, err = z.Hashes.MarshalMsg(o)
+	if err != nil {
+		return
+	}
+	o = msgp.AppendUint32(o, z.RequestId)
+	return
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *MessageGetNodeData) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	var zb0001 uint32
+	zb0001, bts, err = msgp.ReadArrayHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if zb0001 != 2 {
+		err = msgp.ArrayError{Wanted: 2, Got: zb0001}
+		return
+	}
+	bts, err = z.Hashes.UnmarshalMsg(bts)
+	if err != nil {
+		return
+	}
+	z.RequestId, bts, err = msgp.ReadUint32Bytes(bts)
+	if err != nil {
+		return
+	}
+	o = bts
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *MessageGetNodeData) Msgsize() (s int) {
+	s = 1 + z.Hashes.Msgsize() + msgp.Uint32Size
+	return
+}
+
+// DecodeMsg implements msgp.Decodable
+func (z *MessageGetProof) DecodeMsg(dc *msgp.Reader) (err error) {
+	var zb0001 uint32
+	zb0001, err = dc.ReadArrayHeader()
+	if err != nil {
+		return
+	}
+	if zb0001 != 4 {
+		err = msgp.ArrayError{Wanted: 4, Got: zb0001}
+		return
+	}
+	err = z.Root.DecodeMsg(dc)
+	if err != nil {
+		return
+	}
+	err = z.Address.DecodeMsg(dc)
+	if err != nil {
+		return
+	}
+	err = z.Keys.DecodeMsg(dc)
+	if err != nil {
+		return
+	}
+	z.RequestId, err = dc.ReadUint32()
+	if err != nil {
+		return
+	}
+	return
+}
+
+// EncodeMsg implements msgp.Encodable
+func (z *MessageGetProof) EncodeMsg(en *msgp.Writer) (err error) {
+	// array header, size 4
+	err = en.Append(0x94)
+	if err != nil {
+		return
+	}
+	err = z.Root.EncodeMsg(en)
+	if err != nil {
+		return
+	}
+	err = z.Address.EncodeMsg(en)
+	if err != nil {
+		return
+	}
+	err = z.Keys.EncodeMsg(en)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint32(z.RequestId)
+	if err != nil {
+		return
+	}
+	return
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z *MessageGetProof) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	// array header, size 4
+	o = append(o, 0x94)
+	o, err = z.Root.MarshalMsg(o)
+	if err != nil {
+		return
+	}
+	o, err = z.Address.MarshalMsg(o)
+	if err != nil {
+		return
+	}
+	o, err = z.Keys.MarshalMsg(o)
+	if err != nil {
+		return
+	}
+	o = msgp.AppendUint32(o, z.RequestId)
+	return
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *MessageGetProof) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	var zb0001 uint32
+	zb0001, bts, err = msgp.ReadArrayHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if zb0001 != 4 {
+		err = msgp.ArrayError{Wanted: 4, Got: zb0001}
+		return
+	}
+	bts, err = z.Root.UnmarshalMsg(bts)
+	if err != nil {
+		return
+	}
+	bts, err = z.Address.UnmarshalMsg(bts)
+	if err != nil {
+		return
+	}
+	bts, err = z.Keys.UnmarshalMsg(bts)
+	if err != nil {
+		return
+	}
+	z.RequestId, bts, err = msgp.ReadUint32Bytes(bts)
+	if err != nil {
+		return
+	}
+	o = bts
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *MessageGetProof) Msgsize() (s int) {
+	s = 1 + z.Root.Msgsize() + z.Address.Msgsize() + z.Keys.Msgsize() + msgp.Uint32Size
+	return
+}
+
+// DecodeMsg implements msgp.Decodable
+func (z *MessageGetReceipts) DecodeMsg(dc *msgp.Reader) (err error) {
+	var zb0001 uint32
+	zb0001, err = dc.ReadArrayHeader()
+	if err != nil {
+		return
+	}
+	if zb0001 != 3 {
+		err = msgp.ArrayError{Wanted: 3, Got: zb0001}
+		return
+	}
+	z.From, err = dc.ReadUint64()
+	if err != nil {
+		return
+	}
+	z.Amount, err = dc.ReadUint64()
+	if err != nil {
+		return
+	}
+	z.RequestId, err = dc.ReadUint32()
+	if err != nil {
+		return
+	}
+	return
+}
+
+// EncodeMsg implements msgp.Encodable
+func (z *MessageGetReceipts) EncodeMsg(en *msgp.Writer) (err error) {
+	// array header, size 3
+	err = en.Append(0x93)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint64(z.From)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint64(z.Amount)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint32(z.RequestId)
+	if err != nil {
+		return
+	}
+	return
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z *MessageGetReceipts) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	// array header, size 3
+	o = append(o, 0x93)
+	o = msgp.AppendUint64(o, z.From)
+	o = msgp.AppendUint64(o, z.Amount)
+	o = msgp.AppendUint32(o, z.RequestId)
+	return
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *MessageGetReceipts) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	var zb0001 uint32
+	zb0001, bts, err = msgp.ReadArrayHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if zb0001 != 3 {
+		err = msgp.ArrayError{Wanted: 3, Got: zb0001}
+		return
+	}
+	z.From, bts, err = msgp.ReadUint64Bytes(bts)
+	if err != nil {
+		return
+	}
+	z.Amount, bts, err = msgp.ReadUint64Bytes(bts)
+	if err != nil {
+		return
+	}
+	z.RequestId, bts, err = msgp.ReadUint32Bytes(bts)
+	if err != nil {
+		return
+	}
+	o = bts
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *MessageGetReceipts) Msgsize() (s int) {
+	s = 1 + msgp.Uint64Size + msgp.Uint64Size + msgp.Uint32Size
+	return
+}
+
 // DecodeMsg implements msgp.Decodable
 func (z *MessageHeaderRequest) DecodeMsg(dc *msgp.Reader) (err error) {
 	var zb0001 uint32
@@ -1118,8 +1406,132 @@ func (z *MessageNewTxs) Msgsize() (s int) {
 }
 
 // DecodeMsg implements msgp.Decodable
-func (z *MessagePing) DecodeMsg(dc *msgp.Reader) (err error) {
-	var field []byte
+func (z *MessageNodeData) DecodeMsg(dc *msgp.Reader) (err error) {
+	var zb0001 uint32
+	zb0001, err = dc.ReadArrayHeader()
+	if err != nil {
+		return
+	}
+	if zb0001 != 2 {
+		err = msgp.ArrayError{Wanted: 2, Got: zb0001}
+		return
+	}
+	var zb0002 uint32
+	zb0002, err = dc.ReadArrayHeader()
+	if err != nil {
+		return
+	}
+	if cap(z.Data) >= int(zb0002) {
+		z.Data = (z.Data)[:zb0002]
+	} else {
+		z.Data = make([]RawData, zb0002)
+	}
+	for za0001 := range z.Data {
+		{
+			var zb0003 []byte
+			zb0003, err = dc.ReadBytes([]byte(z.Data[za0001]))
+			if err != nil {
+				return
+			}
+			z.Data[za0001] = RawData(zb0003)
+		}
+	}
+	z.RequestedId, err = dc.ReadUint32()
+	if err != nil {
+		return
+	}
+	return
+}
+
+// EncodeMsg implements msgp.Encodable
+func (z *MessageNodeData) EncodeMsg(en *msgp.Writer) (err error) {
+	// array header, size 2
+	err = en.Append(0x92)
+	if err != nil {
+		return
+	}
+	err = en.WriteArrayHeader(uint32(len(z.Data)))
+	if err != nil {
+		return
+	}
+	for za0001 := range z.Data {
+		err = en.WriteBytes([]byte(z.Data[za0001]))
+		if err != nil {
+			return
+		}
+	}
+	err = en.WriteUint32(z.RequestedId)
+	if err != nil {
+		return
+	}
+	return
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z *MessageNodeData) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	// array header, size 2
+	o = append(o, 0x92)
+	o = msgp.AppendArrayHeader(o, uint32(len(z.Data)))
+	for za0001 := range z.Data {
+		o = msgp.AppendBytes(o, []byte(z.Data[za0001]))
+	}
+	o = msgp.AppendUint32(o, z.RequestedId)
+	return
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *MessageNodeData) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	var zb0001 uint32
+	zb0001, bts, err = msgp.ReadArrayHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if zb0001 != 2 {
+		err = msgp.ArrayError{Wanted: 2, Got: zb0001}
+		return
+	}
+	var zb0002 uint32
+	zb0002, bts, err = msgp.ReadArrayHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if cap(z.Data) >= int(zb0002) {
+		z.Data = (z.Data)[:zb0002]
+	} else {
+		z.Data = make([]RawData, zb0002)
+	}
+	for za0001 := range z.Data {
+		{
+			var zb0003 []byte
+			zb0003, bts, err = msgp.ReadBytesBytes(bts, []byte(z.Data[za0001]))
+			if err != nil {
+				return
+			}
+			z.Data[za0001] = RawData(zb0003)
+		}
+	}
+	z.RequestedId, bts, err = msgp.ReadUint32Bytes(bts)
+	if err != nil {
+		return
+	}
+	o = bts
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *MessageNodeData) Msgsize() (s int) {
+	s = 1 + msgp.ArrayHeaderSize
+	for za0001 := range z.Data {
+		s += msgp.BytesPrefixSize + len([]byte(z.Data[za0001]))
+	}
+	s += msgp.Uint32Size
+	return
+}
+
+// DecodeMsg implements msgp.Decodable
+func (z *MessagePing) DecodeMsg(dc *msgp.Reader) (err error) {
+	var field []byte
 	_ = field
 	var zb0001 uint32
 	zb0001, err = dc.ReadMapHeader()
@@ -1305,6 +1717,254 @@ func (z *MessagePong) Msgsize() (s int) {
 	return
 }
 
+// DecodeMsg implements msgp.Decodable
+func (z *MessageProof) DecodeMsg(dc *msgp.Reader) (err error) {
+	var zb0001 uint32
+	zb0001, err = dc.ReadArrayHeader()
+	if err != nil {
+		return
+	}
+	if zb0001 != 2 {
+		err = msgp.ArrayError{Wanted: 2, Got: zb0001}
+		return
+	}
+	var zb0002 uint32
+	zb0002, err = dc.ReadArrayHeader()
+	if err != nil {
+		return
+	}
+	if cap(z.Proof) >= int(zb0002) {
+		z.Proof = (z.Proof)[:zb0002]
+	} else {
+		z.Proof = make([]RawData, zb0002)
+	}
+	for za0001 := range z.Proof {
+		{
+			var zb0003 []byte
+			zb0003, err = dc.ReadBytes([]byte(z.Proof[za0001]))
+			if err != nil {
+				return
+			}
+			z.Proof[za0001] = RawData(zb0003)
+		}
+	}
+	z.RequestedId, err = dc.ReadUint32()
+	if err != nil {
+		return
+	}
+	return
+}
+
+// EncodeMsg implements msgp.Encodable
+func (z *MessageProof) EncodeMsg(en *msgp.Writer) (err error) {
+	// array header, size 2
+	err = en.Append(0x92)
+	if err != nil {
+		return
+	}
+	err = en.WriteArrayHeader(uint32(len(z.Proof)))
+	if err != nil {
+		return
+	}
+	for za0001 := range z.Proof {
+		err = en.WriteBytes([]byte(z.Proof[za0001]))
+		if err != nil {
+			return
+		}
+	}
+	err = en.WriteUint32(z.RequestedId)
+	if err != nil {
+		return
+	}
+	return
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z *MessageProof) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	// array header, size 2
+	o = append(o, 0x92)
+	o = msgp.AppendArrayHeader(o, uint32(len(z.Proof)))
+	for za0001 := range z.Proof {
+		o = msgp.AppendBytes(o, []byte(z.Proof[za0001]))
+	}
+	o = msgp.AppendUint32(o, z.RequestedId)
+	return
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *MessageProof) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	var zb0001 uint32
+	zb0001, bts, err = msgp.ReadArrayHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if zb0001 != 2 {
+		err = msgp.ArrayError{Wanted: 2, Got: zb0001}
+		return
+	}
+	var zb0002 uint32
+	zb0002, bts, err = msgp.ReadArrayHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if cap(z.Proof) >= int(zb0002) {
+		z.Proof = (z.Proof)[:zb0002]
+	} else {
+		z.Proof = make([]RawData, zb0002)
+	}
+	for za0001 := range z.Proof {
+		{
+			var zb0003 []byte
+			zb0003, bts, err = msgp.ReadBytesBytes(bts, []byte(z.Proof[za0001]))
+			if err != nil {
+				return
+			}
+			z.Proof[za0001] = RawData(zb0003)
+		}
+	}
+	z.RequestedId, bts, err = msgp.ReadUint32Bytes(bts)
+	if err != nil {
+		return
+	}
+	o = bts
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *MessageProof) Msgsize() (s int) {
+	s = 1 + msgp.ArrayHeaderSize
+	for za0001 := range z.Proof {
+		s += msgp.BytesPrefixSize + len([]byte(z.Proof[za0001]))
+	}
+	s += msgp.Uint32Size
+	return
+}
+
+// DecodeMsg implements msgp.Decodable
+func (z *MessageReceipts) DecodeMsg(dc *msgp.Reader) (err error) {
+	var zb0001 uint32
+	zb0001, err = dc.ReadArrayHeader()
+	if err != nil {
+		return
+	}
+	if zb0001 != 2 {
+		err = msgp.ArrayError{Wanted: 2, Got: zb0001}
+		return
+	}
+	var zb0002 uint32
+	zb0002, err = dc.ReadArrayHeader()
+	if err != nil {
+		return
+	}
+	if cap(z.Receipts) >= int(zb0002) {
+		z.Receipts = (z.Receipts)[:zb0002]
+	} else {
+		z.Receipts = make([]RawData, zb0002)
+	}
+	for za0001 := range z.Receipts {
+		{
+			var zb0003 []byte
+			zb0003, err = dc.ReadBytes([]byte(z.Receipts[za0001]))
+			if err != nil {
+				return
+			}
+			z.Receipts[za0001] = RawData(zb0003)
+		}
+	}
+	z.RequestedId, err = dc.ReadUint32()
+	if err != nil {
+		return
+	}
+	return
+}
+
+// EncodeMsg implements msgp.Encodable
+func (z *MessageReceipts) EncodeMsg(en *msgp.Writer) (err error) {
+	// array header, size 2
+	err = en.Append(0x92)
+	if err != nil {
+		return
+	}
+	err = en.WriteArrayHeader(uint32(len(z.Receipts)))
+	if err != nil {
+		return
+	}
+	for za0001 := range z.Receipts {
+		err = en.WriteBytes([]byte(z.Receipts[za0001]))
+		if err != nil {
+			return
+		}
+	}
+	err = en.WriteUint32(z.RequestedId)
+	if err != nil {
+		return
+	}
+	return
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z *MessageReceipts) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	// array header, size 2
+	o = append(o, 0x92)
+	o = msgp.AppendArrayHeader(o, uint32(len(z.Receipts)))
+	for za0001 := range z.Receipts {
+		o = msgp.AppendBytes(o, []byte(z.Receipts[za0001]))
+	}
+	o = msgp.AppendUint32(o, z.RequestedId)
+	return
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *MessageReceipts) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	var zb0001 uint32
+	zb0001, bts, err = msgp.ReadArrayHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if zb0001 != 2 {
+		err = msgp.ArrayError{Wanted: 2, Got: zb0001}
+		return
+	}
+	var zb0002 uint32
+	zb0002, bts, err = msgp.ReadArrayHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if cap(z.Receipts) >= int(zb0002) {
+		z.Receipts = (z.Receipts)[:zb0002]
+	} else {
+		z.Receipts = make([]RawData, zb0002)
+	}
+	for za0001 := range z.Receipts {
+		{
+			var zb0003 []byte
+			zb0003, bts, err = msgp.ReadBytesBytes(bts, []byte(z.Receipts[za0001]))
+			if err != nil {
+				return
+			}
+			z.Receipts[za0001] = RawData(zb0003)
+		}
+	}
+	z.RequestedId, bts, err = msgp.ReadUint32Bytes(bts)
+	if err != nil {
+		return
+	}
+	o = bts
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *MessageReceipts) Msgsize() (s int) {
+	s = 1 + msgp.ArrayHeaderSize
+	for za0001 := range z.Receipts {
+		s += msgp.BytesPrefixSize + len([]byte(z.Receipts[za0001]))
+	}
+	s += msgp.Uint32Size
+	return
+}
+
 // DecodeMsg implements msgp.Decodable
 func (z *MessageSequencerHeader) DecodeMsg(dc *msgp.Reader) (err error) {
 	var zb0001 uint32
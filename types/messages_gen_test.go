@@ -574,6 +574,345 @@ func BenchmarkDecodeMessageBodyData(b *testing.B) {
 	}
 }
 
+func TestMarshalUnmarshalMessageGetNodeData(t *testing.T) {
+	v := MessageGetNodeData{}
+	bts, err := v.MarshalMsg(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	left, err := v.UnmarshalMsg(bts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(left) > 0 {
+		t.Errorf("%d bytes left over after UnmarshalMsg(): %q", len(left), left)
+	}
+
+	left, err = msgp.Skip(bts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(left) > 0 {
+		t.Errorf("%d bytes left over after Skip(): %q", len(left), left)
+	}
+}
+
+func BenchmarkMarshalMsgMessageGetNodeData(b *testing.B) {
+	v := MessageGetNodeData{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		v.MarshalMsg(nil)
+	}
+}
+
+func BenchmarkAppendMsgMessageGetNodeData(b *testing.B) {
+	v := MessageGetNodeData{}
+	bts := make([]byte, 0, v.Msgsize())
+	bts, _ = v.MarshalMsg(bts[0:0])
+	b.SetBytes(int64(len(bts)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bts, _ = v.MarshalMsg(bts[0:0])
+	}
+}
+
+func BenchmarkUnmarshalMessageGetNodeData(b *testing.B) {
+	v := MessageGetNodeData{}
+	bts, _ := v.MarshalMsg(nil)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(bts)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := v.UnmarshalMsg(bts)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestEncodeDecodeMessageGetNodeData(t *testing.T) {
+	v := MessageGetNodeData{}
+	var buf bytes.Buffer
+	msgp.Encode(&buf, &v)
+
+	m := v.Msgsize()
+	if buf.Len() > m {
+		t.Logf("WARNING: Msgsize() for %v is inaccurate", v)
+	}
+
+	vn := MessageGetNodeData{}
+	err := msgp.Decode(&buf, &vn)
+	if err != nil {
+		t.Error(err)
+	}
+
+	buf.Reset()
+	msgp.Encode(&buf, &v)
+	err = msgp.NewReader(&buf).Skip()
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func BenchmarkEncodeMessageGetNodeData(b *testing.B) {
+	v := MessageGetNodeData{}
+	var buf bytes.Buffer
+	msgp.Encode(&buf, &v)
+	b.SetBytes(int64(buf.Len()))
+	en := msgp.NewWriter(msgp.Nowhere)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		v.EncodeMsg(en)
+	}
+	en.Flush()
+}
+
+func BenchmarkDecodeMessageGetNodeData(b *testing.B) {
+	v := MessageGetNodeData{}
+	var buf bytes.Buffer
+	msgp.Encode(&buf, &v)
+	b.SetBytes(int64(buf.Len()))
+	rd := msgp.NewEndlessReader(buf.Bytes(), b)
+	dc := msgp.NewReader(rd)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := v.DecodeMsg(dc)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestMarshalUnmarshalMessageGetProof(t *testing.T) {
+	v := MessageGetProof{}
+	bts, err := v.MarshalMsg(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	left, err := v.UnmarshalMsg(bts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(left) > 0 {
+		t.Errorf("%d bytes left over after UnmarshalMsg(): %q", len(left), left)
+	}
+
+	left, err = msgp.Skip(bts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(left) > 0 {
+		t.Errorf("%d bytes left over after Skip(): %q", len(left), left)
+	}
+}
+
+func BenchmarkMarshalMsgMessageGetProof(b *testing.B) {
+	v := MessageGetProof{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		v.MarshalMsg(nil)
+	}
+}
+
+func BenchmarkAppendMsgMessageGetProof(b *testing.B) {
+	v := MessageGetProof{}
+	bts := make([]byte, 0, v.Msgsize())
+	bts, _ = v.MarshalMsg(bts[0:0])
+	b.SetBytes(int64(len(bts)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bts, _ = v.MarshalMsg(bts[0:0])
+	}
+}
+
+func BenchmarkUnmarshalMessageGetProof(b *testing.B) {
+	v := MessageGetProof{}
+	bts, _ := v.MarshalMsg(nil)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(bts)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := v.UnmarshalMsg(bts)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestEncodeDecodeMessageGetProof(t *testing.T) {
+	v := MessageGetProof{}
+	var buf bytes.Buffer
+	msgp.Encode(&buf, &v)
+
+	m := v.Msgsize()
+	if buf.Len() > m {
+		t.Logf("WARNING: Msgsize() for %v is inaccurate", v)
+	}
+
+	vn := MessageGetProof{}
+	err := msgp.Decode(&buf, &vn)
+	if err != nil {
+		t.Error(err)
+	}
+
+	buf.Reset()
+	msgp.Encode(&buf, &v)
+	err = msgp.NewReader(&buf).Skip()
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func BenchmarkEncodeMessageGetProof(b *testing.B) {
+	v := MessageGetProof{}
+	var buf bytes.Buffer
+	msgp.Encode(&buf, &v)
+	b.SetBytes(int64(buf.Len()))
+	en := msgp.NewWriter(msgp.Nowhere)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		v.EncodeMsg(en)
+	}
+	en.Flush()
+}
+
+func BenchmarkDecodeMessageGetProof(b *testing.B) {
+	v := MessageGetProof{}
+	var buf bytes.Buffer
+	msgp.Encode(&buf, &v)
+	b.SetBytes(int64(buf.Len()))
+	rd := msgp.NewEndlessReader(buf.Bytes(), b)
+	dc := msgp.NewReader(rd)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := v.DecodeMsg(dc)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestMarshalUnmarshalMessageGetReceipts(t *testing.T) {
+	v := MessageGetReceipts{}
+	bts, err := v.MarshalMsg(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	left, err := v.UnmarshalMsg(bts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(left) > 0 {
+		t.Errorf("%d bytes left over after UnmarshalMsg(): %q", len(left), left)
+	}
+
+	left, err = msgp.Skip(bts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(left) > 0 {
+		t.Errorf("%d bytes left over after Skip(): %q", len(left), left)
+	}
+}
+
+func BenchmarkMarshalMsgMessageGetReceipts(b *testing.B) {
+	v := MessageGetReceipts{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		v.MarshalMsg(nil)
+	}
+}
+
+func BenchmarkAppendMsgMessageGetReceipts(b *testing.B) {
+	v := MessageGetReceipts{}
+	bts := make([]byte, 0, v.Msgsize())
+	bts, _ = v.MarshalMsg(bts[0:0])
+	b.SetBytes(int64(len(bts)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bts, _ = v.MarshalMsg(bts[0:0])
+	}
+}
+
+func BenchmarkUnmarshalMessageGetReceipts(b *testing.B) {
+	v := MessageGetReceipts{}
+	bts, _ := v.MarshalMsg(nil)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(bts)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := v.UnmarshalMsg(bts)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestEncodeDecodeMessageGetReceipts(t *testing.T) {
+	v := MessageGetReceipts{}
+	var buf bytes.Buffer
+	msgp.Encode(&buf, &v)
+
+	m := v.Msgsize()
+	if buf.Len() > m {
+		t.Logf("WARNING: Msgsize() for %v is inaccurate", v)
+	}
+
+	vn := MessageGetReceipts{}
+	err := msgp.Decode(&buf, &vn)
+	if err != nil {
+		t.Error(err)
+	}
+
+	buf.Reset()
+	msgp.Encode(&buf, &v)
+	err = msgp.NewReader(&buf).Skip()
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func BenchmarkEncodeMessageGetReceipts(b *testing.B) {
+	v := MessageGetReceipts{}
+	var buf bytes.Buffer
+	msgp.Encode(&buf, &v)
+	b.SetBytes(int64(buf.Len()))
+	en := msgp.NewWriter(msgp.Nowhere)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		v.EncodeMsg(en)
+	}
+	en.Flush()
+}
+
+func BenchmarkDecodeMessageGetReceipts(b *testing.B) {
+	v := MessageGetReceipts{}
+	var buf bytes.Buffer
+	msgp.Encode(&buf, &v)
+	b.SetBytes(int64(buf.Len()))
+	rd := msgp.NewEndlessReader(buf.Bytes(), b)
+	dc := msgp.NewReader(rd)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := v.DecodeMsg(dc)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
 func TestMarshalUnmarshalMessageHeaderRequest(t *testing.T) {
 	v := MessageHeaderRequest{}
 	bts, err := v.MarshalMsg(nil)
@@ -597,8 +936,347 @@ func TestMarshalUnmarshalMessageHeaderRequest(t *testing.T) {
 	}
 }
 
-func BenchmarkMarshalMsgMessageHeaderRequest(b *testing.B) {
-	v := MessageHeaderRequest{}
+func BenchmarkMarshalMsgMessageHeaderRequest(b *testing.B) {
+	v := MessageHeaderRequest{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		v.MarshalMsg(nil)
+	}
+}
+
+func BenchmarkAppendMsgMessageHeaderRequest(b *testing.B) {
+	v := MessageHeaderRequest{}
+	bts := make([]byte, 0, v.Msgsize())
+	bts, _ = v.MarshalMsg(bts[0:0])
+	b.SetBytes(int64(len(bts)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bts, _ = v.MarshalMsg(bts[0:0])
+	}
+}
+
+func BenchmarkUnmarshalMessageHeaderRequest(b *testing.B) {
+	v := MessageHeaderRequest{}
+	bts, _ := v.MarshalMsg(nil)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(bts)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := v.UnmarshalMsg(bts)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestEncodeDecodeMessageHeaderRequest(t *testing.T) {
+	v := MessageHeaderRequest{}
+	var buf bytes.Buffer
+	msgp.Encode(&buf, &v)
+
+	m := v.Msgsize()
+	if buf.Len() > m {
+		t.Logf("WARNING: Msgsize() for %v is inaccurate", v)
+	}
+
+	vn := MessageHeaderRequest{}
+	err := msgp.Decode(&buf, &vn)
+	if err != nil {
+		t.Error(err)
+	}
+
+	buf.Reset()
+	msgp.Encode(&buf, &v)
+	err = msgp.NewReader(&buf).Skip()
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func BenchmarkEncodeMessageHeaderRequest(b *testing.B) {
+	v := MessageHeaderRequest{}
+	var buf bytes.Buffer
+	msgp.Encode(&buf, &v)
+	b.SetBytes(int64(buf.Len()))
+	en := msgp.NewWriter(msgp.Nowhere)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		v.EncodeMsg(en)
+	}
+	en.Flush()
+}
+
+func BenchmarkDecodeMessageHeaderRequest(b *testing.B) {
+	v := MessageHeaderRequest{}
+	var buf bytes.Buffer
+	msgp.Encode(&buf, &v)
+	b.SetBytes(int64(buf.Len()))
+	rd := msgp.NewEndlessReader(buf.Bytes(), b)
+	dc := msgp.NewReader(rd)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := v.DecodeMsg(dc)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestMarshalUnmarshalMessageHeaderResponse(t *testing.T) {
+	v := MessageHeaderResponse{}
+	bts, err := v.MarshalMsg(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	left, err := v.UnmarshalMsg(bts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(left) > 0 {
+		t.Errorf("%d bytes left over after UnmarshalMsg(): %q", len(left), left)
+	}
+
+	left, err = msgp.Skip(bts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(left) > 0 {
+		t.Errorf("%d bytes left over after Skip(): %q", len(left), left)
+	}
+}
+
+func BenchmarkMarshalMsgMessageHeaderResponse(b *testing.B) {
+	v := MessageHeaderResponse{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		v.MarshalMsg(nil)
+	}
+}
+
+func BenchmarkAppendMsgMessageHeaderResponse(b *testing.B) {
+	v := MessageHeaderResponse{}
+	bts := make([]byte, 0, v.Msgsize())
+	bts, _ = v.MarshalMsg(bts[0:0])
+	b.SetBytes(int64(len(bts)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bts, _ = v.MarshalMsg(bts[0:0])
+	}
+}
+
+func BenchmarkUnmarshalMessageHeaderResponse(b *testing.B) {
+	v := MessageHeaderResponse{}
+	bts, _ := v.MarshalMsg(nil)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(bts)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := v.UnmarshalMsg(bts)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestEncodeDecodeMessageHeaderResponse(t *testing.T) {
+	v := MessageHeaderResponse{}
+	var buf bytes.Buffer
+	msgp.Encode(&buf, &v)
+
+	m := v.Msgsize()
+	if buf.Len() > m {
+		t.Logf("WARNING: Msgsize() for %v is inaccurate", v)
+	}
+
+	vn := MessageHeaderResponse{}
+	err := msgp.Decode(&buf, &vn)
+	if err != nil {
+		t.Error(err)
+	}
+
+	buf.Reset()
+	msgp.Encode(&buf, &v)
+	err = msgp.NewReader(&buf).Skip()
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func BenchmarkEncodeMessageHeaderResponse(b *testing.B) {
+	v := MessageHeaderResponse{}
+	var buf bytes.Buffer
+	msgp.Encode(&buf, &v)
+	b.SetBytes(int64(buf.Len()))
+	en := msgp.NewWriter(msgp.Nowhere)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		v.EncodeMsg(en)
+	}
+	en.Flush()
+}
+
+func BenchmarkDecodeMessageHeaderResponse(b *testing.B) {
+	v := MessageHeaderResponse{}
+	var buf bytes.Buffer
+	msgp.Encode(&buf, &v)
+	b.SetBytes(int64(buf.Len()))
+	rd := msgp.NewEndlessReader(buf.Bytes(), b)
+	dc := msgp.NewReader(rd)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := v.DecodeMsg(dc)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestMarshalUnmarshalMessageNewSequencer(t *testing.T) {
+	v := MessageNewSequencer{}
+	bts, err := v.MarshalMsg(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	left, err := v.UnmarshalMsg(bts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(left) > 0 {
+		t.Errorf("%d bytes left over after UnmarshalMsg(): %q", len(left), left)
+	}
+
+	left, err = msgp.Skip(bts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(left) > 0 {
+		t.Errorf("%d bytes left over after Skip(): %q", len(left), left)
+	}
+}
+
+func BenchmarkMarshalMsgMessageNewSequencer(b *testing.B) {
+	v := MessageNewSequencer{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		v.MarshalMsg(nil)
+	}
+}
+
+func BenchmarkAppendMsgMessageNewSequencer(b *testing.B) {
+	v := MessageNewSequencer{}
+	bts := make([]byte, 0, v.Msgsize())
+	bts, _ = v.MarshalMsg(bts[0:0])
+	b.SetBytes(int64(len(bts)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bts, _ = v.MarshalMsg(bts[0:0])
+	}
+}
+
+func BenchmarkUnmarshalMessageNewSequencer(b *testing.B) {
+	v := MessageNewSequencer{}
+	bts, _ := v.MarshalMsg(nil)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(bts)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := v.UnmarshalMsg(bts)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestEncodeDecodeMessageNewSequencer(t *testing.T) {
+	v := MessageNewSequencer{}
+	var buf bytes.Buffer
+	msgp.Encode(&buf, &v)
+
+	m := v.Msgsize()
+	if buf.Len() > m {
+		t.Logf("WARNING: Msgsize() for %v is inaccurate", v)
+	}
+
+	vn := MessageNewSequencer{}
+	err := msgp.Decode(&buf, &vn)
+	if err != nil {
+		t.Error(err)
+	}
+
+	buf.Reset()
+	msgp.Encode(&buf, &v)
+	err = msgp.NewReader(&buf).Skip()
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func BenchmarkEncodeMessageNewSequencer(b *testing.B) {
+	v := MessageNewSequencer{}
+	var buf bytes.Buffer
+	msgp.Encode(&buf, &v)
+	b.SetBytes(int64(buf.Len()))
+	en := msgp.NewWriter(msgp.Nowhere)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		v.EncodeMsg(en)
+	}
+	en.Flush()
+}
+
+func BenchmarkDecodeMessageNewSequencer(b *testing.B) {
+	v := MessageNewSequencer{}
+	var buf bytes.Buffer
+	msgp.Encode(&buf, &v)
+	b.SetBytes(int64(buf.Len()))
+	rd := msgp.NewEndlessReader(buf.Bytes(), b)
+	dc := msgp.NewReader(rd)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := v.DecodeMsg(dc)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestMarshalUnmarshalMessageNewTx(t *testing.T) {
+	v := MessageNewTx{}
+	bts, err := v.MarshalMsg(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	left, err := v.UnmarshalMsg(bts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(left) > 0 {
+		t.Errorf("%d bytes left over after UnmarshalMsg(): %q", len(left), left)
+	}
+
+	left, err = msgp.Skip(bts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(left) > 0 {
+		t.Errorf("%d bytes left over after Skip(): %q", len(left), left)
+	}
+}
+
+func BenchmarkMarshalMsgMessageNewTx(b *testing.B) {
+	v := MessageNewTx{}
 	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -606,8 +1284,8 @@ func BenchmarkMarshalMsgMessageHeaderRequest(b *testing.B) {
 	}
 }
 
-func BenchmarkAppendMsgMessageHeaderRequest(b *testing.B) {
-	v := MessageHeaderRequest{}
+func BenchmarkAppendMsgMessageNewTx(b *testing.B) {
+	v := MessageNewTx{}
 	bts := make([]byte, 0, v.Msgsize())
 	bts, _ = v.MarshalMsg(bts[0:0])
 	b.SetBytes(int64(len(bts)))
@@ -618,8 +1296,8 @@ func BenchmarkAppendMsgMessageHeaderRequest(b *testing.B) {
 	}
 }
 
-func BenchmarkUnmarshalMessageHeaderRequest(b *testing.B) {
-	v := MessageHeaderRequest{}
+func BenchmarkUnmarshalMessageNewTx(b *testing.B) {
+	v := MessageNewTx{}
 	bts, _ := v.MarshalMsg(nil)
 	b.ReportAllocs()
 	b.SetBytes(int64(len(bts)))
@@ -632,8 +1310,8 @@ func BenchmarkUnmarshalMessageHeaderRequest(b *testing.B) {
 	}
 }
 
-func TestEncodeDecodeMessageHeaderRequest(t *testing.T) {
-	v := MessageHeaderRequest{}
+func TestEncodeDecodeMessageNewTx(t *testing.T) {
+	v := MessageNewTx{}
 	var buf bytes.Buffer
 	msgp.Encode(&buf, &v)
 
@@ -642,7 +1320,7 @@ func TestEncodeDecodeMessageHeaderRequest(t *testing.T) {
 		t.Logf("WARNING: Msgsize() for %v is inaccurate", v)
 	}
 
-	vn := MessageHeaderRequest{}
+	vn := MessageNewTx{}
 	err := msgp.Decode(&buf, &vn)
 	if err != nil {
 		t.Error(err)
@@ -656,8 +1334,8 @@ func TestEncodeDecodeMessageHeaderRequest(t *testing.T) {
 	}
 }
 
-func BenchmarkEncodeMessageHeaderRequest(b *testing.B) {
-	v := MessageHeaderRequest{}
+func BenchmarkEncodeMessageNewTx(b *testing.B) {
+	v := MessageNewTx{}
 	var buf bytes.Buffer
 	msgp.Encode(&buf, &v)
 	b.SetBytes(int64(buf.Len()))
@@ -670,8 +1348,8 @@ func BenchmarkEncodeMessageHeaderRequest(b *testing.B) {
 	en.Flush()
 }
 
-func BenchmarkDecodeMessageHeaderRequest(b *testing.B) {
-	v := MessageHeaderRequest{}
+func BenchmarkDecodeMessageNewTx(b *testing.B) {
+	v := MessageNewTx{}
 	var buf bytes.Buffer
 	msgp.Encode(&buf, &v)
 	b.SetBytes(int64(buf.Len()))
@@ -687,8 +1365,8 @@ func BenchmarkDecodeMessageHeaderRequest(b *testing.B) {
 	}
 }
 
-func TestMarshalUnmarshalMessageHeaderResponse(t *testing.T) {
-	v := MessageHeaderResponse{}
+func TestMarshalUnmarshalMessageNewTxs(t *testing.T) {
+	v := MessageNewTxs{}
 	bts, err := v.MarshalMsg(nil)
 	if err != nil {
 		t.Fatal(err)
@@ -710,8 +1388,8 @@ func TestMarshalUnmarshalMessageHeaderResponse(t *testing.T) {
 	}
 }
 
-func BenchmarkMarshalMsgMessageHeaderResponse(b *testing.B) {
-	v := MessageHeaderResponse{}
+func BenchmarkMarshalMsgMessageNewTxs(b *testing.B) {
+	v := MessageNewTxs{}
 	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -719,8 +1397,8 @@ func BenchmarkMarshalMsgMessageHeaderResponse(b *testing.B) {
 	}
 }
 
-func BenchmarkAppendMsgMessageHeaderResponse(b *testing.B) {
-	v := MessageHeaderResponse{}
+func BenchmarkAppendMsgMessageNewTxs(b *testing.B) {
+	v := MessageNewTxs{}
 	bts := make([]byte, 0, v.Msgsize())
 	bts, _ = v.MarshalMsg(bts[0:0])
 	b.SetBytes(int64(len(bts)))
@@ -731,8 +1409,8 @@ func BenchmarkAppendMsgMessageHeaderResponse(b *testing.B) {
 	}
 }
 
-func BenchmarkUnmarshalMessageHeaderResponse(b *testing.B) {
-	v := MessageHeaderResponse{}
+func BenchmarkUnmarshalMessageNewTxs(b *testing.B) {
+	v := MessageNewTxs{}
 	bts, _ := v.MarshalMsg(nil)
 	b.ReportAllocs()
 	b.SetBytes(int64(len(bts)))
@@ -745,8 +1423,8 @@ func BenchmarkUnmarshalMessageHeaderResponse(b *testing.B) {
 	}
 }
 
-func TestEncodeDecodeMessageHeaderResponse(t *testing.T) {
-	v := MessageHeaderResponse{}
+func TestEncodeDecodeMessageNewTxs(t *testing.T) {
+	v := MessageNewTxs{}
 	var buf bytes.Buffer
 	msgp.Encode(&buf, &v)
 
@@ -755,7 +1433,7 @@ func TestEncodeDecodeMessageHeaderResponse(t *testing.T) {
 		t.Logf("WARNING: Msgsize() for %v is inaccurate", v)
 	}
 
-	vn := MessageHeaderResponse{}
+	vn := MessageNewTxs{}
 	err := msgp.Decode(&buf, &vn)
 	if err != nil {
 		t.Error(err)
@@ -769,8 +1447,8 @@ func TestEncodeDecodeMessageHeaderResponse(t *testing.T) {
 	}
 }
 
-func BenchmarkEncodeMessageHeaderResponse(b *testing.B) {
-	v := MessageHeaderResponse{}
+func BenchmarkEncodeMessageNewTxs(b *testing.B) {
+	v := MessageNewTxs{}
 	var buf bytes.Buffer
 	msgp.Encode(&buf, &v)
 	b.SetBytes(int64(buf.Len()))
@@ -783,8 +1461,8 @@ func BenchmarkEncodeMessageHeaderResponse(b *testing.B) {
 	en.Flush()
 }
 
-func BenchmarkDecodeMessageHeaderResponse(b *testing.B) {
-	v := MessageHeaderResponse{}
+func BenchmarkDecodeMessageNewTxs(b *testing.B) {
+	v := MessageNewTxs{}
 	var buf bytes.Buffer
 	msgp.Encode(&buf, &v)
 	b.SetBytes(int64(buf.Len()))
@@ -800,8 +1478,8 @@ func BenchmarkDecodeMessageHeaderResponse(b *testing.B) {
 	}
 }
 
-func TestMarshalUnmarshalMessageNewSequencer(t *testing.T) {
-	v := MessageNewSequencer{}
+func TestMarshalUnmarshalMessageNodeData(t *testing.T) {
+	v := MessageNodeData{}
 	bts, err := v.MarshalMsg(nil)
 	if err != nil {
 		t.Fatal(err)
@@ -823,8 +1501,8 @@ func TestMarshalUnmarshalMessageNewSequencer(t *testing.T) {
 	}
 }
 
-func BenchmarkMarshalMsgMessageNewSequencer(b *testing.B) {
-	v := MessageNewSequencer{}
+func BenchmarkMarshalMsgMessageNodeData(b *testing.B) {
+	v := MessageNodeData{}
 	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -832,8 +1510,8 @@ func BenchmarkMarshalMsgMessageNewSequencer(b *testing.B) {
 	}
 }
 
-func BenchmarkAppendMsgMessageNewSequencer(b *testing.B) {
-	v := MessageNewSequencer{}
+func BenchmarkAppendMsgMessageNodeData(b *testing.B) {
+	v := MessageNodeData{}
 	bts := make([]byte, 0, v.Msgsize())
 	bts, _ = v.MarshalMsg(bts[0:0])
 	b.SetBytes(int64(len(bts)))
@@ -844,8 +1522,8 @@ func BenchmarkAppendMsgMessageNewSequencer(b *testing.B) {
 	}
 }
 
-func BenchmarkUnmarshalMessageNewSequencer(b *testing.B) {
-	v := MessageNewSequencer{}
+func BenchmarkUnmarshalMessageNodeData(b *testing.B) {
+	v := MessageNodeData{}
 	bts, _ := v.MarshalMsg(nil)
 	b.ReportAllocs()
 	b.SetBytes(int64(len(bts)))
@@ -858,8 +1536,8 @@ func BenchmarkUnmarshalMessageNewSequencer(b *testing.B) {
 	}
 }
 
-func TestEncodeDecodeMessageNewSequencer(t *testing.T) {
-	v := MessageNewSequencer{}
+func TestEncodeDecodeMessageNodeData(t *testing.T) {
+	v := MessageNodeData{}
 	var buf bytes.Buffer
 	msgp.Encode(&buf, &v)
 
@@ -868,7 +1546,7 @@ func TestEncodeDecodeMessageNewSequencer(t *testing.T) {
 		t.Logf("WARNING: Msgsize() for %v is inaccurate", v)
 	}
 
-	vn := MessageNewSequencer{}
+	vn := MessageNodeData{}
 	err := msgp.Decode(&buf, &vn)
 	if err != nil {
 		t.Error(err)
@@ -882,8 +1560,8 @@ func TestEncodeDecodeMessageNewSequencer(t *testing.T) {
 	}
 }
 
-func BenchmarkEncodeMessageNewSequencer(b *testing.B) {
-	v := MessageNewSequencer{}
+func BenchmarkEncodeMessageNodeData(b *testing.B) {
+	v := MessageNodeData{}
 	var buf bytes.Buffer
 	msgp.Encode(&buf, &v)
 	b.SetBytes(int64(buf.Len()))
@@ -896,8 +1574,8 @@ func BenchmarkEncodeMessageNewSequencer(b *testing.B) {
 	en.Flush()
 }
 
-func BenchmarkDecodeMessageNewSequencer(b *testing.B) {
-	v := MessageNewSequencer{}
+func BenchmarkDecodeMessageNodeData(b *testing.B) {
+	v := MessageNodeData{}
 	var buf bytes.Buffer
 	msgp.Encode(&buf, &v)
 	b.SetBytes(int64(buf.Len()))
@@ -913,8 +1591,8 @@ func BenchmarkDecodeMessageNewSequencer(b *testing.B) {
 	}
 }
 
-func TestMarshalUnmarshalMessageNewTx(t *testing.T) {
-	v := MessageNewTx{}
+func TestMarshalUnmarshalMessagePing(t *testing.T) {
+	v := MessagePing{}
 	bts, err := v.MarshalMsg(nil)
 	if err != nil {
 		t.Fatal(err)
@@ -936,8 +1614,8 @@ func TestMarshalUnmarshalMessageNewTx(t *testing.T) {
 	}
 }
 
-func BenchmarkMarshalMsgMessageNewTx(b *testing.B) {
-	v := MessageNewTx{}
+func BenchmarkMarshalMsgMessagePing(b *testing.B) {
+	v := MessagePing{}
 	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -945,8 +1623,8 @@ func BenchmarkMarshalMsgMessageNewTx(b *testing.B) {
 	}
 }
 
-func BenchmarkAppendMsgMessageNewTx(b *testing.B) {
-	v := MessageNewTx{}
+func BenchmarkAppendMsgMessagePing(b *testing.B) {
+	v := MessagePing{}
 	bts := make([]byte, 0, v.Msgsize())
 	bts, _ = v.MarshalMsg(bts[0:0])
 	b.SetBytes(int64(len(bts)))
@@ -957,8 +1635,8 @@ func BenchmarkAppendMsgMessageNewTx(b *testing.B) {
 	}
 }
 
-func BenchmarkUnmarshalMessageNewTx(b *testing.B) {
-	v := MessageNewTx{}
+func BenchmarkUnmarshalMessagePing(b *testing.B) {
+	v := MessagePing{}
 	bts, _ := v.MarshalMsg(nil)
 	b.ReportAllocs()
 	b.SetBytes(int64(len(bts)))
@@ -971,8 +1649,8 @@ func BenchmarkUnmarshalMessageNewTx(b *testing.B) {
 	}
 }
 
-func TestEncodeDecodeMessageNewTx(t *testing.T) {
-	v := MessageNewTx{}
+func TestEncodeDecodeMessagePing(t *testing.T) {
+	v := MessagePing{}
 	var buf bytes.Buffer
 	msgp.Encode(&buf, &v)
 
@@ -981,7 +1659,7 @@ func TestEncodeDecodeMessageNewTx(t *testing.T) {
 		t.Logf("WARNING: Msgsize() for %v is inaccurate", v)
 	}
 
-	vn := MessageNewTx{}
+	vn := MessagePing{}
 	err := msgp.Decode(&buf, &vn)
 	if err != nil {
 		t.Error(err)
@@ -995,8 +1673,8 @@ func TestEncodeDecodeMessageNewTx(t *testing.T) {
 	}
 }
 
-func BenchmarkEncodeMessageNewTx(b *testing.B) {
-	v := MessageNewTx{}
+func BenchmarkEncodeMessagePing(b *testing.B) {
+	v := MessagePing{}
 	var buf bytes.Buffer
 	msgp.Encode(&buf, &v)
 	b.SetBytes(int64(buf.Len()))
@@ -1009,8 +1687,8 @@ func BenchmarkEncodeMessageNewTx(b *testing.B) {
 	en.Flush()
 }
 
-func BenchmarkDecodeMessageNewTx(b *testing.B) {
-	v := MessageNewTx{}
+func BenchmarkDecodeMessagePing(b *testing.B) {
+	v := MessagePing{}
 	var buf bytes.Buffer
 	msgp.Encode(&buf, &v)
 	b.SetBytes(int64(buf.Len()))
@@ -1026,8 +1704,8 @@ func BenchmarkDecodeMessageNewTx(b *testing.B) {
 	}
 }
 
-func TestMarshalUnmarshalMessageNewTxs(t *testing.T) {
-	v := MessageNewTxs{}
+func TestMarshalUnmarshalMessagePong(t *testing.T) {
+	v := MessagePong{}
 	bts, err := v.MarshalMsg(nil)
 	if err != nil {
 		t.Fatal(err)
@@ -1049,8 +1727,8 @@ func TestMarshalUnmarshalMessageNewTxs(t *testing.T) {
 	}
 }
 
-func BenchmarkMarshalMsgMessageNewTxs(b *testing.B) {
-	v := MessageNewTxs{}
+func BenchmarkMarshalMsgMessagePong(b *testing.B) {
+	v := MessagePong{}
 	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -1058,8 +1736,8 @@ func BenchmarkMarshalMsgMessageNewTxs(b *testing.B) {
 	}
 }
 
-func BenchmarkAppendMsgMessageNewTxs(b *testing.B) {
-	v := MessageNewTxs{}
+func BenchmarkAppendMsgMessagePong(b *testing.B) {
+	v := MessagePong{}
 	bts := make([]byte, 0, v.Msgsize())
 	bts, _ = v.MarshalMsg(bts[0:0])
 	b.SetBytes(int64(len(bts)))
@@ -1070,8 +1748,8 @@ func BenchmarkAppendMsgMessageNewTxs(b *testing.B) {
 	}
 }
 
-func BenchmarkUnmarshalMessageNewTxs(b *testing.B) {
-	v := MessageNewTxs{}
+func BenchmarkUnmarshalMessagePong(b *testing.B) {
+	v := MessagePong{}
 	bts, _ := v.MarshalMsg(nil)
 	b.ReportAllocs()
 	b.SetBytes(int64(len(bts)))
@@ -1084,8 +1762,8 @@ func BenchmarkUnmarshalMessageNewTxs(b *testing.B) {
 	}
 }
 
-func TestEncodeDecodeMessageNewTxs(t *testing.T) {
-	v := MessageNewTxs{}
+func TestEncodeDecodeMessagePong(t *testing.T) {
+	v := MessagePong{}
 	var buf bytes.Buffer
 	msgp.Encode(&buf, &v)
 
@@ -1094,7 +1772,7 @@ func TestEncodeDecodeMessageNewTxs(t *testing.T) {
 		t.Logf("WARNING: Msgsize() for %v is inaccurate", v)
 	}
 
-	vn := MessageNewTxs{}
+	vn := MessagePong{}
 	err := msgp.Decode(&buf, &vn)
 	if err != nil {
 		t.Error(err)
@@ -1108,8 +1786,8 @@ func TestEncodeDecodeMessageNewTxs(t *testing.T) {
 	}
 }
 
-func BenchmarkEncodeMessageNewTxs(b *testing.B) {
-	v := MessageNewTxs{}
+func BenchmarkEncodeMessagePong(b *testing.B) {
+	v := MessagePong{}
 	var buf bytes.Buffer
 	msgp.Encode(&buf, &v)
 	b.SetBytes(int64(buf.Len()))
@@ -1122,8 +1800,8 @@ func BenchmarkEncodeMessageNewTxs(b *testing.B) {
 	en.Flush()
 }
 
-func BenchmarkDecodeMessageNewTxs(b *testing.B) {
-	v := MessageNewTxs{}
+func BenchmarkDecodeMessagePong(b *testing.B) {
+	v := MessagePong{}
 	var buf bytes.Buffer
 	msgp.Encode(&buf, &v)
 	b.SetBytes(int64(buf.Len()))
@@ -1139,8 +1817,8 @@ func BenchmarkDecodeMessageNewTxs(b *testing.B) {
 	}
 }
 
-func TestMarshalUnmarshalMessagePing(t *testing.T) {
-	v := MessagePing{}
+func TestMarshalUnmarshalMessageProof(t *testing.T) {
+	v := MessageProof{}
 	bts, err := v.MarshalMsg(nil)
 	if err != nil {
 		t.Fatal(err)
@@ -1162,8 +1840,8 @@ func TestMarshalUnmarshalMessagePing(t *testing.T) {
 	}
 }
 
-func BenchmarkMarshalMsgMessagePing(b *testing.B) {
-	v := MessagePing{}
+func BenchmarkMarshalMsgMessageProof(b *testing.B) {
+	v := MessageProof{}
 	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -1171,8 +1849,8 @@ func BenchmarkMarshalMsgMessagePing(b *testing.B) {
 	}
 }
 
-func BenchmarkAppendMsgMessagePing(b *testing.B) {
-	v := MessagePing{}
+func BenchmarkAppendMsgMessageProof(b *testing.B) {
+	v := MessageProof{}
 	bts := make([]byte, 0, v.Msgsize())
 	bts, _ = v.MarshalMsg(bts[0:0])
 	b.SetBytes(int64(len(bts)))
@@ -1183,8 +1861,8 @@ func BenchmarkAppendMsgMessagePing(b *testing.B) {
 	}
 }
 
-func BenchmarkUnmarshalMessagePing(b *testing.B) {
-	v := MessagePing{}
+func BenchmarkUnmarshalMessageProof(b *testing.B) {
+	v := MessageProof{}
 	bts, _ := v.MarshalMsg(nil)
 	b.ReportAllocs()
 	b.SetBytes(int64(len(bts)))
@@ -1197,8 +1875,8 @@ func BenchmarkUnmarshalMessagePing(b *testing.B) {
 	}
 }
 
-func TestEncodeDecodeMessagePing(t *testing.T) {
-	v := MessagePing{}
+func TestEncodeDecodeMessageProof(t *testing.T) {
+	v := MessageProof{}
 	var buf bytes.Buffer
 	msgp.Encode(&buf, &v)
 
@@ -1207,7 +1885,7 @@ func TestEncodeDecodeMessagePing(t *testing.T) {
 		t.Logf("WARNING: Msgsize() for %v is inaccurate", v)
 	}
 
-	vn := MessagePing{}
+	vn := MessageProof{}
 	err := msgp.Decode(&buf, &vn)
 	if err != nil {
 		t.Error(err)
@@ -1221,8 +1899,8 @@ func TestEncodeDecodeMessagePing(t *testing.T) {
 	}
 }
 
-func BenchmarkEncodeMessagePing(b *testing.B) {
-	v := MessagePing{}
+func BenchmarkEncodeMessageProof(b *testing.B) {
+	v := MessageProof{}
 	var buf bytes.Buffer
 	msgp.Encode(&buf, &v)
 	b.SetBytes(int64(buf.Len()))
@@ -1235,8 +1913,8 @@ func BenchmarkEncodeMessagePing(b *testing.B) {
 	en.Flush()
 }
 
-func BenchmarkDecodeMessagePing(b *testing.B) {
-	v := MessagePing{}
+func BenchmarkDecodeMessageProof(b *testing.B) {
+	v := MessageProof{}
 	var buf bytes.Buffer
 	msgp.Encode(&buf, &v)
 	b.SetBytes(int64(buf.Len()))
@@ -1252,8 +1930,8 @@ func BenchmarkDecodeMessagePing(b *testing.B) {
 	}
 }
 
-func TestMarshalUnmarshalMessagePong(t *testing.T) {
-	v := MessagePong{}
+func TestMarshalUnmarshalMessageReceipts(t *testing.T) {
+	v := MessageReceipts{}
 	bts, err := v.MarshalMsg(nil)
 	if err != nil {
 		t.Fatal(err)
@@ -1275,8 +1953,8 @@ func TestMarshalUnmarshalMessagePong(t *testing.T) {
 	}
 }
 
-func BenchmarkMarshalMsgMessagePong(b *testing.B) {
-	v := MessagePong{}
+func BenchmarkMarshalMsgMessageReceipts(b *testing.B) {
+	v := MessageReceipts{}
 	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -1284,8 +1962,8 @@ func BenchmarkMarshalMsgMessagePong(b *testing.B) {
 	}
 }
 
-func BenchmarkAppendMsgMessagePong(b *testing.B) {
-	v := MessagePong{}
+func BenchmarkAppendMsgMessageReceipts(b *testing.B) {
+	v := MessageReceipts{}
 	bts := make([]byte, 0, v.Msgsize())
 	bts, _ = v.MarshalMsg(bts[0:0])
 	b.SetBytes(int64(len(bts)))
@@ -1296,8 +1974,8 @@ func BenchmarkAppendMsgMessagePong(b *testing.B) {
 	}
 }
 
-func BenchmarkUnmarshalMessagePong(b *testing.B) {
-	v := MessagePong{}
+func BenchmarkUnmarshalMessageReceipts(b *testing.B) {
+	v := MessageReceipts{}
 	bts, _ := v.MarshalMsg(nil)
 	b.ReportAllocs()
 	b.SetBytes(int64(len(bts)))
@@ -1310,8 +1988,8 @@ func BenchmarkUnmarshalMessagePong(b *testing.B) {
 	}
 }
 
-func TestEncodeDecodeMessagePong(t *testing.T) {
-	v := MessagePong{}
+func TestEncodeDecodeMessageReceipts(t *testing.T) {
+	v := MessageReceipts{}
 	var buf bytes.Buffer
 	msgp.Encode(&buf, &v)
 
@@ -1320,7 +1998,7 @@ func TestEncodeDecodeMessagePong(t *testing.T) {
 		t.Logf("WARNING: Msgsize() for %v is inaccurate", v)
 	}
 
-	vn := MessagePong{}
+	vn := MessageReceipts{}
 	err := msgp.Decode(&buf, &vn)
 	if err != nil {
 		t.Error(err)
@@ -1334,8 +2012,8 @@ func TestEncodeDecodeMessagePong(t *testing.T) {
 	}
 }
 
-func BenchmarkEncodeMessagePong(b *testing.B) {
-	v := MessagePong{}
+func BenchmarkEncodeMessageReceipts(b *testing.B) {
+	v := MessageReceipts{}
 	var buf bytes.Buffer
 	msgp.Encode(&buf, &v)
 	b.SetBytes(int64(buf.Len()))
@@ -1348,8 +2026,8 @@ func BenchmarkEncodeMessagePong(b *testing.B) {
 	en.Flush()
 }
 
-func BenchmarkDecodeMessagePong(b *testing.B) {
-	v := MessagePong{}
+func BenchmarkDecodeMessageReceipts(b *testing.B) {
+	v := MessageReceipts{}
 	var buf bytes.Buffer
 	msgp.Encode(&buf, &v)
 	b.SetBytes(int64(buf.Len()))
@@ -11,12 +11,17 @@ import (
 // compress data ,for p2p  , small size
 type RawTx struct {
 	TxBase
-	To    Address
-	Value *math.BigInt
+	To       Address
+	Value    *math.BigInt
+	GasLimit uint64
+	GasPrice *math.BigInt
 }
 
 type RawSequencer struct {
 	TxBase
+	Timestamp    int64
+	StateRoot    Hash
+	ReceiptsRoot Hash
 }
 
 type RawSequencers []*RawSequencer
@@ -28,9 +33,11 @@ func (t *RawTx) Tx() *Tx {
 		return nil
 	}
 	tx := &Tx{
-		TxBase: t.TxBase,
-		To:     t.To,
-		Value:  t.Value,
+		TxBase:   t.TxBase,
+		To:       t.To,
+		Value:    t.Value,
+		GasLimit: t.GasLimit,
+		GasPrice: t.GasPrice,
 	}
 	tx.From = Signer.AddressFromPubKeyBytes(tx.PublicKey)
 	return tx
@@ -41,7 +48,10 @@ func (t *RawSequencer) Sequencer() *Sequencer {
 		return nil
 	}
 	tx := &Sequencer{
-		TxBase: t.TxBase,
+		TxBase:       t.TxBase,
+		Timestamp:    t.Timestamp,
+		StateRoot:    t.StateRoot,
+		ReceiptsRoot: t.ReceiptsRoot,
 	}
 	tx.Issuer = Signer.AddressFromPubKeyBytes(tx.PublicKey)
 	return tx
@@ -109,8 +119,7 @@ func (seqs RawSequencers) ToHeaders() SequencerHeaders {
 	}
 	var headers SequencerHeaders
 	for _, v := range seqs {
-		head := NewSequencerHead(v.Hash, v.Height)
-		headers = append(headers, head)
+		headers = append(headers, v.Sequencer().GetHead())
 	}
 	return headers
 }
@@ -28,6 +28,21 @@ func (z *RawSequencer) DecodeMsg(dc *msgp.Reader) (err error) {
 			if err != nil {
 				return
 			}
+		case "Timestamp":
+			z.Timestamp, err = dc.ReadInt64()
+			if err != nil {
+				return
+			}
+		case "StateRoot":
+			err = z.StateRoot.DecodeMsg(dc)
+			if err != nil {
+				return
+			}
+		case "ReceiptsRoot":
+			err = z.ReceiptsRoot.DecodeMsg(dc)
+			if err != nil {
+				return
+			}
 		default:
 			err = dc.Skip()
 			if err != nil {
@@ -40,9 +55,9 @@ func (z *RawSequencer) DecodeMsg(dc *msgp.Reader) (err error) {
 
 // EncodeMsg implements msgp.Encodable
 func (z *RawSequencer) EncodeMsg(en *msgp.Writer) (err error) {
-	// map header, size 1
+	// map header, size 4
 	// write "TxBase"
-	err = en.Append(0x81, 0xa6, 0x54, 0x78, 0x42, 0x61, 0x73, 0x65)
+	err = en.Append(0x84, 0xa6, 0x54, 0x78, 0x42, 0x61, 0x73, 0x65)
 	if err != nil {
 		return
 	}
@@ -50,19 +65,61 @@ func (z *RawSequencer) EncodeMsg(en *msgp.Writer) (err error) {
 	if err != nil {
 		return
 	}
+	// write "Timestamp"
+	err = en.Append(0xa9, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70)
+	if err != nil {
+		return
+	}
+	err = en.WriteInt64(z.Timestamp)
+	if err != nil {
+		return
+	}
+	// write "StateRoot"
+	err = en.Append(0xa9, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x74)
+	if err != nil {
+		return
+	}
+	err = z.StateRoot.EncodeMsg(en)
+	if err != nil {
+		return
+	}
+	// write "ReceiptsRoot"
+	err = en.Append(0xac, 0x52, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x73, 0x52, 0x6f, 0x6f, 0x74)
+	if err != nil {
+		return
+	}
+	err = z.ReceiptsRoot.EncodeMsg(en)
+	if err != nil {
+		return
+	}
 	return
 }
 
 // MarshalMsg implements msgp.Marshaler
 func (z *RawSequencer) MarshalMsg(b []byte) (o []byte, err error) {
 	o = msgp.Require(b, z.Msgsize())
-	// map header, size 1
+	// map header, size 4
 	// string "TxBase"
-	o = append(o, 0x81, 0xa6, 0x54, 0x78, 0x42, 0x61, 0x73, 0x65)
+	o = append(o, 0x84, 0xa6, 0x54, 0x78, 0x42, 0x61, 0x73, 0x65)
 	o, err = z.TxBase.MarshalMsg(o)
 	if err != nil {
 		return
 	}
+	// string "Timestamp"
+	o = append(o, 0xa9, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70)
+	o = msgp.AppendInt64(o, z.Timestamp)
+	// string "StateRoot"
+	o = append(o, 0xa9, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x74)
+	o, err = z.StateRoot.MarshalMsg(o)
+	if err != nil {
+		return
+	}
+	// string "ReceiptsRoot"
+	o = append(o, 0xac, 0x52, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x73, 0x52, 0x6f, 0x6f, 0x74)
+	o, err = z.ReceiptsRoot.MarshalMsg(o)
+	if err != nil {
+		return
+	}
 	return
 }
 
@@ -87,6 +144,21 @@ func (z *RawSequencer) UnmarshalMsg(bts []byte) (o []byte, err error) {
 			if err != nil {
 				return
 			}
+		case "Timestamp":
+			z.Timestamp, bts, err = msgp.ReadInt64Bytes(bts)
+			if err != nil {
+				return
+			}
+		case "StateRoot":
+			bts, err = z.StateRoot.UnmarshalMsg(bts)
+			if err != nil {
+				return
+			}
+		case "ReceiptsRoot":
+			bts, err = z.ReceiptsRoot.UnmarshalMsg(bts)
+			if err != nil {
+				return
+			}
 		default:
 			bts, err = msgp.Skip(bts)
 			if err != nil {
@@ -100,7 +172,7 @@ func (z *RawSequencer) UnmarshalMsg(bts []byte) (o []byte, err error) {
 
 // Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
 func (z *RawSequencer) Msgsize() (s int) {
-	s = 1 + 7 + z.TxBase.Msgsize()
+	s = 1 + 7 + z.TxBase.Msgsize() + 10 + msgp.Int64Size + 10 + z.StateRoot.Msgsize() + 13 + z.ReceiptsRoot.Msgsize()
 	return
 }
 
@@ -146,6 +218,21 @@ func (z *RawSequencers) DecodeMsg(dc *msgp.Reader) (err error) {
 					if err != nil {
 						return
 					}
+				case "Timestamp":
+					(*z)[zb0001].Timestamp, err = dc.ReadInt64()
+					if err != nil {
+						return
+					}
+				case "StateRoot":
+					err = (*z)[zb0001].StateRoot.DecodeMsg(dc)
+					if err != nil {
+						return
+					}
+				case "ReceiptsRoot":
+					err = (*z)[zb0001].ReceiptsRoot.DecodeMsg(dc)
+					if err != nil {
+						return
+					}
 				default:
 					err = dc.Skip()
 					if err != nil {
@@ -171,9 +258,9 @@ func (z RawSequencers) EncodeMsg(en *msgp.Writer) (err error) {
 				return
 			}
 		} else {
-			// map header, size 1
+			// map header, size 4
 			// write "TxBase"
-			err = en.Append(0x81, 0xa6, 0x54, 0x78, 0x42, 0x61, 0x73, 0x65)
+			err = en.Append(0x84, 0xa6, 0x54, 0x78, 0x42, 0x61, 0x73, 0x65)
 			if err != nil {
 				return
 			}
@@ -181,6 +268,33 @@ func (z RawSequencers) EncodeMsg(en *msgp.Writer) (err error) {
 			if err != nil {
 				return
 			}
+			// write "Timestamp"
+			err = en.Append(0xa9, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70)
+			if err != nil {
+				return
+			}
+			err = en.WriteInt64(z[zb0004].Timestamp)
+			if err != nil {
+				return
+			}
+			// write "StateRoot"
+			err = en.Append(0xa9, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x74)
+			if err != nil {
+				return
+			}
+			err = z[zb0004].StateRoot.EncodeMsg(en)
+			if err != nil {
+				return
+			}
+			// write "ReceiptsRoot"
+			err = en.Append(0xac, 0x52, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x73, 0x52, 0x6f, 0x6f, 0x74)
+			if err != nil {
+				return
+			}
+			err = z[zb0004].ReceiptsRoot.EncodeMsg(en)
+			if err != nil {
+				return
+			}
 		}
 	}
 	return
@@ -194,13 +308,28 @@ func (z RawSequencers) MarshalMsg(b []byte) (o []byte, err error) {
 		if z[zb0004] == nil {
 			o = msgp.AppendNil(o)
 		} else {
-			// map header, size 1
+			// map header, size 4
 			// string "TxBase"
-			o = append(o, 0x81, 0xa6, 0x54, 0x78, 0x42, 0x61, 0x73, 0x65)
+			o = append(o, 0x84, 0xa6, 0x54, 0x78, 0x42, 0x61, 0x73, 0x65)
 			o, err = z[zb0004].TxBase.MarshalMsg(o)
 			if err != nil {
 				return
 			}
+			// string "Timestamp"
+			o = append(o, 0xa9, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70)
+			o = msgp.AppendInt64(o, z[zb0004].Timestamp)
+			// string "StateRoot"
+			o = append(o, 0xa9, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x74)
+			o, err = z[zb0004].StateRoot.MarshalMsg(o)
+			if err != nil {
+				return
+			}
+			// string "ReceiptsRoot"
+			o = append(o, 0xac, 0x52, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x73, 0x52, 0x6f, 0x6f, 0x74)
+			o, err = z[zb0004].ReceiptsRoot.MarshalMsg(o)
+			if err != nil {
+				return
+			}
 		}
 	}
 	return
@@ -248,6 +377,21 @@ func (z *RawSequencers) UnmarshalMsg(bts []byte) (o []byte, err error) {
 					if err != nil {
 						return
 					}
+				case "Timestamp":
+					(*z)[zb0001].Timestamp, bts, err = msgp.ReadInt64Bytes(bts)
+					if err != nil {
+						return
+					}
+				case "StateRoot":
+					bts, err = (*z)[zb0001].StateRoot.UnmarshalMsg(bts)
+					if err != nil {
+						return
+					}
+				case "ReceiptsRoot":
+					bts, err = (*z)[zb0001].ReceiptsRoot.UnmarshalMsg(bts)
+					if err != nil {
+						return
+					}
 				default:
 					bts, err = msgp.Skip(bts)
 					if err != nil {
@@ -268,7 +412,7 @@ func (z RawSequencers) Msgsize() (s int) {
 		if z[zb0004] == nil {
 			s += msgp.NilSize
 		} else {
-			s += 1 + 7 + z[zb0004].TxBase.Msgsize()
+			s += 1 + 7 + z[zb0004].TxBase.Msgsize() + 10 + msgp.Int64Size + 10 + z[zb0004].StateRoot.Msgsize() + 13 + z[zb0004].ReceiptsRoot.Msgsize()
 		}
 	}
 	return
@@ -316,6 +460,27 @@ func (z *RawTx) DecodeMsg(dc *msgp.Reader) (err error) {
 					return
 				}
 			}
+		case "GasLimit":
+			z.GasLimit, err = dc.ReadUint64()
+			if err != nil {
+				return
+			}
+		case "GasPrice":
+			if dc.IsNil() {
+				err = dc.ReadNil()
+				if err != nil {
+					return
+				}
+				z.GasPrice = nil
+			} else {
+				if z.GasPrice == nil {
+					z.GasPrice = new(math.BigInt)
+				}
+				err = z.GasPrice.DecodeMsg(dc)
+				if err != nil {
+					return
+				}
+			}
 		default:
 			err = dc.Skip()
 			if err != nil {
@@ -328,9 +493,9 @@ func (z *RawTx) DecodeMsg(dc *msgp.Reader) (err error) {
 
 // EncodeMsg implements msgp.Encodable
 func (z *RawTx) EncodeMsg(en *msgp.Writer) (err error) {
-	// map header, size 3
+	// map header, size 5
 	// write "TxBase"
-	err = en.Append(0x83, 0xa6, 0x54, 0x78, 0x42, 0x61, 0x73, 0x65)
+	err = en.Append(0x85, 0xa6, 0x54, 0x78, 0x42, 0x61, 0x73, 0x65)
 	if err != nil {
 		return
 	}
@@ -363,15 +528,40 @@ func (z *RawTx) EncodeMsg(en *msgp.Writer) (err error) {
 			return
 		}
 	}
+	// write "GasLimit"
+	err = en.Append(0xa8, 0x47, 0x61, 0x73, 0x4c, 0x69, 0x6d, 0x69, 0x74)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint64(z.GasLimit)
+	if err != nil {
+		return
+	}
+	// write "GasPrice"
+	err = en.Append(0xa8, 0x47, 0x61, 0x73, 0x50, 0x72, 0x69, 0x63, 0x65)
+	if err != nil {
+		return
+	}
+	if z.GasPrice == nil {
+		err = en.WriteNil()
+		if err != nil {
+			return
+		}
+	} else {
+		err = z.GasPrice.EncodeMsg(en)
+		if err != nil {
+			return
+		}
+	}
 	return
 }
 
 // MarshalMsg implements msgp.Marshaler
 func (z *RawTx) MarshalMsg(b []byte) (o []byte, err error) {
 	o = msgp.Require(b, z.Msgsize())
-	// map header, size 3
+	// map header, size 5
 	// string "TxBase"
-	o = append(o, 0x83, 0xa6, 0x54, 0x78, 0x42, 0x61, 0x73, 0x65)
+	o = append(o, 0x85, 0xa6, 0x54, 0x78, 0x42, 0x61, 0x73, 0x65)
 	o, err = z.TxBase.MarshalMsg(o)
 	if err != nil {
 		return
@@ -392,6 +582,19 @@ func (z *RawTx) MarshalMsg(b []byte) (o []byte, err error) {
 			return
 		}
 	}
+	// string "GasLimit"
+	o = append(o, 0xa8, 0x47, 0x61, 0x73, 0x4c, 0x69, 0x6d, 0x69, 0x74)
+	o = msgp.AppendUint64(o, z.GasLimit)
+	// string "GasPrice"
+	o = append(o, 0xa8, 0x47, 0x61, 0x73, 0x50, 0x72, 0x69, 0x63, 0x65)
+	if z.GasPrice == nil {
+		o = msgp.AppendNil(o)
+	} else {
+		o, err = z.GasPrice.MarshalMsg(o)
+		if err != nil {
+			return
+		}
+	}
 	return
 }
 
@@ -437,6 +640,27 @@ func (z *RawTx) UnmarshalMsg(bts []byte) (o []byte, err error) {
 					return
 				}
 			}
+		case "GasLimit":
+			z.GasLimit, bts, err = msgp.ReadUint64Bytes(bts)
+			if err != nil {
+				return
+			}
+		case "GasPrice":
+			if msgp.IsNil(bts) {
+				bts, err = msgp.ReadNilBytes(bts)
+				if err != nil {
+					return
+				}
+				z.GasPrice = nil
+			} else {
+				if z.GasPrice == nil {
+					z.GasPrice = new(math.BigInt)
+				}
+				bts, err = z.GasPrice.UnmarshalMsg(bts)
+				if err != nil {
+					return
+				}
+			}
 		default:
 			bts, err = msgp.Skip(bts)
 			if err != nil {
@@ -456,6 +680,14 @@ func (z *RawTx) Msgsize() (s int) {
 	} else {
 		s += z.Value.Msgsize()
 	}
+	s += 9
+	s += msgp.Uint64Size
+	s += 9
+	if z.GasPrice == nil {
+		s += msgp.NilSize
+	} else {
+		s += z.GasPrice.Msgsize()
+	}
 	return
 }
 
@@ -1,8 +1,6 @@
 package types
 
 import (
-	"bytes"
-	"encoding/binary"
 	"fmt"
 	"github.com/annchain/OG/common/hexutil"
 	"github.com/annchain/OG/common/math"
@@ -17,6 +15,16 @@ type Sequencer struct {
 	// TODO: need more states in sequencer to differentiate multiple chains
 	TxBase
 	Issuer Address
+	// Timestamp is the unix time in milliseconds when the sequencer is
+	// issued. Consensus uses it to decide the proposer slot.
+	Timestamp int64
+	// StateRoot is the root of the state trie after all the txs confirmed
+	// by this sequencer, and the sequencer itself, are executed.
+	StateRoot Hash
+	// ReceiptsRoot is the hash of the receipts of the txs confirmed by this
+	// sequencer and of the sequencer itself, in execution order. See
+	// core.ReceiptsRoot.
+	ReceiptsRoot Hash
 }
 
 func (t *Sequencer) String() string {
@@ -53,13 +61,14 @@ func RandomSequencer() *Sequencer {
 }
 
 func (t *Sequencer) SignatureTargets() []byte {
-	var buf bytes.Buffer
-
-	panicIfError(binary.Write(&buf, binary.BigEndian, t.AccountNonce))
-	panicIfError(binary.Write(&buf, binary.BigEndian, t.Issuer.Bytes))
-	panicIfError(binary.Write(&buf, binary.BigEndian, t.Height))
+	return t.GetHead().SignatureTargets()
+}
 
-	return buf.Bytes()
+// CalcTxHash hashes the signature targets besides the fields of TxBase,
+// so that the hash covers the state root and the timestamp even if the
+// signature is not a real one, as the one of genesis.
+func (t *Sequencer) CalcTxHash() (hash Hash) {
+	return t.GetHead().CalcHash()
 }
 
 func (t *Sequencer) Sender() Address {
@@ -95,7 +104,18 @@ func (t *Sequencer) GetBase() *TxBase {
 }
 
 func (t *Sequencer) GetHead() *SequencerHeader {
-	return NewSequencerHead(t.GetTxHash(), t.Height)
+	return &SequencerHeader{
+		Hash:         t.GetTxHash(),
+		BaseHash:     t.TxBase.CalcTxHash(),
+		Height:       t.Height,
+		Issuer:       t.Issuer,
+		AccountNonce: t.AccountNonce,
+		Timestamp:    t.Timestamp,
+		StateRoot:    t.StateRoot,
+		ReceiptsRoot: t.ReceiptsRoot,
+		PublicKey:    t.PublicKey,
+		Signature:    t.Signature,
+	}
 }
 
 func (t *Sequencer) Dump() string {
@@ -103,8 +123,8 @@ func (t *Sequencer) Dump() string {
 	for _, p := range t.ParentsHash {
 		phashes = append(phashes, p.Hex())
 	}
-	return fmt.Sprintf("pHash:[%s], Issuer : %s , Height :%d , nonce : %d , signatute : %s, pubkey %s",
-		strings.Join(phashes, " ,"), t.Issuer.Hex(), t.Height,
+	return fmt.Sprintf("pHash:[%s], Issuer : %s , Height :%d , Timestamp : %d , StateRoot : %s , ReceiptsRoot : %s , nonce : %d , signatute : %s, pubkey %s",
+		strings.Join(phashes, " ,"), t.Issuer.Hex(), t.Height, t.Timestamp, t.StateRoot.Hex(), t.ReceiptsRoot.Hex(),
 		t.AccountNonce, hexutil.Encode(t.Signature), hexutil.Encode(t.PublicKey))
 }
 
@@ -113,7 +133,10 @@ func (s *Sequencer) RawSequencer() *RawSequencer {
 		return nil
 	}
 	return &RawSequencer{
-		TxBase: s.TxBase,
+		TxBase:       s.TxBase,
+		Timestamp:    s.Timestamp,
+		StateRoot:    s.StateRoot,
+		ReceiptsRoot: s.ReceiptsRoot,
 	}
 }
 
@@ -131,8 +154,7 @@ func (s Sequencers) ToHeaders() SequencerHeaders {
 	}
 	var headers SequencerHeaders
 	for _, v := range s {
-		head := NewSequencerHead(v.Hash, v.Height)
-		headers = append(headers, head)
+		headers = append(headers, v.GetHead())
 	}
 	return headers
 }
@@ -32,6 +32,21 @@ func (z *Sequencer) DecodeMsg(dc *msgp.Reader) (err error) {
 			if err != nil {
 				return
 			}
+		case "Timestamp":
+			z.Timestamp, err = dc.ReadInt64()
+			if err != nil {
+				return
+			}
+		case "StateRoot":
+			err = z.StateRoot.DecodeMsg(dc)
+			if err != nil {
+				return
+			}
+		case "ReceiptsRoot":
+			err = z.ReceiptsRoot.DecodeMsg(dc)
+			if err != nil {
+				return
+			}
 		default:
 			err = dc.Skip()
 			if err != nil {
@@ -44,9 +59,9 @@ func (z *Sequencer) DecodeMsg(dc *msgp.Reader) (err error) {
 
 // EncodeMsg implements msgp.Encodable
 func (z *Sequencer) EncodeMsg(en *msgp.Writer) (err error) {
-	// map header, size 2
+	// map header, size 5
 	// write "TxBase"
-	err = en.Append(0x82, 0xa6, 0x54, 0x78, 0x42, 0x61, 0x73, 0x65)
+	err = en.Append(0x85, 0xa6, 0x54, 0x78, 0x42, 0x61, 0x73, 0x65)
 	if err != nil {
 		return
 	}
@@ -63,15 +78,42 @@ func (z *Sequencer) EncodeMsg(en *msgp.Writer) (err error) {
 	if err != nil {
 		return
 	}
+	// write "Timestamp"
+	err = en.Append(0xa9, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70)
+	if err != nil {
+		return
+	}
+	err = en.WriteInt64(z.Timestamp)
+	if err != nil {
+		return
+	}
+	// write "StateRoot"
+	err = en.Append(0xa9, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x74)
+	if err != nil {
+		return
+	}
+	err = z.StateRoot.EncodeMsg(en)
+	if err != nil {
+		return
+	}
+	// write "ReceiptsRoot"
+	err = en.Append(0xac, 0x52, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x73, 0x52, 0x6f, 0x6f, 0x74)
+	if err != nil {
+		return
+	}
+	err = z.ReceiptsRoot.EncodeMsg(en)
+	if err != nil {
+		return
+	}
 	return
 }
 
 // MarshalMsg implements msgp.Marshaler
 func (z *Sequencer) MarshalMsg(b []byte) (o []byte, err error) {
 	o = msgp.Require(b, z.Msgsize())
-	// map header, size 2
+	// map header, size 5
 	// string "TxBase"
-	o = append(o, 0x82, 0xa6, 0x54, 0x78, 0x42, 0x61, 0x73, 0x65)
+	o = append(o, 0x85, 0xa6, 0x54, 0x78, 0x42, 0x61, 0x73, 0x65)
 	o, err = z.TxBase.MarshalMsg(o)
 	if err != nil {
 		return
@@ -82,6 +124,21 @@ func (z *Sequencer) MarshalMsg(b []byte) (o []byte, err error) {
 	if err != nil {
 		return
 	}
+	// string "Timestamp"
+	o = append(o, 0xa9, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70)
+	o = msgp.AppendInt64(o, z.Timestamp)
+	// string "StateRoot"
+	o = append(o, 0xa9, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x74)
+	o, err = z.StateRoot.MarshalMsg(o)
+	if err != nil {
+		return
+	}
+	// string "ReceiptsRoot"
+	o = append(o, 0xac, 0x52, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x73, 0x52, 0x6f, 0x6f, 0x74)
+	o, err = z.ReceiptsRoot.MarshalMsg(o)
+	if err != nil {
+		return
+	}
 	return
 }
 
@@ -111,6 +168,21 @@ func (z *Sequencer) UnmarshalMsg(bts []byte) (o []byte, err error) {
 			if err != nil {
 				return
 			}
+		case "Timestamp":
+			z.Timestamp, bts, err = msgp.ReadInt64Bytes(bts)
+			if err != nil {
+				return
+			}
+		case "StateRoot":
+			bts, err = z.StateRoot.UnmarshalMsg(bts)
+			if err != nil {
+				return
+			}
+		case "ReceiptsRoot":
+			bts, err = z.ReceiptsRoot.UnmarshalMsg(bts)
+			if err != nil {
+				return
+			}
 		default:
 			bts, err = msgp.Skip(bts)
 			if err != nil {
@@ -124,7 +196,7 @@ func (z *Sequencer) UnmarshalMsg(bts []byte) (o []byte, err error) {
 
 // Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
 func (z *Sequencer) Msgsize() (s int) {
-	s = 1 + 7 + z.TxBase.Msgsize() + 7 + z.Issuer.Msgsize()
+	s = 1 + 7 + z.TxBase.Msgsize() + 7 + z.Issuer.Msgsize() + 10 + msgp.Int64Size + 10 + z.StateRoot.Msgsize() + 13 + z.ReceiptsRoot.Msgsize()
 	return
 }
 
@@ -175,6 +247,21 @@ func (z *Sequencers) DecodeMsg(dc *msgp.Reader) (err error) {
 					if err != nil {
 						return
 					}
+				case "Timestamp":
+					(*z)[zb0001].Timestamp, err = dc.ReadInt64()
+					if err != nil {
+						return
+					}
+				case "StateRoot":
+					err = (*z)[zb0001].StateRoot.DecodeMsg(dc)
+					if err != nil {
+						return
+					}
+				case "ReceiptsRoot":
+					err = (*z)[zb0001].ReceiptsRoot.DecodeMsg(dc)
+					if err != nil {
+						return
+					}
 				default:
 					err = dc.Skip()
 					if err != nil {
@@ -200,9 +287,9 @@ func (z Sequencers) EncodeMsg(en *msgp.Writer) (err error) {
 				return
 			}
 		} else {
-			// map header, size 2
+			// map header, size 5
 			// write "TxBase"
-			err = en.Append(0x82, 0xa6, 0x54, 0x78, 0x42, 0x61, 0x73, 0x65)
+			err = en.Append(0x85, 0xa6, 0x54, 0x78, 0x42, 0x61, 0x73, 0x65)
 			if err != nil {
 				return
 			}
@@ -219,6 +306,33 @@ func (z Sequencers) EncodeMsg(en *msgp.Writer) (err error) {
 			if err != nil {
 				return
 			}
+			// write "Timestamp"
+			err = en.Append(0xa9, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70)
+			if err != nil {
+				return
+			}
+			err = en.WriteInt64(z[zb0004].Timestamp)
+			if err != nil {
+				return
+			}
+			// write "StateRoot"
+			err = en.Append(0xa9, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x74)
+			if err != nil {
+				return
+			}
+			err = z[zb0004].StateRoot.EncodeMsg(en)
+			if err != nil {
+				return
+			}
+			// write "ReceiptsRoot"
+			err = en.Append(0xac, 0x52, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x73, 0x52, 0x6f, 0x6f, 0x74)
+			if err != nil {
+				return
+			}
+			err = z[zb0004].ReceiptsRoot.EncodeMsg(en)
+			if err != nil {
+				return
+			}
 		}
 	}
 	return
@@ -232,9 +346,9 @@ func (z Sequencers) MarshalMsg(b []byte) (o []byte, err error) {
 		if z[zb0004] == nil {
 			o = msgp.AppendNil(o)
 		} else {
-			// map header, size 2
+			// map header, size 5
 			// string "TxBase"
-			o = append(o, 0x82, 0xa6, 0x54, 0x78, 0x42, 0x61, 0x73, 0x65)
+			o = append(o, 0x85, 0xa6, 0x54, 0x78, 0x42, 0x61, 0x73, 0x65)
 			o, err = z[zb0004].TxBase.MarshalMsg(o)
 			if err != nil {
 				return
@@ -245,6 +359,21 @@ func (z Sequencers) MarshalMsg(b []byte) (o []byte, err error) {
 			if err != nil {
 				return
 			}
+			// string "Timestamp"
+			o = append(o, 0xa9, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70)
+			o = msgp.AppendInt64(o, z[zb0004].Timestamp)
+			// string "StateRoot"
+			o = append(o, 0xa9, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x74)
+			o, err = z[zb0004].StateRoot.MarshalMsg(o)
+			if err != nil {
+				return
+			}
+			// string "ReceiptsRoot"
+			o = append(o, 0xac, 0x52, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x73, 0x52, 0x6f, 0x6f, 0x74)
+			o, err = z[zb0004].ReceiptsRoot.MarshalMsg(o)
+			if err != nil {
+				return
+			}
 		}
 	}
 	return
@@ -297,6 +426,21 @@ func (z *Sequencers) UnmarshalMsg(bts []byte) (o []byte, err error) {
 					if err != nil {
 						return
 					}
+				case "Timestamp":
+					(*z)[zb0001].Timestamp, bts, err = msgp.ReadInt64Bytes(bts)
+					if err != nil {
+						return
+					}
+				case "StateRoot":
+					bts, err = (*z)[zb0001].StateRoot.UnmarshalMsg(bts)
+					if err != nil {
+						return
+					}
+				case "ReceiptsRoot":
+					bts, err = (*z)[zb0001].ReceiptsRoot.UnmarshalMsg(bts)
+					if err != nil {
+						return
+					}
 				default:
 					bts, err = msgp.Skip(bts)
 					if err != nil {
@@ -317,7 +461,7 @@ func (z Sequencers) Msgsize() (s int) {
 		if z[zb0004] == nil {
 			s += msgp.NilSize
 		} else {
-			s += 1 + 7 + z[zb0004].TxBase.Msgsize() + 7 + z[zb0004].Issuer.Msgsize()
+			s += 1 + 7 + z[zb0004].TxBase.Msgsize() + 7 + z[zb0004].Issuer.Msgsize() + 10 + msgp.Int64Size + 10 + z[zb0004].StateRoot.Msgsize() + 13 + z[zb0004].ReceiptsRoot.Msgsize()
 		}
 	}
 	return
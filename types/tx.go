@@ -23,6 +23,10 @@ type Tx struct {
 	To    Address
 	Value *math.BigInt
 	Data  []byte
+	// GasLimit is the max gas a contract tx may consume, and GasPrice is
+	// the price paid for each unit of gas to the sequencer issuer.
+	GasLimit uint64
+	GasPrice *math.BigInt
 }
 
 func (t *Tx) String() string {
@@ -91,10 +95,29 @@ func (t *Tx) SignatureTargets() []byte {
 	panicIfError(binary.Write(&buf, binary.BigEndian, t.To.Bytes))
 	panicIfError(binary.Write(&buf, binary.BigEndian, t.Value.GetSigBytes()))
 	panicIfError(binary.Write(&buf, binary.BigEndian, t.Data))
+	// sign the type of staking txs so that they can't be replayed as
+	// normal transfers.
+	if t.Type.IsStaking() {
+		panicIfError(binary.Write(&buf, binary.BigEndian, t.Type))
+	}
+	// gas fields are only signed when set, txs without gas keep the same
+	// signature targets as before.
+	if t.GasLimit != 0 || t.GetGasPrice().Value.Sign() != 0 {
+		panicIfError(binary.Write(&buf, binary.BigEndian, t.GasLimit))
+		panicIfError(binary.Write(&buf, binary.BigEndian, t.GetGasPrice().GetSigBytes()))
+	}
 
 	return buf.Bytes()
 }
 
+// GetGasPrice returns the gas price of tx, zero if not set.
+func (t *Tx) GetGasPrice() *math.BigInt {
+	if t.GasPrice == nil {
+		return math.NewBigInt(0)
+	}
+	return t.GasPrice
+}
+
 func (t *Tx) Sender() Address {
 	return t.From
 }
@@ -129,18 +152,21 @@ func (t *Tx) Dump() string {
 		phashes = append(phashes, p.Hex())
 	}
 	return fmt.Sprintf("hash %s pHash:[%s], from : %s , to :%s ,value : %s ,\n nonce : %d , signatute : %s, pubkey %s ,"+
-		"height %d ,mined Nonce %v type %v weight %d", t.Hash.Hex(),
+		"height %d ,mined Nonce %v type %v weight %d, gas limit %d, gas price %s", t.Hash.Hex(),
 		strings.Join(phashes, " ,"), t.From.Hex(), t.To.Hex(), t.Value,
-		t.AccountNonce, hexutil.Encode(t.Signature), hexutil.Encode(t.PublicKey), t.Height, t.MineNonce, t.Type, t.Weight)
+		t.AccountNonce, hexutil.Encode(t.Signature), hexutil.Encode(t.PublicKey), t.Height, t.MineNonce, t.Type, t.Weight,
+		t.GasLimit, t.GetGasPrice())
 }
 func (t *Tx) RawTx() *RawTx {
 	if t == nil {
 		return nil
 	}
 	rawTx := &RawTx{
-		TxBase: t.TxBase,
-		To:     t.To,
-		Value:  t.Value,
+		TxBase:   t.TxBase,
+		To:       t.To,
+		Value:    t.Value,
+		GasLimit: t.GasLimit,
+		GasPrice: t.GasPrice,
 	}
 	return rawTx
 }
@@ -14,8 +14,8 @@ func (z *Tx) DecodeMsg(dc *msgp.Reader) (err error) {
 	if err != nil {
 		return
 	}
-	if zb0001 != 5 {
-		err = msgp.ArrayError{Wanted: 5, Got: zb0001}
+	if zb0001 != 7 {
+		err = msgp.ArrayError{Wanted: 7, Got: zb0001}
 		return
 	}
 	err = z.TxBase.DecodeMsg(dc)
@@ -49,13 +49,32 @@ func (z *Tx) DecodeMsg(dc *msgp.Reader) (err error) {
 	if err != nil {
 		return
 	}
+	z.GasLimit, err = dc.ReadUint64()
+	if err != nil {
+		return
+	}
+	if dc.IsNil() {
+		err = dc.ReadNil()
+		if err != nil {
+			return
+		}
+		z.GasPrice = nil
+	} else {
+		if z.GasPrice == nil {
+			z.GasPrice = new(math.BigInt)
+		}
+		err = z.GasPrice.DecodeMsg(dc)
+		if err != nil {
+			return
+		}
+	}
 	return
 }
 
 // EncodeMsg implements msgp.Encodable
 func (z *Tx) EncodeMsg(en *msgp.Writer) (err error) {
-	// array header, size 5
-	err = en.Append(0x95)
+	// array header, size 7
+	err = en.Append(0x97)
 	if err != nil {
 		return
 	}
@@ -86,14 +105,29 @@ func (z *Tx) EncodeMsg(en *msgp.Writer) (err error) {
 	if err != nil {
 		return
 	}
+	err = en.WriteUint64(z.GasLimit)
+	if err != nil {
+		return
+	}
+	if z.GasPrice == nil {
+		err = en.WriteNil()
+		if err != nil {
+			return
+		}
+	} else {
+		err = z.GasPrice.EncodeMsg(en)
+		if err != nil {
+			return
+		}
+	}
 	return
 }
 
 // MarshalMsg implements msgp.Marshaler
 func (z *Tx) MarshalMsg(b []byte) (o []byte, err error) {
 	o = msgp.Require(b, z.Msgsize())
-	// array header, size 5
-	o = append(o, 0x95)
+	// array header, size 7
+	o = append(o, 0x97)
 	o, err = z.TxBase.MarshalMsg(o)
 	if err != nil {
 		return
@@ -115,6 +149,15 @@ func (z *Tx) MarshalMsg(b []byte) (o []byte, err error) {
 		}
 	}
 	o = msgp.AppendBytes(o, z.Data)
+	o = msgp.AppendUint64(o, z.GasLimit)
+	if z.GasPrice == nil {
+		o = msgp.AppendNil(o)
+	} else {
+		o, err = z.GasPrice.MarshalMsg(o)
+		if err != nil {
+			return
+		}
+	}
 	return
 }
 
@@ -125,8 +168,8 @@ func (z *Tx) UnmarshalMsg(bts []byte) (o []byte, err error) {
 	if err != nil {
 		return
 	}
-	if zb0001 != 5 {
-		err = msgp.ArrayError{Wanted: 5, Got: zb0001}
+	if zb0001 != 7 {
+		err = msgp.ArrayError{Wanted: 7, Got: zb0001}
 		return
 	}
 	bts, err = z.TxBase.UnmarshalMsg(bts)
@@ -160,6 +203,25 @@ func (z *Tx) UnmarshalMsg(bts []byte) (o []byte, err error) {
 	if err != nil {
 		return
 	}
+	z.GasLimit, bts, err = msgp.ReadUint64Bytes(bts)
+	if err != nil {
+		return
+	}
+	if msgp.IsNil(bts) {
+		bts, err = msgp.ReadNilBytes(bts)
+		if err != nil {
+			return
+		}
+		z.GasPrice = nil
+	} else {
+		if z.GasPrice == nil {
+			z.GasPrice = new(math.BigInt)
+		}
+		bts, err = z.GasPrice.UnmarshalMsg(bts)
+		if err != nil {
+			return
+		}
+	}
 	o = bts
 	return
 }
@@ -173,6 +235,12 @@ func (z *Tx) Msgsize() (s int) {
 		s += z.Value.Msgsize()
 	}
 	s += msgp.BytesPrefixSize + len(z.Data)
+	s += msgp.Uint64Size
+	if z.GasPrice == nil {
+		s += msgp.NilSize
+	} else {
+		s += z.GasPrice.Msgsize()
+	}
 	return
 }
 
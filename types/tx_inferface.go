@@ -17,6 +17,14 @@ type TxBaseType uint16
 const (
 	TxBaseTypeNormal TxBaseType = iota
 	TxBaseTypeSequencer
+	// TxBaseTypeCandidate registers the sender as a delegate candidate,
+	// Value is staked as the candidate's own vote.
+	TxBaseTypeCandidate
+	// TxBaseTypeVote stakes Value of the sender to the candidate in To.
+	TxBaseTypeVote
+	// TxBaseTypeUnvote withdraws all the stake the sender voted to the
+	// candidate in To. Value must be zero.
+	TxBaseTypeUnvote
 )
 
 func (t TxBaseType) String() string {
@@ -25,11 +33,40 @@ func (t TxBaseType) String() string {
 		return "TX"
 	case TxBaseTypeSequencer:
 		return "SQ"
+	case TxBaseTypeCandidate:
+		return "CD"
+	case TxBaseTypeVote:
+		return "VT"
+	case TxBaseTypeUnvote:
+		return "UV"
 	default:
 		return "NA"
 	}
 }
 
+// IsStaking returns true if t is one of the txs that changes the
+// candidates and votes of dpos consensus.
+func (t TxBaseType) IsStaking() bool {
+	return t == TxBaseTypeCandidate || t == TxBaseTypeVote || t == TxBaseTypeUnvote
+}
+
+// ParseTxBaseType converts the name of a tx type to TxBaseType. An empty
+// name means a normal tx.
+func ParseTxBaseType(name string) (TxBaseType, error) {
+	switch strings.ToLower(name) {
+	case "", "normal":
+		return TxBaseTypeNormal, nil
+	case "candidate":
+		return TxBaseTypeCandidate, nil
+	case "vote":
+		return TxBaseTypeVote, nil
+	case "unvote":
+		return TxBaseTypeUnvote, nil
+	default:
+		return TxBaseTypeNormal, fmt.Errorf("unknown tx type: %s", name)
+	}
+}
+
 // Here indicates what fields should be concerned during hash calculation and signature generation
 // |      |                   | Signature target |     NonceHash(Slow) |    TxHash(Fast) |
 // |------|-------------------|------------------|---------------------|-----------------|
@@ -39,6 +76,7 @@ func (t TxBaseType) String() string {
 // | Base | Signature         |                  |                   1 | 1 (nonce hash)  |
 // | Base | MinedNonce        |                  |                   1 | 1 (nonce hash)  |
 // | Base | AccountNonce      |                1 |                     |                 |
+// | Base | Type (staking tx) |                1 |                     |                 |
 // | Tx   | From              |                1 |                     |                 |
 // | Tx   | To                |                1 |                     |                 |
 // | Tx   | Value             |                1 |                     |                 |
@@ -0,0 +1,310 @@
+// Package abi encodes and decodes the calls, return data and event logs of
+// contracts in the solidity abi.
+package abi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/annchain/OG/common/crypto"
+	"github.com/annchain/OG/types"
+)
+
+// Argument is an input or output of a method or an event.
+type Argument struct {
+	Name    string
+	Type    Type
+	Indexed bool
+}
+
+// Arguments are the inputs or outputs of a method or an event.
+type Arguments []Argument
+
+// Types returns the types of args.
+func (args Arguments) Types() []Type {
+	ts := make([]Type, len(args))
+	for i, arg := range args {
+		ts[i] = arg.Type
+	}
+	return ts
+}
+
+// signature returns name with the types of args, like transfer(address,uint256).
+func (args Arguments) signature(name string) string {
+	names := make([]string, len(args))
+	for i, arg := range args {
+		names[i] = arg.Type.String()
+	}
+	return name + "(" + strings.Join(names, ",") + ")"
+}
+
+// Method is a function or the constructor of a contract.
+type Method struct {
+	Name    string
+	Inputs  Arguments
+	Outputs Arguments
+	// Constant tells whether the method doesn't change the state, which
+	// is view or pure.
+	Constant bool
+	Payable  bool
+}
+
+// Sig returns the signature of m, like transfer(address,uint256).
+func (m *Method) Sig() string {
+	return m.Inputs.signature(m.Name)
+}
+
+// ID returns the 4 bytes selector of m.
+func (m *Method) ID() []byte {
+	return crypto.Keccak256([]byte(m.Sig()))[:4]
+}
+
+// Event is an event of a contract.
+type Event struct {
+	Name      string
+	Anonymous bool
+	Inputs    Arguments
+}
+
+// Sig returns the signature of e, like Transfer(address,address,uint256).
+func (e *Event) Sig() string {
+	return e.Inputs.signature(e.Name)
+}
+
+// ID returns the first topic of the logs of e.
+func (e *Event) ID() types.Hash {
+	return crypto.Keccak256Hash([]byte(e.Sig()))
+}
+
+// ABI is the interface of a contract.
+type ABI struct {
+	// Constructor is nil if the contract declares none.
+	Constructor *Method
+	Methods     []*Method
+	Events      []*Event
+}
+
+type jsonArgument struct {
+	Name       string         `json:"name"`
+	Type       string         `json:"type"`
+	Indexed    bool           `json:"indexed"`
+	Components []jsonArgument `json:"components"`
+}
+
+type jsonEntry struct {
+	Type            string         `json:"type"`
+	Name            string         `json:"name"`
+	Inputs          []jsonArgument `json:"inputs"`
+	Outputs         []jsonArgument `json:"outputs"`
+	Anonymous       bool           `json:"anonymous"`
+	Constant        bool           `json:"constant"`
+	Payable         bool           `json:"payable"`
+	StateMutability string         `json:"stateMutability"`
+}
+
+// JSON reads the json abi generated by solc.
+func JSON(reader io.Reader) (*ABI, error) {
+	var entries []jsonEntry
+	if err := json.NewDecoder(reader).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("decode abi error: %v", err)
+	}
+	a := &ABI{}
+	for _, entry := range entries {
+		inputs, err := newArguments(entry.Inputs)
+		if err != nil {
+			return nil, fmt.Errorf("inputs of %s: %v", entry.Name, err)
+		}
+		switch entry.Type {
+		case "", "function", "constructor":
+			outputs, err := newArguments(entry.Outputs)
+			if err != nil {
+				return nil, fmt.Errorf("outputs of %s: %v", entry.Name, err)
+			}
+			m := &Method{
+				Name:     entry.Name,
+				Inputs:   inputs,
+				Outputs:  outputs,
+				Constant: entry.Constant || entry.StateMutability == "view" || entry.StateMutability == "pure",
+				Payable:  entry.Payable || entry.StateMutability == "payable",
+			}
+			if entry.Type == "constructor" {
+				a.Constructor = m
+			} else {
+				a.Methods = append(a.Methods, m)
+			}
+		case "event":
+			a.Events = append(a.Events, &Event{
+				Name:      entry.Name,
+				Anonymous: entry.Anonymous,
+				Inputs:    inputs,
+			})
+		}
+		// fallback, receive and error entries can't be encoded by name.
+	}
+	return a, nil
+}
+
+// ParseJSON is JSON of a json abi in data.
+func ParseJSON(data []byte) (*ABI, error) {
+	return JSON(bytes.NewReader(data))
+}
+
+func newArguments(jsonArgs []jsonArgument) (Arguments, error) {
+	args := make(Arguments, 0, len(jsonArgs))
+	for _, jsonArg := range jsonArgs {
+		components, err := newArguments(jsonArg.Components)
+		if err != nil {
+			return nil, err
+		}
+		t, err := NewType(jsonArg.Type, components)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, Argument{Name: jsonArg.Name, Type: t, Indexed: jsonArg.Indexed})
+	}
+	return args, nil
+}
+
+// Method returns the method of a name, or of a signature like
+// transfer(address,uint256) if the name is overloaded.
+func (a *ABI) Method(name string) (*Method, error) {
+	var found *Method
+	for _, m := range a.Methods {
+		if m.Sig() == name {
+			return m, nil
+		}
+		if m.Name != name {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("method %s is overloaded, tell it by the signature", name)
+		}
+		found = m
+	}
+	if found == nil {
+		return nil, fmt.Errorf("method %s not found", name)
+	}
+	return found, nil
+}
+
+// MethodByID returns the method of a 4 bytes selector.
+func (a *ABI) MethodByID(id []byte) (*Method, error) {
+	if len(id) < 4 {
+		return nil, fmt.Errorf("selector too short: %x", id)
+	}
+	for _, m := range a.Methods {
+		if bytes.Equal(m.ID(), id[:4]) {
+			return m, nil
+		}
+	}
+	return nil, fmt.Errorf("method of selector %x not found", id[:4])
+}
+
+// Event returns the event of a name, or of a signature if the name is
+// overloaded.
+func (a *ABI) Event(name string) (*Event, error) {
+	var found *Event
+	for _, e := range a.Events {
+		if e.Sig() == name {
+			return e, nil
+		}
+		if e.Name != name {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("event %s is overloaded, tell it by the signature", name)
+		}
+		found = e
+	}
+	if found == nil {
+		return nil, fmt.Errorf("event %s not found", name)
+	}
+	return found, nil
+}
+
+// EventByID returns the event whose logs have id as the first topic.
+func (a *ABI) EventByID(id types.Hash) (*Event, error) {
+	for _, e := range a.Events {
+		if !e.Anonymous && e.ID() == id {
+			return e, nil
+		}
+	}
+	return nil, fmt.Errorf("event of topic %s not found", id.Hex())
+}
+
+// Pack encodes a call of method with args, led by its selector. The
+// constructor args are encoded if method is empty, which are appended to
+// the bytecode to deploy a contract.
+func (a *ABI) Pack(method string, args ...interface{}) ([]byte, error) {
+	if method == "" {
+		if a.Constructor == nil {
+			if len(args) != 0 {
+				return nil, fmt.Errorf("no constructor takes %d args", len(args))
+			}
+			return nil, nil
+		}
+		return a.Constructor.Inputs.Pack(args...)
+	}
+	m, err := a.Method(method)
+	if err != nil {
+		return nil, err
+	}
+	data, err := m.Inputs.Pack(args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", m.Sig(), err)
+	}
+	return append(m.ID(), data...), nil
+}
+
+// PackJSON is Pack of args in a json array. Numbers can be json numbers or
+// strings in decimal or 0x hex, bytes are 0x hex strings and tuples are
+// arrays or objects of their fields.
+func (a *ABI) PackJSON(method string, args []byte) ([]byte, error) {
+	values, err := decodeJSONArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	return a.Pack(method, values...)
+}
+
+// Unpack decodes the return data of method.
+func (a *ABI) Unpack(method string, data []byte) ([]interface{}, error) {
+	m, err := a.Method(method)
+	if err != nil {
+		return nil, err
+	}
+	return m.Outputs.Unpack(data)
+}
+
+// UnpackLog decodes a log of an event of a, found by the first topic.
+func (a *ABI) UnpackLog(topics []types.Hash, data []byte) (*Event, []interface{}, error) {
+	if len(topics) == 0 {
+		return nil, nil, fmt.Errorf("log without topics")
+	}
+	e, err := a.EventByID(topics[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	values, err := e.Unpack(topics, data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %v", e.Sig(), err)
+	}
+	return e, values, nil
+}
+
+func decodeJSONArgs(args []byte) ([]interface{}, error) {
+	if len(bytes.TrimSpace(args)) == 0 {
+		return nil, nil
+	}
+	var values []interface{}
+	decoder := json.NewDecoder(bytes.NewReader(args))
+	// keep big numbers exact.
+	decoder.UseNumber()
+	if err := decoder.Decode(&values); err != nil {
+		return nil, fmt.Errorf("args are not a json array: %v", err)
+	}
+	return values, nil
+}
@@ -0,0 +1,298 @@
+package abi
+
+import (
+	"encoding/hex"
+	"math/big"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/annchain/OG/common/hexutil"
+	"github.com/annchain/OG/types"
+)
+
+const testABI = `[
+	{"type":"constructor","inputs":[{"name":"name","type":"string"},{"name":"supply","type":"uint256"}]},
+	{"type":"function","name":"baz","inputs":[{"name":"x","type":"uint32"},{"name":"y","type":"bool"}],"outputs":[{"name":"r","type":"bool"}],"stateMutability":"pure"},
+	{"type":"function","name":"sam","inputs":[{"name":"","type":"bytes"},{"name":"","type":"bool"},{"name":"","type":"uint256[]"}],"outputs":[]},
+	{"type":"function","name":"f","inputs":[{"name":"","type":"uint"},{"name":"","type":"uint32[]"},{"name":"","type":"bytes10"},{"name":"","type":"bytes"}],"outputs":[]},
+	{"type":"function","name":"g","inputs":[{"name":"","type":"uint256[][]"},{"name":"","type":"string[]"}],"outputs":[{"name":"","type":"uint256[][]"},{"name":"","type":"string[]"}]},
+	{"type":"function","name":"h","inputs":[{"name":"p","type":"tuple","components":[{"name":"id","type":"int64"},{"name":"owner","type":"address"},{"name":"tags","type":"string[2]"}]}],"outputs":[]},
+	{"type":"function","name":"over","inputs":[{"name":"","type":"uint8"}],"outputs":[]},
+	{"type":"function","name":"over","inputs":[{"name":"","type":"int8"}],"outputs":[]},
+	{"type":"event","name":"Transfer","inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}],"anonymous":false},
+	{"type":"event","name":"Named","inputs":[{"name":"name","type":"string","indexed":true},{"name":"memo","type":"string","indexed":false}],"anonymous":false}
+]`
+
+func mustParse(t *testing.T) *ABI {
+	a, err := ParseJSON([]byte(testABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func words(ws ...string) string {
+	return strings.Join(ws, "")
+}
+
+func TestPack(t *testing.T) {
+	a := mustParse(t)
+	// examples of the abi spec in the solidity docs.
+	cases := []struct {
+		method, args, expected string
+	}{
+		{"baz", `[69, true]`, words(
+			"cdcd77c0",
+			"0000000000000000000000000000000000000000000000000000000000000045",
+			"0000000000000000000000000000000000000000000000000000000000000001",
+		)},
+		{"sam", `["0x64617665", true, [1, 2, 3]]`, words(
+			"a5643bf2",
+			"0000000000000000000000000000000000000000000000000000000000000060",
+			"0000000000000000000000000000000000000000000000000000000000000001",
+			"00000000000000000000000000000000000000000000000000000000000000a0",
+			"0000000000000000000000000000000000000000000000000000000000000004",
+			"6461766500000000000000000000000000000000000000000000000000000000",
+			"0000000000000000000000000000000000000000000000000000000000000003",
+			"0000000000000000000000000000000000000000000000000000000000000001",
+			"0000000000000000000000000000000000000000000000000000000000000002",
+			"0000000000000000000000000000000000000000000000000000000000000003",
+		)},
+		{"f", `["0x123", ["0x456", "0x789"], "0x31323334353637383930", "0x48656c6c6f2c20776f726c6421"]`, words(
+			"8be65246",
+			"0000000000000000000000000000000000000000000000000000000000000123",
+			"0000000000000000000000000000000000000000000000000000000000000080",
+			"3132333435363738393000000000000000000000000000000000000000000000",
+			"00000000000000000000000000000000000000000000000000000000000000e0",
+			"0000000000000000000000000000000000000000000000000000000000000002",
+			"0000000000000000000000000000000000000000000000000000000000000456",
+			"0000000000000000000000000000000000000000000000000000000000000789",
+			"000000000000000000000000000000000000000000000000000000000000000d",
+			"48656c6c6f2c20776f726c642100000000000000000000000000000000000000",
+		)},
+		{"g", `[[[1, 2], [3]], ["one", "two", "three"]]`, words(
+			"2289b18c",
+			"0000000000000000000000000000000000000000000000000000000000000040",
+			"0000000000000000000000000000000000000000000000000000000000000140",
+			"0000000000000000000000000000000000000000000000000000000000000002",
+			"0000000000000000000000000000000000000000000000000000000000000040",
+			"00000000000000000000000000000000000000000000000000000000000000a0",
+			"0000000000000000000000000000000000000000000000000000000000000002",
+			"0000000000000000000000000000000000000000000000000000000000000001",
+			"0000000000000000000000000000000000000000000000000000000000000002",
+			"0000000000000000000000000000000000000000000000000000000000000001",
+			"0000000000000000000000000000000000000000000000000000000000000003",
+			"0000000000000000000000000000000000000000000000000000000000000003",
+			"0000000000000000000000000000000000000000000000000000000000000060",
+			"00000000000000000000000000000000000000000000000000000000000000a0",
+			"00000000000000000000000000000000000000000000000000000000000000e0",
+			"0000000000000000000000000000000000000000000000000000000000000003",
+			"6f6e650000000000000000000000000000000000000000000000000000000000",
+			"0000000000000000000000000000000000000000000000000000000000000003",
+			"74776f0000000000000000000000000000000000000000000000000000000000",
+			"0000000000000000000000000000000000000000000000000000000000000005",
+			"7468726565000000000000000000000000000000000000000000000000000000",
+		)},
+		{"over(int8)", `[-1]`, words(
+			"938f5c2f",
+			"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+		)},
+	}
+	for _, c := range cases {
+		data, err := a.PackJSON(c.method, []byte(c.args))
+		if err != nil {
+			t.Fatalf("pack %s error: %v", c.method, err)
+		}
+		if hex.EncodeToString(data) != c.expected {
+			t.Fatalf("pack %s:\nexpected %s\ngot      %x", c.method, c.expected, data)
+		}
+	}
+
+	// go values encode the same as json.
+	data, err := a.Pack("baz", uint32(69), true)
+	if err != nil || hex.EncodeToString(data) != cases[0].expected {
+		t.Fatalf("pack baz of go values: %x, %v", data, err)
+	}
+	ctor, err := a.PackJSON("", []byte(`["og", "1000"]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ctor) != 4*32 {
+		t.Fatalf("unexpected constructor args %x", ctor)
+	}
+
+	for _, bad := range []struct{ method, args string }{
+		{"baz", `[4294967296, true]`},
+		{"baz", `[1]`},
+		{"over", `[1]`},
+		{"over(int8)", `[128]`},
+		{"f", `[1, [], "0x3132", "0x"]`},
+		{"h", `[{"id": 1, "owner": "0x01"}]`},
+		{"nope", `[]`},
+	} {
+		if _, err := a.PackJSON(bad.method, []byte(bad.args)); err == nil {
+			t.Fatalf("expected error packing %s %s", bad.method, bad.args)
+		}
+	}
+}
+
+func TestUnpack(t *testing.T) {
+	a := mustParse(t)
+	g, _ := a.Method("g")
+	args := `[[[1, 2], [3]], ["one", "two", "three"]]`
+	data, err := a.PackJSON("g", []byte(args))
+	if err != nil {
+		t.Fatal(err)
+	}
+	values, err := a.Unpack("g", data[4:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []interface{}{
+		[]interface{}{
+			[]interface{}{big.NewInt(1), big.NewInt(2)},
+			[]interface{}{big.NewInt(3)},
+		},
+		[]interface{}{"one", "two", "three"},
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Fatalf("unpack %s: expected %v, got %v", g.Sig(), expected, values)
+	}
+
+	// tuples round trip, given as objects or arrays.
+	owner := types.HexToAddress("0x643d534e15a315173a3c18cd13c9f95c7484a9bc")
+	h, _ := a.Method("h")
+	data, err = a.PackJSON("h", []byte(`[{"id": -7, "owner": "0x643d534e15a315173a3c18cd13c9f95c7484a9bc", "tags": ["a", "b"]}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	values, err = h.Inputs.Unpack(data[4:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = []interface{}{[]interface{}{big.NewInt(-7), owner, []interface{}{"a", "b"}}}
+	if !reflect.DeepEqual(values, expected) {
+		t.Fatalf("unpack %s: expected %v, got %v", h.Sig(), expected, values)
+	}
+	again, err := a.Pack("h", values...)
+	if err != nil || hex.EncodeToString(again) != hex.EncodeToString(data) {
+		t.Fatalf("repack %s: %x, %v", h.Sig(), again, err)
+	}
+
+	baz, _ := a.Method("baz")
+	if !baz.Constant {
+		t.Fatal("pure method baz should be constant")
+	}
+	if _, err := baz.Outputs.Unpack(make([]byte, 16)); err == nil {
+		t.Fatal("expected error unpacking short data")
+	}
+	if _, err := baz.Outputs.Unpack(append(make([]byte, 31), 2)); err == nil {
+		t.Fatal("expected error unpacking invalid bool")
+	}
+	// an offset out of the data.
+	bad := make([]byte, 64)
+	bad[31] = 0xff
+	if _, err := g.Outputs.Unpack(bad); err == nil {
+		t.Fatal("expected error unpacking bad offset")
+	}
+
+	reason := hexutil.MustDecode("0x08c379a0" + words(
+		"0000000000000000000000000000000000000000000000000000000000000020",
+		"000000000000000000000000000000000000000000000000000000000000000d",
+		"6e6f7420746865206f776e657200000000000000000000000000000000000000",
+	))
+	if msg, err := UnpackRevert(reason); err != nil || msg != "not the owner" {
+		t.Fatalf("unexpected revert reason %q, %v", msg, err)
+	}
+}
+
+func TestUnpackLog(t *testing.T) {
+	a := mustParse(t)
+	transfer, _ := a.Event("Transfer")
+	if transfer.ID().Hex() != "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef" {
+		t.Fatalf("unexpected event id %s", transfer.ID().Hex())
+	}
+	from := types.HexToAddress("0x643d534e15a315173a3c18cd13c9f95c7484a9bc")
+	to := types.HexToAddress("0x9858effd232b4033e47d90003d41ec34ecaeda94")
+	topics := []types.Hash{
+		transfer.ID(),
+		types.BytesToHash(from.Bytes[:]),
+		types.BytesToHash(to.Bytes[:]),
+	}
+	data, _ := Arguments{{Type: Type{Kind: UintTy, Size: 256, str: "uint256"}}}.Pack(1000)
+	e, values, err := a.UnpackLog(topics, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e != transfer || !reflect.DeepEqual(values, []interface{}{from, to, big.NewInt(1000)}) {
+		t.Fatalf("unexpected log %s %v", e.Sig(), values)
+	}
+
+	// an indexed string is its hash in the topic.
+	named, _ := a.Event("Named")
+	nameHash := types.HexToHash("0x01")
+	data, _ = Arguments{{Type: Type{Kind: StringTy, str: "string"}}}.Pack("hi")
+	values, err = named.Unpack([]types.Hash{named.ID(), nameHash}, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(values, []interface{}{nameHash, "hi"}) {
+		t.Fatalf("unexpected log %v", values)
+	}
+
+	if _, _, err := a.UnpackLog(topics[:2], data); err == nil {
+		t.Fatal("expected error unpacking log missing a topic")
+	}
+	if _, _, err := a.UnpackLog([]types.Hash{types.HexToHash("0x02")}, nil); err == nil {
+		t.Fatal("expected error unpacking log of unknown event")
+	}
+}
+
+func TestParseSolcABI(t *testing.T) {
+	f, err := os.Open("../vm_test/contracts/o/TokenERC20.abi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	a, err := JSON(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Constructor == nil || len(a.Constructor.Inputs) != 3 {
+		t.Fatal("constructor of TokenERC20 not parsed")
+	}
+	transfer, err := a.Method("transfer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(transfer.ID()) != "a9059cbb" {
+		t.Fatalf("unexpected selector %x of %s", transfer.ID(), transfer.Sig())
+	}
+	if m, err := a.MethodByID(transfer.ID()); err != nil || m != transfer {
+		t.Fatalf("method by id: %v", err)
+	}
+}
+
+func TestNewType(t *testing.T) {
+	for s, expected := range map[string]string{
+		"uint":         "uint256",
+		"int[]":        "int256[]",
+		"bytes32[2][]": "bytes32[2][]",
+		"address[3]":   "address[3]",
+	} {
+		typ, err := NewType(s, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if typ.String() != expected {
+			t.Fatalf("expected %s, got %s", expected, typ)
+		}
+	}
+	for _, bad := range []string{"uint7", "uint264", "bytes33", "bytes0", "fixed128x18", "tuple", "uint[0]", "uint]"} {
+		if _, err := NewType(bad, nil); err == nil {
+			t.Fatalf("expected error parsing %q", bad)
+		}
+	}
+}
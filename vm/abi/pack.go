@@ -0,0 +1,303 @@
+package abi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/annchain/OG/common/hexutil"
+	"github.com/annchain/OG/common/math"
+	"github.com/annchain/OG/types"
+)
+
+var (
+	tt256   = new(big.Int).Lsh(big.NewInt(1), 256)
+	typeOfB = reflect.TypeOf(byte(0))
+)
+
+// Pack encodes values of args as a tuple.
+func (args Arguments) Pack(values ...interface{}) ([]byte, error) {
+	if len(values) != len(args) {
+		return nil, fmt.Errorf("expected %d args, got %d", len(args), len(values))
+	}
+	return packTuple(args.Types(), values)
+}
+
+func packTuple(ts []Type, values []interface{}) ([]byte, error) {
+	headSize := 0
+	for _, t := range ts {
+		headSize += t.headSize()
+	}
+	var head, tail []byte
+	for i, t := range ts {
+		enc, err := pack(t, values[i])
+		if err != nil {
+			return nil, fmt.Errorf("arg %d: %v", i, err)
+		}
+		if t.isDynamic() {
+			head = append(head, packUint(uint64(headSize+len(tail)))...)
+			tail = append(tail, enc...)
+		} else {
+			head = append(head, enc...)
+		}
+	}
+	return append(head, tail...), nil
+}
+
+func pack(t Type, v interface{}) ([]byte, error) {
+	switch t.Kind {
+	case IntTy, UintTy:
+		n, err := toBig(v)
+		if err != nil {
+			return nil, err
+		}
+		return packInt(t, n)
+	case BoolTy:
+		b, err := toBool(v)
+		if err != nil {
+			return nil, err
+		}
+		if b {
+			return packUint(1), nil
+		}
+		return packUint(0), nil
+	case AddressTy:
+		addr, err := toAddress(v)
+		if err != nil {
+			return nil, err
+		}
+		return leftPad(addr.Bytes[:]), nil
+	case FixedBytesTy:
+		b, err := toBytes(v)
+		if err != nil {
+			return nil, err
+		}
+		if len(b) != t.Size {
+			return nil, fmt.Errorf("expected %d bytes for %s, got %d", t.Size, t, len(b))
+		}
+		return rightPad(b), nil
+	case BytesTy:
+		b, err := toBytes(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(packUint(uint64(len(b))), rightPad(b)...), nil
+	case StringTy:
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected a string, got %T", v)
+		}
+		return append(packUint(uint64(len(s))), rightPad([]byte(s))...), nil
+	case SliceTy:
+		list, err := toList(v)
+		if err != nil {
+			return nil, err
+		}
+		data, err := packTuple(repeat(*t.Elem, len(list)), list)
+		if err != nil {
+			return nil, err
+		}
+		return append(packUint(uint64(len(list))), data...), nil
+	case ArrayTy:
+		list, err := toList(v)
+		if err != nil {
+			return nil, err
+		}
+		if len(list) != t.Size {
+			return nil, fmt.Errorf("expected %d elements for %s, got %d", t.Size, t, len(list))
+		}
+		return packTuple(repeat(*t.Elem, t.Size), list)
+	case TupleTy:
+		fields, err := toFields(t.Components, v)
+		if err != nil {
+			return nil, err
+		}
+		return packTuple(t.Components.Types(), fields)
+	}
+	return nil, fmt.Errorf("unknown type %s", t)
+}
+
+func packUint(n uint64) []byte {
+	return leftPad(new(big.Int).SetUint64(n).Bytes())
+}
+
+// packInt encodes n in 32 bytes, in two's complement if it's negative.
+func packInt(t Type, n *big.Int) ([]byte, error) {
+	if t.Kind == UintTy {
+		if n.Sign() < 0 || n.BitLen() > t.Size {
+			return nil, fmt.Errorf("%s overflows %s", n, t)
+		}
+		return leftPad(n.Bytes()), nil
+	}
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(t.Size-1))
+	if n.Cmp(limit) >= 0 || n.Cmp(new(big.Int).Neg(limit)) < 0 {
+		return nil, fmt.Errorf("%s overflows %s", n, t)
+	}
+	if n.Sign() < 0 {
+		n = new(big.Int).Add(n, tt256)
+	}
+	return leftPad(n.Bytes()), nil
+}
+
+func leftPad(b []byte) []byte {
+	padded := make([]byte, 32)
+	copy(padded[32-len(b):], b)
+	return padded
+}
+
+// rightPad pads b to a multiple of 32 bytes.
+func rightPad(b []byte) []byte {
+	padded := make([]byte, (len(b)+31)/32*32)
+	copy(padded, b)
+	return padded
+}
+
+func toBig(v interface{}) (*big.Int, error) {
+	switch n := v.(type) {
+	case *big.Int:
+		return n, nil
+	case big.Int:
+		return &n, nil
+	case *math.BigInt:
+		return n.Value, nil
+	case json.Number:
+		return parseBig(string(n))
+	case string:
+		return parseBig(n)
+	case float64:
+		// json numbers decoded without UseNumber.
+		if n != float64(int64(n)) {
+			return nil, fmt.Errorf("%v is not an integer", n)
+		}
+		return big.NewInt(int64(n)), nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Int).SetUint64(rv.Uint()), nil
+	}
+	return nil, fmt.Errorf("expected an integer, got %T", v)
+}
+
+// parseBig parses s in decimal or 0x hex.
+func parseBig(s string) (*big.Int, error) {
+	s = strings.TrimSpace(s)
+	n, ok := new(big.Int), false
+	switch {
+	case strings.HasPrefix(s, "0x"), strings.HasPrefix(s, "0X"):
+		n, ok = n.SetString(s[2:], 16)
+	case strings.HasPrefix(s, "-0x"), strings.HasPrefix(s, "-0X"):
+		if n, ok = n.SetString(s[3:], 16); ok {
+			n.Neg(n)
+		}
+	default:
+		n, ok = n.SetString(s, 10)
+	}
+	if !ok {
+		return nil, fmt.Errorf("invalid integer %q", s)
+	}
+	return n, nil
+}
+
+func toBool(v interface{}) (bool, error) {
+	switch b := v.(type) {
+	case bool:
+		return b, nil
+	case string:
+		return strconv.ParseBool(b)
+	}
+	return false, fmt.Errorf("expected a bool, got %T", v)
+}
+
+func toAddress(v interface{}) (types.Address, error) {
+	switch addr := v.(type) {
+	case types.Address:
+		return addr, nil
+	case *types.Address:
+		return *addr, nil
+	case string:
+		b, err := hexutil.Decode(addr)
+		if err != nil {
+			return types.Address{}, fmt.Errorf("invalid address %q: %v", addr, err)
+		}
+		if len(b) != types.AddressLength {
+			return types.Address{}, fmt.Errorf("invalid address %q", addr)
+		}
+		return types.BytesToAddress(b), nil
+	}
+	b, err := toBytes(v)
+	if err != nil || len(b) != types.AddressLength {
+		return types.Address{}, fmt.Errorf("expected an address, got %T", v)
+	}
+	return types.BytesToAddress(b), nil
+}
+
+// toBytes converts 0x hex strings, byte slices and byte arrays to bytes.
+func toBytes(v interface{}) ([]byte, error) {
+	switch b := v.(type) {
+	case []byte:
+		return b, nil
+	case hexutil.Bytes:
+		return b, nil
+	case types.Hash:
+		return b.Bytes[:], nil
+	case string:
+		data, err := hexutil.Decode(b)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bytes %q: %v", b, err)
+		}
+		return data, nil
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Array && rv.Type().Elem() == typeOfB {
+		data := make([]byte, rv.Len())
+		reflect.Copy(reflect.ValueOf(data), rv)
+		return data, nil
+	}
+	return nil, fmt.Errorf("expected bytes, got %T", v)
+}
+
+// toList converts slices and arrays to a list of their elements.
+func toList(v interface{}) ([]interface{}, error) {
+	if list, ok := v.([]interface{}); ok {
+		return list, nil
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("expected an array, got %T", v)
+	}
+	list := make([]interface{}, rv.Len())
+	for i := range list {
+		list[i] = rv.Index(i).Interface()
+	}
+	return list, nil
+}
+
+// toFields returns the fields of a tuple given as a list, or as a map by
+// the names of its components.
+func toFields(components Arguments, v interface{}) ([]interface{}, error) {
+	if m, ok := v.(map[string]interface{}); ok {
+		fields := make([]interface{}, len(components))
+		for i, c := range components {
+			field, ok := m[c.Name]
+			if !ok {
+				return nil, fmt.Errorf("missing field %q of the tuple", c.Name)
+			}
+			fields[i] = field
+		}
+		return fields, nil
+	}
+	fields, err := toList(v)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) != len(components) {
+		return nil, fmt.Errorf("expected %d fields of the tuple, got %d", len(components), len(fields))
+	}
+	return fields, nil
+}
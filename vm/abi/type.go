@@ -0,0 +1,153 @@
+package abi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TypeKind is the kind of an abi type.
+type TypeKind int
+
+const (
+	IntTy TypeKind = iota
+	UintTy
+	BoolTy
+	AddressTy
+	StringTy
+	BytesTy
+	FixedBytesTy
+	SliceTy
+	ArrayTy
+	TupleTy
+)
+
+// Type is a solidity abi type.
+type Type struct {
+	Kind TypeKind
+	// Size is the bits of an int, the length of a bytesN or of a fixed
+	// size array.
+	Size int
+	// Elem is the element type of an array.
+	Elem *Type
+	// Components are the fields of a tuple.
+	Components Arguments
+
+	str string
+}
+
+// NewType parses a type of the json abi. components are the fields of a
+// tuple, or of the tuples in an array of them.
+func NewType(t string, components Arguments) (Type, error) {
+	if strings.HasSuffix(t, "]") {
+		i := strings.LastIndex(t, "[")
+		if i < 0 {
+			return Type{}, fmt.Errorf("invalid array type %q", t)
+		}
+		elem, err := NewType(t[:i], components)
+		if err != nil {
+			return Type{}, err
+		}
+		size := t[i+1 : len(t)-1]
+		if size == "" {
+			return Type{Kind: SliceTy, Elem: &elem, str: elem.str + "[]"}, nil
+		}
+		n, err := strconv.Atoi(size)
+		if err != nil || n <= 0 {
+			return Type{}, fmt.Errorf("invalid array size of %q", t)
+		}
+		return Type{Kind: ArrayTy, Size: n, Elem: &elem, str: fmt.Sprintf("%s[%d]", elem.str, n)}, nil
+	}
+
+	switch {
+	case t == "tuple":
+		if len(components) == 0 {
+			return Type{}, fmt.Errorf("tuple without components")
+		}
+		names := make([]string, len(components))
+		for i, c := range components {
+			names[i] = c.Type.str
+		}
+		return Type{Kind: TupleTy, Components: components, str: "(" + strings.Join(names, ",") + ")"}, nil
+	case t == "bool":
+		return Type{Kind: BoolTy, str: t}, nil
+	case t == "address":
+		return Type{Kind: AddressTy, Size: 20, str: t}, nil
+	case t == "string":
+		return Type{Kind: StringTy, str: t}, nil
+	case t == "bytes":
+		return Type{Kind: BytesTy, str: t}, nil
+	case strings.HasPrefix(t, "bytes"):
+		n, err := strconv.Atoi(t[len("bytes"):])
+		if err != nil || n < 1 || n > 32 {
+			return Type{}, fmt.Errorf("invalid type %q", t)
+		}
+		return Type{Kind: FixedBytesTy, Size: n, str: t}, nil
+	case strings.HasPrefix(t, "uint"):
+		return newIntType(UintTy, t, t[len("uint"):])
+	case strings.HasPrefix(t, "int"):
+		return newIntType(IntTy, t, t[len("int"):])
+	}
+	return Type{}, fmt.Errorf("unsupported type %q", t)
+}
+
+func newIntType(kind TypeKind, t string, bits string) (Type, error) {
+	if bits == "" {
+		// uint and int are aliases of uint256 and int256.
+		return Type{Kind: kind, Size: 256, str: t + "256"}, nil
+	}
+	n, err := strconv.Atoi(bits)
+	if err != nil || n < 8 || n > 256 || n%8 != 0 {
+		return Type{}, fmt.Errorf("invalid type %q", t)
+	}
+	return Type{Kind: kind, Size: n, str: t}, nil
+}
+
+// String returns the canonical name of t used in signatures.
+func (t Type) String() string {
+	return t.str
+}
+
+// isDynamic tells whether t is encoded in the tail of its tuple.
+func (t Type) isDynamic() bool {
+	switch t.Kind {
+	case StringTy, BytesTy, SliceTy:
+		return true
+	case ArrayTy:
+		return t.Elem.isDynamic()
+	case TupleTy:
+		for _, c := range t.Components {
+			if c.Type.isDynamic() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// headSize is the bytes t takes in the head of its tuple.
+func (t Type) headSize() int {
+	if t.isDynamic() {
+		return 32
+	}
+	switch t.Kind {
+	case ArrayTy:
+		return t.Size * t.Elem.headSize()
+	case TupleTy:
+		size := 0
+		for _, c := range t.Components {
+			size += c.Type.headSize()
+		}
+		return size
+	}
+	return 32
+}
+
+// repeat returns n copies of t, the types of an array of t.
+func repeat(t Type, n int) []Type {
+	ts := make([]Type, n)
+	for i := range ts {
+		ts[i] = t
+	}
+	return ts
+}
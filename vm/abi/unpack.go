@@ -0,0 +1,197 @@
+package abi
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/annchain/OG/common/hexutil"
+	"github.com/annchain/OG/types"
+)
+
+// revertSelector leads the data of a revert with a reason, the selector of
+// Error(string).
+var revertSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+
+// Unpack decodes data encoded as a tuple of args. Integers are decoded to
+// *big.Int, addresses to types.Address, bytes to hexutil.Bytes, arrays
+// and tuples to []interface{}.
+func (args Arguments) Unpack(data []byte) ([]interface{}, error) {
+	return unpackTuple(args.Types(), data)
+}
+
+// Unpack decodes a log of e, whose indexed inputs are in topics and the
+// others in data. The indexed inputs of arrays, tuples, strings and bytes
+// are decoded to the types.Hash of their values.
+func (e *Event) Unpack(topics []types.Hash, data []byte) ([]interface{}, error) {
+	if !e.Anonymous {
+		if len(topics) == 0 || topics[0] != e.ID() {
+			return nil, fmt.Errorf("log is not an event of %s", e.Sig())
+		}
+		topics = topics[1:]
+	}
+	var indexed, others Arguments
+	for _, arg := range e.Inputs {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		} else {
+			others = append(others, arg)
+		}
+	}
+	if len(topics) != len(indexed) {
+		return nil, fmt.Errorf("expected %d indexed topics, got %d", len(indexed), len(topics))
+	}
+	decoded, err := others.Unpack(data)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]interface{}, 0, len(e.Inputs))
+	for _, arg := range e.Inputs {
+		if !arg.Indexed {
+			values = append(values, decoded[0])
+			decoded = decoded[1:]
+			continue
+		}
+		topic := topics[0]
+		topics = topics[1:]
+		switch arg.Type.Kind {
+		case StringTy, BytesTy, SliceTy, ArrayTy, TupleTy:
+			values = append(values, topic)
+		default:
+			v, err := unpack(arg.Type, topic.Bytes[:])
+			if err != nil {
+				return nil, fmt.Errorf("topic of %s: %v", arg.Name, err)
+			}
+			values = append(values, v)
+		}
+	}
+	return values, nil
+}
+
+// UnpackRevert decodes the reason of a revert, in the data returned by a
+// failed call.
+func UnpackRevert(data []byte) (string, error) {
+	if len(data) < 4 || !bytes.Equal(data[:4], revertSelector) {
+		return "", fmt.Errorf("data is not a revert reason")
+	}
+	values, err := unpackTuple([]Type{{Kind: StringTy, str: "string"}}, data[4:])
+	if err != nil {
+		return "", err
+	}
+	return values[0].(string), nil
+}
+
+// unpackTuple decodes values of ts from data, which starts at the head of
+// the tuple. Offsets of the dynamic values are relative to it.
+func unpackTuple(ts []Type, data []byte) ([]interface{}, error) {
+	values := make([]interface{}, len(ts))
+	offset := 0
+	for i, t := range ts {
+		if len(data) < offset+t.headSize() {
+			return nil, fmt.Errorf("arg %d: data too short", i)
+		}
+		var (
+			v   interface{}
+			err error
+		)
+		if t.isDynamic() {
+			var start int
+			start, err = readLength(data[offset:], len(data))
+			if err == nil {
+				v, err = unpack(t, data[start:])
+			}
+		} else {
+			v, err = unpack(t, data[offset:])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("arg %d: %v", i, err)
+		}
+		values[i] = v
+		offset += t.headSize()
+	}
+	return values, nil
+}
+
+// unpack decodes a value of t from data, which starts at the value.
+func unpack(t Type, data []byte) (interface{}, error) {
+	switch t.Kind {
+	case IntTy, UintTy:
+		if len(data) < 32 {
+			return nil, fmt.Errorf("data too short")
+		}
+		return unpackInt(t, data[:32])
+	case BoolTy:
+		if len(data) < 32 {
+			return nil, fmt.Errorf("data too short")
+		}
+		n := new(big.Int).SetBytes(data[:32])
+		if n.BitLen() > 1 {
+			return nil, fmt.Errorf("invalid bool %x", data[:32])
+		}
+		return n.Sign() == 1, nil
+	case AddressTy:
+		if len(data) < 32 {
+			return nil, fmt.Errorf("data too short")
+		}
+		return types.BytesToAddress(data[12:32]), nil
+	case FixedBytesTy:
+		if len(data) < 32 {
+			return nil, fmt.Errorf("data too short")
+		}
+		return hexutil.Bytes(append([]byte{}, data[:t.Size]...)), nil
+	case BytesTy, StringTy:
+		length, err := readLength(data, len(data)-32)
+		if err != nil {
+			return nil, err
+		}
+		b := append([]byte{}, data[32:32+length]...)
+		if t.Kind == StringTy {
+			return string(b), nil
+		}
+		return hexutil.Bytes(b), nil
+	case SliceTy:
+		// every element takes its head size at least.
+		length, err := readLength(data, (len(data)-32)/t.Elem.headSize())
+		if err != nil {
+			return nil, err
+		}
+		return unpackTuple(repeat(*t.Elem, length), data[32:])
+	case ArrayTy:
+		return unpackTuple(repeat(*t.Elem, t.Size), data)
+	case TupleTy:
+		return unpackTuple(t.Components.Types(), data)
+	}
+	return nil, fmt.Errorf("unknown type %s", t)
+}
+
+// unpackInt decodes an integer of t, in two's complement if it's signed.
+func unpackInt(t Type, word []byte) (*big.Int, error) {
+	n := new(big.Int).SetBytes(word)
+	if t.Kind == IntTy && word[0]&0x80 != 0 {
+		n.Sub(n, tt256)
+	}
+	bits := n.BitLen()
+	if t.Kind == IntTy && n.Sign() < 0 {
+		// -2^(size-1) is the least of the type.
+		bits = new(big.Int).Add(n, big.NewInt(1)).BitLen() + 1
+	} else if t.Kind == IntTy {
+		bits++
+	}
+	if bits > t.Size {
+		return nil, fmt.Errorf("%s overflows %s", n, t)
+	}
+	return n, nil
+}
+
+// readLength reads a length or an offset in the first word of data, which
+// must not exceed max.
+func readLength(data []byte, max int) (int, error) {
+	if len(data) < 32 {
+		return 0, fmt.Errorf("data too short")
+	}
+	n := new(big.Int).SetBytes(data[:32])
+	if max < 0 || !n.IsInt64() || n.Int64() > int64(max) {
+		return 0, fmt.Errorf("length or offset %s out of the data", n)
+	}
+	return int(n.Int64()), nil
+}
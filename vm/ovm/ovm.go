@@ -159,8 +159,7 @@ func (ovm *OVM) Call(caller vmtypes.ContractRef, addr types.Address, input []byt
 		}
 		ctx.StateDB.CreateAccount(addr)
 	}
-	if value.Sign() != 0 && !txCall {
-
+	if value.Sign() != 0 {
 		ctx.Transfer(ctx.StateDB, caller.Address(), to.Address(), value)
 	}
 
@@ -340,6 +339,7 @@ func (ovm *OVM) create(caller vmtypes.ContractRef, codeAndHash *vmtypes.CodeAndH
 	if !ctx.CanTransfer(ctx.StateDB, caller.Address(), value) {
 		return nil, types.Address{}, gas, vmtypes.ErrInsufficientBalance
 	}
+	// the nonce of a tx sender is kept by the dag.
 	nonce := ctx.StateDB.GetNonce(caller.Address())
 	if !txCall {
 		ctx.StateDB.SetNonce(caller.Address(), nonce+1)
@@ -355,7 +355,7 @@ func (ovm *OVM) create(caller vmtypes.ContractRef, codeAndHash *vmtypes.CodeAndH
 	ctx.StateDB.CreateAccount(address)
 	ctx.StateDB.SetNonce(address, 1)
 
-	if value.Sign() != 0 && !txCall {
+	if value.Sign() != 0 {
 		ctx.Transfer(ctx.StateDB, caller.Address(), address, value)
 	}
 
@@ -25,6 +25,9 @@ import (
 )
 
 //go:generate gencodec -type Log -field-override logMarshaling -out gen_log_json.go
+//go:generate msgp
+//msgp:tuple Log
+//msgp:ignore logMarshaling rlpLog rlpStorageLog LogForStorage
 
 // Log represents a contract log event. These events are generated by the LOG opcode and
 // stored/indexed by the node.
@@ -55,6 +58,8 @@ type Log struct {
 	Removed bool `json:"removed"`
 }
 
+type Logs []*Log
+
 type logMarshaling struct {
 	Data        hexutil.Bytes
 	BlockNumber hexutil.Uint64
@@ -161,11 +161,12 @@ func (e *event2Cons) Remove(eventType string, conn *Conn) error {
 		return fmt.Errorf("No Connection with eventType: %s\n", eventType)
 	}
 	thisID := conn.GetID()
-	if _, ok := e.getFromMap(thisID); !ok {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if _, ok := conns[thisID]; !ok {
 		return fmt.Errorf("No connection with ID: %s\n", thisID)
-	} else {
-		delete(conns, thisID)
 	}
+	delete(conns, thisID)
 	return nil
 }
 
@@ -174,6 +175,8 @@ func (e *event2Cons) Get(eventType string) ([]*Conn, error) {
 	if !ok {
 		return nil, fmt.Errorf("No Connection with eventType: %s\n", eventType)
 	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
 	var ret []*Conn
 	for _, c := range conns {
 		ret = append(ret, c)
@@ -1,20 +1,27 @@
 package wserver
 
 import (
-	"fmt"
-	"github.com/annchain/OG/types"
 	"testing"
+
+	"github.com/annchain/OG/types"
 )
 
 func TestConvertor(t *testing.T) {
-	tx := types.Tx{
+	tx := &types.Tx{
 		TxBase: types.TxBase{
+			Type:        types.TxBaseTypeNormal,
 			Hash:        types.BytesToHash([]byte{1, 2, 3, 4, 5}),
 			ParentsHash: []types.Hash{types.BytesToHash([]byte{1, 1, 2, 2, 3, 3})},
 		},
-		From:  types.HexToAddress("0x12345"),
-		To:    types.HexToAddress("0x56789"),
-		Value: nil,
+		From: types.HexToAddress("0x12345"),
+		To:   types.HexToAddress("0x56789"),
+	}
+	var uidata UIData
+	uidata.AddToBatch(tx, true)
+	if len(uidata.Nodes) != 1 || uidata.Nodes[0].Data.Unit != tx.GetTxHash().Hex() {
+		t.Fatalf("expected the node of tx, got %v", uidata.Nodes)
+	}
+	if len(uidata.Edges) != 1 || uidata.Edges[0].Target != tx.ParentsHash[0].Hex() {
+		t.Fatalf("expected the edge to the parent, got %v", uidata.Edges)
 	}
-	fmt.Println(tx2UIData(tx))
 }
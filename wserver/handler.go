@@ -18,14 +18,22 @@ type websocketHandler struct {
 	// upgrader is used to upgrade request.
 	upgrader *websocket.Upgrader
 
-	event2Cons *event2Cons
+	event2Cons    *event2Cons
+	subscriptions *subscriptions
 }
 
 // RegisterMessage defines message struct client send after connect
-// to the server.
+// to the server. A conn may register several events by sending one
+// message for each. Registering an event again replaces its parameters.
 type RegisterMessage struct {
 	//Token string
 	Event string `json:"event"`
+	// Addresses and Topics are the parameters of the "logs" and "txs"
+	// events. "logs" pushes the logs emitted by any of Addresses whose
+	// i-th topic is any of Topics[i]. "txs" pushes the txs sent from or
+	// to any of Addresses. Empty parameters match anything.
+	Addresses []string   `json:"addresses"`
+	Topics    [][]string `json:"topics"`
 }
 
 func (wh *websocketHandler) Handle(ctx *gin.Context) {
@@ -43,7 +51,7 @@ func (wh *websocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	// handle Websocket request
 	conn := NewConn(wsConn)
-	var eventType string
+	eventTypes := map[string]struct{}{}
 	conn.AfterReadFunc = func(messageType int, r io.Reader) {
 		var rm RegisterMessage
 		decoder := json.NewDecoder(r)
@@ -52,11 +60,22 @@ func (wh *websocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if isParameterised(rm.Event) {
+			sub, err := newSubscription(&rm)
+			if err != nil {
+				logrus.WithError(err).WithField("event", rm.Event).Debug("invalid subscription")
+				return
+			}
+			wh.subscriptions.set(rm.Event, conn.GetID(), sub)
+		}
 		wh.event2Cons.Add(rm.Event, conn)
-		eventType = rm.Event
+		eventTypes[rm.Event] = struct{}{}
 	}
 	conn.BeforeCloseFunc = func() {
-		wh.event2Cons.Remove(eventType, conn)
+		for eventType := range eventTypes {
+			wh.event2Cons.Remove(eventType, conn)
+			wh.subscriptions.remove(eventType, conn.GetID())
+		}
 	}
 
 	conn.Listen()
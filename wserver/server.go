@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/annchain/OG/core"
 	"github.com/annchain/OG/types"
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
@@ -59,6 +60,13 @@ type Server struct {
 	// to receive confirmation events
 	BatchConfirmedChan chan map[types.Hash]types.Txi
 
+	// to receive confirmed sequencers
+	SequencerConfirmedChan chan *types.Sequencer
+
+	// Ledger is read for the txs and logs of a confirmed sequencer. The
+	// "txs" and "logs" events are not pushed if it is nil.
+	Ledger Ledger
+
 	wh     *websocketHandler
 	ph     *pushHandler
 	engine *gin.Engine
@@ -70,6 +78,7 @@ func (s *Server) GetBenchmarks() map[string]interface{} {
 	return map[string]interface{}{
 		"newtx":   len(s.NewTxReceivedChan),
 		"batchtx": len(s.BatchConfirmedChan),
+		"seq":     len(s.SequencerConfirmedChan),
 	}
 }
 
@@ -90,20 +99,22 @@ func (s *Server) Push(event, message string) (int, error) {
 // NewServer creates a new Server.
 func NewServer(addr string) *Server {
 	s := &Server{
-		Addr:               addr,
-		WSPath:             serverDefaultWSPath,
-		PushPath:           serverDefaultPushPath,
-		NewTxReceivedChan:  make(chan types.Txi, 10000),
-		BatchConfirmedChan: make(chan map[types.Hash]types.Txi, 1000),
-		quit:               make(chan bool),
+		Addr:                   addr,
+		WSPath:                 serverDefaultWSPath,
+		PushPath:               serverDefaultPushPath,
+		NewTxReceivedChan:      make(chan types.Txi, 10000),
+		BatchConfirmedChan:     make(chan map[types.Hash]types.Txi, 1000),
+		SequencerConfirmedChan: make(chan *types.Sequencer, 1000),
+		quit:                   make(chan bool),
 	}
 
 	e2c := NewEvent2Cons()
 
 	// websocket request handler
 	wh := websocketHandler{
-		upgrader:   defaultUpgrader,
-		event2Cons: e2c,
+		upgrader:      defaultUpgrader,
+		event2Cons:    e2c,
+		subscriptions: newSubscriptions(),
 	}
 	if s.Upgrader != nil {
 		wh.upgrader = s.Upgrader
@@ -169,6 +180,8 @@ func (s *Server) WatchNewTxs() {
 			uidata = nil
 			// then publish batch
 			s.publishBatch(batch)
+		case seq := <-s.SequencerConfirmedChan:
+			s.publishSequencer(seq)
 		case <-ticker.C:
 			s.publishTxs(uidata)
 			uidata = nil
@@ -212,3 +225,82 @@ func (s *Server) publishBatch(elders map[types.Hash]types.Txi) {
 	s.Push(messageTypeConfirmed, string(bs))
 
 }
+
+// publishSequencer pushes the header of a confirmed sequencer, and the
+// txs and logs it confirmed to the conns whose subscriptions match them.
+func (s *Server) publishSequencer(seq *types.Sequencer) {
+	var txs types.Txs
+	var receipts core.ReceiptSet
+	if s.Ledger != nil {
+		txs = s.Ledger.GetTxsByNumber(seq.Height)
+		receipts = s.Ledger.GetReceipts(seq.Height)
+	}
+
+	bs, err := json.Marshal(Message{
+		Type: messageTypeNewSequencer,
+		Data: newSequencerData(seq, len(txs)),
+	})
+	if err != nil {
+		logrus.WithError(err).Error("Failed to marshal ws message")
+		return
+	}
+	s.Push(messageTypeNewSequencer, string(bs))
+
+	if s.Ledger == nil {
+		return
+	}
+	s.pushSubscribed(messageTypeTxs, func(sub *subscription) interface{} {
+		var data []TxData
+		for _, tx := range txs {
+			if sub.matchTx(tx) {
+				data = append(data, newTxData(tx, seq.Height))
+			}
+		}
+		if len(data) == 0 {
+			return nil
+		}
+		return data
+	})
+	logs := sortedLogs(receipts)
+	s.pushSubscribed(messageTypeLogs, func(sub *subscription) interface{} {
+		var data []LogData
+		for _, l := range logs {
+			if sub.matchLog(l) {
+				data = append(data, newLogData(l))
+			}
+		}
+		if len(data) == 0 {
+			return nil
+		}
+		return data
+	})
+}
+
+// pushSubscribed pushes the data selected by each conn's subscription
+// to the conns registered eventType. Nothing is pushed to a conn if
+// selectFunc returns nil.
+func (s *Server) pushSubscribed(eventType string, selectFunc func(sub *subscription) interface{}) {
+	conns, err := s.wh.event2Cons.Get(eventType)
+	if err != nil {
+		return
+	}
+	for _, conn := range conns {
+		sub := s.wh.subscriptions.get(eventType, conn.GetID())
+		if sub == nil {
+			continue
+		}
+		data := selectFunc(sub)
+		if data == nil {
+			continue
+		}
+		bs, err := json.Marshal(Message{Type: eventType, Data: data})
+		if err != nil {
+			logrus.WithError(err).Error("Failed to marshal ws message")
+			continue
+		}
+		if _, err := conn.Write(bs); err != nil {
+			s.wh.event2Cons.Remove(eventType, conn)
+			s.wh.subscriptions.remove(eventType, conn.GetID())
+		}
+	}
+}
@@ -2,11 +2,17 @@ package wserver
 
 import (
 	"fmt"
+	"os"
 	"testing"
 	"time"
 )
 
+// TestServer runs a demo server pushing messages until it is killed, so it
+// is only run by hand.
 func TestServer(t *testing.T) {
+	if os.Getenv("OG_WSERVER_DEMO") == "" {
+		t.Skip("demo server, set OG_WSERVER_DEMO to run it")
+	}
 	addr := ":12345"
 	srv := NewServer(addr)
 	go func() {
@@ -0,0 +1,197 @@
+package wserver
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/annchain/OG/common/hexutil"
+	"github.com/annchain/OG/core"
+	"github.com/annchain/OG/types"
+	vmtypes "github.com/annchain/OG/vm/types"
+)
+
+const (
+	messageTypeLogs         = "logs"
+	messageTypeTxs          = "txs"
+	messageTypeNewSequencer = "new_sequencer"
+)
+
+// Ledger provides the confirmed txs and receipts of a sequencer.
+type Ledger interface {
+	GetTxsByNumber(height uint64) types.Txs
+	GetReceipts(height uint64) core.ReceiptSet
+}
+
+// subscription holds the parameters a conn registers an event with.
+// "logs" matches logs by the addresses and topics of filter, "txs"
+// matches txs sent from or to any of filter.Addresses. Empty parameters
+// match anything.
+type subscription struct {
+	filter core.LogFilter
+}
+
+func newSubscription(rm *RegisterMessage) (*subscription, error) {
+	sub := &subscription{}
+	for _, addrStr := range rm.Addresses {
+		addr, err := types.StringToAddress(addrStr)
+		if err != nil {
+			return nil, fmt.Errorf("address format error: %v", err)
+		}
+		sub.filter.Addresses = append(sub.filter.Addresses, addr)
+	}
+	for _, topicStrs := range rm.Topics {
+		var topics []types.Hash
+		for _, topicStr := range topicStrs {
+			topic, err := types.HexStringToHash(topicStr)
+			if err != nil {
+				return nil, fmt.Errorf("topic format error: %v", err)
+			}
+			topics = append(topics, topic)
+		}
+		sub.filter.Topics = append(sub.filter.Topics, topics)
+	}
+	return sub, nil
+}
+
+func (s *subscription) matchTx(tx *types.Tx) bool {
+	if len(s.filter.Addresses) == 0 {
+		return true
+	}
+	for _, addr := range s.filter.Addresses {
+		if tx.From == addr || tx.To == addr {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *subscription) matchLog(l *vmtypes.Log) bool {
+	return s.filter.Match(l)
+}
+
+// subscriptions keeps the subscription of each conn, keyed by event type
+// and then conn ID.
+type subscriptions struct {
+	subs map[string]map[string]*subscription
+	mu   sync.RWMutex
+}
+
+func newSubscriptions() *subscriptions {
+	return &subscriptions{
+		subs: make(map[string]map[string]*subscription),
+	}
+}
+
+func (s *subscriptions) set(eventType string, connID string, sub *subscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.subs[eventType] == nil {
+		s.subs[eventType] = make(map[string]*subscription)
+	}
+	s.subs[eventType][connID] = sub
+}
+
+func (s *subscriptions) get(eventType string, connID string) *subscription {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.subs[eventType][connID]
+}
+
+func (s *subscriptions) remove(eventType string, connID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.subs[eventType], connID)
+}
+
+// isParameterised checks if the event is registered with a subscription.
+func isParameterised(eventType string) bool {
+	return eventType == messageTypeLogs || eventType == messageTypeTxs
+}
+
+// Message is the ws message of the "logs", "txs" and "new_sequencer"
+// events.
+type Message struct {
+	Type string      `json:"type"`
+	Data interface{} `json:"data"`
+}
+
+type SequencerData struct {
+	Hash      string `json:"hash"`
+	Height    uint64 `json:"height"`
+	Issuer    string `json:"issuer"`
+	Timestamp int64  `json:"timestamp"`
+	StateRoot string `json:"state_root"`
+	TxCount   int    `json:"tx_count"`
+}
+
+type TxData struct {
+	Hash      string `json:"hash"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Value     string `json:"value"`
+	Nonce     uint64 `json:"nonce"`
+	SeqHeight uint64 `json:"seq_height"`
+}
+
+type LogData struct {
+	Address   string   `json:"address"`
+	Topics    []string `json:"topics"`
+	Data      string   `json:"data"`
+	SeqHeight uint64   `json:"seq_height"`
+	SeqHash   string   `json:"seq_hash"`
+	TxHash    string   `json:"tx_hash"`
+	TxIndex   uint     `json:"tx_index"`
+	LogIndex  uint     `json:"log_index"`
+}
+
+func newSequencerData(seq *types.Sequencer, txCount int) SequencerData {
+	return SequencerData{
+		Hash:      seq.GetTxHash().Hex(),
+		Height:    seq.Height,
+		Issuer:    seq.Issuer.Hex(),
+		Timestamp: seq.Timestamp,
+		StateRoot: seq.StateRoot.Hex(),
+		TxCount:   txCount,
+	}
+}
+
+func newTxData(tx *types.Tx, seqHeight uint64) TxData {
+	return TxData{
+		Hash:      tx.GetTxHash().Hex(),
+		From:      tx.From.Hex(),
+		To:        tx.To.Hex(),
+		Value:     tx.GetValue().String(),
+		Nonce:     tx.GetNonce(),
+		SeqHeight: seqHeight,
+	}
+}
+
+func newLogData(l *vmtypes.Log) LogData {
+	ld := LogData{
+		Address:   l.Address.Hex(),
+		Topics:    make([]string, 0, len(l.Topics)),
+		Data:      hexutil.Encode(l.Data),
+		SeqHeight: l.SequenceID,
+		SeqHash:   l.BlockHash.Hex(),
+		TxHash:    l.TxHash.Hex(),
+		TxIndex:   l.TxIndex,
+		LogIndex:  l.Index,
+	}
+	for _, topic := range l.Topics {
+		ld.Topics = append(ld.Topics, topic.Hex())
+	}
+	return ld
+}
+
+// sortedLogs returns all the logs in receipts ordered by log index.
+func sortedLogs(receipts core.ReceiptSet) []*vmtypes.Log {
+	var logs []*vmtypes.Log
+	for _, receipt := range receipts {
+		logs = append(logs, receipt.Logs...)
+	}
+	sort.Slice(logs, func(i, j int) bool {
+		return logs[i].Index < logs[j].Index
+	})
+	return logs
+}
@@ -0,0 +1,61 @@
+package wserver
+
+import (
+	"testing"
+
+	"github.com/annchain/OG/types"
+	vmtypes "github.com/annchain/OG/vm/types"
+)
+
+func TestSubscription(t *testing.T) {
+	addr1 := types.HexToAddress("0x01")
+	addr2 := types.HexToAddress("0x02")
+	topic1 := types.HexToHash("0x01")
+	topic2 := types.HexToHash("0x02")
+
+	rm := &RegisterMessage{
+		Event:     messageTypeLogs,
+		Addresses: []string{addr1.Hex()},
+		Topics:    [][]string{{}, {topic2.Hex()}},
+	}
+	sub, err := newSubscription(rm)
+	if err != nil {
+		t.Fatalf("new subscription error: %v", err)
+	}
+	logs := []struct {
+		log   *vmtypes.Log
+		match bool
+	}{
+		{&vmtypes.Log{Address: addr1, Topics: []types.Hash{topic1, topic2}}, true},
+		{&vmtypes.Log{Address: addr1, Topics: []types.Hash{topic2, topic1}}, false},
+		{&vmtypes.Log{Address: addr1, Topics: []types.Hash{topic1}}, false},
+		{&vmtypes.Log{Address: addr2, Topics: []types.Hash{topic1, topic2}}, false},
+	}
+	for i, l := range logs {
+		if sub.matchLog(l.log) != l.match {
+			t.Errorf("log %d: expected match %v", i, l.match)
+		}
+	}
+
+	txs := []struct {
+		tx    *types.Tx
+		match bool
+	}{
+		{&types.Tx{From: addr1, To: addr2}, true},
+		{&types.Tx{From: addr2, To: addr1}, true},
+		{&types.Tx{From: addr2, To: addr2}, false},
+	}
+	for i, tx := range txs {
+		if sub.matchTx(tx.tx) != tx.match {
+			t.Errorf("tx %d: expected match %v", i, tx.match)
+		}
+	}
+	if !(&subscription{}).matchTx(txs[2].tx) {
+		t.Error("empty subscription should match any tx")
+	}
+
+	rm.Addresses = []string{addr1.Hex() + "00"}
+	if _, err := newSubscription(rm); err == nil {
+		t.Error("expected error on bad address")
+	}
+}